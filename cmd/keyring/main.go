@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go-drive/internal/keyring"
+	"go-drive/internal/storage"
+)
+
+func main() {
+	// Parse command line flags
+	action := flag.String("action", "rotate", "Action to perform: generate, rotate")
+	id := flag.String("id", time.Now().UTC().Format("2006-01-02"), "ID of the generated master key")
+	keyringFile := flag.String("keyring-file", getEnv("ENCRYPTION_KEYRING_FILE", ""), "Keyring file, active key first")
+	keys := flag.String("keys", getEnv("ENCRYPTION_KEYS", ""), "Comma-separated keyring entries, active key first")
+
+	flag.Parse()
+
+	switch *action {
+	case "generate":
+		entry, err := keyring.Generate(*id)
+		if err != nil {
+			log.Fatalf("Generate failed: %v", err)
+		}
+		// Printed alone on stdout so it can be appended to a keyring file
+		fmt.Println(entry)
+
+	case "rotate":
+		ring, err := keyring.Load(*keyringFile, *keys)
+		if err != nil {
+			log.Fatalf("Failed to load keyring: %v", err)
+		}
+		if ring == nil {
+			log.Fatal("No keyring configured: set ENCRYPTION_KEYRING_FILE or ENCRYPTION_KEYS")
+		}
+
+		blobs, err := storage.New(context.Background(), storage.Config{
+			Driver:      getEnv("STORAGE_DRIVER", storage.DriverLocal),
+			LocalRoot:   getEnv("STORAGE_LOCAL_ROOT", "/var/lib/go-drive/blobs"),
+			S3Endpoint:  getEnv("S3_ENDPOINT", "minio:9000"),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", "go-drive"),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3UseSSL:    getEnv("S3_USE_SSL", "false") == "true",
+			Keyring:     ring,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize storage: %v", err)
+		}
+
+		log.Printf("Re-wrapping data keys under master key %q", ring.Active())
+		rotated, err := blobs.(*storage.EncryptedBackend).RotateKeys(context.Background())
+		if err != nil {
+			log.Fatalf("Rotation failed after re-wrapping %d data keys: %v", rotated, err)
+		}
+		log.Printf("✓ Re-wrapped %d data keys; keys other than %q can now be removed from the keyring", rotated, ring.Active())
+
+	default:
+		log.Fatalf("Unknown action: %s. Valid actions: generate, rotate", *action)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
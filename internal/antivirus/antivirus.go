@@ -0,0 +1,181 @@
+// Package antivirus scans content for malware. Clamd talks to a ClamAV daemon over TCP.
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Result is the outcome of a scan
+type Result struct {
+	Infected bool
+	// Signature names the malware found in infected content
+	Signature string
+}
+
+// Scanner scans content for malware
+type Scanner interface {
+	// Scan reads r to the end and reports whether it is infected. An error means the
+	// content could not be scanned, not that it is clean.
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
+
+const (
+	// defaultTimeout is how long Clamd waits on the daemon when no timeout is given
+	defaultTimeout = 30 * time.Second
+	// chunkSize is the size of the chunks content is streamed to clamd in
+	chunkSize = 64 << 10
+)
+
+var (
+	// ErrDaemon is returned when clamd answers a scan with an error, such as content over
+	// its StreamMaxLength
+	ErrDaemon = errors.New("clamd error")
+	// errWrite marks failures to send to clamd, after which it may still have answered
+	errWrite = errors.New("failed to write to clamd")
+)
+
+// Clamd scans content with a ClamAV daemon through its INSTREAM command
+type Clamd struct {
+	addr    string
+	timeout time.Duration
+}
+
+// NewClamd returns a scanner for the clamd listening on addr (host:port). timeout bounds
+// each exchange with the daemon, not the whole scan; zero selects 30 seconds.
+func NewClamd(addr string, timeout time.Duration) *Clamd {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Clamd{addr: addr, timeout: timeout}
+}
+
+// Scan streams r to clamd in length-prefixed chunks and parses its verdict
+func (c *Clamd) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	reply, err := c.command(ctx, "INSTREAM", func(conn net.Conn) error {
+		buf := make([]byte, 4+chunkSize)
+		for {
+			n, err := io.ReadFull(r, buf[4:])
+			if n > 0 {
+				binary.BigEndian.PutUint32(buf, uint32(n))
+				if err := c.write(conn, buf[:4+n]); err != nil {
+					return err
+				}
+			}
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read content: %w", err)
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		// A zero-length chunk ends the stream
+		return c.write(conn, []byte{0, 0, 0, 0})
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return parseReply(reply)
+}
+
+// Ping checks that clamd is up
+func (c *Clamd) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "PING", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("%w: unexpected reply to PING: %q", ErrDaemon, reply)
+	}
+	return nil
+}
+
+// command sends a null-terminated command on a new connection, lets send write its
+// payload and returns the daemon's reply
+func (c *Clamd) command(ctx context.Context, name string, send func(net.Conn) error) (string, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks reads and writes when ctx is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	err = c.write(conn, []byte("z"+name+"\x00"))
+	if err == nil && send != nil {
+		err = send(conn)
+	}
+	if err != nil {
+		// clamd answers and hangs up when it refuses a stream, such as one that is too long
+		if errors.Is(err, errWrite) && ctx.Err() == nil {
+			if reply, readErr := c.read(conn); readErr == nil {
+				return reply, nil
+			}
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", err
+	}
+
+	reply, err := c.read(conn)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", err
+	}
+	return reply, nil
+}
+
+// read returns the null-terminated reply of the daemon
+func (c *Clamd) read(conn net.Conn) (string, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return "", err
+	}
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil {
+		return "", fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return string(bytes.TrimSuffix(reply, []byte{0})), nil
+}
+
+// write sends data within the timeout
+func (c *Clamd) write(conn net.Conn, data []byte) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		return err
+	}
+	if _, err := conn.Write(data); err != nil {
+		return fmt.Errorf("%w: %v", errWrite, err)
+	}
+	return nil
+}
+
+// parseReply interprets clamd's answer to INSTREAM: "stream: OK",
+// "stream: <signature> FOUND" or "<message> ERROR"
+func parseReply(reply string) (Result, error) {
+	switch {
+	case strings.HasSuffix(reply, " ERROR"):
+		return Result{}, fmt.Errorf("%w: %s", ErrDaemon, strings.TrimSuffix(reply, " ERROR"))
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return Result{Infected: true, Signature: signature}, nil
+	case reply == "stream: OK":
+		return Result{}, nil
+	}
+	return Result{}, fmt.Errorf("%w: unexpected reply: %q", ErrDaemon, reply)
+}
@@ -0,0 +1,203 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eicar is the standard antivirus test file
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd speaks enough of the clamd protocol to answer PING and INSTREAM. Streams
+// containing the EICAR string are reported infected; streams over maxLength are refused.
+type fakeClamd struct {
+	listener  net.Listener
+	maxLength int
+	// received is the content of the last stream
+	received chan []byte
+}
+
+func newFakeClamd(t *testing.T, maxLength int) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &fakeClamd{listener: listener, maxLength: maxLength, received: make(chan []byte, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeClamd) addr() string {
+	return f.listener.Addr().String()
+}
+
+func (f *fakeClamd) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	command, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	switch command {
+	case "zPING\x00":
+		conn.Write([]byte("PONG\x00"))
+	case "zINSTREAM\x00":
+		var content []byte
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if len(content)+int(size) > f.maxLength {
+				conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+				return
+			}
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return
+			}
+			content = append(content, chunk...)
+		}
+		f.received <- content
+		if bytes.Contains(content, []byte(eicar)) {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+			return
+		}
+		conn.Write([]byte("stream: OK\x00"))
+	default:
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+	}
+}
+
+func TestClamd_Scan(t *testing.T) {
+	clamd := newFakeClamd(t, 1<<20)
+	scanner := NewClamd(clamd.addr(), time.Second)
+
+	t.Run("clean", func(t *testing.T) {
+		content := strings.Repeat("harmless ", 20000)
+		result, err := scanner.Scan(context.Background(), strings.NewReader(content))
+
+		require.NoError(t, err)
+		assert.False(t, result.Infected)
+		assert.Equal(t, content, string(<-clamd.received), "content should arrive whole across chunks")
+	})
+
+	t.Run("infected", func(t *testing.T) {
+		result, err := scanner.Scan(context.Background(), strings.NewReader(eicar))
+
+		require.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+		<-clamd.received
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := scanner.Scan(context.Background(), strings.NewReader(""))
+
+		require.NoError(t, err)
+		assert.False(t, result.Infected)
+		assert.Empty(t, <-clamd.received)
+	})
+
+	t.Run("over the stream limit", func(t *testing.T) {
+		_, err := scanner.Scan(context.Background(), io.LimitReader(zeros{}, 8<<20))
+
+		assert.ErrorIs(t, err, ErrDaemon)
+		assert.Contains(t, err.Error(), "size limit exceeded")
+	})
+
+	t.Run("content read failure", func(t *testing.T) {
+		broken := io.MultiReader(strings.NewReader("partial"), errReader{})
+		_, err := scanner.Scan(context.Background(), broken)
+
+		assert.ErrorIs(t, err, errBroken)
+	})
+}
+
+func TestClamd_Ping(t *testing.T) {
+	clamd := newFakeClamd(t, 1<<20)
+
+	assert.NoError(t, NewClamd(clamd.addr(), time.Second).Ping(context.Background()))
+}
+
+func TestClamd_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	_, err = NewClamd(addr, time.Second).Scan(context.Background(), strings.NewReader("content"))
+
+	assert.Error(t, err)
+}
+
+func TestClamd_Canceled(t *testing.T) {
+	// A daemon that accepts connections but never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = NewClamd(listener.Addr().String(), time.Minute).Scan(ctx, strings.NewReader("content"))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParseReply(t *testing.T) {
+	result, err := parseReply("stream: Win.Test.EICAR_HDB-1 FOUND")
+	require.NoError(t, err)
+	assert.Equal(t, Result{Infected: true, Signature: "Win.Test.EICAR_HDB-1"}, result)
+
+	_, err = parseReply("garbage")
+	assert.ErrorIs(t, err, ErrDaemon)
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+var errBroken = errors.New("broken reader")
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errBroken
+}
@@ -0,0 +1,324 @@
+// Package archive reads the ZIP and TAR archives stored in the drive, TAR plain or
+// compressed with gzip. Archives are read through an io.ReaderAt, so listing a ZIP reads
+// only its central directory and listing a plain TAR only its headers, skipping the content
+// in between. Entry paths are checked before they are handed out: an entry that is absolute
+// or climbs out of the archive with .. fails the whole archive, so nothing built to write
+// outside its destination is ever extracted.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrCorrupt is returned for content that does not parse as an archive of its format
+	ErrCorrupt = errors.New("archive is corrupt")
+	// ErrUnsafePath is returned for an archive with an entry whose path leaves the archive
+	ErrUnsafePath = errors.New("archive entry path leaves the archive")
+	// ErrTooManyEntries is returned for an archive with more entries than allowed
+	ErrTooManyEntries = errors.New("archive has too many entries")
+	// ErrTooLarge is returned for an archive whose files add up to more than allowed
+	ErrTooLarge = errors.New("archive expands beyond the size limit")
+)
+
+// Format is the container format of an archive
+type Format int
+
+const (
+	FormatNone Format = iota
+	FormatZip
+	FormatTar
+	FormatTarGzip
+)
+
+// FormatOf picks the format of an archive from its MIME type, falling back on the file
+// name's extension. Gzip content is only taken for a TAR archive when the name says so.
+func FormatOf(mimeType, name string) Format {
+	name = strings.ToLower(name)
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	switch mediaType {
+	case "application/zip", "application/x-zip-compressed":
+		return FormatZip
+	case "application/x-tar":
+		return FormatTar
+	case "application/gzip", "application/x-gzip":
+		if strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") {
+			return FormatTarGzip
+		}
+		return FormatNone
+	case "", "application/octet-stream":
+	default:
+		return FormatNone
+	}
+
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return FormatZip
+	case strings.HasSuffix(name, ".tar"):
+		return FormatTar
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return FormatTarGzip
+	}
+	return FormatNone
+}
+
+// TrimExtension strips the archive extension from a file name: build.tar.gz becomes build
+func TrimExtension(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(lower, ext) && len(name) > len(ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// Entry is a folder or a file in an archive. Other entries, such as links and devices,
+// are left out.
+type Entry struct {
+	// Path is relative and slash-separated, without empty or . segments
+	Path     string
+	Dir      bool
+	Size     int64
+	Modified time.Time
+}
+
+// Limits bound what reading an archive may expand to. Zero values mean unlimited.
+type Limits struct {
+	// MaxEntries caps the entries of the archive, counting those that are left out
+	MaxEntries int
+	// MaxSize caps the total size of the archive's files
+	MaxSize int64
+}
+
+// List returns the entries of the archive read from r, which holds size bytes
+func List(r io.ReaderAt, size int64, format Format, limits Limits) ([]Entry, error) {
+	var entries []Entry
+	err := walk(r, size, format, limits, false, func(entry Entry, _ io.Reader) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// Walk calls fn with every entry of the archive read from r, which holds size bytes, in
+// archive order. The content of files can be read from the reader passed along, which
+// yields exactly the entry's size or fails; it is nil for folders. An error returned by
+// fn stops the walk and is returned as is.
+func Walk(r io.ReaderAt, size int64, format Format, limits Limits, fn func(Entry, io.Reader) error) error {
+	return walk(r, size, format, limits, true, fn)
+}
+
+func walk(r io.ReaderAt, size int64, format Format, limits Limits, open bool, fn func(Entry, io.Reader) error) error {
+	src := &sourceReader{r: r}
+	w := &walker{src: src, limits: limits, open: open, fn: fn}
+
+	var err error
+	switch format {
+	case FormatZip:
+		err = w.zip(size)
+	case FormatTar, FormatTarGzip:
+		err = w.tar(size, format == FormatTarGzip)
+	default:
+		return fmt.Errorf("%w: not a ZIP or TAR archive", ErrCorrupt)
+	}
+	return w.classify(err)
+}
+
+// walker hands out the entries of one archive, enforcing its limits
+type walker struct {
+	src     *sourceReader
+	limits  Limits
+	open    bool
+	fn      func(Entry, io.Reader) error
+	entries int
+	total   int64
+	// fnErr is the error returned by fn, which is passed through unchanged
+	fnErr error
+}
+
+func (w *walker) zip(size int64) error {
+	zr, err := zip.NewReader(w.src, size)
+	if err != nil && !errors.Is(err, zip.ErrInsecurePath) {
+		return err
+	}
+	for _, f := range zr.File {
+		mode := f.Mode()
+		if f.UncompressedSize64 > math.MaxInt64 {
+			return fmt.Errorf("%s: invalid size", f.Name)
+		}
+		entry := Entry{Dir: mode.IsDir(), Size: int64(f.UncompressedSize64), Modified: f.Modified}
+		if !entry.Dir && !mode.IsRegular() {
+			if err := w.count(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.visit(f.Name, entry, func() (io.ReadCloser, error) {
+			// archive/zip fails a read that runs past the declared size or does not
+			// match the checksum, so a file cannot expand beyond what was counted
+			return f.Open()
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) tar(size int64, gzipped bool) error {
+	var r io.Reader = io.NewSectionReader(w.src, 0, size)
+	if gzipped {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry := Entry{Dir: hdr.Typeflag == tar.TypeDir, Size: hdr.Size, Modified: hdr.ModTime}
+		if !entry.Dir && hdr.Typeflag != tar.TypeReg {
+			if err := w.count(); err != nil {
+				return err
+			}
+			continue
+		}
+		if entry.Dir {
+			entry.Size = 0
+		}
+		if err := w.visit(hdr.Name, entry, func() (io.ReadCloser, error) {
+			return io.NopCloser(tr), nil
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// count counts an entry against the limit on entries
+func (w *walker) count() error {
+	w.entries++
+	if w.limits.MaxEntries > 0 && w.entries > w.limits.MaxEntries {
+		return fmt.Errorf("%w: more than %d", ErrTooManyEntries, w.limits.MaxEntries)
+	}
+	return nil
+}
+
+// visit checks an entry and hands it to fn, opening its content if the walk reads content
+func (w *walker) visit(name string, entry Entry, open func() (io.ReadCloser, error)) error {
+	if err := w.count(); err != nil {
+		return err
+	}
+	p, err := cleanPath(name)
+	if err != nil {
+		return err
+	}
+	if p == "" {
+		return nil
+	}
+	entry.Path = p
+	if !entry.Dir {
+		w.total += entry.Size
+		if w.limits.MaxSize > 0 && w.total > w.limits.MaxSize {
+			return fmt.Errorf("%w: files add up to more than %d bytes", ErrTooLarge, w.limits.MaxSize)
+		}
+	}
+
+	var content io.Reader
+	if w.open && !entry.Dir {
+		rc, err := open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		defer rc.Close()
+		content = &contentReader{r: rc, w: w}
+	}
+	if err := w.fn(entry, content); err != nil {
+		w.fnErr = err
+		return err
+	}
+	return nil
+}
+
+// classify tells the errors of fn and of reading the source, which are returned as they
+// are, from errors parsing the archive, which wrap ErrCorrupt
+func (w *walker) classify(err error) error {
+	switch {
+	case err == nil, err == w.fnErr:
+		return err
+	case w.src.err != nil:
+		return w.src.err
+	case errors.Is(err, ErrUnsafePath), errors.Is(err, ErrTooManyEntries),
+		errors.Is(err, ErrTooLarge), errors.Is(err, ErrCorrupt):
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrCorrupt, err)
+}
+
+// cleanPath turns an entry name into a relative slash-separated path. Backslashes count as
+// separators, as some Windows tools write them. Absolute names and names that climb out
+// with .. are refused rather than cleaned, since such entries are built to escape.
+func cleanPath(name string) (string, error) {
+	slashed := strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(slashed, "/") || strings.ContainsRune(slashed, 0) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(slashed, "/") {
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+		}
+		segments = append(segments, segment)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// sourceReader remembers the first failure to read the archive, so that it is reported
+// as such rather than as a corrupt archive
+type sourceReader struct {
+	r   io.ReaderAt
+	err error
+}
+
+func (s *sourceReader) ReadAt(p []byte, off int64) (int, error) {
+	n, err := s.r.ReadAt(p, off)
+	if err != nil && !errors.Is(err, io.EOF) && s.err == nil {
+		s.err = err
+	}
+	return n, err
+}
+
+// contentReader reads the content of a file entry, classifying its errors like the walk's
+type contentReader struct {
+	r io.Reader
+	w *walker
+}
+
+func (c *contentReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = c.w.classify(err)
+	}
+	return n, err
+}
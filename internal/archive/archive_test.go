@@ -0,0 +1,251 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var modified = time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+
+// testEntry describes an entry to build a test archive from; names ending in / are folders
+type testEntry struct {
+	name, content string
+	symlink       bool
+}
+
+var testEntries = []testEntry{
+	{name: "build/"},
+	{name: "build/bin/app", content: "binary"},
+	{name: "./build/README.md", content: "# Build"},
+	{name: "build/latest", symlink: true},
+	{name: "logs/run.log", content: strings.Repeat("ok\n", 1000)},
+}
+
+func buildZip(t *testing.T, entries []testEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: modified}
+		if e.symlink {
+			hdr.SetMode(0o777 | 1<<27)
+		}
+		w, err := zw.CreateHeader(hdr)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, e.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func buildTar(t *testing.T, entries []testEntry, gzipped bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if gzipped {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: tar.TypeReg, Size: int64(len(e.content)), Mode: 0o644, ModTime: modified}
+		switch {
+		case e.symlink:
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, "build", 0
+		case strings.HasSuffix(e.name, "/"):
+			hdr.Typeflag, hdr.Mode = tar.TypeDir, 0o755
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := io.WriteString(tw, e.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if gz != nil {
+		require.NoError(t, gz.Close())
+	}
+	return buf.Bytes()
+}
+
+func buildArchive(t *testing.T, format Format, entries []testEntry) []byte {
+	t.Helper()
+	if format == FormatZip {
+		return buildZip(t, entries)
+	}
+	return buildTar(t, entries, format == FormatTarGzip)
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		mimeType, name string
+		expected       Format
+	}{
+		{"application/zip", "artifacts.bin", FormatZip},
+		{"application/x-tar", "artifacts", FormatTar},
+		{"application/gzip", "release.tar.gz", FormatTarGzip},
+		{"application/gzip", "release.TGZ", FormatTarGzip},
+		{"application/gzip", "access.log.gz", FormatNone},
+		{"application/octet-stream", "bundle.zip", FormatZip},
+		{"", "bundle.tar", FormatTar},
+		{"text/plain", "notes.zip", FormatNone},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FormatOf(tt.mimeType, tt.name), "%s %s", tt.mimeType, tt.name)
+	}
+}
+
+func TestTrimExtension(t *testing.T) {
+	assert.Equal(t, "release-1.2", TrimExtension("release-1.2.tar.gz"))
+	assert.Equal(t, "Build", TrimExtension("Build.ZIP"))
+	assert.Equal(t, "notes.txt", TrimExtension("notes.txt"))
+	assert.Equal(t, ".zip", TrimExtension(".zip"))
+}
+
+func TestList(t *testing.T) {
+	for name, format := range map[string]Format{"zip": FormatZip, "tar": FormatTar, "tar.gz": FormatTarGzip} {
+		t.Run(name, func(t *testing.T) {
+			data := buildArchive(t, format, testEntries)
+
+			entries, err := List(bytes.NewReader(data), int64(len(data)), format, Limits{})
+
+			require.NoError(t, err)
+			assert.Equal(t, []Entry{
+				{Path: "build", Dir: true, Modified: modified},
+				{Path: "build/bin/app", Size: 6, Modified: modified},
+				{Path: "build/README.md", Size: 7, Modified: modified},
+				{Path: "logs/run.log", Size: 3000, Modified: modified},
+			}, normalize(entries), "links are left out")
+		})
+	}
+}
+
+// normalize drops the time zones archives store modification times in
+func normalize(entries []Entry) []Entry {
+	for i := range entries {
+		entries[i].Modified = entries[i].Modified.UTC()
+	}
+	return entries
+}
+
+func TestWalk(t *testing.T) {
+	for name, format := range map[string]Format{"zip": FormatZip, "tar": FormatTar, "tar.gz": FormatTarGzip} {
+		t.Run(name, func(t *testing.T) {
+			data := buildArchive(t, format, testEntries)
+
+			contents := map[string]string{}
+			err := Walk(bytes.NewReader(data), int64(len(data)), format, Limits{}, func(entry Entry, content io.Reader) error {
+				if entry.Dir {
+					assert.Nil(t, content)
+					return nil
+				}
+				b, err := io.ReadAll(content)
+				contents[entry.Path] = string(b)
+				return err
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{
+				"build/bin/app":   "binary",
+				"build/README.md": "# Build",
+				"logs/run.log":    strings.Repeat("ok\n", 1000),
+			}, contents)
+		})
+	}
+
+	t.Run("errors of fn are returned as is", func(t *testing.T) {
+		data := buildZip(t, testEntries)
+		stop := errors.New("stop")
+		err := Walk(bytes.NewReader(data), int64(len(data)), FormatZip, Limits{}, func(Entry, io.Reader) error { return stop })
+		assert.Equal(t, stop, err)
+	})
+}
+
+func TestWalk_UnsafePaths(t *testing.T) {
+	for _, name := range []string{"../evil.sh", "docs/../../evil.sh", "/etc/cron.d/evil", `..\evil.bat`} {
+		for format, label := range map[Format]string{FormatZip: "zip", FormatTar: "tar"} {
+			t.Run(label+" "+name, func(t *testing.T) {
+				data := buildArchive(t, format, []testEntry{{name: "safe.txt", content: "fine"}, {name: name, content: "evil"}})
+
+				err := Walk(bytes.NewReader(data), int64(len(data)), format, Limits{}, func(Entry, io.Reader) error { return nil })
+				assert.ErrorIs(t, err, ErrUnsafePath)
+
+				_, err = List(bytes.NewReader(data), int64(len(data)), format, Limits{})
+				assert.ErrorIs(t, err, ErrUnsafePath)
+			})
+		}
+	}
+}
+
+func TestWalk_Limits(t *testing.T) {
+	data := buildZip(t, testEntries)
+
+	_, err := List(bytes.NewReader(data), int64(len(data)), FormatZip, Limits{MaxEntries: 4})
+	assert.ErrorIs(t, err, ErrTooManyEntries, "left out entries count too")
+
+	_, err = List(bytes.NewReader(data), int64(len(data)), FormatZip, Limits{MaxSize: 3000})
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = List(bytes.NewReader(data), int64(len(data)), FormatZip, Limits{MaxEntries: 5, MaxSize: 3013})
+	assert.NoError(t, err)
+}
+
+func TestWalk_UnderstatedSize(t *testing.T) {
+	// A bomb declares a small size in its directory and inflates far beyond it
+	var deflated bytes.Buffer
+	fw, err := flate.NewWriter(&deflated, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = fw.Write(make([]byte, 1<<20))
+	require.NoError(t, err)
+	require.NoError(t, fw.Close())
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateRaw(&zip.FileHeader{
+		Name: "small.txt", Method: zip.Deflate, CompressedSize64: uint64(deflated.Len()), UncompressedSize64: 10,
+	})
+	require.NoError(t, err)
+	_, err = w.Write(deflated.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	data := buf.Bytes()
+
+	var read int64
+	err = Walk(bytes.NewReader(data), int64(len(data)), FormatZip, Limits{MaxSize: 100}, func(entry Entry, content io.Reader) error {
+		n, err := io.Copy(io.Discard, content)
+		read = n
+		return err
+	})
+
+	assert.ErrorIs(t, err, ErrCorrupt)
+	assert.LessOrEqual(t, read, int64(10)+32<<10, "reading stops around the declared size")
+}
+
+func TestWalk_Corrupt(t *testing.T) {
+	garbage := []byte(strings.Repeat("not an archive ", 100))
+	for _, format := range []Format{FormatZip, FormatTarGzip, FormatNone} {
+		_, err := List(bytes.NewReader(garbage), int64(len(garbage)), format, Limits{})
+		assert.ErrorIs(t, err, ErrCorrupt, "format %d", format)
+	}
+
+	data := buildZip(t, testEntries)
+	failing := errors.New("storage unavailable")
+	_, err := List(failingReader{failing}, int64(len(data)), FormatZip, Limits{})
+	assert.ErrorIs(t, err, failing, "failures to read the archive are not blamed on it")
+	assert.NotErrorIs(t, err, ErrCorrupt)
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) ReadAt([]byte, int64) (int, error) { return 0, r.err }
@@ -23,11 +23,29 @@ func AutoMigrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to create pgcrypto extension: %w", err)
 	}
 
+	// Enable pg_trgm extension for fuzzy name search
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return fmt.Errorf("failed to create pg_trgm extension: %w", err)
+	}
+
 	// AutoMigrate all models
 	if err := db.AutoMigrate(
 		&domain.User{},
 		&domain.Folder{},
 		&domain.File{},
+		&domain.FileVersion{},
+		&domain.Blob{},
+		&domain.StorageUsage{},
+		&domain.ContentIndex{},
+		&domain.FileThumbnails{},
+		&domain.FileScan{},
+		&domain.Share{},
+		&domain.ShareLink{},
+		&domain.FileRequestLink{},
+		&domain.FileRequestSubmission{},
+		&domain.Upload{},
+		&domain.UploadPart{},
+		&domain.AccessKey{},
 	); err != nil {
 		return fmt.Errorf("failed to auto-migrate models: %w", err)
 	}
@@ -90,8 +108,342 @@ func addCustomConstraints(db *gorm.DB) error {
 		return fmt.Errorf("failed to create update_updated_at_column function: %w", err)
 	}
 
+	// Keep names unique among the live items of a folder. Expression indexes
+	// cover same-kind siblings; the trigger covers a file and a folder sharing a name.
+	if err := db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_files_unique_name
+		ON files(user_id, COALESCE(folder_id, '00000000-0000-0000-0000-000000000000'), name)
+		WHERE deleted_at IS NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_unique_name
+		ON folders(user_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), name)
+		WHERE deleted_at IS NULL;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to add unique name indexes: %w", err)
+	}
+
+	// Index deleted items per user for the trash listing
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_files_trash
+		ON files(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS idx_folders_trash
+		ON folders(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to add trash indexes: %w", err)
+	}
+
+	// Index names for search by word and by trigram. Dots, dashes and underscores
+	// separate words, so that report_2026-final.pdf is found by "final".
+	if err := db.Exec(`
+		CREATE OR REPLACE FUNCTION name_search_vector(name TEXT)
+		RETURNS tsvector AS $$
+			SELECT to_tsvector('simple'::regconfig, translate(name, '._-', '   '));
+		$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+		CREATE INDEX IF NOT EXISTS idx_files_name_search
+		ON files USING GIN (name_search_vector(name)) WHERE deleted_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_folders_name_search
+		ON folders USING GIN (name_search_vector(name)) WHERE deleted_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_files_name_trgm
+		ON files USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_folders_name_trgm
+		ON folders USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to add name search indexes: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE OR REPLACE FUNCTION check_sibling_name()
+		RETURNS TRIGGER AS $$
+		DECLARE
+			parent UUID;
+		BEGIN
+			IF NEW.deleted_at IS NOT NULL THEN
+				RETURN NEW;
+			END IF;
+
+			IF TG_TABLE_NAME = 'files' THEN
+				parent := NEW.folder_id;
+			ELSE
+				parent := NEW.parent_id;
+			END IF;
+
+			PERFORM pg_advisory_xact_lock(hashtextextended(
+				NEW.user_id::text || '/' || COALESCE(parent::text, '') || '/' || NEW.name, 0));
+
+			IF TG_TABLE_NAME = 'files' THEN
+				PERFORM 1 FROM folders
+				WHERE user_id = NEW.user_id AND parent_id IS NOT DISTINCT FROM parent
+					AND name = NEW.name AND deleted_at IS NULL;
+			ELSE
+				PERFORM 1 FROM files
+				WHERE user_id = NEW.user_id AND folder_id IS NOT DISTINCT FROM parent
+					AND name = NEW.name AND deleted_at IS NULL;
+			END IF;
+			IF FOUND THEN
+				RAISE EXCEPTION 'an item named "%" already exists in this folder', NEW.name
+					USING ERRCODE = 'unique_violation', CONSTRAINT = 'sibling_name_unique';
+			END IF;
+
+			RETURN NEW;
+		END;
+		$$ language 'plpgsql';
+
+		DROP TRIGGER IF EXISTS check_files_sibling_name ON files;
+		CREATE TRIGGER check_files_sibling_name
+		BEFORE INSERT OR UPDATE OF name, folder_id, deleted_at ON files
+		FOR EACH ROW EXECUTE FUNCTION check_sibling_name();
+
+		DROP TRIGGER IF EXISTS check_folders_sibling_name ON folders;
+		CREATE TRIGGER check_folders_sibling_name
+		BEFORE INSERT OR UPDATE OF name, parent_id, deleted_at ON folders
+		FOR EACH ROW EXECUTE FUNCTION check_sibling_name();
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create sibling name trigger: %w", err)
+	}
+
+	// Count the files and versions that use each blob. Only rows stored under
+	// their blob key are counted; older content keeps a key of its own.
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_blobs_unreferenced
+		ON blobs(updated_at) WHERE ref_count = 0;
+
+		CREATE OR REPLACE FUNCTION count_blob_refs()
+		RETURNS TRIGGER AS $$
+		BEGIN
+			IF TG_OP <> 'INSERT' AND OLD.storage_key = 'blobs/' || left(OLD.checksum, 2) || '/' || OLD.checksum THEN
+				UPDATE blobs SET ref_count = ref_count - 1, updated_at = CURRENT_TIMESTAMP
+				WHERE checksum = OLD.checksum;
+			END IF;
+
+			IF TG_OP <> 'DELETE' AND NEW.storage_key = 'blobs/' || left(NEW.checksum, 2) || '/' || NEW.checksum THEN
+				INSERT INTO blobs (checksum, size, ref_count) VALUES (NEW.checksum, NEW.size, 1)
+				ON CONFLICT (checksum) DO UPDATE
+				SET ref_count = blobs.ref_count + 1, updated_at = CURRENT_TIMESTAMP;
+			END IF;
+
+			RETURN NULL;
+		END;
+		$$ language 'plpgsql';
+
+		DROP TRIGGER IF EXISTS count_files_blob_refs ON files;
+		CREATE TRIGGER count_files_blob_refs
+		AFTER INSERT OR UPDATE OF storage_key, checksum OR DELETE ON files
+		FOR EACH ROW EXECUTE FUNCTION count_blob_refs();
+
+		DROP TRIGGER IF EXISTS count_file_versions_blob_refs ON file_versions;
+		CREATE TRIGGER count_file_versions_blob_refs
+		AFTER INSERT OR UPDATE OF storage_key, checksum OR DELETE ON file_versions
+		FOR EACH ROW EXECUTE FUNCTION count_blob_refs();
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create blob reference triggers: %w", err)
+	}
+
+	// Keep each user's storage usage in step with their files and file versions
+	if err := db.Exec(`
+		CREATE OR REPLACE FUNCTION track_storage_usage()
+		RETURNS TRIGGER AS $$
+		BEGIN
+			IF TG_OP <> 'INSERT' THEN
+				UPDATE storage_usage SET used_bytes = used_bytes - OLD.size, updated_at = CURRENT_TIMESTAMP
+				WHERE user_id = OLD.user_id;
+			END IF;
+
+			IF TG_OP <> 'DELETE' THEN
+				INSERT INTO storage_usage (user_id, used_bytes) VALUES (NEW.user_id, NEW.size)
+				ON CONFLICT (user_id) DO UPDATE
+				SET used_bytes = storage_usage.used_bytes + NEW.size, updated_at = CURRENT_TIMESTAMP;
+			END IF;
+
+			RETURN NULL;
+		END;
+		$$ language 'plpgsql';
+
+		DROP TRIGGER IF EXISTS track_files_storage_usage ON files;
+		CREATE TRIGGER track_files_storage_usage
+		AFTER INSERT OR UPDATE OF size OR DELETE ON files
+		FOR EACH ROW EXECUTE FUNCTION track_storage_usage();
+
+		DROP TRIGGER IF EXISTS track_file_versions_storage_usage ON file_versions;
+		CREATE TRIGGER track_file_versions_storage_usage
+		AFTER INSERT OR DELETE ON file_versions
+		FOR EACH ROW EXECUTE FUNCTION track_storage_usage();
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create storage usage triggers: %w", err)
+	}
+
+	// Queue a file's content for indexing whenever its checksum changes, and index the
+	// extracted text for search. Pending rows are picked up in order of next_attempt_at.
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_file_content_index_pending
+		ON file_content_index(next_attempt_at NULLS FIRST) WHERE status = 'pending';
+		CREATE INDEX IF NOT EXISTS idx_file_content_index_content
+		ON file_content_index USING GIN (content);
+
+		CREATE OR REPLACE FUNCTION queue_content_index()
+		RETURNS TRIGGER AS $$
+		BEGIN
+			IF COALESCE(NEW.checksum, '') = '' THEN
+				DELETE FROM file_content_index WHERE file_id = NEW.id;
+			ELSIF TG_OP = 'INSERT' OR NEW.checksum IS DISTINCT FROM OLD.checksum THEN
+				INSERT INTO file_content_index (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+				ON CONFLICT (file_id) DO UPDATE
+				SET checksum = EXCLUDED.checksum, status = 'pending', attempts = 0, last_error = NULL,
+					next_attempt_at = NULL, indexed_at = NULL, content = NULL;
+			END IF;
+
+			RETURN NULL;
+		END;
+		$$ language 'plpgsql';
+
+		DROP TRIGGER IF EXISTS queue_files_content_index ON files;
+		CREATE TRIGGER queue_files_content_index
+		AFTER INSERT OR UPDATE OF checksum ON files
+		FOR EACH ROW EXECUTE FUNCTION queue_content_index();
+
+		INSERT INTO file_content_index (file_id, checksum)
+		SELECT id, checksum FROM files WHERE checksum <> ''
+		ON CONFLICT (file_id) DO NOTHING;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create content index trigger: %w", err)
+	}
+
+	// Queue thumbnails of an image whenever its checksum changes. Thumbnails are only
+	// rendered for images; other files have no row.
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_file_thumbnails_pending
+		ON file_thumbnails(next_attempt_at NULLS FIRST) WHERE status = 'pending';
+
+		CREATE OR REPLACE FUNCTION queue_thumbnails()
+		RETURNS TRIGGER AS $$
+		BEGIN
+			IF COALESCE(NEW.checksum, '') = '' OR COALESCE(NEW.mime_type, '') NOT LIKE 'image/%' THEN
+				DELETE FROM file_thumbnails WHERE file_id = NEW.id;
+			ELSIF TG_OP = 'INSERT' OR NEW.checksum IS DISTINCT FROM OLD.checksum THEN
+				INSERT INTO file_thumbnails (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+				ON CONFLICT (file_id) DO UPDATE
+				SET checksum = EXCLUDED.checksum, status = 'pending', attempts = 0, last_error = NULL,
+					next_attempt_at = NULL, generated_at = NULL;
+			END IF;
+
+			RETURN NULL;
+		END;
+		$$ language 'plpgsql';
+
+		DROP TRIGGER IF EXISTS queue_files_thumbnails ON files;
+		CREATE TRIGGER queue_files_thumbnails
+		AFTER INSERT OR UPDATE OF checksum ON files
+		FOR EACH ROW EXECUTE FUNCTION queue_thumbnails();
+
+		INSERT INTO file_thumbnails (file_id, checksum)
+		SELECT id, checksum FROM files WHERE checksum <> '' AND mime_type LIKE 'image/%'
+		ON CONFLICT (file_id) DO NOTHING;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create thumbnail trigger: %w", err)
+	}
+
+	// Queue a malware scan whenever content is committed pending one. Content committed
+	// while scanning is turned off is unscanned right away and drops any earlier scan.
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_file_scans_pending
+		ON file_scans(next_attempt_at NULLS FIRST) WHERE status = 'pending';
+		CREATE INDEX IF NOT EXISTS idx_files_quarantined
+		ON files(updated_at DESC) WHERE quarantined;
+
+		CREATE OR REPLACE FUNCTION queue_file_scan()
+		RETURNS TRIGGER AS $$
+		BEGIN
+			IF COALESCE(NEW.checksum, '') = '' THEN
+				DELETE FROM file_scans WHERE file_id = NEW.id;
+			ELSIF NEW.scan_status = 'pending' THEN
+				INSERT INTO file_scans (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+				ON CONFLICT (file_id) DO UPDATE
+				SET checksum = EXCLUDED.checksum, status = 'pending', attempts = 0, last_error = NULL,
+					next_attempt_at = NULL, scanned_at = NULL;
+			ELSE
+				DELETE FROM file_scans WHERE file_id = NEW.id AND checksum <> NEW.checksum;
+			END IF;
+
+			RETURN NULL;
+		END;
+		$$ language 'plpgsql';
+
+		DROP TRIGGER IF EXISTS queue_files_scan ON files;
+		CREATE TRIGGER queue_files_scan
+		AFTER INSERT OR UPDATE OF checksum, scan_status ON files
+		FOR EACH ROW EXECUTE FUNCTION queue_file_scan();
+
+		INSERT INTO file_scans (file_id, checksum)
+		SELECT id, checksum FROM files WHERE checksum <> '' AND scan_status = 'pending'
+		ON CONFLICT (file_id) DO NOTHING;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create scan trigger: %w", err)
+	}
+
+	// Let users share files and folders. The access role functions resolve a user's role
+	// on an item, including roles inherited from shared folders above it.
+	if err := db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_file_shares_unique_file
+		ON file_shares(file_id, grantee_id) WHERE file_id IS NOT NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_file_shares_unique_folder
+		ON file_shares(folder_id, grantee_id) WHERE folder_id IS NOT NULL;
+
+		CREATE OR REPLACE FUNCTION share_role_rank(role VARCHAR)
+		RETURNS INTEGER AS $$
+			SELECT CASE role
+				WHEN 'viewer' THEN 1
+				WHEN 'commenter' THEN 2
+				WHEN 'editor' THEN 3
+				WHEN 'owner' THEN 4
+				ELSE 0
+			END;
+		$$ LANGUAGE sql IMMUTABLE;
+
+		CREATE OR REPLACE FUNCTION folder_access_role(p_user_id UUID, p_folder_id UUID)
+		RETURNS VARCHAR AS $$
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id, user_id FROM folders WHERE id = p_folder_id
+				UNION ALL
+				SELECT f.id, f.parent_id, f.user_id FROM folders f JOIN ancestors a ON f.id = a.parent_id
+			)
+			SELECT CASE
+				WHEN bool_or(a.user_id = p_user_id) THEN 'owner'
+				ELSE (array_agg(s.role ORDER BY share_role_rank(s.role) DESC) FILTER (WHERE s.role IS NOT NULL))[1]
+			END
+			FROM ancestors a
+			LEFT JOIN file_shares s ON s.folder_id = a.id AND s.grantee_id = p_user_id;
+		$$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public;
+
+		CREATE OR REPLACE FUNCTION file_access_role(p_user_id UUID, p_file_id UUID)
+		RETURNS VARCHAR AS $$
+			SELECT CASE
+				WHEN f.user_id = p_user_id THEN 'owner'
+				ELSE (
+					SELECT role FROM (
+						SELECT s.role FROM file_shares s WHERE s.file_id = f.id AND s.grantee_id = p_user_id
+						UNION ALL
+						SELECT folder_access_role(p_user_id, f.folder_id)
+					) roles
+					WHERE role IS NOT NULL
+					ORDER BY share_role_rank(role) DESC
+					LIMIT 1
+				)
+			END
+			FROM files f
+			WHERE f.id = p_file_id;
+		$$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create share access functions: %w", err)
+	}
+
+	// Files uploaded before type detection only have the type their client declared
+	if err := db.Exec(`
+		UPDATE files SET declared_mime_type = mime_type WHERE declared_mime_type IS NULL;
+	`).Error; err != nil {
+		return fmt.Errorf("failed to backfill declared MIME types: %w", err)
+	}
+
 	// Create triggers for auto-updating updated_at
-	tables := []string{"users", "files", "folders"}
+	tables := []string{"users", "files", "folders", "file_content_index", "file_thumbnails", "file_scans", "file_shares", "share_links", "file_request_links", "uploads"}
 	for _, table := range tables {
 		triggerName := fmt.Sprintf("update_%s_updated_at", table)
 		if err := db.Exec(fmt.Sprintf(`
@@ -156,7 +508,7 @@ func grantPermissions(db *gorm.DB) error {
 
 	// Grant permissions to file_service
 	if err := db.Exec(`
-		GRANT SELECT, INSERT, UPDATE, DELETE ON files, folders TO file_service;
+		GRANT SELECT, INSERT, UPDATE, DELETE ON files, folders, file_versions, file_content_index, file_thumbnails, file_scans, file_shares, share_links, file_request_links, file_request_submissions, blobs, storage_usage, uploads, upload_parts, access_keys TO file_service;
 		GRANT SELECT ON users TO file_service;
 		GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO file_service;
 	`).Error; err != nil {
@@ -178,6 +530,19 @@ func DropAllTables(db *gorm.DB) error {
 	log.Println("WARNING: Dropping all tables...")
 
 	if err := db.Migrator().DropTable(
+		&domain.AccessKey{},
+		&domain.UploadPart{},
+		&domain.Upload{},
+		&domain.FileRequestSubmission{},
+		&domain.FileRequestLink{},
+		&domain.ShareLink{},
+		&domain.Share{},
+		&domain.FileScan{},
+		&domain.FileThumbnails{},
+		&domain.ContentIndex{},
+		&domain.StorageUsage{},
+		&domain.Blob{},
+		&domain.FileVersion{},
 		&domain.File{},
 		&domain.Folder{},
 		&domain.User{},
@@ -9,26 +9,184 @@ import (
 
 // File represents a file in the system
 type File struct {
-	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	Name       string         `json:"name" gorm:"type:varchar(255);not null"`
-	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
-	User       *User          `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
-	FolderID   *uuid.UUID     `json:"folder_id,omitempty" gorm:"type:uuid;index"`
-	Folder     *Folder        `json:"folder,omitempty" gorm:"foreignKey:FolderID"`
-	Size       int64          `json:"size" gorm:"not null;check:size >= 0"`
-	MimeType   string         `json:"mime_type" gorm:"type:varchar(100)"`
-	StorageKey string         `json:"storage_key" gorm:"type:varchar(500);not null"`
-	Checksum   string         `json:"checksum,omitempty" gorm:"type:varchar(64)"`
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name       string     `json:"name" gorm:"type:varchar(255);not null"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	User       *User      `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	FolderID   *uuid.UUID `json:"folder_id,omitempty" gorm:"type:uuid;index"`
+	Folder     *Folder    `json:"folder,omitempty" gorm:"foreignKey:FolderID"`
+	Size       int64      `json:"size" gorm:"not null;check:size >= 0"`
+	MimeType   string     `json:"mime_type" gorm:"type:varchar(100)"`
+	StorageKey string     `json:"storage_key" gorm:"type:varchar(500);not null"`
+	Checksum   string     `json:"checksum,omitempty" gorm:"type:varchar(64)"`
+	// DeclaredMimeType is the type the client gave for the file. MimeType is detected from
+	// the content once it is uploaded; MimeMismatch flags content of another type.
+	DeclaredMimeType string `json:"declared_mime_type,omitempty" gorm:"type:varchar(100)"`
+	MimeMismatch     bool   `json:"mime_mismatch" gorm:"not null;default:false"`
+	// Quarantined content is kept but cannot be downloaded
+	Quarantined      bool   `json:"quarantined" gorm:"not null;default:false"`
+	QuarantineReason string `json:"quarantine_reason,omitempty" gorm:"type:text"`
+	// ScanStatus is the outcome of the malware scan of the current content. Only clean
+	// content can be downloaded or shared.
+	ScanStatus string         `json:"scan_status" gorm:"type:varchar(20);not null;default:'pending'"`
 	CreatedAt  time.Time      `json:"created_at" gorm:"index:idx_files_created_at,sort:desc"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
+// Scan statuses of file content
+const (
+	ScanPending  = "pending"
+	ScanClean    = "clean"
+	ScanInfected = "infected"
+	ScanError    = "error"
+	// ScanSkipped marks content committed while scanning was explicitly turned off. It can
+	// be downloaded, but it was never checked for malware.
+	ScanSkipped = "unscanned"
+)
+
 // TableName specifies the table name for the File model
 func (File) TableName() string {
 	return "files"
 }
 
+// FileVersion is an earlier content of a file, kept when the file's content is replaced
+type FileVersion struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	FileID     uuid.UUID `json:"file_id" gorm:"type:uuid;not null;index:idx_file_versions_file_id"`
+	File       *File     `json:"file,omitempty" gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	User       *User     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Size       int64     `json:"size" gorm:"not null;check:file_versions_size_check,size >= 0"`
+	StorageKey string    `json:"storage_key" gorm:"type:varchar(500);not null"`
+	Checksum   string    `json:"checksum,omitempty" gorm:"type:varchar(64)"`
+	// ScanStatus is the outcome of the malware scan of the content. Only clean versions
+	// can be downloaded.
+	ScanStatus string `json:"scan_status" gorm:"type:varchar(20);not null;default:'pending'"`
+	// CreatedAt is when this content was replaced by a newer one
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_file_versions_file_id,sort:desc"`
+}
+
+// TableName specifies the table name for the FileVersion model
+func (FileVersion) TableName() string {
+	return "file_versions"
+}
+
+// Blob is content stored once under its SHA-256 checksum and shared by every file and
+// file version with that content. RefCount is maintained by database triggers.
+type Blob struct {
+	Checksum  string    `json:"checksum" gorm:"type:varchar(64);primaryKey"`
+	Size      int64     `json:"size" gorm:"not null;check:blobs_size_check,size >= 0"`
+	RefCount  int32     `json:"ref_count" gorm:"not null;default:0;check:blobs_ref_count_check,ref_count >= 0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for the Blob model
+func (Blob) TableName() string {
+	return "blobs"
+}
+
+// StorageUsage is the number of bytes a user's files and file versions take up, including
+// those in the trash. UsedBytes is maintained by database triggers.
+type StorageUsage struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	UsedBytes int64     `json:"used_bytes" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for the StorageUsage model
+func (StorageUsage) TableName() string {
+	return "storage_usage"
+}
+
+// Content index statuses
+const (
+	ContentIndexPending     = "pending"
+	ContentIndexIndexed     = "indexed"
+	ContentIndexFailed      = "failed"
+	ContentIndexUnsupported = "unsupported"
+)
+
+// ContentIndex holds the searchable text of a file's current content. A row is queued
+// whenever a file's checksum changes; the file service fills in Content in the background.
+type ContentIndex struct {
+	FileID   uuid.UUID `json:"file_id" gorm:"type:uuid;primaryKey"`
+	File     *File     `json:"file,omitempty" gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+	Checksum string    `json:"checksum" gorm:"type:varchar(64);not null"`
+	Status   string    `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts int32     `json:"attempts" gorm:"not null;default:0"`
+	// LastError says why the last attempt failed or why the content cannot be indexed
+	LastError string `json:"last_error,omitempty" gorm:"type:text"`
+	// NextAttemptAt is when a pending file may be picked up again; NULL is right away
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	IndexedAt     *time.Time `json:"indexed_at,omitempty"`
+	Content       *string    `json:"-" gorm:"type:tsvector"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the ContentIndex model
+func (ContentIndex) TableName() string {
+	return "file_content_index"
+}
+
+// Thumbnail statuses
+const (
+	ThumbnailPending     = "pending"
+	ThumbnailReady       = "ready"
+	ThumbnailFailed      = "failed"
+	ThumbnailUnsupported = "unsupported"
+)
+
+// FileThumbnails tracks the thumbnails of an image file's current content. A row is queued
+// whenever an image's checksum changes; the file service renders the thumbnails in the
+// background and stores them as blobs next to the content.
+type FileThumbnails struct {
+	FileID   uuid.UUID `json:"file_id" gorm:"type:uuid;primaryKey"`
+	File     *File     `json:"file,omitempty" gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+	Checksum string    `json:"checksum" gorm:"type:varchar(64);not null"`
+	Status   string    `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts int32     `json:"attempts" gorm:"not null;default:0"`
+	// LastError says why the last attempt failed or why no thumbnails can be rendered
+	LastError string `json:"last_error,omitempty" gorm:"type:text"`
+	// NextAttemptAt is when a pending file may be picked up again; NULL is right away
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	GeneratedAt   *time.Time `json:"generated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the FileThumbnails model
+func (FileThumbnails) TableName() string {
+	return "file_thumbnails"
+}
+
+// FileScan tracks the malware scan of content a file holds, current or kept as a version. A
+// row is queued whenever new content is committed with a pending scan status and kept while
+// the file holds the content; the file service scans it in the background and copies the
+// outcome to the ScanStatus of the file and its versions with the content.
+type FileScan struct {
+	FileID   uuid.UUID `json:"file_id" gorm:"type:uuid;primaryKey"`
+	File     *File     `json:"file,omitempty" gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+	Checksum string    `json:"checksum" gorm:"type:varchar(64);primaryKey"`
+	Status   string    `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts int32     `json:"attempts" gorm:"not null;default:0"`
+	// LastError says why the last attempt failed, or names the malware found
+	LastError string `json:"last_error,omitempty" gorm:"type:text"`
+	// NextAttemptAt is when a pending file may be picked up again; NULL is right away
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	ScannedAt     *time.Time `json:"scanned_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the FileScan model
+func (FileScan) TableName() string {
+	return "file_scans"
+}
+
 // Folder represents a folder in the system
 type Folder struct {
 	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
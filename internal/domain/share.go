@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Share grants a user access to another user's file or folder. Exactly one of FileID and
+// FolderID is set; a folder share covers everything below the folder.
+type Share struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	OwnerID   uuid.UUID  `json:"owner_id" gorm:"type:uuid;not null;index"`
+	Owner     *User      `json:"owner,omitempty" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+	GranteeID uuid.UUID  `json:"grantee_id" gorm:"type:uuid;not null;index:idx_file_shares_grantee_id;check:file_shares_not_owner_check,grantee_id <> owner_id"`
+	Grantee   *User      `json:"grantee,omitempty" gorm:"foreignKey:GranteeID;constraint:OnDelete:CASCADE"`
+	FileID    *uuid.UUID `json:"file_id,omitempty" gorm:"type:uuid"`
+	File      *File      `json:"file,omitempty" gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+	FolderID  *uuid.UUID `json:"folder_id,omitempty" gorm:"type:uuid;check:file_shares_target_check,(file_id IS NULL) <> (folder_id IS NULL)"`
+	Folder    *Folder    `json:"folder,omitempty" gorm:"foreignKey:FolderID;constraint:OnDelete:CASCADE"`
+	Role      string     `json:"role" gorm:"type:varchar(20);not null;check:file_shares_role_check,role IN ('viewer', 'commenter', 'editor')"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index:idx_file_shares_grantee_id,sort:desc"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the Share model
+func (Share) TableName() string {
+	return "file_shares"
+}
+
+// ShareRole constants, from least to most access. Owners are not granted a share;
+// ShareRoleOwner is the access they have to their own items.
+const (
+	ShareRoleViewer    = "viewer"
+	ShareRoleCommenter = "commenter"
+	ShareRoleEditor    = "editor"
+	ShareRoleOwner     = "owner"
+)
+
+// IsValidShareRole checks if a role can be granted by a share
+func IsValidShareRole(role string) bool {
+	switch role {
+	case ShareRoleViewer, ShareRoleCommenter, ShareRoleEditor:
+		return true
+	default:
+		return false
+	}
+}
+
+// ShareRoleAllows reports whether role includes the access of required
+func ShareRoleAllows(role, required string) bool {
+	return shareRoleRank(role) >= shareRoleRank(required) && shareRoleRank(required) > 0
+}
+
+func shareRoleRank(role string) int {
+	switch role {
+	case ShareRoleViewer:
+		return 1
+	case ShareRoleCommenter:
+		return 2
+	case ShareRoleEditor:
+		return 3
+	case ShareRoleOwner:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// ShareLink makes a file or folder available to anyone holding its token, without an account.
+// Only a hash of the token is stored; the token itself is shown once, when the link is created.
+type ShareLink struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	OwnerID       uuid.UUID  `json:"owner_id" gorm:"type:uuid;not null;index:idx_share_links_owner_id"`
+	Owner         *User      `json:"owner,omitempty" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+	FileID        *uuid.UUID `json:"file_id,omitempty" gorm:"type:uuid"`
+	File          *File      `json:"file,omitempty" gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+	FolderID      *uuid.UUID `json:"folder_id,omitempty" gorm:"type:uuid;check:share_links_target_check,(file_id IS NULL) <> (folder_id IS NULL)"`
+	Folder        *Folder    `json:"folder,omitempty" gorm:"foreignKey:FolderID;constraint:OnDelete:CASCADE"`
+	TokenHash     string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	PasswordHash  string     `json:"-" gorm:"type:varchar(60)"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	MaxDownloads  int32      `json:"max_downloads" gorm:"not null;default:0;check:share_links_max_downloads_check,max_downloads >= 0"`
+	DownloadCount int32      `json:"download_count" gorm:"not null;default:0"`
+	PreviewOnly   bool       `json:"preview_only" gorm:"not null;default:false"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"index:idx_share_links_owner_id,sort:desc"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the ShareLink model
+func (ShareLink) TableName() string {
+	return "share_links"
+}
+
+// FileRequestLink lets anyone holding its token upload files into a folder without seeing
+// what is in it. AllowedMimeTypes is a comma-separated list, empty to allow any type.
+type FileRequestLink struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	OwnerID          uuid.UUID  `json:"owner_id" gorm:"type:uuid;not null;index:idx_file_request_links_owner_id"`
+	Owner            *User      `json:"owner,omitempty" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+	FolderID         uuid.UUID  `json:"folder_id" gorm:"type:uuid;not null"`
+	Folder           *Folder    `json:"folder,omitempty" gorm:"foreignKey:FolderID;constraint:OnDelete:CASCADE"`
+	TokenHash        string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Title            string     `json:"title" gorm:"type:varchar(255)"`
+	Deadline         *time.Time `json:"deadline,omitempty"`
+	MaxFileSize      int64      `json:"max_file_size" gorm:"not null;default:0;check:file_request_links_max_file_size_check,max_file_size >= 0"`
+	AllowedMimeTypes string     `json:"allowed_mime_types" gorm:"type:text;not null;default:''"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"index:idx_file_request_links_owner_id,sort:desc"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the FileRequestLink model
+func (FileRequestLink) TableName() string {
+	return "file_request_links"
+}
+
+// FileRequestSubmission records who uploaded a file through a file request link
+type FileRequestSubmission struct {
+	ID            uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	RequestID     uuid.UUID        `json:"request_id" gorm:"type:uuid;not null;index:idx_file_request_submissions_request_id"`
+	Request       *FileRequestLink `json:"request,omitempty" gorm:"foreignKey:RequestID;constraint:OnDelete:CASCADE"`
+	FileID        uuid.UUID        `json:"file_id" gorm:"type:uuid;not null;uniqueIndex"`
+	File          *File            `json:"file,omitempty" gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+	UploaderName  string           `json:"uploader_name" gorm:"type:varchar(255);not null"`
+	UploaderEmail string           `json:"uploader_email" gorm:"type:varchar(255);not null"`
+	CreatedAt     time.Time        `json:"created_at" gorm:"index:idx_file_request_submissions_request_id,sort:desc"`
+}
+
+// TableName specifies the table name for the FileRequestSubmission model
+func (FileRequestSubmission) TableName() string {
+	return "file_request_submissions"
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxUploadSize caps the content of any single upload, whatever a user's quota allows
+const MaxUploadSize = 5 << 30
+
+// Upload tracks a resumable upload that has not been assembled into a File yet
+type Upload struct {
+	ID        uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID    `json:"user_id" gorm:"type:uuid;not null;index"`
+	User      *User        `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Name      string       `json:"name" gorm:"type:varchar(255);not null"`
+	FolderID  *uuid.UUID   `json:"folder_id,omitempty" gorm:"type:uuid"`
+	MimeType  string       `json:"mime_type" gorm:"type:varchar(100)"`
+	Size      int64        `json:"size" gorm:"not null;check:uploads_size_check,size >= 0"`
+	Offset    int64        `json:"offset" gorm:"column:upload_offset;not null;default:0"`
+	Parts     []UploadPart `json:"parts,omitempty" gorm:"foreignKey:UploadID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" gorm:"index"`
+}
+
+// TableName specifies the table name for the Upload model
+func (Upload) TableName() string {
+	return "uploads"
+}
+
+// UploadPart is a chunk of a resumable upload stored as its own blob
+type UploadPart struct {
+	UploadID   uuid.UUID `json:"upload_id" gorm:"type:uuid;primaryKey"`
+	Offset     int64     `json:"offset" gorm:"column:part_offset;primaryKey"`
+	Size       int64     `json:"size" gorm:"not null"`
+	StorageKey string    `json:"storage_key" gorm:"type:varchar(500);not null"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the UploadPart model
+func (UploadPart) TableName() string {
+	return "upload_parts"
+}
@@ -37,6 +37,28 @@ const (
 	UserTypeAdmin    = "admin"
 )
 
+// AccessKey lets tools sign requests to the S3-compatible API on behalf of its user. SigV4
+// signatures can only be checked with the secret itself, so unlike link tokens the secret is
+// stored rather than a hash of it, wrapped with a master key when encryption at rest is on; it
+// is still only shown once, when the key is created.
+type AccessKey struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_access_keys_user_id"`
+	User        *User     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	AccessKeyID string    `json:"access_key_id" gorm:"type:varchar(32);not null;uniqueIndex"`
+	SecretKey   string    `json:"-" gorm:"type:text;not null"`
+	// SecretMasterKeyID names the master key SecretKey is wrapped with; empty when the
+	// secret is stored as it is
+	SecretMasterKeyID string    `json:"-" gorm:"type:varchar(64);not null;default:''"`
+	Name              string    `json:"name" gorm:"type:varchar(255)"`
+	CreatedAt         time.Time `json:"created_at" gorm:"index:idx_access_keys_user_id,sort:desc"`
+}
+
+// TableName specifies the table name for the AccessKey model
+func (AccessKey) TableName() string {
+	return "access_keys"
+}
+
 // IsValidUserType checks if a user type is valid
 func IsValidUserType(userType string) bool {
 	switch userType {
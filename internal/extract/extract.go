@@ -0,0 +1,160 @@
+// Package extract pulls the plain text out of uploaded documents so that their content
+// can be indexed for search. Plain text, Markdown, CSV and PDF are supported, all with
+// pure-Go parsers.
+package extract
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// MaxPDFSize is the largest PDF Text accepts. PDFs are parsed in memory.
+const MaxPDFSize = 64 << 20
+
+var (
+	// ErrUnsupported is returned for content no text can be extracted from
+	ErrUnsupported = errors.New("content type cannot be indexed")
+	// ErrTooLarge is returned for documents too large to parse
+	ErrTooLarge = errors.New("document is too large to index")
+)
+
+type format int
+
+const (
+	formatNone format = iota
+	formatText
+	formatCSV
+	formatPDF
+)
+
+var mimeFormats = map[string]format{
+	"text/plain":      formatText,
+	"text/markdown":   formatText,
+	"text/x-markdown": formatText,
+	"text/csv":        formatCSV,
+	"application/csv": formatCSV,
+	"application/pdf": formatPDF,
+}
+
+var extensionFormats = map[string]format{
+	".txt":      formatText,
+	".text":     formatText,
+	".md":       formatText,
+	".markdown": formatText,
+	".csv":      formatCSV,
+	".pdf":      formatPDF,
+}
+
+// formatOf picks the parser for a MIME type. The file name's extension decides when
+// the type is missing or generic, as it often is for Markdown.
+func formatOf(mimeType, name string) format {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = ""
+	}
+	if f, ok := mimeFormats[mediaType]; ok {
+		return f
+	}
+	if mediaType == "" || mediaType == "application/octet-stream" {
+		return extensionFormats[strings.ToLower(path.Ext(name))]
+	}
+	return formatNone
+}
+
+// Supported reports whether Text can extract text from content of the MIME type,
+// stored under the file name
+func Supported(mimeType, name string) bool {
+	return formatOf(mimeType, name) != formatNone
+}
+
+// Text extracts at most limit bytes of text from the content read from r. The text is
+// valid UTF-8 without NUL characters.
+func Text(r io.Reader, mimeType, name string, limit int) (string, error) {
+	var text string
+	var err error
+	switch formatOf(mimeType, name) {
+	case formatText:
+		var b []byte
+		b, err = io.ReadAll(io.LimitReader(r, int64(limit)))
+		text = string(b)
+	case formatCSV:
+		text, err = csvText(r, limit)
+	case formatPDF:
+		text, err = pdfText(r, limit)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if len(text) > limit {
+		text = text[:limit]
+	}
+	text = strings.ToValidUTF8(text, "")
+	return strings.ReplaceAll(text, "\x00", " "), nil
+}
+
+// csvText joins the fields of each record with spaces and the records with newlines
+func csvText(r io.Reader, limit int) (string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	var b strings.Builder
+	for b.Len() < limit {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read CSV: %w", err)
+		}
+		b.WriteString(strings.Join(record, " "))
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// pdfText extracts the text of a PDF page by page. The parser panics on some malformed
+// documents; those are reported as errors.
+func pdfText(r io.Reader, limit int) (text string, err error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxPDFSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > MaxPDFSize {
+		return "", ErrTooLarge
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			text, err = "", fmt.Errorf("malformed PDF: %v", p)
+		}
+	}()
+
+	doc, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	var b strings.Builder
+	for i := 1; i <= doc.NumPage() && b.Len() < limit; i++ {
+		page, err := doc.Page(i).GetPlainText(nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to read PDF page %d: %w", i, err)
+		}
+		b.WriteString(page)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
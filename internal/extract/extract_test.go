@@ -0,0 +1,100 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onePagePDF builds a PDF with a single page showing text in Helvetica
+func onePagePDF(text string) string {
+	content := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var b strings.Builder
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.String()
+}
+
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		name     string
+		expected bool
+	}{
+		{"text/plain; charset=utf-8", "notes", true},
+		{"text/markdown", "README", true},
+		{"application/octet-stream", "README.MD", true},
+		{"", "data.csv", true},
+		{"application/pdf", "scan", true},
+		{"image/png", "photo.txt", false},
+		{"application/zip", "archive.zip", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Supported(tt.mimeType, tt.name))
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		mimeType string
+		fileName string
+		limit    int
+		expected string
+	}{
+		{name: "plain text", content: "hello world", mimeType: "text/plain", limit: 100, expected: "hello world"},
+		{name: "markdown by extension", content: "# Title\n\n*emphasis*", fileName: "notes.md", limit: 100, expected: "# Title\n\n*emphasis*"},
+		{name: "csv", content: "name,city\n\"Doe, Jane\",Toronto\nsolo", mimeType: "text/csv", limit: 100, expected: "name city\nDoe, Jane Toronto\nsolo\n"},
+		{name: "cut at the limit inside a rune", content: "ab€", mimeType: "text/plain", limit: 3, expected: "ab"},
+		{name: "invalid UTF-8 and NUL dropped", content: "a\xffb\x00c", mimeType: "text/plain", limit: 100, expected: "ab c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := Text(strings.NewReader(tt.content), tt.mimeType, tt.fileName, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, text)
+		})
+	}
+
+	t.Run("pdf", func(t *testing.T) {
+		text, err := Text(strings.NewReader(onePagePDF("Quarterly revenue report")), "application/pdf", "report.pdf", 100)
+		require.NoError(t, err)
+		assert.Contains(t, text, "Quarterly revenue report")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := Text(strings.NewReader("PK"), "application/zip", "a.zip", 100)
+		assert.ErrorIs(t, err, ErrUnsupported)
+	})
+
+	t.Run("malformed pdf", func(t *testing.T) {
+		_, err := Text(strings.NewReader("%PDF-1.4\nnot really"), "application/pdf", "broken.pdf", 100)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,105 @@
+// Package filetype detects the type of file content from its leading bytes, so that the
+// MIME type a client declares for an upload need not be trusted.
+package filetype
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// HeaderSize is how many leading bytes of content detection looks at
+const HeaderSize = 3072
+
+const (
+	// octetStream is detected for binary content of no known type
+	octetStream = "application/octet-stream"
+	// plainText is detected for text of no more specific known type
+	plainText = "text/plain"
+)
+
+// Detect returns the media type of content starting with header, without parameters
+func Detect(header []byte) string {
+	return mediaType(mimetype.Detect(header).String())
+}
+
+// Resolve decides the type of content starting with header, given the type the client
+// declared for it. The detected type wins, except where detection can only tell that the
+// content is text, or binary of no known type, and the declared type is compatible with
+// that; the declared type is then the more specific one. mismatch reports content that is
+// not of the declared type. Empty content keeps the declared type.
+func Resolve(declared string, header []byte) (mimeType string, mismatch bool) {
+	declared = mediaType(declared)
+	if len(header) == 0 {
+		return declared, false
+	}
+
+	detected := mimetype.Detect(header)
+	detectedType := mediaType(detected.String())
+	if declared == "" || declared == octetStream {
+		return detectedType, false
+	}
+
+	// The declared type, or a broader type the detected one belongs to, such as
+	// application/zip for an OpenDocument file
+	for m := detected; m != nil; m = m.Parent() {
+		if m.Is(declared) && m.String() != octetStream {
+			return detectedType, false
+		}
+	}
+
+	switch detectedType {
+	case plainText:
+		if isText(declared) {
+			return declared, false
+		}
+	case octetStream:
+		// Only types the detector knows by their content can be told apart from others
+		if mimetype.Lookup(declared) == nil {
+			return declared, false
+		}
+	}
+	return detectedType, true
+}
+
+// Is reports whether mimeType is pattern, an alias of it or a more specific type of it.
+// pattern is a MIME type or a family such as image/*.
+func Is(mimeType, pattern string) bool {
+	mimeType, pattern = mediaType(mimeType), strings.ToLower(pattern)
+	if family, ok := strings.CutSuffix(pattern, "/*"); ok {
+		major, _, _ := strings.Cut(mimeType, "/")
+		return major == family
+	}
+	if mimeType == pattern {
+		return true
+	}
+
+	m := mimetype.Lookup(mimeType)
+	for ; m != nil && m.String() != octetStream; m = m.Parent() {
+		if m.Is(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// isText reports whether a declared type is a kind of text
+func isText(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") || strings.HasSuffix(mimeType, "+json") || strings.HasSuffix(mimeType, "+xml") {
+		return true
+	}
+	return Is(mimeType, plainText)
+}
+
+// mediaType lowercases a MIME type and drops its parameters. Malformed types yield "".
+func mediaType(mimeType string) string {
+	if mimeType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
@@ -0,0 +1,60 @@
+package filetype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	exeHeader = []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff")
+	pdfHeader = []byte("%PDF-1.7\n")
+)
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, "image/png", Detect(pngHeader))
+	assert.Equal(t, "text/plain", Detect([]byte("hello world")))
+	assert.Equal(t, "application/octet-stream", Detect([]byte{0, 1, 2, 3}))
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name             string
+		declared         string
+		header           []byte
+		expectedType     string
+		expectedMismatch bool
+	}{
+		{name: "declared type confirmed", declared: "image/png", header: pngHeader, expectedType: "image/png"},
+		{name: "parameters and case ignored", declared: "Application/PDF; name=x", header: pdfHeader, expectedType: "application/pdf"},
+		{name: "nothing declared", declared: "", header: pdfHeader, expectedType: "application/pdf"},
+		{name: "generic type declared", declared: "application/octet-stream", header: pngHeader, expectedType: "image/png"},
+		{name: "alias declared", declared: "application/x-pdf", header: pdfHeader, expectedType: "application/pdf"},
+		{name: "executable posing as an image", declared: "image/png", header: exeHeader, expectedType: "application/vnd.microsoft.portable-executable", expectedMismatch: true},
+		{name: "text kept as the declared text type", declared: "text/markdown", header: []byte("# Notes\n"), expectedType: "text/markdown"},
+		{name: "text declared as an image", declared: "image/jpeg", header: []byte("just text"), expectedType: "text/plain", expectedMismatch: true},
+		{name: "type the detector cannot recognize", declared: "application/x-custom", header: []byte{0, 1, 2, 3}, expectedType: "application/x-custom"},
+		{name: "unrecognized content declared as a known type", declared: "application/pdf", header: []byte{0, 1, 2, 3}, expectedType: "application/octet-stream", expectedMismatch: true},
+		{name: "empty content", declared: "image/png", header: nil, expectedType: "image/png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimeType, mismatch := Resolve(tt.declared, tt.header)
+			assert.Equal(t, tt.expectedType, mimeType)
+			assert.Equal(t, tt.expectedMismatch, mismatch)
+		})
+	}
+}
+
+func TestIs(t *testing.T) {
+	assert.True(t, Is("image/png", "image/png"))
+	assert.True(t, Is("image/png", "image/*"))
+	assert.True(t, Is("application/pdf", "application/x-pdf"))
+	assert.True(t, Is("text/x-shellscript", "text/plain"))
+	assert.True(t, Is("application/x-custom; v=1", "application/x-custom"))
+	assert.False(t, Is("image/png", "video/*"))
+	assert.False(t, Is("image/png", "application/octet-stream"))
+	assert.False(t, Is("text/plain", "text/x-shellscript"))
+}
@@ -0,0 +1,171 @@
+// Package keyring holds the master keys that wrap per-object data keys for encryption at
+// rest. A keyring lists one or more AES-256 keys by ID; the first is active and wraps new
+// data keys, the others only unwrap keys wrapped before a rotation.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize is the size of master and data keys in bytes (AES-256)
+const KeySize = 32
+
+var (
+	// ErrUnknownKey is returned when a data key was wrapped with a master key that is not
+	// in the keyring
+	ErrUnknownKey = errors.New("unknown master key")
+	// ErrUnwrap is returned when a wrapped data key has been altered or does not belong to
+	// the object it is unwrapped for
+	ErrUnwrap = errors.New("failed to unwrap data key")
+)
+
+// WrappedKey is a data key encrypted with a master key
+type WrappedKey struct {
+	// MasterKeyID names the master key that wrapped the data key
+	MasterKeyID string `json:"master_key_id"`
+	// Ciphertext is the GCM nonce followed by the sealed data key
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Keyring holds master keys by ID
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// Parse reads a keyring from entries of the form "<id>:<base64 key>", separated by commas
+// or newlines. Blank lines and lines starting with # are ignored. The first entry is the
+// active key.
+func Parse(s string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+	for _, line := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(line, ":")
+		if !ok || id == "" || strings.ContainsAny(id, " \t") {
+			// The entry is not quoted, as it may be a bare key
+			return nil, errors.New("invalid keyring entry: expected <id>:<base64 key>")
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate master key %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != KeySize {
+			return nil, fmt.Errorf("master key %q must be %d base64-encoded bytes", id, KeySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		k.keys[id] = aead
+		if k.active == "" {
+			k.active = id
+		}
+	}
+	if k.active == "" {
+		return nil, errors.New("keyring has no keys")
+	}
+	return k, nil
+}
+
+// Load reads the keyring from the file at path, or from inline entries in the format
+// Parse accepts. It returns nil when neither is set, which leaves encryption off.
+func Load(path, inline string) (*Keyring, error) {
+	switch {
+	case path != "" && inline != "":
+		return nil, errors.New("set either a keyring file or inline keys, not both")
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyring: %w", err)
+		}
+		return Parse(string(data))
+	case inline != "":
+		return Parse(inline)
+	}
+	return nil, nil
+}
+
+// Generate returns a keyring entry with a new random key under id
+func Generate(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, ":, \t\n") {
+		return "", fmt.Errorf("invalid master key id %q", id)
+	}
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Active returns the ID of the key new data keys are wrapped with
+func (k *Keyring) Active() string {
+	return k.active
+}
+
+// Wrap encrypts dataKey with the active master key. aad binds the wrapped key to what it
+// protects; the same aad must be given to Unwrap.
+func (k *Keyring) Wrap(dataKey, aad []byte) (WrappedKey, error) {
+	aead := k.keys[k.active]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return WrappedKey{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return WrappedKey{
+		MasterKeyID: k.active,
+		Ciphertext:  aead.Seal(nonce, nonce, dataKey, aad),
+	}, nil
+}
+
+// Unwrap decrypts a data key wrapped by any key in the keyring
+func (k *Keyring) Unwrap(w WrappedKey, aad []byte) ([]byte, error) {
+	aead, ok := k.keys[w.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, w.MasterKeyID)
+	}
+	if len(w.Ciphertext) < aead.NonceSize() {
+		return nil, ErrUnwrap
+	}
+	nonce, sealed := w.Ciphertext[:aead.NonceSize()], w.Ciphertext[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, ErrUnwrap
+	}
+	return dataKey, nil
+}
+
+// Rewrap re-encrypts a wrapped data key with the active master key. It reports false,
+// and returns w as it is, when w is already wrapped with the active key.
+func (k *Keyring) Rewrap(w WrappedKey, aad []byte) (WrappedKey, bool, error) {
+	if w.MasterKeyID == k.active {
+		return w, false, nil
+	}
+	dataKey, err := k.Unwrap(w, aad)
+	if err != nil {
+		return WrappedKey{}, false, err
+	}
+	rewrapped, err := k.Wrap(dataKey, aad)
+	if err != nil {
+		return WrappedKey{}, false, err
+	}
+	return rewrapped, true, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,143 @@
+package keyring
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generate(t *testing.T, id string) string {
+	t.Helper()
+	entry, err := Generate(id)
+	require.NoError(t, err)
+	return entry
+}
+
+func TestParse(t *testing.T) {
+	k1, k2 := generate(t, "2026-01"), generate(t, "2025-07")
+
+	tests := []struct {
+		name     string
+		input    string
+		active   string
+		errorMsg string
+	}{
+		{name: "single key", input: k1, active: "2026-01"},
+		{name: "comma separated", input: k1 + "," + k2, active: "2026-01"},
+		{name: "keyring file", input: "# rotated 2026-01-05\n" + k2 + "\n\n" + k1 + "\n", active: "2025-07"},
+		{name: "empty", input: " \n# nothing yet\n", errorMsg: "no keys"},
+		{name: "missing id", input: strings.SplitN(k1, ":", 2)[1], errorMsg: "invalid keyring entry"},
+		{name: "short key", input: "short:c2hvcnQ=", errorMsg: "must be 32"},
+		{name: "not base64", input: "bad:***", errorMsg: "must be 32"},
+		{name: "duplicate id", input: k1 + "," + k1, errorMsg: "duplicate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := Parse(tt.input)
+
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				assert.NotContains(t, err.Error(), strings.SplitN(k1, ":", 2)[1], "key material should not leak into errors")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.active, k.Active())
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	entry := generate(t, "primary")
+	path := filepath.Join(t.TempDir(), "keyring")
+	require.NoError(t, os.WriteFile(path, []byte(entry+"\n"), 0o600))
+
+	k, err := Load(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", k.Active())
+
+	k, err = Load("", entry)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", k.Active())
+
+	k, err = Load("", "")
+	require.NoError(t, err)
+	assert.Nil(t, k, "no keyring leaves encryption off")
+
+	_, err = Load(path, entry)
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing"), "")
+	assert.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	for _, id := range []string{"", "a:b", "a,b", "a b"} {
+		_, err := Generate(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestKeyring_WrapAndRotate(t *testing.T) {
+	oldEntry, newEntry := generate(t, "old"), generate(t, "new")
+	old, err := Parse(oldEntry)
+	require.NoError(t, err)
+	rotated, err := Parse(newEntry + "," + oldEntry)
+	require.NoError(t, err)
+	dataKey := bytes.Repeat([]byte{7}, KeySize)
+	aad := []byte("object-1")
+
+	wrapped, err := old.Wrap(dataKey, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "old", wrapped.MasterKeyID)
+	assert.NotContains(t, string(wrapped.Ciphertext), string(dataKey))
+
+	t.Run("unwrap", func(t *testing.T) {
+		unwrapped, err := old.Unwrap(wrapped, aad)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, unwrapped)
+	})
+
+	t.Run("bound to its associated data", func(t *testing.T) {
+		_, err := old.Unwrap(wrapped, []byte("object-2"))
+		assert.ErrorIs(t, err, ErrUnwrap)
+	})
+
+	t.Run("altered", func(t *testing.T) {
+		altered := wrapped
+		altered.Ciphertext = bytes.Clone(wrapped.Ciphertext)
+		altered.Ciphertext[len(altered.Ciphertext)-1] ^= 1
+		_, err := old.Unwrap(altered, aad)
+		assert.ErrorIs(t, err, ErrUnwrap)
+	})
+
+	t.Run("rewrap under the new key", func(t *testing.T) {
+		rewrapped, changed, err := rotated.Rewrap(wrapped, aad)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "new", rewrapped.MasterKeyID)
+
+		newOnly, err := Parse(newEntry)
+		require.NoError(t, err)
+		unwrapped, err := newOnly.Unwrap(rewrapped, aad)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, unwrapped, "the old key should no longer be needed")
+
+		_, changed, err = rotated.Rewrap(rewrapped, aad)
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("unknown master key", func(t *testing.T) {
+		newOnly, err := Parse(newEntry)
+		require.NoError(t, err)
+		_, err = newOnly.Unwrap(wrapped, aad)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
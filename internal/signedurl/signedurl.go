@@ -0,0 +1,152 @@
+// Package signedurl issues and verifies expiring HMAC-signed URLs that grant
+// direct upload or download access to a single stored object
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PathPrefix is the gateway route signed URLs point at
+const PathPrefix = "/api/v1/blobs/"
+
+// Query parameter names
+const (
+	paramMethod      = "method"
+	paramUserID      = "user_id"
+	paramKey         = "key"
+	paramVersionID   = "version_id"
+	paramMaxSize     = "max_size"
+	paramContentType = "content_type"
+	paramExpires     = "expires"
+	paramSignature   = "signature"
+)
+
+var (
+	// ErrInvalidSignature is returned when a URL was not issued by this signer or has been altered
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrExpired is returned when a URL is used after its expiry time
+	ErrExpired = errors.New("signed url has expired")
+	// ErrMethodNotAllowed is returned when a URL is used with a different HTTP method than it was issued for
+	ErrMethodNotAllowed = errors.New("method not allowed for this url")
+)
+
+// Grant describes what a signed URL allows its holder to do
+type Grant struct {
+	// Method is http.MethodGet for downloads or http.MethodPut for uploads
+	Method string
+	FileID string
+	UserID string
+	// Key is the storage key of the object
+	Key string
+	// VersionID names the file version a download grant serves; empty means the current content
+	VersionID string
+	// MaxSize caps the upload size in bytes; zero means no limit
+	MaxSize int64
+	// ContentType must match the upload's Content-Type header, or is served on download
+	ContentType string
+	ExpiresAt   time.Time
+}
+
+// Signer issues and verifies signed URLs with a shared secret
+type Signer struct {
+	secret  []byte
+	baseURL string
+}
+
+// NewSigner creates a signer producing URLs below baseURL, e.g. "https://drive.example.com"
+func NewSigner(secret, baseURL string) (*Signer, error) {
+	if secret == "" {
+		return nil, errors.New("signed url secret is required")
+	}
+	return &Signer{
+		secret:  []byte(secret),
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+// Sign returns the URL granting g
+func (s *Signer) Sign(g Grant) string {
+	q := url.Values{}
+	q.Set(paramMethod, g.Method)
+	q.Set(paramUserID, g.UserID)
+	q.Set(paramKey, g.Key)
+	if g.VersionID != "" {
+		q.Set(paramVersionID, g.VersionID)
+	}
+	if g.MaxSize > 0 {
+		q.Set(paramMaxSize, strconv.FormatInt(g.MaxSize, 10))
+	}
+	if g.ContentType != "" {
+		q.Set(paramContentType, g.ContentType)
+	}
+	q.Set(paramExpires, strconv.FormatInt(g.ExpiresAt.Unix(), 10))
+	q.Set(paramSignature, s.signature(g.FileID, q))
+
+	return s.baseURL + PathPrefix + url.PathEscape(g.FileID) + "?" + q.Encode()
+}
+
+// Verify checks the signature and expiry of a request made to a signed URL
+// and returns the grant it carries
+func (s *Signer) Verify(r *http.Request, now time.Time) (Grant, error) {
+	fileID := strings.TrimPrefix(r.URL.Path, PathPrefix)
+	if fileID == "" || fileID == r.URL.Path || strings.Contains(fileID, "/") {
+		return Grant{}, ErrInvalidSignature
+	}
+
+	q := r.URL.Query()
+	got, err := hex.DecodeString(q.Get(paramSignature))
+	if err != nil {
+		return Grant{}, ErrInvalidSignature
+	}
+	want, _ := hex.DecodeString(s.signature(fileID, q))
+	if !hmac.Equal(got, want) {
+		return Grant{}, ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(q.Get(paramExpires), 10, 64)
+	if err != nil {
+		return Grant{}, ErrInvalidSignature
+	}
+	g := Grant{
+		Method:      q.Get(paramMethod),
+		FileID:      fileID,
+		UserID:      q.Get(paramUserID),
+		Key:         q.Get(paramKey),
+		VersionID:   q.Get(paramVersionID),
+		ContentType: q.Get(paramContentType),
+		ExpiresAt:   time.Unix(expires, 0).UTC(),
+	}
+	if v := q.Get(paramMaxSize); v != "" {
+		if g.MaxSize, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return Grant{}, ErrInvalidSignature
+		}
+	}
+
+	if !now.Before(g.ExpiresAt) {
+		return Grant{}, ErrExpired
+	}
+	if r.Method != g.Method && !(r.Method == http.MethodHead && g.Method == http.MethodGet) {
+		return Grant{}, ErrMethodNotAllowed
+	}
+
+	return g, nil
+}
+
+// signature computes the hex HMAC over the file ID and every signed parameter
+func (s *Signer) signature(fileID string, q url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n", fileID)
+	for _, name := range []string{paramMethod, paramUserID, paramKey, paramVersionID, paramMaxSize, paramContentType, paramExpires} {
+		fmt.Fprintf(mac, "%s=%s\n", name, q.Get(name))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
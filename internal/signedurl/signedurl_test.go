@@ -0,0 +1,120 @@
+package signedurl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T) *Signer {
+	t.Helper()
+	s, err := NewSigner("test-secret", "https://drive.example.com/")
+	require.NoError(t, err)
+	return s
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	s := newTestSigner(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	grant := Grant{
+		Method:      http.MethodPut,
+		FileID:      "223e4567-e89b-12d3-a456-426614174000",
+		UserID:      "123e4567-e89b-12d3-a456-426614174000",
+		Key:         "123e4567-e89b-12d3-a456-426614174000/223e4567-e89b-12d3-a456-426614174000",
+		MaxSize:     1024,
+		ContentType: "text/plain",
+		ExpiresAt:   now.Add(15 * time.Minute),
+	}
+
+	signed := s.Sign(grant)
+	assert.Contains(t, signed, "https://drive.example.com/api/v1/blobs/"+grant.FileID+"?")
+
+	got, err := s.Verify(httptest.NewRequest(http.MethodPut, signed, nil), now)
+	require.NoError(t, err)
+	assert.Equal(t, grant, got)
+}
+
+func TestSigner_Verify(t *testing.T) {
+	s := newTestSigner(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	download := s.Sign(Grant{
+		Method:    http.MethodGet,
+		FileID:    "file-1",
+		UserID:    "user-1",
+		Key:       "user-1/file-1",
+		ExpiresAt: now.Add(time.Minute),
+	})
+
+	tamper := func(param, value string) string {
+		u, _ := url.Parse(download)
+		q := u.Query()
+		q.Set(param, value)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	other, err := NewSigner("other-secret", "https://drive.example.com")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		now         time.Time
+		expectedErr error
+	}{
+		{name: "valid download", method: http.MethodGet, url: download, now: now},
+		{name: "head allowed on download", method: http.MethodHead, url: download, now: now},
+		{name: "expired", method: http.MethodGet, url: download, now: now.Add(time.Minute), expectedErr: ErrExpired},
+		{name: "wrong method", method: http.MethodPut, url: download, now: now, expectedErr: ErrMethodNotAllowed},
+		{name: "tampered key", method: http.MethodGet, url: tamper(paramKey, "user-2/file-1"), now: now, expectedErr: ErrInvalidSignature},
+		{name: "tampered expiry", method: http.MethodGet, url: tamper(paramExpires, "9999999999"), now: now, expectedErr: ErrInvalidSignature},
+		{name: "added size limit", method: http.MethodGet, url: tamper(paramMaxSize, "1"), now: now, expectedErr: ErrInvalidSignature},
+		{name: "added version", method: http.MethodGet, url: tamper(paramVersionID, "version-1"), now: now, expectedErr: ErrInvalidSignature},
+		{name: "missing signature", method: http.MethodGet, url: tamper(paramSignature, ""), now: now, expectedErr: ErrInvalidSignature},
+		{
+			name:   "other secret",
+			method: http.MethodGet,
+			url: other.Sign(Grant{
+				Method: http.MethodGet, FileID: "file-1", UserID: "user-1", Key: "user-1/file-1", ExpiresAt: now.Add(time.Minute),
+			}),
+			now:         now,
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "different file",
+			method:      http.MethodGet,
+			url:         "https://drive.example.com/api/v1/blobs/file-2?" + mustQuery(download),
+			now:         now,
+			expectedErr: ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Verify(httptest.NewRequest(tt.method, tt.url, nil), tt.now)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewSigner_RequiresSecret(t *testing.T) {
+	_, err := NewSigner("", "https://drive.example.com")
+	assert.Error(t, err)
+}
+
+func mustQuery(raw string) string {
+	u, _ := url.Parse(raw)
+	return u.RawQuery
+}
@@ -0,0 +1,184 @@
+package sigv4
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Payload hashes that announce a body sent in aws-chunked encoding
+const (
+	// StreamingPayload is a body whose chunks are each signed
+	StreamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	// StreamingUnsignedPayloadTrailer is a body of unsigned chunks followed by trailing
+	// headers, usually a checksum of the content
+	StreamingUnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+)
+
+var (
+	// ErrMalformedChunk is returned for a body that is not valid aws-chunked encoding
+	ErrMalformedChunk = errors.New("malformed aws-chunked body")
+	// ErrChunkSignature is returned for a chunk whose signature does not match its content
+	ErrChunkSignature = errors.New("chunk signature does not match")
+)
+
+// ChunkSigner holds what the chunks of a signed streaming body are checked with. Each
+// chunk is signed over the signature of the chunk before it, the first over the request's.
+type ChunkSigner struct {
+	SigningKey []byte
+	AmzDate    string
+	Scope      string
+	// Previous is the signature of the request, then of each chunk read
+	Previous string
+}
+
+// sign returns the signature of a chunk whose content hashes to contentHash
+func (s *ChunkSigner) sign(contentHash []byte) string {
+	return Sign(s.SigningKey, strings.Join([]string{
+		Algorithm + "-PAYLOAD",
+		s.AmzDate,
+		s.Scope,
+		s.Previous,
+		EmptyPayloadHash,
+		hex.EncodeToString(contentHash),
+	}, "\n"))
+}
+
+// NewChunkedReader decodes a body sent in aws-chunked encoding. With a signer, every chunk
+// must carry a valid chunk-signature, and a chunk's content is only passed on together with
+// the error should its signature not match. Without one the chunks are unsigned; trailing
+// headers that follow them are read but not checked.
+func NewChunkedReader(r io.Reader, signer *ChunkSigner) io.Reader {
+	return &chunkedReader{r: bufio.NewReader(r), signer: signer}
+}
+
+type chunkedReader struct {
+	r      *bufio.Reader
+	signer *ChunkSigner
+
+	// remaining counts the bytes left in the current chunk
+	remaining int64
+	// hash and signature check the current chunk, when chunks are signed
+	hash      hash.Hash
+	signature string
+	err       error
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for c.err == nil && c.remaining == 0 {
+		c.err = c.nextChunk()
+	}
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if c.hash != nil {
+		c.hash.Write(p[:n])
+	}
+	switch {
+	case errors.Is(err, io.EOF):
+		c.err = io.ErrUnexpectedEOF
+	case err != nil:
+		c.err = err
+	case c.remaining == 0:
+		c.err = c.endChunk()
+	}
+	return n, c.err
+}
+
+// nextChunk reads the header of the next chunk. After the last, empty chunk it reads the
+// trailing headers and returns io.EOF.
+func (c *chunkedReader) nextChunk() error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	sizeField, extension, _ := strings.Cut(line, ";")
+	size, err := strconv.ParseInt(sizeField, 16, 64)
+	if err != nil || size < 0 {
+		return ErrMalformedChunk
+	}
+
+	if c.signer != nil {
+		signature, ok := strings.CutPrefix(extension, "chunk-signature=")
+		if !ok || len(signature) != 64 {
+			return ErrMalformedChunk
+		}
+		c.hash, c.signature = sha256.New(), signature
+	}
+	if size > 0 {
+		c.remaining = size
+		return nil
+	}
+
+	if err := c.verify(); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+		if line == "" {
+			return io.EOF
+		}
+		if c.signer != nil {
+			return ErrMalformedChunk
+		}
+	}
+}
+
+// endChunk reads the line break that closes a chunk's content and checks its signature
+func (c *chunkedReader) endChunk() error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	if line != "" {
+		return ErrMalformedChunk
+	}
+	return c.verify()
+}
+
+// verify checks the signature of the chunk just read, when chunks are signed
+func (c *chunkedReader) verify() error {
+	if c.signer == nil {
+		return nil
+	}
+	expected := c.signer.sign(c.hash.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(c.signature)) {
+		return ErrChunkSignature
+	}
+	c.signer.Previous = expected
+	return nil
+}
+
+// readLine reads a CRLF-terminated line without its line break
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.r.ReadSlice('\n')
+	switch {
+	case errors.Is(err, io.EOF):
+		return "", io.ErrUnexpectedEOF
+	case errors.Is(err, bufio.ErrBufferFull):
+		return "", ErrMalformedChunk
+	case err != nil:
+		return "", err
+	}
+	line, ok := bytes.CutSuffix(line, []byte("\r\n"))
+	if !ok {
+		return "", ErrMalformedChunk
+	}
+	return string(line), nil
+}
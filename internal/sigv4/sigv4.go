@@ -0,0 +1,185 @@
+// Package sigv4 implements AWS Signature Version 4 request signing as used by S3
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Algorithm is the signing algorithm identifier used in Authorization headers
+	Algorithm = "AWS4-HMAC-SHA256"
+	// UnsignedPayload lets a request body stream without hashing it up front
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+	// EmptyPayloadHash is the SHA-256 of an empty body
+	EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// TimeFormat is the layout of the X-Amz-Date header
+	TimeFormat = "20060102T150405Z"
+	// DateFormat is the layout of the date component of the credential scope
+	DateFormat = "20060102"
+)
+
+// Credentials identify the signer of a request
+type Credentials struct {
+	AccessKey string
+	SecretKey string
+}
+
+// SignRequest signs req in place for the given region and service.
+// payloadHash is the hex SHA-256 of the body, EmptyPayloadHash or UnsignedPayload.
+func SignRequest(req *http.Request, creds Credentials, region, service, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(TimeFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+
+	signedHeaders := headersToSign(req)
+	scope := Scope(now, region, service)
+	signature := Signature(creds.SecretKey, now, region, service,
+		StringToSign(amzDate, scope, CanonicalRequest(req, signedHeaders, payloadHash)))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		Algorithm, creds.AccessKey, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// Scope returns the credential scope for a signing date, region and service
+func Scope(t time.Time, region, service string) string {
+	return strings.Join([]string{t.UTC().Format(DateFormat), region, service, "aws4_request"}, "/")
+}
+
+// CanonicalRequest builds the canonical form of req over the named headers
+func CanonicalRequest(req *http.Request, signedHeaders []string, payloadHash string) string {
+	var headers strings.Builder
+	for _, name := range signedHeaders {
+		headers.WriteString(name)
+		headers.WriteByte(':')
+		headers.WriteString(canonicalHeaderValue(req, name))
+		headers.WriteByte('\n')
+	}
+
+	return strings.Join([]string{
+		req.Method,
+		EncodePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+// StringToSign combines the request timestamp, scope and canonical request
+func StringToSign(amzDate, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	return strings.Join([]string{Algorithm, amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+}
+
+// Signature derives the signing key and signs stringToSign with it
+func Signature(secretKey string, t time.Time, region, service, stringToSign string) string {
+	return Sign(SigningKey(secretKey, t, region, service), stringToSign)
+}
+
+// SigningKey derives the key that signs requests of one day, region and service from a
+// secret key. It can be handed out without revealing the secret.
+func SigningKey(secretKey string, t time.Time, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), t.UTC().Format(DateFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// Sign signs stringToSign with a key derived by SigningKey
+func Sign(signingKey []byte, stringToSign string) string {
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+// EncodePath URI-encodes each segment of an object path, keeping the slashes
+func EncodePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// headersToSign selects the headers covered by the signature, sorted by name
+func headersToSign(req *http.Request) []string {
+	names := []string{"host"}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		switch {
+		case lower == "authorization":
+		case lower == "content-type", lower == "content-md5", lower == "range", strings.HasPrefix(lower, "x-amz-"):
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func canonicalHeaderValue(req *http.Request, name string) string {
+	if name == "host" {
+		if req.Host != "" {
+			return req.Host
+		}
+		return req.URL.Host
+	}
+	values := req.Header.Values(name)
+	for i, v := range values {
+		values[i] = strings.Join(strings.Fields(v), " ")
+	}
+	return strings.Join(values, ",")
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything except RFC 3986 unreserved characters
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,497 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-drive/internal/keyring"
+)
+
+// Encrypted objects start with a header naming their data key, followed by the content
+// sealed with AES-256-GCM in chunks of encryptedChunkSize bytes. Every chunk has its own
+// tag, so a range is read by decrypting only the chunks it covers.
+const (
+	encryptedMagic      = "GDRVENC1"
+	keyIDSize           = 16
+	encryptedHeaderSize = len(encryptedMagic) + keyIDSize
+	encryptedChunkSize  = 64 << 10
+	tagSize             = 16
+	sealedChunkSize     = encryptedChunkSize + tagSize
+
+	// keyPrefix holds the wrapped data keys, one object per data key. Keys below it are
+	// reserved and refused to callers.
+	keyPrefix = "keys/"
+)
+
+// ErrCorrupt is returned when encrypted content fails authentication, such as after it was
+// altered or truncated in the backend
+var ErrCorrupt = errors.New("encrypted object is corrupt")
+
+// EncryptedBackend encrypts objects before they reach another backend. Each object gets
+// its own data key, wrapped with the keyring's active master key and stored under
+// keys/<id>; rotating the master key re-wraps those without touching the objects.
+// Objects stored before encryption was turned on are read as they are.
+type EncryptedBackend struct {
+	inner Backend
+	keys  *keyring.Keyring
+}
+
+// NewEncryptedBackend wraps inner so that everything it stores is encrypted with data keys
+// wrapped by keys
+func NewEncryptedBackend(inner Backend, keys *keyring.Keyring) *EncryptedBackend {
+	return &EncryptedBackend{inner: inner, keys: keys}
+}
+
+func (b *EncryptedBackend) Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error) {
+	if err := checkKey(key); err != nil {
+		return ObjectInfo{}, err
+	}
+	// The data key of the object being replaced is dropped once the new one is stored
+	replaced, _, _ := b.header(ctx, key)
+
+	id, aead, err := b.newDataKey(ctx)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if size >= 0 {
+		size = encryptedSize(size)
+	}
+	info, err := b.inner.Put(ctx, key, newEncryptReader(r, id, aead), size)
+	if err != nil {
+		b.deleteDataKey(ctx, id)
+		return ObjectInfo{}, err
+	}
+	b.deleteDataKey(ctx, replaced)
+
+	return plainInfo(info)
+}
+
+func (b *EncryptedBackend) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	return b.GetRange(ctx, key, 0, -1)
+}
+
+func (b *EncryptedBackend) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	if err := checkKey(key); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	id, stored, err := b.header(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if id == nil {
+		return b.inner.GetRange(ctx, key, offset, length)
+	}
+
+	info, err := plainInfo(stored)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if offset < 0 || offset > info.Size {
+		return nil, ObjectInfo{}, fmt.Errorf("%w: offset %d of %s", ErrInvalidRange, offset, key)
+	}
+	end := info.Size
+	if length >= 0 && offset+length < end {
+		end = offset + length
+	}
+	if offset == end {
+		return io.NopCloser(strings.NewReader("")), info, nil
+	}
+
+	aead, err := b.dataKey(ctx, id)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	first, last := offset/encryptedChunkSize, (end-1)/encryptedChunkSize
+	body, _, err := b.inner.GetRange(ctx, key, int64(encryptedHeaderSize)+first*sealedChunkSize, (last-first+1)*sealedChunkSize)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	return &decryptReader{
+		body:      body,
+		aead:      aead,
+		index:     first,
+		final:     chunkCount(info.Size) - 1,
+		sealed:    make([]byte, sealedChunkSize),
+		skip:      offset - first*encryptedChunkSize,
+		remaining: end - offset,
+	}, info, nil
+}
+
+func (b *EncryptedBackend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	if err := checkKey(key); err != nil {
+		return ObjectInfo{}, err
+	}
+	id, info, err := b.header(ctx, key)
+	if err != nil || id == nil {
+		return info, err
+	}
+	return plainInfo(info)
+}
+
+func (b *EncryptedBackend) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	id, _, err := b.header(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err := b.inner.Delete(ctx, key); err != nil {
+		return err
+	}
+	b.deleteDataKey(ctx, id)
+	return nil
+}
+
+func (b *EncryptedBackend) Move(ctx context.Context, src, dst string) error {
+	if err := checkKey(src); err != nil {
+		return err
+	}
+	if err := checkKey(dst); err != nil {
+		return err
+	}
+	// The header moves with the object, so its data key stays where it is
+	replaced, _, _ := b.header(ctx, dst)
+	if err := b.inner.Move(ctx, src, dst); err != nil {
+		return err
+	}
+	b.deleteDataKey(ctx, replaced)
+	return nil
+}
+
+func (b *EncryptedBackend) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	stored, err := b.inner.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ObjectInfo, 0, len(stored))
+	for _, obj := range stored {
+		if strings.HasPrefix(obj.Key, keyPrefix) {
+			continue
+		}
+		info, err := b.Stat(ctx, obj.Key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, info)
+	}
+	return objects, nil
+}
+
+// RotateKeys re-wraps every data key that is not wrapped with the keyring's active master
+// key, leaving the objects they encrypt untouched. Once it has run, the other master keys
+// can be removed from the keyring. It returns the number of data keys re-wrapped.
+func (b *EncryptedBackend) RotateKeys(ctx context.Context) (int, error) {
+	stored, err := b.inner.List(ctx, keyPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for _, obj := range stored {
+		id, err := hex.DecodeString(strings.TrimPrefix(obj.Key, keyPrefix))
+		if err != nil || len(id) != keyIDSize {
+			continue
+		}
+		wrapped, err := b.readDataKey(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			// Deleted with its object since it was listed
+			continue
+		}
+		if err != nil {
+			return rotated, err
+		}
+
+		rewrapped, changed, err := b.keys.Rewrap(wrapped, id)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to rewrap data key %s: %w", obj.Key, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := b.writeDataKey(ctx, id, rewrapped); err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+	return rotated, nil
+}
+
+// header reads the data key ID of the object under key, and the stored object's metadata.
+// The ID is nil for objects stored unencrypted.
+func (b *EncryptedBackend) header(ctx context.Context, key string) ([]byte, ObjectInfo, error) {
+	body, info, err := b.inner.GetRange(ctx, key, 0, int64(encryptedHeaderSize))
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	defer body.Close()
+
+	header, err := io.ReadAll(body)
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	if len(header) < encryptedHeaderSize || !bytes.HasPrefix(header, []byte(encryptedMagic)) {
+		return nil, info, nil
+	}
+	return header[len(encryptedMagic):], info, nil
+}
+
+// newDataKey generates a data key and stores it wrapped with the active master key
+func (b *EncryptedBackend) newDataKey(ctx context.Context) ([]byte, cipher.AEAD, error) {
+	id := make([]byte, keyIDSize)
+	dataKey := make([]byte, keyring.KeySize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key id: %w", err)
+	}
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	// Wrapping with the ID as associated data keeps a wrapped key from being swapped in
+	// for another
+	wrapped, err := b.keys.Wrap(dataKey, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := b.writeDataKey(ctx, id, wrapped); err != nil {
+		return nil, nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, aead, nil
+}
+
+// dataKey reads and unwraps the data key with the given ID
+func (b *EncryptedBackend) dataKey(ctx context.Context, id []byte) (cipher.AEAD, error) {
+	wrapped, err := b.readDataKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := b.keys.Unwrap(wrapped, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %x: %w", id, err)
+	}
+	return newGCM(dataKey)
+}
+
+func (b *EncryptedBackend) readDataKey(ctx context.Context, id []byte) (keyring.WrappedKey, error) {
+	body, _, err := b.inner.Get(ctx, dataKeyPath(id))
+	if err != nil {
+		return keyring.WrappedKey{}, err
+	}
+	defer body.Close()
+
+	var wrapped keyring.WrappedKey
+	if err := json.NewDecoder(body).Decode(&wrapped); err != nil {
+		return keyring.WrappedKey{}, fmt.Errorf("failed to read data key %x: %w", id, err)
+	}
+	return wrapped, nil
+}
+
+func (b *EncryptedBackend) writeDataKey(ctx context.Context, id []byte, wrapped keyring.WrappedKey) error {
+	data, err := json.Marshal(wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to encode data key: %w", err)
+	}
+	if _, err := b.inner.Put(ctx, dataKeyPath(id), bytes.NewReader(data), int64(len(data))); err != nil {
+		return fmt.Errorf("failed to store data key %x: %w", id, err)
+	}
+	return nil
+}
+
+// deleteDataKey removes a data key no object uses anymore. Failing to is not an error: the
+// key is left behind wrapped, and RotateKeys keeps re-wrapping it.
+func (b *EncryptedBackend) deleteDataKey(ctx context.Context, id []byte) {
+	if id != nil {
+		_ = b.inner.Delete(ctx, dataKeyPath(id))
+	}
+}
+
+func dataKeyPath(id []byte) string {
+	return keyPrefix + hex.EncodeToString(id)
+}
+
+// checkKey rejects invalid keys and the keys reserved for data keys
+func checkKey(key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if strings.HasPrefix(key, keyPrefix) {
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkCount returns the number of chunks size bytes are sealed in. Empty content is
+// sealed as one empty chunk, so that its tag still authenticates it.
+func chunkCount(size int64) int64 {
+	return max(1, (size+encryptedChunkSize-1)/encryptedChunkSize)
+}
+
+// encryptedSize returns the stored size of size bytes of content
+func encryptedSize(size int64) int64 {
+	return int64(encryptedHeaderSize) + size + chunkCount(size)*tagSize
+}
+
+// plainInfo turns the metadata of a stored encrypted object into that of its content
+func plainInfo(info ObjectInfo) (ObjectInfo, error) {
+	body := info.Size - int64(encryptedHeaderSize)
+	chunks := (body + sealedChunkSize - 1) / sealedChunkSize
+	if body < tagSize || body%sealedChunkSize != 0 && body%sealedChunkSize < tagSize {
+		return ObjectInfo{}, fmt.Errorf("%w: %s has an invalid size", ErrCorrupt, info.Key)
+	}
+	info.Size = body - chunks*tagSize
+	return info, nil
+}
+
+// chunkNonce derives the nonce of a chunk from its index. The last chunk is flagged, so
+// that content cut at a chunk boundary does not authenticate.
+func chunkNonce(index int64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce, uint64(index))
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// encryptReader yields the header and sealed chunks of the content read from src
+type encryptReader struct {
+	src   *bufio.Reader
+	aead  cipher.AEAD
+	index int64
+	plain []byte
+	out   []byte
+	done  bool
+}
+
+func newEncryptReader(r io.Reader, id []byte, aead cipher.AEAD) *encryptReader {
+	out := make([]byte, 0, sealedChunkSize)
+	out = append(out, encryptedMagic...)
+	out = append(out, id...)
+	return &encryptReader{
+		src:   bufio.NewReader(r),
+		aead:  aead,
+		plain: make([]byte, encryptedChunkSize),
+		out:   out,
+	}
+}
+
+func (e *encryptReader) Read(p []byte) (int, error) {
+	for len(e.out) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.seal(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+// seal reads and seals the next chunk, looking ahead to tell whether it is the last
+func (e *encryptReader) seal() error {
+	n, err := io.ReadFull(e.src, e.plain)
+	last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !last {
+		return err
+	}
+	if !last {
+		_, err := e.src.Peek(1)
+		last = errors.Is(err, io.EOF)
+		if err != nil && !last {
+			return err
+		}
+	}
+
+	e.out = e.aead.Seal(e.out[:0], chunkNonce(e.index, last), e.plain[:n], nil)
+	e.index++
+	e.done = last
+	return nil
+}
+
+// decryptReader opens the sealed chunks read from body and yields remaining bytes of
+// content, after skipping skip bytes of the first chunk
+type decryptReader struct {
+	body      io.ReadCloser
+	aead      cipher.AEAD
+	index     int64
+	final     int64
+	sealed    []byte
+	plain     []byte
+	skip      int64
+	remaining int64
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	if d.remaining == 0 {
+		return 0, io.EOF
+	}
+	if len(d.plain) == 0 {
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain[:min(int64(len(d.plain)), d.remaining)])
+	d.plain = d.plain[n:]
+	d.remaining -= int64(n)
+	return n, nil
+}
+
+// open reads and authenticates the next chunk
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.body, d.sealed)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// Only the last chunk may be short
+		if d.index != d.final {
+			return fmt.Errorf("%w: chunk %d is truncated", ErrCorrupt, d.index)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	plain, err := d.aead.Open(d.sealed[:0], chunkNonce(d.index, d.index == d.final), d.sealed[:n], nil)
+	if err != nil {
+		return fmt.Errorf("%w: chunk %d failed authentication", ErrCorrupt, d.index)
+	}
+	if d.skip > int64(len(plain)) {
+		return fmt.Errorf("%w: chunk %d is truncated", ErrCorrupt, d.index)
+	}
+	d.plain = plain[d.skip:]
+	d.skip = 0
+	d.index++
+	return nil
+}
+
+func (d *decryptReader) Close() error {
+	return d.body.Close()
+}
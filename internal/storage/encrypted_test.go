@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/keyring"
+)
+
+func newTestKeyring(t *testing.T, ids ...string) (*keyring.Keyring, []string) {
+	t.Helper()
+	entries := make([]string, len(ids))
+	for i, id := range ids {
+		entry, err := keyring.Generate(id)
+		require.NoError(t, err)
+		entries[i] = entry
+	}
+	keys, err := keyring.Parse(strings.Join(entries, ","))
+	require.NoError(t, err)
+	return keys, entries
+}
+
+func newTestEncryptedBackend(t *testing.T) (*EncryptedBackend, *LocalBackend) {
+	t.Helper()
+	inner, err := NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+	keys, _ := newTestKeyring(t, "primary")
+	return NewEncryptedBackend(inner, keys), inner
+}
+
+func TestEncryptedBackend(t *testing.T) {
+	b, _ := newTestEncryptedBackend(t)
+
+	testBackend(t, b)
+}
+
+func TestEncryptedBackend_StoresCiphertext(t *testing.T) {
+	b, inner := newTestEncryptedBackend(t)
+	ctx := context.Background()
+	content := strings.Repeat("confidential ", 10000)
+
+	info, err := b.Put(ctx, "user/file", strings.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), info.Size)
+
+	stored, err := os.ReadFile(filepath.Join(inner.root, "user", "file"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(stored), "confidential")
+	assert.Equal(t, encryptedSize(int64(len(content))), int64(len(stored)))
+
+	keys, err := inner.List(ctx, keyPrefix)
+	require.NoError(t, err)
+	require.Len(t, keys, 1, "the object should have its own data key")
+
+	_, err = b.Put(ctx, keys[0].Key, strings.NewReader("x"), 1)
+	assert.ErrorIs(t, err, ErrInvalidKey, "data keys are not reachable through the backend")
+}
+
+func TestEncryptedBackend_RangesAcrossChunks(t *testing.T) {
+	b, _ := newTestEncryptedBackend(t)
+	ctx := context.Background()
+	content := make([]byte, 3*encryptedChunkSize+100)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	_, err := b.Put(ctx, "user/large", bytes.NewReader(content), -1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		offset, length int64
+	}{
+		{"whole", 0, -1},
+		{"within a chunk", 10, 100},
+		{"across a boundary", encryptedChunkSize - 5, 10},
+		{"spanning chunks", encryptedChunkSize / 2, 2 * encryptedChunkSize},
+		{"from a boundary", encryptedChunkSize, encryptedChunkSize},
+		{"last chunk", 3 * encryptedChunkSize, -1},
+		{"tail", int64(len(content)) - 1, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc, info, err := b.GetRange(ctx, "user/large", tt.offset, tt.length)
+			require.NoError(t, err)
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+
+			end := int64(len(content))
+			if tt.length >= 0 {
+				end = min(end, tt.offset+tt.length)
+			}
+			assert.Equal(t, content[tt.offset:end], data)
+			assert.Equal(t, int64(len(content)), info.Size)
+		})
+	}
+}
+
+func TestEncryptedBackend_DetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("a"), 2*encryptedChunkSize+10)
+
+	tests := []struct {
+		name   string
+		tamper func(stored []byte) []byte
+	}{
+		{
+			name: "flipped bit",
+			tamper: func(stored []byte) []byte {
+				stored[encryptedHeaderSize+sealedChunkSize+10] ^= 1
+				return stored
+			},
+		},
+		{
+			name: "truncated at a chunk boundary",
+			tamper: func(stored []byte) []byte {
+				return stored[:encryptedHeaderSize+2*sealedChunkSize]
+			},
+		},
+		{
+			name: "chunks swapped",
+			tamper: func(stored []byte) []byte {
+				first := encryptedHeaderSize
+				second := first + sealedChunkSize
+				swapped := bytes.Clone(stored)
+				copy(swapped[first:second], stored[second:second+sealedChunkSize])
+				copy(swapped[second:second+sealedChunkSize], stored[first:second])
+				return swapped
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, inner := newTestEncryptedBackend(t)
+			_, err := b.Put(ctx, "user/file", bytes.NewReader(content), int64(len(content)))
+			require.NoError(t, err)
+
+			path := filepath.Join(inner.root, "user", "file")
+			stored, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, tt.tamper(stored), 0o600))
+
+			rc, _, err := b.Get(ctx, "user/file")
+			require.NoError(t, err)
+			defer rc.Close()
+			_, err = io.ReadAll(rc)
+			assert.ErrorIs(t, err, ErrCorrupt)
+		})
+	}
+}
+
+func TestEncryptedBackend_DataKeysFollowObjects(t *testing.T) {
+	b, inner := newTestEncryptedBackend(t)
+	ctx := context.Background()
+	countKeys := func() int {
+		keys, err := inner.List(ctx, keyPrefix)
+		require.NoError(t, err)
+		return len(keys)
+	}
+
+	_, err := b.Put(ctx, "user/file", strings.NewReader("first"), 5)
+	require.NoError(t, err)
+	_, err = b.Put(ctx, "user/file", strings.NewReader("second"), 6)
+	require.NoError(t, err)
+	assert.Equal(t, 1, countKeys(), "replacing an object should drop its old data key")
+
+	_, err = b.Put(ctx, "user/staged", strings.NewReader("third"), 5)
+	require.NoError(t, err)
+	require.NoError(t, b.Move(ctx, "user/staged", "user/file"))
+	assert.Equal(t, 1, countKeys(), "moving over an object should drop its data key")
+
+	require.NoError(t, b.Delete(ctx, "user/file"))
+	assert.Equal(t, 0, countKeys())
+
+	_, err = b.Put(ctx, "user/short", strings.NewReader("short"), 100)
+	assert.Error(t, err)
+	assert.Equal(t, 0, countKeys(), "a failed write should not leave its data key behind")
+}
+
+func TestEncryptedBackend_ReadsUnencryptedObjects(t *testing.T) {
+	b, inner := newTestEncryptedBackend(t)
+	ctx := context.Background()
+	_, err := inner.Put(ctx, "user/legacy", strings.NewReader("stored before encryption"), -1)
+	require.NoError(t, err)
+
+	info, err := b.Stat(ctx, "user/legacy")
+	require.NoError(t, err)
+	assert.Equal(t, int64(24), info.Size)
+
+	rc, _, err := b.GetRange(ctx, "user/legacy", 7, 6)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "before", string(data))
+}
+
+func TestEncryptedBackend_RotateKeys(t *testing.T) {
+	inner, err := NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	oldKeys, oldEntries := newTestKeyring(t, "old")
+	old := NewEncryptedBackend(inner, oldKeys)
+	for _, key := range []string{"user/a", "user/b"} {
+		_, err := old.Put(ctx, key, strings.NewReader("content of "+key), -1)
+		require.NoError(t, err)
+	}
+	before, err := os.ReadFile(filepath.Join(inner.root, "user", "a"))
+	require.NoError(t, err)
+
+	newEntry, err := keyring.Generate("new")
+	require.NoError(t, err)
+	both, err := keyring.Parse(newEntry + "," + oldEntries[0])
+	require.NoError(t, err)
+	rotating := NewEncryptedBackend(inner, both)
+
+	rotated, err := rotating.RotateKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rotated)
+
+	rotated, err = rotating.RotateKeys(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, rotated, "keys already under the active key are left alone")
+
+	after, err := os.ReadFile(filepath.Join(inner.root, "user", "a"))
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "objects should not be rewritten")
+
+	newOnly, err := keyring.Parse(newEntry)
+	require.NoError(t, err)
+	rc, _, err := NewEncryptedBackend(inner, newOnly).Get(ctx, "user/b")
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "content of user/b", string(data))
+
+	_, _, err = old.Get(ctx, "user/b")
+	assert.ErrorIs(t, err, keyring.ErrUnknownKey, "the retired key no longer unwraps anything")
+}
+
+func TestNew_Encrypted(t *testing.T) {
+	keys, _ := newTestKeyring(t, "primary")
+	b, err := New(context.Background(), Config{Driver: DriverLocal, LocalRoot: t.TempDir(), Keyring: keys})
+	require.NoError(t, err)
+	assert.IsType(t, &EncryptedBackend{}, b)
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// tmpDir holds partially written objects so readers never observe a half-written blob
+const tmpDir = ".tmp"
+
+// LocalBackend stores objects as files below a root directory
+type LocalBackend struct {
+	root string
+}
+
+// NewLocalBackend creates a backend rooted at dir, creating the directory if needed
+func NewLocalBackend(dir string) (*LocalBackend, error) {
+	if dir == "" {
+		return nil, errors.New("local storage root is required")
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage root: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, tmpDir), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage root: %w", err)
+	}
+
+	return &LocalBackend{root: root}, nil
+}
+
+func (b *LocalBackend) Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(b.root, tmpDir), "put-*")
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, contextReader{ctx: ctx, r: r})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+	if size >= 0 && written != size {
+		return ObjectInfo{}, fmt.Errorf("failed to write object %s: expected %d bytes, got %d", key, size, written)
+	}
+
+	dst := b.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to create object directory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to store object %s: %w", key, err)
+	}
+
+	return b.Stat(ctx, key)
+}
+
+func (b *LocalBackend) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	f, err := os.Open(b.path(key))
+	if err != nil {
+		return nil, ObjectInfo{}, b.wrapError(key, err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, b.wrapError(key, err)
+	}
+
+	return f, objectInfo(key, fi), nil
+}
+
+func (b *LocalBackend) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	f, info, err := b.Get(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if offset < 0 || offset > info.Size {
+		f.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("%w: offset %d of %s", ErrInvalidRange, offset, key)
+	}
+
+	if _, err := f.(*os.File).Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("failed to seek object %s: %w", key, err)
+	}
+	if length < 0 {
+		return f, info, nil
+	}
+
+	return limitedReadCloser{Reader: io.LimitReader(f, length), Closer: f}, info, nil
+}
+
+func (b *LocalBackend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	fi, err := os.Stat(b.path(key))
+	if err != nil {
+		return ObjectInfo{}, b.wrapError(key, err)
+	}
+	if fi.IsDir() {
+		return ObjectInfo{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	return objectInfo(key, fi), nil
+}
+
+func (b *LocalBackend) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	if err := os.Remove(b.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	b.pruneDirs(key)
+
+	return nil
+}
+
+func (b *LocalBackend) Move(ctx context.Context, src, dst string) error {
+	if err := validateKey(src); err != nil {
+		return err
+	}
+	if err := validateKey(dst); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path(dst)), 0o750); err != nil {
+		return fmt.Errorf("failed to create object directory: %w", err)
+	}
+	if err := os.Rename(b.path(src), b.path(dst)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, src)
+		}
+		return fmt.Errorf("failed to move object %s to %s: %w", src, dst, err)
+	}
+	b.pruneDirs(src)
+
+	return nil
+}
+
+func (b *LocalBackend) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	// Only walk the deepest directory that can contain matching keys
+	start := b.root
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		if err := validateKey(prefix[:i]); err != nil {
+			return nil, err
+		}
+		start = b.path(prefix[:i])
+	}
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(b.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if key == tmpDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, objectInfo(key, fi))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+
+	return objects, nil
+}
+
+// pruneDirs removes the directories left empty by removing key, stopping at the root
+func (b *LocalBackend) pruneDirs(key string) {
+	dir := path.Dir(key)
+	for dir != "." {
+		if err := os.Remove(b.path(dir)); err != nil {
+			return
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// path maps a validated key to its location on disk
+func (b *LocalBackend) path(key string) string {
+	return filepath.Join(b.root, filepath.FromSlash(key))
+}
+
+func (b *LocalBackend) wrapError(key string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return fmt.Errorf("failed to access object %s: %w", key, err)
+}
+
+func objectInfo(key string, fi fs.FileInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          key,
+		Size:         fi.Size(),
+		LastModified: fi.ModTime().UTC(),
+	}
+}
+
+// limitedReadCloser closes the underlying file of a limited reader
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// contextReader stops a copy once its context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBackend(t *testing.T) {
+	b, err := NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+
+	testBackend(t, b)
+}
+
+func TestLocalBackend_WriteFailureKeepsExistingObject(t *testing.T) {
+	b, err := NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = b.Put(ctx, "user/file", strings.NewReader("original"), 8)
+	require.NoError(t, err)
+
+	// A short body must not replace the stored object
+	_, err = b.Put(ctx, "user/file", strings.NewReader("short"), 100)
+	assert.Error(t, err)
+
+	info, err := b.Stat(ctx, "user/file")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), info.Size)
+
+	entries, err := os.ReadDir(filepath.Join(b.root, tmpDir))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "temporary files should be cleaned up")
+}
+
+func TestLocalBackend_DeletePrunesEmptyDirectories(t *testing.T) {
+	root := t.TempDir()
+	b, err := NewLocalBackend(root)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = b.Put(ctx, "user/nested/file", strings.NewReader("x"), 1)
+	require.NoError(t, err)
+	require.NoError(t, b.Delete(ctx, "user/nested/file"))
+
+	_, err = os.Stat(filepath.Join(root, "user"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNew(t *testing.T) {
+	b, err := New(context.Background(), Config{Driver: DriverLocal, LocalRoot: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &LocalBackend{}, b)
+
+	_, err = New(context.Background(), Config{Driver: "ftp"})
+	assert.Error(t, err)
+}
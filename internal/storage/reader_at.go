@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// readAheadSize is the least a ReaderAt fetches per ranged read
+	readAheadSize = 64 << 10
+	// maxReadAheadSize caps the read-ahead, which doubles while reads are sequential
+	maxReadAheadSize = 4 << 20
+)
+
+// ReaderAt reads an object of known size through ranged reads, so that formats indexed at
+// the end, such as ZIP, can be read without fetching the whole object. Every read fetches at
+// least readAheadSize bytes and later reads within them are served from memory, which keeps
+// the many small reads of a directory or a run of headers from each costing a request.
+// While reads run on sequentially the read-ahead grows, so streaming content takes few requests.
+type ReaderAt struct {
+	ctx   context.Context
+	b     Backend
+	key   string
+	size  int64
+	mu    sync.Mutex
+	buf   []byte
+	start int64
+}
+
+// NewReaderAt creates a ReaderAt over the object stored under key, which holds size bytes
+func NewReaderAt(ctx context.Context, b Backend, key string, size int64) *ReaderAt {
+	return &ReaderAt{ctx: ctx, b: b, key: key, size: size}
+}
+
+// Size returns the size of the object
+func (r *ReaderAt) Size() int64 {
+	return r.size
+}
+
+func (r *ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, ErrInvalidRange
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for n < len(p) && off < r.size {
+		if off < r.start || off >= r.start+int64(len(r.buf)) {
+			if err := r.fill(off, int64(len(p)-n)); err != nil {
+				return n, err
+			}
+		}
+		copied := copy(p[n:], r.buf[off-r.start:])
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fill fetches at least want bytes, and at least the read-ahead, starting at off
+func (r *ReaderAt) fill(off, want int64) error {
+	ahead := int64(readAheadSize)
+	if end := r.start + int64(len(r.buf)); off == end && len(r.buf) > 0 {
+		ahead = min(2*int64(len(r.buf)), maxReadAheadSize)
+	}
+	length := min(max(want, ahead), r.size-off)
+	body, _, err := r.b.GetRange(r.ctx, r.key, off, length)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(body, buf); err != nil {
+		return fmt.Errorf("failed to read %s at %d: %w", r.key, off, err)
+	}
+	r.buf, r.start = buf, off
+	return nil
+}
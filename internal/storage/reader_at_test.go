@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend counts the ranged reads made through it
+type countingBackend struct {
+	Backend
+	reads int
+}
+
+func (b *countingBackend) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	b.reads++
+	return b.Backend.GetRange(ctx, key, offset, length)
+}
+
+func TestReaderAt(t *testing.T) {
+	inner, err := NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	content := make([]byte, 3*readAheadSize+10)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	_, err = inner.Put(ctx, "user/object", bytes.NewReader(content), -1)
+	require.NoError(t, err)
+
+	t.Run("small reads share a ranged read", func(t *testing.T) {
+		b := &countingBackend{Backend: inner}
+		r := NewReaderAt(ctx, b, "user/object", int64(len(content)))
+		p := make([]byte, 100)
+		for _, off := range []int64{0, 200, 100, 5000} {
+			n, err := r.ReadAt(p, off)
+			require.NoError(t, err)
+			assert.Equal(t, content[off:off+int64(n)], p)
+		}
+		assert.Equal(t, 1, b.reads)
+	})
+
+	t.Run("sequential reads grow the read-ahead", func(t *testing.T) {
+		b := &countingBackend{Backend: inner}
+		data, err := io.ReadAll(io.NewSectionReader(NewReaderAt(ctx, b, "user/object", int64(len(content))), 0, int64(len(content))))
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+		assert.Equal(t, 3, b.reads, "64 KiB, 128 KiB, then the last 10 bytes")
+	})
+
+	t.Run("reads past the end", func(t *testing.T) {
+		r := NewReaderAt(ctx, inner, "user/object", int64(len(content)))
+		p := make([]byte, 20)
+		n, err := r.ReadAt(p, int64(len(content))-10)
+		assert.Equal(t, 10, n)
+		assert.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, content[len(content)-10:], p[:n])
+
+		_, err = r.ReadAt(p, int64(len(content)))
+		assert.ErrorIs(t, err, io.EOF)
+		_, err = r.ReadAt(p, -1)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		_, err := NewReaderAt(ctx, inner, "user/missing", 10).ReadAt(make([]byte, 5), 0)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,569 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-drive/internal/sigv4"
+)
+
+const (
+	// s3PartSize is the chunk size used for multipart uploads of unknown length
+	s3PartSize = 16 << 20
+	// s3MaxCopySize is the largest object a single CopyObject can copy
+	s3MaxCopySize = 5 << 30
+	// s3CopyPartSize is the part size objects larger than s3MaxCopySize are copied in
+	s3CopyPartSize = 1 << 30
+)
+
+// S3Backend stores objects in a bucket of an S3-compatible service such as MinIO.
+// Requests use path-style addressing so any endpoint works without DNS setup.
+type S3Backend struct {
+	client   *http.Client
+	endpoint *url.URL
+	region   string
+	bucket   string
+	creds    sigv4.Credentials
+}
+
+// NewS3Backend creates a backend for cfg.S3Bucket, creating the bucket if it does not exist
+func NewS3Backend(ctx context.Context, cfg Config) (*S3Backend, error) {
+	if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+
+	scheme := "http"
+	if cfg.S3UseSSL {
+		scheme = "https"
+	}
+	endpoint, err := url.Parse(fmt.Sprintf("%s://%s", scheme, strings.TrimSuffix(cfg.S3Endpoint, "/")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+
+	region := cfg.S3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	b := &S3Backend{
+		client:   &http.Client{},
+		endpoint: endpoint,
+		region:   region,
+		bucket:   cfg.S3Bucket,
+		creds:    sigv4.Credentials{AccessKey: cfg.S3AccessKey, SecretKey: cfg.S3SecretKey},
+	}
+
+	if err := b.ensureBucket(ctx); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (b *S3Backend) Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if size < 0 || size > s3PartSize {
+		if err := b.putMultipart(ctx, key, r); err != nil {
+			return ObjectInfo{}, err
+		}
+		return b.Stat(ctx, key)
+	}
+
+	resp, err := b.do(ctx, http.MethodPut, key, nil, http.Header{}, r, size)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	resp.Body.Close()
+
+	return b.Stat(ctx, key)
+}
+
+func (b *S3Backend) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	resp, err := b.do(ctx, http.MethodGet, key, nil, http.Header{}, nil, 0)
+	if err != nil {
+		return nil, ObjectInfo{}, b.wrapError(key, err)
+	}
+
+	return resp.Body, s3ObjectInfo(key, resp), nil
+}
+
+func (b *S3Backend) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if offset < 0 {
+		return nil, ObjectInfo{}, fmt.Errorf("%w: offset %d of %s", ErrInvalidRange, offset, key)
+	}
+	if offset == 0 && length < 0 {
+		return b.Get(ctx, key)
+	}
+	if length == 0 {
+		return b.emptyRange(ctx, key, offset)
+	}
+
+	header := http.Header{}
+	if length < 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	} else {
+		header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	}
+
+	resp, err := b.do(ctx, http.MethodGet, key, nil, header, nil, 0)
+	if err != nil {
+		// S3 rejects ranges starting at the end of the object, which are valid but empty
+		var s3Err *s3Error
+		if errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return b.emptyRange(ctx, key, offset)
+		}
+		return nil, ObjectInfo{}, b.wrapError(key, err)
+	}
+
+	info := s3ObjectInfo(key, resp)
+	if _, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+		if size, err := strconv.ParseInt(total, 10, 64); err == nil {
+			info.Size = size
+		}
+	}
+
+	return resp.Body, info, nil
+}
+
+// emptyRange returns an empty reader if offset is within or at the end of the object
+func (b *S3Backend) emptyRange(ctx context.Context, key string, offset int64) (io.ReadCloser, ObjectInfo, error) {
+	info, err := b.Stat(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if offset > info.Size {
+		return nil, ObjectInfo{}, fmt.Errorf("%w: offset %d of %s", ErrInvalidRange, offset, key)
+	}
+	return io.NopCloser(strings.NewReader("")), info, nil
+}
+
+func (b *S3Backend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	resp, err := b.do(ctx, http.MethodHead, key, nil, http.Header{}, nil, 0)
+	if err != nil {
+		return ObjectInfo{}, b.wrapError(key, err)
+	}
+	resp.Body.Close()
+
+	return s3ObjectInfo(key, resp), nil
+}
+
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	resp, err := b.do(ctx, http.MethodDelete, key, nil, http.Header{}, nil, 0)
+	if err != nil {
+		var s3Err *s3Error
+		if errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Move copies the object server side and then deletes the source. CopyObject is limited
+// to s3MaxCopySize, so larger objects, such as encrypted content at the upload limit, are
+// copied in parts with UploadPartCopy.
+func (b *S3Backend) Move(ctx context.Context, src, dst string) error {
+	if err := validateKey(src); err != nil {
+		return err
+	}
+	if err := validateKey(dst); err != nil {
+		return err
+	}
+
+	info, err := b.Stat(ctx, src)
+	if err != nil {
+		return err
+	}
+	if info.Size > s3MaxCopySize {
+		err = b.copyMultipart(ctx, src, dst, info.Size, s3CopyPartSize)
+	} else {
+		err = b.copyObject(ctx, src, dst)
+	}
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(ctx, src)
+}
+
+// copyObject copies src to dst with a single CopyObject
+func (b *S3Backend) copyObject(ctx context.Context, src, dst string) error {
+	header := http.Header{}
+	header.Set("x-amz-copy-source", b.copySource(src))
+	resp, err := b.do(ctx, http.MethodPut, dst, nil, header, nil, 0)
+	if err != nil {
+		return b.wrapCopyError(src, dst, err)
+	}
+	defer resp.Body.Close()
+
+	// Like multipart completion, CopyObject can report an error in a 200 response body
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read copy result: %w", err)
+	}
+	if bytes.Contains(data, []byte("<Error>")) {
+		return fmt.Errorf("failed to copy object %s to %s: %w", src, dst, parseS3Error(resp.StatusCode, data))
+	}
+
+	return nil
+}
+
+// copyMultipart copies the size bytes of src to dst in parts of partSize, aborting the
+// upload on failure
+func (b *S3Backend) copyMultipart(ctx context.Context, src, dst string, size, partSize int64) error {
+	uploadID, err := b.createMultipart(ctx, dst)
+	if err != nil {
+		return err
+	}
+
+	var parts []s3CompletedPart
+	for offset, partNumber := int64(0), 1; offset < size || partNumber == 1; offset, partNumber = offset+partSize, partNumber+1 {
+		last := min(offset+partSize, size) - 1
+		header := http.Header{}
+		header.Set("x-amz-copy-source", b.copySource(src))
+		if size > 0 {
+			header.Set("x-amz-copy-source-range", fmt.Sprintf("bytes=%d-%d", offset, last))
+		}
+
+		etag, err := b.copyPart(ctx, src, dst, uploadID, partNumber, header)
+		if err != nil {
+			b.abortMultipart(ctx, dst, uploadID)
+			return err
+		}
+		parts = append(parts, s3CompletedPart{PartNumber: partNumber, ETag: etag})
+	}
+
+	if err := b.completeMultipart(ctx, dst, uploadID, parts); err != nil {
+		b.abortMultipart(ctx, dst, uploadID)
+		return err
+	}
+	return nil
+}
+
+// copyPart copies a range of src into a part of a multipart upload to dst, returning the
+// part's ETag
+func (b *S3Backend) copyPart(ctx context.Context, src, dst, uploadID string, partNumber int, header http.Header) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+	resp, err := b.do(ctx, http.MethodPut, dst, query, header, nil, 0)
+	if err != nil {
+		return "", b.wrapCopyError(src, dst, err)
+	}
+	defer resp.Body.Close()
+
+	// The part's ETag is in the body, which like CopyObject's can also hold an error
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read part copy result: %w", err)
+	}
+	if bytes.Contains(data, []byte("<Error>")) {
+		return "", fmt.Errorf("failed to copy part %d of %s to %s: %w", partNumber, src, dst, parseS3Error(resp.StatusCode, data))
+	}
+	var result struct {
+		ETag string `xml:"ETag"`
+	}
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("failed to decode part copy result: %w", err)
+	}
+	return result.ETag, nil
+}
+
+// copySource returns the x-amz-copy-source header naming key in the bucket
+func (b *S3Backend) copySource(key string) string {
+	return sigv4.EncodePath("/" + b.bucket + "/" + key)
+}
+
+func (b *S3Backend) wrapCopyError(src, dst string, err error) error {
+	var s3Err *s3Error
+	if errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, src)
+	}
+	return fmt.Errorf("failed to copy object %s to %s: %w", src, dst, err)
+}
+
+func (b *S3Backend) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	token := ""
+
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := b.do(ctx, http.MethodGet, "", query, http.Header{}, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		var result struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object listing: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, ObjectInfo{Key: c.Key, Size: c.Size, LastModified: c.LastModified.UTC()})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// s3CompletedPart is a part listed when completing a multipart upload
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// putMultipart uploads r in s3PartSize chunks, aborting the upload on failure
+func (b *S3Backend) putMultipart(ctx context.Context, key string, r io.Reader) error {
+	uploadID, err := b.createMultipart(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err := b.uploadParts(ctx, key, uploadID, r); err != nil {
+		b.abortMultipart(ctx, key, uploadID)
+		return err
+	}
+
+	return nil
+}
+
+// createMultipart starts a multipart upload to key, returning its upload ID
+func (b *S3Backend) createMultipart(ctx context.Context, key string) (string, error) {
+	resp, err := b.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, http.Header{}, nil, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to start multipart upload for %s: %w", key, err)
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode multipart upload: %w", err)
+	}
+	return initiated.UploadID, nil
+}
+
+// abortMultipart discards a failed multipart upload, even when ctx is done
+func (b *S3Backend) abortMultipart(ctx context.Context, key, uploadID string) {
+	abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	if resp, err := b.do(abortCtx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, http.Header{}, nil, 0); err == nil {
+		resp.Body.Close()
+	}
+}
+
+func (b *S3Backend) uploadParts(ctx context.Context, key, uploadID string, r io.Reader) error {
+	var parts []s3CompletedPart
+	buf := make([]byte, s3PartSize)
+
+	for partNumber := 1; ; partNumber++ {
+		n, readErr := io.ReadFull(r, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return fmt.Errorf("failed to read upload content: %w", readErr)
+		}
+		// S3 needs at least one part, even for an empty object
+		if n == 0 && len(parts) > 0 {
+			break
+		}
+
+		query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+		resp, err := b.do(ctx, http.MethodPut, key, query, http.Header{}, bytes.NewReader(buf[:n]), int64(n))
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d of %s: %w", partNumber, key, err)
+		}
+		resp.Body.Close()
+		parts = append(parts, s3CompletedPart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")})
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	return b.completeMultipart(ctx, key, uploadID, parts)
+}
+
+// completeMultipart joins the parts of a multipart upload into the object at key
+func (b *S3Backend) completeMultipart(ctx context.Context, key, uploadID string, parts []s3CompletedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return fmt.Errorf("failed to encode multipart completion: %w", err)
+	}
+
+	resp, err := b.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, http.Header{}, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload for %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	// CompleteMultipartUpload can report an error in a 200 response body
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read multipart completion: %w", err)
+	}
+	if bytes.Contains(data, []byte("<Error>")) {
+		return fmt.Errorf("failed to complete multipart upload for %s: %w", key, parseS3Error(resp.StatusCode, data))
+	}
+
+	return nil
+}
+
+func (b *S3Backend) ensureBucket(ctx context.Context) error {
+	resp, err := b.do(ctx, http.MethodHead, "", nil, http.Header{}, nil, 0)
+	if err == nil {
+		resp.Body.Close()
+		return nil
+	}
+
+	var s3Err *s3Error
+	if !errors.As(err, &s3Err) || s3Err.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to check bucket %s: %w", b.bucket, err)
+	}
+
+	resp, err = b.do(ctx, http.MethodPut, "", nil, http.Header{}, nil, 0)
+	if err != nil {
+		if errors.As(err, &s3Err) && s3Err.Code == "BucketAlreadyOwnedByYou" {
+			return nil
+		}
+		return fmt.Errorf("failed to create bucket %s: %w", b.bucket, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// do sends a signed request for key (or the bucket itself when key is empty).
+// Responses outside the 2xx range are returned as *s3Error.
+func (b *S3Backend) do(ctx context.Context, method, key string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u := *b.endpoint
+	u.Path = "/" + b.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = sigv4.EncodePath(u.Path)
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	payloadHash := sigv4.EmptyPayloadHash
+	if body != nil {
+		req.ContentLength = size
+		payloadHash = sigv4.UnsignedPayload
+		// In-memory bodies (parts and control documents) are cheap to hash up front
+		if buffered, ok := body.(*bytes.Reader); ok {
+			sum := sha256.New()
+			if _, err := buffered.WriteTo(sum); err != nil {
+				return nil, err
+			}
+			if _, err := buffered.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			payloadHash = hex.EncodeToString(sum.Sum(nil))
+		}
+	}
+	sigv4.SignRequest(req, b.creds, b.region, "s3", payloadHash, time.Now())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, parseS3Error(resp.StatusCode, data)
+	}
+
+	return resp, nil
+}
+
+func (b *S3Backend) wrapError(key string, err error) error {
+	var s3Err *s3Error
+	if errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return fmt.Errorf("failed to access object %s: %w", key, err)
+}
+
+func s3ObjectInfo(key string, resp *http.Response) ObjectInfo {
+	info := ObjectInfo{Key: key, Size: resp.ContentLength}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = t.UTC()
+	}
+	return info
+}
+
+// s3Error is an error response returned by the S3 service
+type s3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3 request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("s3 request failed with status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func parseS3Error(statusCode int, data []byte) error {
+	e := &s3Error{}
+	// HEAD responses and some proxies carry no XML body
+	_ = xml.Unmarshal(data, e)
+	e.StatusCode = statusCode
+	return e
+}
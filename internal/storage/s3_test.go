@@ -0,0 +1,101 @@
+//go:build integration
+// +build integration
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// This test requires Docker
+// Run with: go test -tags=integration -v ./internal/storage/...
+
+const (
+	minioUser     = "minioadmin"
+	minioPassword = "minioadmin"
+)
+
+func startMinio(t *testing.T) string {
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "minio/minio:latest",
+			Cmd:          []string{"server", "/data"},
+			ExposedPorts: []string{"9000/tcp"},
+			Env: map[string]string{
+				"MINIO_ROOT_USER":     minioUser,
+				"MINIO_ROOT_PASSWORD": minioPassword,
+			},
+			WaitingFor: wait.ForHTTP("/minio/health/ready").
+				WithPort("9000/tcp").
+				WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err, "could not start minio container")
+	t.Cleanup(func() {
+		_ = container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "9000/tcp")
+	require.NoError(t, err)
+
+	return fmt.Sprintf("%s:%s", host, port.Port())
+}
+
+func TestS3Backend(t *testing.T) {
+	endpoint := startMinio(t)
+
+	b, err := NewS3Backend(context.Background(), Config{
+		Driver:      DriverS3,
+		S3Endpoint:  endpoint,
+		S3Bucket:    "go-drive-test",
+		S3AccessKey: minioUser,
+		S3SecretKey: minioPassword,
+	})
+	require.NoError(t, err)
+
+	testBackend(t, b)
+
+	t.Run("multipart upload", func(t *testing.T) {
+		ctx := context.Background()
+		content := bytes.Repeat([]byte{0xAB}, s3PartSize+1024)
+
+		info, err := b.Put(ctx, "user-4/large", bytes.NewReader(content), -1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), info.Size)
+	})
+
+	t.Run("multipart copy", func(t *testing.T) {
+		ctx := context.Background()
+		content := make([]byte, s3PartSize+1024)
+		for i := range content {
+			content[i] = byte(i)
+		}
+		_, err := b.Put(ctx, "user-4/copy-src", bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+
+		// Parts may be as small as 5 MiB, so copying in them takes several
+		require.NoError(t, b.copyMultipart(ctx, "user-4/copy-src", "user-4/copy-dst", int64(len(content)), 5<<20))
+
+		rc, _, err := b.Get(ctx, "user-4/copy-dst")
+		require.NoError(t, err)
+		defer rc.Close()
+		copied, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.True(t, bytes.Equal(content, copied))
+	})
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go-drive/internal/keyring"
+)
+
+// ErrNotFound is returned when no object exists under the requested key
+var ErrNotFound = errors.New("object not found")
+
+// ErrInvalidKey is returned for keys that are empty or would escape the backend's namespace
+var ErrInvalidKey = errors.New("invalid object key")
+
+// ErrInvalidRange is returned when a ranged read starts outside the object
+var ErrInvalidRange = errors.New("invalid object range")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Backend stores blob content addressed by key.
+// Keys are slash-separated relative paths such as "<user_id>/<file_id>".
+type Backend interface {
+	// Put stores the content of r under key, replacing any existing object.
+	// size is the number of bytes r yields, or -1 if unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error)
+	// Get opens the object stored under key. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	// GetRange opens up to length bytes of the object starting at offset; a negative length
+	// reads to the end. The returned ObjectInfo describes the whole object.
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error)
+	// Stat returns the object's metadata without reading its content
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// Move renames the object under src to dst, replacing any existing object at dst
+	Move(ctx context.Context, src, dst string) error
+	// List returns all objects whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// Driver names accepted by Config.Driver
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Config holds storage backend configuration
+type Config struct {
+	Driver string
+
+	// Local driver
+	LocalRoot string
+
+	// S3 driver
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+
+	// Keyring turns on encryption at rest: when set, objects are encrypted with data keys
+	// wrapped by its active master key
+	Keyring *keyring.Keyring
+}
+
+// New creates the backend selected by cfg.Driver
+func New(ctx context.Context, cfg Config) (Backend, error) {
+	var b Backend
+	var err error
+	switch cfg.Driver {
+	case DriverLocal, "":
+		b, err = NewLocalBackend(cfg.LocalRoot)
+	case DriverS3:
+		b, err = NewS3Backend(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Keyring != nil {
+		return NewEncryptedBackend(b, cfg.Keyring), nil
+	}
+	return b, nil
+}
+
+// validateKey rejects keys that are empty, absolute or contain path traversal segments
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackend runs the behaviour every Backend implementation must share
+func testBackend(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	t.Run("put and get", func(t *testing.T) {
+		info, err := b.Put(ctx, "user-1/file-1", strings.NewReader("hello world"), 11)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1/file-1", info.Key)
+		assert.Equal(t, int64(11), info.Size)
+		assert.False(t, info.LastModified.IsZero())
+
+		rc, info, err := b.Get(ctx, "user-1/file-1")
+		require.NoError(t, err)
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(data))
+		assert.Equal(t, int64(11), info.Size)
+	})
+
+	t.Run("put with unknown size", func(t *testing.T) {
+		content := bytes.Repeat([]byte("go-drive"), 1024)
+		info, err := b.Put(ctx, "user-1/file-2", bytes.NewReader(content), -1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), info.Size)
+	})
+
+	t.Run("put replaces existing object", func(t *testing.T) {
+		_, err := b.Put(ctx, "user-1/file-3", strings.NewReader("first"), 5)
+		require.NoError(t, err)
+		_, err = b.Put(ctx, "user-1/file-3", strings.NewReader("second"), 6)
+		require.NoError(t, err)
+
+		info, err := b.Stat(ctx, "user-1/file-3")
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), info.Size)
+	})
+
+	t.Run("ranged reads", func(t *testing.T) {
+		_, err := b.Put(ctx, "user-1/range", strings.NewReader("0123456789"), 10)
+		require.NoError(t, err)
+
+		tests := []struct {
+			offset, length int64
+			expected       string
+		}{
+			{0, -1, "0123456789"},
+			{3, 4, "3456"},
+			{7, -1, "789"},
+			{8, 100, "89"},
+			{10, -1, ""},
+			{2, 0, ""},
+		}
+		for _, tt := range tests {
+			rc, info, err := b.GetRange(ctx, "user-1/range", tt.offset, tt.length)
+			require.NoError(t, err, "offset %d length %d", tt.offset, tt.length)
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data), "offset %d length %d", tt.offset, tt.length)
+			assert.Equal(t, int64(10), info.Size)
+		}
+
+		_, _, err = b.GetRange(ctx, "user-1/range", 11, -1)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+
+		_, _, err = b.GetRange(ctx, "user-1/missing", 0, 1)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		_, err := b.Stat(ctx, "user-1/missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, _, err = b.Get(ctx, "user-1/missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		assert.NoError(t, b.Delete(ctx, "user-1/missing"))
+	})
+
+	t.Run("list by prefix", func(t *testing.T) {
+		for _, key := range []string{"user-2/a", "user-2/b", "user-2/nested/c", "user-20/d"} {
+			_, err := b.Put(ctx, key, strings.NewReader(key), int64(len(key)))
+			require.NoError(t, err)
+		}
+
+		objects, err := b.List(ctx, "user-2/")
+		require.NoError(t, err)
+		keys := make([]string, len(objects))
+		for i, obj := range objects {
+			keys[i] = obj.Key
+		}
+		assert.Equal(t, []string{"user-2/a", "user-2/b", "user-2/nested/c"}, keys)
+
+		objects, err = b.List(ctx, "user-2")
+		require.NoError(t, err)
+		assert.Len(t, objects, 4)
+
+		objects, err = b.List(ctx, "nobody/")
+		require.NoError(t, err)
+		assert.Empty(t, objects)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := b.Put(ctx, "user-3/file", strings.NewReader("bye"), 3)
+		require.NoError(t, err)
+
+		require.NoError(t, b.Delete(ctx, "user-3/file"))
+
+		_, err = b.Stat(ctx, "user-3/file")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		_, err := b.Put(ctx, "user-4/staged", strings.NewReader("moved"), 5)
+		require.NoError(t, err)
+		_, err = b.Put(ctx, "blobs/ab/abc", strings.NewReader("old"), 3)
+		require.NoError(t, err)
+
+		require.NoError(t, b.Move(ctx, "user-4/staged", "blobs/ab/abc"))
+
+		_, err = b.Stat(ctx, "user-4/staged")
+		assert.ErrorIs(t, err, ErrNotFound)
+		rc, _, err := b.Get(ctx, "blobs/ab/abc")
+		require.NoError(t, err)
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, "moved", string(data))
+
+		assert.ErrorIs(t, b.Move(ctx, "user-4/missing", "blobs/ab/abd"), ErrNotFound)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, key := range []string{"", "/abs", "../escape", "a/../b", "a//b", `a\b`} {
+			_, err := b.Put(ctx, key, strings.NewReader("x"), 1)
+			assert.ErrorIs(t, err, ErrInvalidKey, key)
+		}
+	})
+}
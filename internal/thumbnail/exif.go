@@ -0,0 +1,121 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// exifHeader prefixes the EXIF data of JPEG APP1 segments, and sometimes of WebP EXIF chunks
+var exifHeader = []byte("Exif\x00\x00")
+
+// orientationTag is the EXIF tag of the image orientation
+const orientationTag = 0x0112
+
+// orientation returns the EXIF orientation of a JPEG, PNG or WebP image, from 1 (upright)
+// to 8, or 1 when the image has none
+func orientation(data []byte) int {
+	var exif []byte
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xd8")):
+		exif = jpegExif(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		exif = pngExif(data)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		exif = webpExif(data)
+	}
+	if o := tiffOrientation(bytes.TrimPrefix(exif, exifHeader)); o >= 1 && o <= 8 {
+		return o
+	}
+	return 1
+}
+
+// jpegExif returns the EXIF data of the APP1 segment that precedes the image data
+func jpegExif(data []byte) []byte {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			return nil
+		}
+		marker := data[i+1]
+		if marker == 0xda || marker == 0xd9 { // start of scan, end of image
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+		if segment := data[i+4 : end]; marker == 0xe1 && bytes.HasPrefix(segment, exifHeader) {
+			return segment
+		}
+		i = end
+	}
+	return nil
+}
+
+// pngExif returns the content of the eXIf chunk
+func pngExif(data []byte) []byte {
+	for i := 8; i+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 8 + length
+		if length < 0 || end+4 > len(data) {
+			return nil
+		}
+		if string(data[i+4:i+8]) == "eXIf" {
+			return data[i+8 : end]
+		}
+		i = end + 4 // skip the CRC
+	}
+	return nil
+}
+
+// webpExif returns the content of the EXIF chunk of a RIFF container
+func webpExif(data []byte) []byte {
+	for i := 12; i+8 <= len(data); {
+		length := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + length
+		if length < 0 || end > len(data) {
+			return nil
+		}
+		if string(data[i:i+4]) == "EXIF" {
+			return data[i+8 : end]
+		}
+		i = end + length%2 // chunks are padded to an even length
+	}
+	return nil
+}
+
+// tiffOrientation reads the orientation tag of the first IFD of TIFF-structured EXIF data.
+// It returns 0 when the tag is missing or the data is malformed.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
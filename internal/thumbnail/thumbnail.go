@@ -0,0 +1,155 @@
+// Package thumbnail renders small JPEG previews of JPEG, PNG, GIF and WebP images,
+// turned upright according to their EXIF orientation. Only pure-Go decoders are used.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+	"io"
+	"mime"
+	"sort"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// ContentType is the MIME type of rendered thumbnails
+const ContentType = "image/jpeg"
+
+const (
+	// MaxSourceSize is the largest image Render reads. Images are decoded in memory.
+	MaxSourceSize = 64 << 20
+	// MaxPixels caps the decoded size of an image, so that small files with huge
+	// dimensions cannot exhaust memory
+	MaxPixels = 64_000_000
+	// jpegQuality is the quality thumbnails are encoded with
+	jpegQuality = 80
+)
+
+var (
+	// ErrUnsupported is returned for content that is not a supported image
+	ErrUnsupported = errors.New("image format not supported")
+	// ErrTooLarge is returned for images over MaxSourceSize bytes or MaxPixels pixels
+	ErrTooLarge = errors.New("image is too large")
+)
+
+var supportedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Supported reports whether Render handles images of the MIME type
+func Supported(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	return err == nil && supportedTypes[mediaType]
+}
+
+// Render decodes the image read from r and returns a JPEG thumbnail for each size, fitting
+// in a size × size box. Images are never enlarged; GIFs are rendered from their first frame.
+func Render(r io.Reader, sizes []int) (map[int][]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxSourceSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxSourceSize {
+		return nil, ErrTooLarge
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, ErrUnsupported
+		}
+		return nil, fmt.Errorf("failed to read image header: %w", err)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	orient := orientation(data)
+
+	// Each size is scaled down from the next larger one, which is much cheaper than
+	// scaling every size from a large original
+	descending := append([]int(nil), sizes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(descending)))
+
+	thumbnails := make(map[int][]byte, len(sizes))
+	for _, size := range descending {
+		img = fit(img, size)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, reorient(img, orient), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		thumbnails[size] = buf.Bytes()
+	}
+	return thumbnails, nil
+}
+
+// fit scales img down to fit in a size × size box, on a white background so that
+// transparent areas do not turn black in the JPEG
+func fit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// reorient turns an image upright according to its EXIF orientation (1-8)
+func reorient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // flip horizontally
+				sx, sy = w-1-x, y
+			case 3: // turn 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // flip vertically
+				sx, sy = x, h-1-y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // turn 90° clockwise
+				sx, sy = y, h-1-x
+			case 7: // transverse
+				sx, sy = w-1-y, h-1-x
+			case 8: // turn 90° counterclockwise
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
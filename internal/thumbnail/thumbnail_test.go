@@ -0,0 +1,155 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// halves returns a width × height image, red on the left and blue on the right
+func halves(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// exifTIFF returns little-endian TIFF data holding only an orientation tag
+func exifTIFF(orientation uint16) []byte {
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientationTag)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // value padding, no next IFD
+	return tiff
+}
+
+// jpegWithOrientation encodes img as a JPEG with an EXIF orientation
+func jpegWithOrientation(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+
+	segment := append(append([]byte(nil), exifHeader...), exifTIFF(orientation)...)
+	app1 := []byte{0xff, 0xe1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := buf.Bytes()
+	return append(append(append([]byte(nil), data[:2]...), app1...), data[2:]...)
+}
+
+func decode(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	return img
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xc000 && g < 0x4000 && b < 0x4000
+}
+
+func TestSupported(t *testing.T) {
+	assert.True(t, Supported("image/jpeg"))
+	assert.True(t, Supported("image/webp"))
+	assert.True(t, Supported("image/png; charset=binary"))
+	assert.False(t, Supported("image/svg+xml"))
+	assert.False(t, Supported("application/pdf"))
+}
+
+func TestRender(t *testing.T) {
+	t.Run("sizes fit the box", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, halves(400, 200)))
+
+		thumbnails, err := Render(&buf, []int{50, 100, 800})
+
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 50, 25), decode(t, thumbnails[50]).Bounds())
+		assert.Equal(t, image.Rect(0, 0, 100, 50), decode(t, thumbnails[100]).Bounds())
+		// Never enlarged
+		assert.Equal(t, image.Rect(0, 0, 400, 200), decode(t, thumbnails[800]).Bounds())
+	})
+
+	t.Run("turned upright", func(t *testing.T) {
+		data := jpegWithOrientation(t, halves(80, 40), 6)
+
+		thumbnails, err := Render(bytes.NewReader(data), []int{40})
+
+		require.NoError(t, err)
+		img := decode(t, thumbnails[40])
+		assert.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+		// Turned clockwise, the red left half ends up on top
+		assert.True(t, isRed(img.At(10, 5)))
+		assert.False(t, isRed(img.At(10, 35)))
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		_, err := Render(bytes.NewReader([]byte("%PDF-1.4")), []int{40})
+		assert.ErrorIs(t, err, ErrUnsupported)
+	})
+
+	t.Run("too many pixels", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 10000, 6401))))
+
+		_, err := Render(&buf, []int{40})
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestOrientation(t *testing.T) {
+	webp := func(chunk []byte) []byte {
+		data := []byte("RIFF\x00\x00\x00\x00WEBPVP8X")
+		data = binary.LittleEndian.AppendUint32(data, 10)
+		data = append(data, make([]byte, 10)...)
+		data = append(data, "EXIF"...)
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(chunk)))
+		return append(data, chunk...)
+	}
+	pngData := func(chunk []byte) []byte {
+		data := []byte("\x89PNG\r\n\x1a\n")
+		data = binary.BigEndian.AppendUint32(data, uint32(len(chunk)))
+		data = append(data, "eXIf"...)
+		data = append(data, chunk...)
+		return append(data, 0, 0, 0, 0)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected int
+	}{
+		{name: "jpeg", data: jpegWithOrientation(t, halves(2, 2), 8), expected: 8},
+		{name: "jpeg without exif", data: []byte("\xff\xd8\xff\xda"), expected: 1},
+		{name: "png", data: pngData(exifTIFF(3)), expected: 3},
+		{name: "webp", data: webp(exifTIFF(6)), expected: 6},
+		{name: "webp with exif header", data: webp(append(append([]byte(nil), exifHeader...), exifTIFF(5)...)), expected: 5},
+		{name: "out of range", data: webp(exifTIFF(9)), expected: 1},
+		{name: "truncated", data: webp(exifTIFF(6)[:12]), expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, orientation(tt.data))
+		})
+	}
+}
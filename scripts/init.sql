@@ -4,6 +4,13 @@
 -- Enable required extensions
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Words of a file or folder name for search; dots, dashes and underscores separate words
+CREATE OR REPLACE FUNCTION name_search_vector(name TEXT)
+RETURNS tsvector AS $$
+    SELECT to_tsvector('simple'::regconfig, translate(name, '._-', '   '));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
 
 -- Create service roles for microservices
 DO $$
@@ -61,6 +68,14 @@ CREATE TABLE IF NOT EXISTS files (
     mime_type VARCHAR(100),
     storage_key VARCHAR(500) NOT NULL,
     checksum VARCHAR(64),
+    -- mime_type is detected from the content; declared_mime_type is what the client claimed
+    declared_mime_type VARCHAR(100),
+    mime_mismatch BOOLEAN NOT NULL DEFAULT false,
+    -- Quarantined content is kept but cannot be downloaded
+    quarantined BOOLEAN NOT NULL DEFAULT false,
+    quarantine_reason TEXT,
+    -- Only content whose malware scan came back clean can be downloaded or shared
+    scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP WITH TIME ZONE,
@@ -70,6 +85,18 @@ CREATE TABLE IF NOT EXISTS files (
 CREATE INDEX IF NOT EXISTS idx_files_user_id ON files(user_id) WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_files_folder_id ON files(folder_id) WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_files_created_at ON files(created_at DESC) WHERE deleted_at IS NULL;
+-- Trash listing and the retention purge
+CREATE INDEX IF NOT EXISTS idx_files_trash ON files(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_files_deleted_at ON files(deleted_at) WHERE deleted_at IS NOT NULL;
+-- Live files in the same folder must have distinct names; the root has no folder_id
+CREATE UNIQUE INDEX IF NOT EXISTS idx_files_unique_name
+    ON files(user_id, COALESCE(folder_id, '00000000-0000-0000-0000-000000000000'), name)
+    WHERE deleted_at IS NULL;
+-- Name search by word, substring and similarity
+CREATE INDEX IF NOT EXISTS idx_files_name_search ON files USING GIN (name_search_vector(name)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_files_name_trgm ON files USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL;
+-- Quarantined files are listed for admins
+CREATE INDEX IF NOT EXISTS idx_files_quarantined ON files(updated_at DESC) WHERE quarantined;
 
 -- Folders table (for future file service)
 CREATE TABLE IF NOT EXISTS folders (
@@ -85,6 +112,222 @@ CREATE TABLE IF NOT EXISTS folders (
 
 CREATE INDEX IF NOT EXISTS idx_folders_user_id ON folders(user_id) WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_folders_parent_id ON folders(parent_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_folders_trash ON folders(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_folders_deleted_at ON folders(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_unique_name
+    ON folders(user_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), name)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_folders_name_search ON folders USING GIN (name_search_vector(name)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_folders_name_trgm ON folders USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL;
+
+-- Earlier contents of files, kept when a file's content is replaced
+CREATE TABLE IF NOT EXISTS file_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    checksum VARCHAR(64),
+    -- Outcome of the malware scan of the content; only clean versions can be downloaded
+    scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT file_versions_size_check CHECK (size >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_versions_file_id ON file_versions(file_id, created_at DESC);
+
+-- Content stored once per checksum and shared by files and file versions.
+-- ref_count is maintained by the count_blob_refs triggers below.
+CREATE TABLE IF NOT EXISTS blobs (
+    checksum VARCHAR(64) PRIMARY KEY,
+    size BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT blobs_size_check CHECK (size >= 0),
+    CONSTRAINT blobs_ref_count_check CHECK (ref_count >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blobs_unreferenced ON blobs(updated_at) WHERE ref_count = 0;
+
+-- Bytes used by each user's files and file versions, including the trash.
+-- used_bytes is maintained by the track_storage_usage triggers below.
+CREATE TABLE IF NOT EXISTS storage_usage (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    used_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Searchable text of each file's current content. Rows are queued by the queue_content_index
+-- trigger below whenever a file's checksum changes; the file service extracts the text in the
+-- background, retrying failures until next_attempt_at.
+CREATE TABLE IF NOT EXISTS file_content_index (
+    file_id UUID PRIMARY KEY REFERENCES files(id) ON DELETE CASCADE,
+    checksum VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    indexed_at TIMESTAMP WITH TIME ZONE,
+    content TSVECTOR,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_content_index_pending
+    ON file_content_index(next_attempt_at NULLS FIRST) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_file_content_index_content ON file_content_index USING GIN (content);
+
+-- Thumbnail state of each image file's current content. Rows are queued by the queue_thumbnails
+-- trigger below; the file service renders the thumbnails in the background and stores them as
+-- blobs next to the content.
+CREATE TABLE IF NOT EXISTS file_thumbnails (
+    file_id UUID PRIMARY KEY REFERENCES files(id) ON DELETE CASCADE,
+    checksum VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    generated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_thumbnails_pending
+    ON file_thumbnails(next_attempt_at NULLS FIRST) WHERE status = 'pending';
+
+-- Malware scan state of each content a file holds, current or kept as a version. Rows are
+-- queued by the queue_file_scan trigger below; the file service scans the content in the
+-- background and copies the outcome to files.scan_status and file_versions.scan_status.
+CREATE TABLE IF NOT EXISTS file_scans (
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    checksum VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    scanned_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (file_id, checksum)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_scans_pending
+    ON file_scans(next_attempt_at NULLS FIRST) WHERE status = 'pending';
+
+-- Files and folders shared with other users. A folder share covers everything below the folder.
+CREATE TABLE IF NOT EXISTS file_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    grantee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_id UUID REFERENCES files(id) ON DELETE CASCADE,
+    folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT file_shares_role_check CHECK (role IN ('viewer', 'commenter', 'editor')),
+    CONSTRAINT file_shares_target_check CHECK ((file_id IS NULL) <> (folder_id IS NULL)),
+    CONSTRAINT file_shares_not_owner_check CHECK (grantee_id <> owner_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_shares_owner_id ON file_shares(owner_id);
+CREATE INDEX IF NOT EXISTS idx_file_shares_grantee_id ON file_shares(grantee_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_file_shares_unique_file ON file_shares(file_id, grantee_id) WHERE file_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_file_shares_unique_folder ON file_shares(folder_id, grantee_id) WHERE folder_id IS NOT NULL;
+
+-- Public links to a file or folder for people without an account. Only a SHA-256 hash of
+-- the token and a bcrypt hash of the optional password are stored.
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_id UUID REFERENCES files(id) ON DELETE CASCADE,
+    folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    password_hash VARCHAR(60),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    max_downloads INTEGER NOT NULL DEFAULT 0,
+    download_count INTEGER NOT NULL DEFAULT 0,
+    preview_only BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT share_links_target_check CHECK ((file_id IS NULL) <> (folder_id IS NULL)),
+    CONSTRAINT share_links_max_downloads_check CHECK (max_downloads >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_owner_id ON share_links(owner_id, created_at DESC);
+
+-- Upload-only links that let people without an account drop files into a folder.
+-- allowed_mime_types is a comma-separated list; empty allows any type.
+CREATE TABLE IF NOT EXISTS file_request_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    folder_id UUID NOT NULL REFERENCES folders(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    title VARCHAR(255),
+    deadline TIMESTAMP WITH TIME ZONE,
+    max_file_size BIGINT NOT NULL DEFAULT 0,
+    allowed_mime_types TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT file_request_links_max_file_size_check CHECK (max_file_size >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_request_links_owner_id ON file_request_links(owner_id, created_at DESC);
+
+-- Who uploaded each file received through a file request link
+CREATE TABLE IF NOT EXISTS file_request_submissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES file_request_links(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL UNIQUE REFERENCES files(id) ON DELETE CASCADE,
+    uploader_name VARCHAR(255) NOT NULL,
+    uploader_email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_request_submissions_request_id ON file_request_submissions(request_id, created_at DESC);
+
+-- Resumable uploads (tus) in progress
+CREATE TABLE IF NOT EXISTS uploads (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    folder_id UUID,
+    mime_type VARCHAR(100),
+    size BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uploads_size_check CHECK (size >= 0),
+    CONSTRAINT uploads_offset_check CHECK (upload_offset >= 0 AND upload_offset <= size)
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploads_user_id ON uploads(user_id);
+CREATE INDEX IF NOT EXISTS idx_uploads_updated_at ON uploads(updated_at);
+
+-- Chunks received for a resumable upload, each stored as its own blob
+CREATE TABLE IF NOT EXISTS upload_parts (
+    upload_id UUID NOT NULL REFERENCES uploads(id) ON DELETE CASCADE,
+    part_offset BIGINT NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (upload_id, part_offset)
+);
+
+-- Access keys for the S3-compatible API. SigV4 signatures can only be checked with the
+-- secret itself, so it is kept, wrapped with the encryption keyring's master key named by
+-- secret_master_key_id when one is configured; only the file service can read it.
+CREATE TABLE IF NOT EXISTS access_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    access_key_id VARCHAR(32) NOT NULL UNIQUE,
+    secret_key TEXT NOT NULL,
+    secret_master_key_id VARCHAR(64) NOT NULL DEFAULT '',
+    name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_keys_user_id ON access_keys(user_id, created_at DESC);
 
 -- Function to update updated_at timestamp
 CREATE OR REPLACE FUNCTION update_updated_at_column()
@@ -108,10 +351,298 @@ DROP TRIGGER IF EXISTS update_folders_updated_at ON folders;
 CREATE TRIGGER update_folders_updated_at BEFORE UPDATE ON folders
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+DROP TRIGGER IF EXISTS update_file_content_index_updated_at ON file_content_index;
+CREATE TRIGGER update_file_content_index_updated_at BEFORE UPDATE ON file_content_index
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_file_thumbnails_updated_at ON file_thumbnails;
+CREATE TRIGGER update_file_thumbnails_updated_at BEFORE UPDATE ON file_thumbnails
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_file_scans_updated_at ON file_scans;
+CREATE TRIGGER update_file_scans_updated_at BEFORE UPDATE ON file_scans
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_uploads_updated_at ON uploads;
+CREATE TRIGGER update_uploads_updated_at BEFORE UPDATE ON uploads
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_file_shares_updated_at ON file_shares;
+CREATE TRIGGER update_file_shares_updated_at BEFORE UPDATE ON file_shares
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_share_links_updated_at ON share_links;
+CREATE TRIGGER update_share_links_updated_at BEFORE UPDATE ON share_links
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_file_request_links_updated_at ON file_request_links;
+CREATE TRIGGER update_file_request_links_updated_at BEFORE UPDATE ON file_request_links
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Function to keep file and folder names unique within a folder.
+-- Unique indexes cover siblings of the same kind; this covers a file and a folder sharing a name.
+CREATE OR REPLACE FUNCTION check_sibling_name()
+RETURNS TRIGGER AS $$
+DECLARE
+    parent UUID;
+BEGIN
+    IF NEW.deleted_at IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF TG_TABLE_NAME = 'files' THEN
+        parent := NEW.folder_id;
+    ELSE
+        parent := NEW.parent_id;
+    END IF;
+
+    -- Serialise writers of the same name in the same folder across both tables
+    PERFORM pg_advisory_xact_lock(hashtextextended(
+        NEW.user_id::text || '/' || COALESCE(parent::text, '') || '/' || NEW.name, 0));
+
+    IF TG_TABLE_NAME = 'files' THEN
+        PERFORM 1 FROM folders
+        WHERE user_id = NEW.user_id AND parent_id IS NOT DISTINCT FROM parent
+            AND name = NEW.name AND deleted_at IS NULL;
+    ELSE
+        PERFORM 1 FROM files
+        WHERE user_id = NEW.user_id AND folder_id IS NOT DISTINCT FROM parent
+            AND name = NEW.name AND deleted_at IS NULL;
+    END IF;
+    IF FOUND THEN
+        RAISE EXCEPTION 'an item named "%" already exists in this folder', NEW.name
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'sibling_name_unique';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Triggers to check names whenever an item is created, renamed, moved or restored
+DROP TRIGGER IF EXISTS check_files_sibling_name ON files;
+CREATE TRIGGER check_files_sibling_name BEFORE INSERT OR UPDATE OF name, folder_id, deleted_at ON files
+    FOR EACH ROW EXECUTE FUNCTION check_sibling_name();
+
+DROP TRIGGER IF EXISTS check_folders_sibling_name ON folders;
+CREATE TRIGGER check_folders_sibling_name BEFORE INSERT OR UPDATE OF name, parent_id, deleted_at ON folders
+    FOR EACH ROW EXECUTE FUNCTION check_sibling_name();
+
+-- Function to count the files and file versions that use each blob.
+-- Only rows stored under their blob key are counted; content uploaded before
+-- deduplication keeps a key of its own.
+CREATE OR REPLACE FUNCTION count_blob_refs()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' AND OLD.storage_key = 'blobs/' || left(OLD.checksum, 2) || '/' || OLD.checksum THEN
+        UPDATE blobs SET ref_count = ref_count - 1, updated_at = CURRENT_TIMESTAMP
+        WHERE checksum = OLD.checksum;
+    END IF;
+
+    IF TG_OP <> 'DELETE' AND NEW.storage_key = 'blobs/' || left(NEW.checksum, 2) || '/' || NEW.checksum THEN
+        INSERT INTO blobs (checksum, size, ref_count) VALUES (NEW.checksum, NEW.size, 1)
+        ON CONFLICT (checksum) DO UPDATE
+        SET ref_count = blobs.ref_count + 1, updated_at = CURRENT_TIMESTAMP;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+-- Triggers to count blob references as content is set, archived and deleted
+DROP TRIGGER IF EXISTS count_files_blob_refs ON files;
+CREATE TRIGGER count_files_blob_refs AFTER INSERT OR UPDATE OF storage_key, checksum OR DELETE ON files
+    FOR EACH ROW EXECUTE FUNCTION count_blob_refs();
+
+DROP TRIGGER IF EXISTS count_file_versions_blob_refs ON file_versions;
+CREATE TRIGGER count_file_versions_blob_refs AFTER INSERT OR UPDATE OF storage_key, checksum OR DELETE ON file_versions
+    FOR EACH ROW EXECUTE FUNCTION count_blob_refs();
+
+-- Function to keep each user's storage usage in step with their files and file versions
+CREATE OR REPLACE FUNCTION track_storage_usage()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        UPDATE storage_usage SET used_bytes = used_bytes - OLD.size, updated_at = CURRENT_TIMESTAMP
+        WHERE user_id = OLD.user_id;
+    END IF;
+
+    IF TG_OP <> 'DELETE' THEN
+        INSERT INTO storage_usage (user_id, used_bytes) VALUES (NEW.user_id, NEW.size)
+        ON CONFLICT (user_id) DO UPDATE
+        SET used_bytes = storage_usage.used_bytes + NEW.size, updated_at = CURRENT_TIMESTAMP;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+-- Triggers to track usage as files are created, replaced and purged
+DROP TRIGGER IF EXISTS track_files_storage_usage ON files;
+CREATE TRIGGER track_files_storage_usage AFTER INSERT OR UPDATE OF size OR DELETE ON files
+    FOR EACH ROW EXECUTE FUNCTION track_storage_usage();
+
+DROP TRIGGER IF EXISTS track_file_versions_storage_usage ON file_versions;
+CREATE TRIGGER track_file_versions_storage_usage AFTER INSERT OR DELETE ON file_versions
+    FOR EACH ROW EXECUTE FUNCTION track_storage_usage();
+
+-- Function to queue a file's content for indexing when it is set or replaced.
+-- Files without content have nothing to index.
+CREATE OR REPLACE FUNCTION queue_content_index()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.checksum, '') = '' THEN
+        DELETE FROM file_content_index WHERE file_id = NEW.id;
+    ELSIF TG_OP = 'INSERT' OR NEW.checksum IS DISTINCT FROM OLD.checksum THEN
+        INSERT INTO file_content_index (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+        ON CONFLICT (file_id) DO UPDATE
+        SET checksum = EXCLUDED.checksum, status = 'pending', attempts = 0, last_error = NULL,
+            next_attempt_at = NULL, indexed_at = NULL, content = NULL;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS queue_files_content_index ON files;
+CREATE TRIGGER queue_files_content_index AFTER INSERT OR UPDATE OF checksum ON files
+    FOR EACH ROW EXECUTE FUNCTION queue_content_index();
+
+-- Function to queue thumbnails of an image when its content is set or replaced.
+-- Other files have no thumbnails.
+CREATE OR REPLACE FUNCTION queue_thumbnails()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.checksum, '') = '' OR COALESCE(NEW.mime_type, '') NOT LIKE 'image/%' THEN
+        DELETE FROM file_thumbnails WHERE file_id = NEW.id;
+    ELSIF TG_OP = 'INSERT' OR NEW.checksum IS DISTINCT FROM OLD.checksum THEN
+        INSERT INTO file_thumbnails (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+        ON CONFLICT (file_id) DO UPDATE
+        SET checksum = EXCLUDED.checksum, status = 'pending', attempts = 0, last_error = NULL,
+            next_attempt_at = NULL, generated_at = NULL;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS queue_files_thumbnails ON files;
+CREATE TRIGGER queue_files_thumbnails AFTER INSERT OR UPDATE OF checksum ON files
+    FOR EACH ROW EXECUTE FUNCTION queue_thumbnails();
+
+-- Function to queue a malware scan when content is committed pending one. Content committed
+-- while scanning is turned off is clean right away. Scans of content the file no longer holds,
+-- as its current content or as a version, are dropped; replaced content keeps its scan.
+CREATE OR REPLACE FUNCTION queue_file_scan()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.checksum, '') <> '' AND NEW.scan_status = 'pending' THEN
+        INSERT INTO file_scans (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+        ON CONFLICT (file_id, checksum) DO UPDATE
+        SET status = 'pending', attempts = 0, last_error = NULL, next_attempt_at = NULL, scanned_at = NULL;
+    END IF;
+
+    DELETE FROM file_scans s
+    WHERE s.file_id = NEW.id AND s.checksum <> COALESCE(NEW.checksum, '')
+        AND NOT EXISTS (SELECT 1 FROM file_versions v WHERE v.file_id = s.file_id AND v.checksum = s.checksum);
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS queue_files_scan ON files;
+CREATE TRIGGER queue_files_scan AFTER INSERT OR UPDATE OF checksum, scan_status ON files
+    FOR EACH ROW EXECUTE FUNCTION queue_file_scan();
+
+-- Function to drop the scan of a deleted version's content once the file no longer holds it
+CREATE OR REPLACE FUNCTION drop_version_scan()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM file_scans s
+    WHERE s.file_id = OLD.file_id AND s.checksum = OLD.checksum
+        AND NOT EXISTS (SELECT 1 FROM files f WHERE f.id = s.file_id AND f.checksum = s.checksum)
+        AND NOT EXISTS (SELECT 1 FROM file_versions v WHERE v.file_id = s.file_id AND v.checksum = s.checksum);
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS drop_file_versions_scan ON file_versions;
+CREATE TRIGGER drop_file_versions_scan AFTER DELETE ON file_versions
+    FOR EACH ROW EXECUTE FUNCTION drop_version_scan();
+
+-- Function to rank access roles, from none (0) to owner (4)
+CREATE OR REPLACE FUNCTION share_role_rank(role VARCHAR)
+RETURNS INTEGER AS $$
+    SELECT CASE role
+        WHEN 'viewer' THEN 1
+        WHEN 'commenter' THEN 2
+        WHEN 'editor' THEN 3
+        WHEN 'owner' THEN 4
+        ELSE 0
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Functions returning a user's role on a folder or file: owner, the best role shared on the
+-- item or any folder above it, or NULL without access. They run as their owner so that the
+-- RLS policies below can call them without recursing into themselves.
+CREATE OR REPLACE FUNCTION folder_access_role(p_user_id UUID, p_folder_id UUID)
+RETURNS VARCHAR AS $$
+    WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id, user_id FROM folders WHERE id = p_folder_id
+        UNION ALL
+        SELECT f.id, f.parent_id, f.user_id FROM folders f JOIN ancestors a ON f.id = a.parent_id
+    )
+    SELECT CASE
+        WHEN bool_or(a.user_id = p_user_id) THEN 'owner'
+        ELSE (array_agg(s.role ORDER BY share_role_rank(s.role) DESC) FILTER (WHERE s.role IS NOT NULL))[1]
+    END
+    FROM ancestors a
+    LEFT JOIN file_shares s ON s.folder_id = a.id AND s.grantee_id = p_user_id;
+$$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public;
+
+CREATE OR REPLACE FUNCTION file_access_role(p_user_id UUID, p_file_id UUID)
+RETURNS VARCHAR AS $$
+    SELECT CASE
+        WHEN f.user_id = p_user_id THEN 'owner'
+        ELSE (
+            SELECT role FROM (
+                SELECT s.role FROM file_shares s WHERE s.file_id = f.id AND s.grantee_id = p_user_id
+                UNION ALL
+                SELECT folder_access_role(p_user_id, f.folder_id)
+            ) roles
+            WHERE role IS NOT NULL
+            ORDER BY share_role_rank(role) DESC
+            LIMIT 1
+        )
+    END
+    FROM files f
+    WHERE f.id = p_file_id;
+$$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public;
+
+-- Function returning the user a transaction acts for, set with set_config('app.user_id', ..., true).
+-- NULL when the file service acts on its own behalf, e.g. when purging the trash.
+CREATE OR REPLACE FUNCTION current_drive_user()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.user_id', true), '')::uuid;
+$$ LANGUAGE sql STABLE;
+
 -- Enable Row Level Security
 ALTER TABLE users ENABLE ROW LEVEL SECURITY;
 ALTER TABLE files ENABLE ROW LEVEL SECURITY;
 ALTER TABLE folders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_versions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE blobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE storage_usage ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_content_index ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_thumbnails ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_scans ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_shares ENABLE ROW LEVEL SECURITY;
+ALTER TABLE share_links ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_request_links ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_request_submissions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE uploads ENABLE ROW LEVEL SECURITY;
+ALTER TABLE upload_parts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE access_keys ENABLE ROW LEVEL SECURITY;
 
 -- RLS Policies for users table
 -- User service can do everything with users
@@ -173,6 +704,203 @@ CREATE POLICY analytics_read_folders ON folders
     TO analytics_reader
     USING (deleted_at IS NULL);
 
+-- RLS Policies for file versions
+-- File service has full access
+CREATE POLICY file_service_all ON file_versions
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- RLS Policies for blobs
+-- File service has full access
+CREATE POLICY file_service_all ON blobs
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- RLS Policies for storage usage
+-- File service has full access
+CREATE POLICY file_service_all ON storage_usage
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- RLS Policies for the content index
+-- File service has full access; the index is written by triggers and the background indexer
+CREATE POLICY file_service_all ON file_content_index
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- RLS Policies for thumbnails
+-- File service has full access; rows are written by triggers and the background thumbnailer
+CREATE POLICY file_service_all ON file_thumbnails
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- RLS Policies for malware scans
+-- File service has full access; rows are written by triggers and the background scanner
+CREATE POLICY file_service_all ON file_scans
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- RLS Policies for resumable uploads
+-- Only the file service touches upload state
+CREATE POLICY file_service_all ON uploads
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+CREATE POLICY file_service_all ON upload_parts
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- RLS Policies for sharing
+-- File service has full access to shares
+CREATE POLICY file_service_all ON file_shares
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- When the file service acts for a user, it only reaches what that user owns or was shared.
+-- These policies are restrictive: they narrow file_service_all instead of adding to it.
+CREATE POLICY acting_user_read ON files AS RESTRICTIVE
+    FOR SELECT
+    TO file_service
+    USING (current_drive_user() IS NULL OR file_access_role(current_drive_user(), id) IS NOT NULL);
+
+CREATE POLICY acting_user_insert ON files AS RESTRICTIVE
+    FOR INSERT
+    TO file_service
+    WITH CHECK (current_drive_user() IS NULL OR user_id = current_drive_user()
+        OR share_role_rank(folder_access_role(current_drive_user(), folder_id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_update ON files AS RESTRICTIVE
+    FOR UPDATE
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR share_role_rank(file_access_role(current_drive_user(), id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_delete ON files AS RESTRICTIVE
+    FOR DELETE
+    TO file_service
+    USING (current_drive_user() IS NULL OR file_access_role(current_drive_user(), id) = 'owner');
+
+CREATE POLICY acting_user_read ON folders AS RESTRICTIVE
+    FOR SELECT
+    TO file_service
+    USING (current_drive_user() IS NULL OR folder_access_role(current_drive_user(), id) IS NOT NULL);
+
+CREATE POLICY acting_user_insert ON folders AS RESTRICTIVE
+    FOR INSERT
+    TO file_service
+    WITH CHECK (current_drive_user() IS NULL OR user_id = current_drive_user()
+        OR share_role_rank(folder_access_role(current_drive_user(), parent_id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_update ON folders AS RESTRICTIVE
+    FOR UPDATE
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR share_role_rank(folder_access_role(current_drive_user(), id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_delete ON folders AS RESTRICTIVE
+    FOR DELETE
+    TO file_service
+    USING (current_drive_user() IS NULL OR folder_access_role(current_drive_user(), id) = 'owner');
+
+CREATE POLICY acting_user_read ON file_versions AS RESTRICTIVE
+    FOR SELECT
+    TO file_service
+    USING (current_drive_user() IS NULL OR file_access_role(current_drive_user(), file_id) IS NOT NULL);
+
+CREATE POLICY acting_user_insert ON file_versions AS RESTRICTIVE
+    FOR INSERT
+    TO file_service
+    WITH CHECK (current_drive_user() IS NULL
+        OR share_role_rank(file_access_role(current_drive_user(), file_id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_delete ON file_versions AS RESTRICTIVE
+    FOR DELETE
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR share_role_rank(file_access_role(current_drive_user(), file_id)) >= share_role_rank('editor'));
+
+-- Users see the shares they granted or received, and only owners grant them
+CREATE POLICY acting_user_all ON file_shares AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR owner_id = current_drive_user() OR grantee_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR owner_id = current_drive_user());
+
+-- RLS Policies for share links
+-- File service has full access; links are resolved without an acting user
+CREATE POLICY file_service_all ON share_links
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- Users only see and manage the links they created
+CREATE POLICY acting_user_all ON share_links AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR owner_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR owner_id = current_drive_user());
+
+-- RLS Policies for file request links
+-- File service has full access; uploads through a link have no acting user
+CREATE POLICY file_service_all ON file_request_links
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+CREATE POLICY file_service_all ON file_request_submissions
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- Users only see and manage their own file requests and what was uploaded through them
+CREATE POLICY acting_user_all ON file_request_links AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR owner_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR owner_id = current_drive_user());
+
+CREATE POLICY acting_user_all ON file_request_submissions AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR request_id IN (SELECT id FROM file_request_links WHERE owner_id = current_drive_user()));
+
+-- RLS Policies for access keys
+-- File service has full access; keys are looked up without an acting user to check signatures
+CREATE POLICY file_service_all ON access_keys
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- Users only see and manage their own keys
+CREATE POLICY acting_user_all ON access_keys AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR user_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR user_id = current_drive_user());
+
 -- Grant permissions to service roles
 GRANT CONNECT ON DATABASE postgres TO user_service, file_service, analytics_reader;
 
@@ -182,7 +910,7 @@ GRANT SELECT ON files, folders TO user_service;
 GRANT USAGE ON SCHEMA public TO user_service;
 
 -- File Service permissions
-GRANT SELECT, INSERT, UPDATE, DELETE ON files, folders TO file_service;
+GRANT SELECT, INSERT, UPDATE, DELETE ON files, folders, file_versions, file_content_index, file_thumbnails, file_scans, file_shares, share_links, file_request_links, file_request_submissions, blobs, storage_usage, uploads, upload_parts, access_keys TO file_service;
 GRANT SELECT ON users TO file_service;
 GRANT USAGE ON SCHEMA public TO file_service;
 
@@ -0,0 +1,57 @@
+-- Migration: Add resumable upload state
+-- Version: 003_add_resumable_uploads
+-- Description: Persist tus upload offsets and received chunks so partial uploads survive gateway restarts
+
+CREATE TABLE IF NOT EXISTS uploads (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    folder_id UUID,
+    mime_type VARCHAR(100),
+    size BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uploads_size_check CHECK (size >= 0),
+    CONSTRAINT uploads_offset_check CHECK (upload_offset >= 0 AND upload_offset <= size)
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploads_user_id ON uploads(user_id);
+CREATE INDEX IF NOT EXISTS idx_uploads_updated_at ON uploads(updated_at);
+
+CREATE TABLE IF NOT EXISTS upload_parts (
+    upload_id UUID NOT NULL REFERENCES uploads(id) ON DELETE CASCADE,
+    part_offset BIGINT NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (upload_id, part_offset)
+);
+
+DROP TRIGGER IF EXISTS update_uploads_updated_at ON uploads;
+CREATE TRIGGER update_uploads_updated_at BEFORE UPDATE ON uploads
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE uploads ENABLE ROW LEVEL SECURITY;
+ALTER TABLE upload_parts ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON uploads;
+CREATE POLICY file_service_all ON uploads
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+DROP POLICY IF EXISTS file_service_all ON upload_parts;
+CREATE POLICY file_service_all ON upload_parts
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON uploads, upload_parts TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('003_add_resumable_uploads', 'Add resumable upload state')
+ON CONFLICT (version) DO NOTHING;
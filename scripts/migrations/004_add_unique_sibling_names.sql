@@ -0,0 +1,65 @@
+-- Migration: Enforce unique sibling names
+-- Version: 004_add_unique_sibling_names
+-- Description: Make file and folder names unique within a folder so paths resolve to a single item.
+-- Rename or delete any duplicate names before applying; index creation fails while they exist.
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_files_unique_name
+    ON files(user_id, COALESCE(folder_id, '00000000-0000-0000-0000-000000000000'), name)
+    WHERE deleted_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_unique_name
+    ON folders(user_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), name)
+    WHERE deleted_at IS NULL;
+
+-- Function to keep file and folder names unique within a folder.
+-- Unique indexes cover siblings of the same kind; this covers a file and a folder sharing a name.
+CREATE OR REPLACE FUNCTION check_sibling_name()
+RETURNS TRIGGER AS $$
+DECLARE
+    parent UUID;
+BEGIN
+    IF NEW.deleted_at IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF TG_TABLE_NAME = 'files' THEN
+        parent := NEW.folder_id;
+    ELSE
+        parent := NEW.parent_id;
+    END IF;
+
+    -- Serialise writers of the same name in the same folder across both tables
+    PERFORM pg_advisory_xact_lock(hashtextextended(
+        NEW.user_id::text || '/' || COALESCE(parent::text, '') || '/' || NEW.name, 0));
+
+    IF TG_TABLE_NAME = 'files' THEN
+        PERFORM 1 FROM folders
+        WHERE user_id = NEW.user_id AND parent_id IS NOT DISTINCT FROM parent
+            AND name = NEW.name AND deleted_at IS NULL;
+    ELSE
+        PERFORM 1 FROM files
+        WHERE user_id = NEW.user_id AND folder_id IS NOT DISTINCT FROM parent
+            AND name = NEW.name AND deleted_at IS NULL;
+    END IF;
+    IF FOUND THEN
+        RAISE EXCEPTION 'an item named "%" already exists in this folder', NEW.name
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'sibling_name_unique';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Triggers to check names whenever an item is created, renamed, moved or restored
+DROP TRIGGER IF EXISTS check_files_sibling_name ON files;
+CREATE TRIGGER check_files_sibling_name BEFORE INSERT OR UPDATE OF name, folder_id, deleted_at ON files
+    FOR EACH ROW EXECUTE FUNCTION check_sibling_name();
+
+DROP TRIGGER IF EXISTS check_folders_sibling_name ON folders;
+CREATE TRIGGER check_folders_sibling_name BEFORE INSERT OR UPDATE OF name, parent_id, deleted_at ON folders
+    FOR EACH ROW EXECUTE FUNCTION check_sibling_name();
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('004_add_unique_sibling_names', 'Enforce unique sibling names')
+ON CONFLICT (version) DO NOTHING;
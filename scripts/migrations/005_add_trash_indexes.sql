@@ -0,0 +1,26 @@
+-- Migration: Add indexes for the trash
+-- Version: 005_add_trash_indexes
+-- Description: Index deleted files and folders for trash listing and the retention purge
+
+-- Trash listing, most recently deleted first
+CREATE INDEX IF NOT EXISTS idx_files_trash
+ON files(user_id, deleted_at DESC)
+WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_folders_trash
+ON folders(user_id, deleted_at DESC)
+WHERE deleted_at IS NOT NULL;
+
+-- Retention purge across all users
+CREATE INDEX IF NOT EXISTS idx_files_deleted_at
+ON files(deleted_at)
+WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_folders_deleted_at
+ON folders(deleted_at)
+WHERE deleted_at IS NOT NULL;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('005_add_trash_indexes', 'Add indexes for the trash')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,32 @@
+-- Migration: Add file version history
+-- Version: 006_add_file_versions
+-- Description: Keep the previous content of a file whenever new content is uploaded
+
+CREATE TABLE IF NOT EXISTS file_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    checksum VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT file_versions_size_check CHECK (size >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_versions_file_id ON file_versions(file_id, created_at DESC);
+
+ALTER TABLE file_versions ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON file_versions;
+CREATE POLICY file_service_all ON file_versions
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON file_versions TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('006_add_file_versions', 'Add file version history')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,61 @@
+-- Migration: Deduplicate file content
+-- Version: 007_add_blob_dedup
+-- Description: Store identical content once, keyed by checksum, and count the files and versions using it.
+-- Content uploaded before this migration keeps its own storage key and is not counted.
+
+CREATE TABLE IF NOT EXISTS blobs (
+    checksum VARCHAR(64) PRIMARY KEY,
+    size BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT blobs_size_check CHECK (size >= 0),
+    CONSTRAINT blobs_ref_count_check CHECK (ref_count >= 0)
+);
+
+-- Blobs waiting to be collected
+CREATE INDEX IF NOT EXISTS idx_blobs_unreferenced ON blobs(updated_at) WHERE ref_count = 0;
+
+-- Function to count the files and file versions that use each blob.
+-- Only rows stored under their blob key are counted.
+CREATE OR REPLACE FUNCTION count_blob_refs()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' AND OLD.storage_key = 'blobs/' || left(OLD.checksum, 2) || '/' || OLD.checksum THEN
+        UPDATE blobs SET ref_count = ref_count - 1, updated_at = CURRENT_TIMESTAMP
+        WHERE checksum = OLD.checksum;
+    END IF;
+
+    IF TG_OP <> 'DELETE' AND NEW.storage_key = 'blobs/' || left(NEW.checksum, 2) || '/' || NEW.checksum THEN
+        INSERT INTO blobs (checksum, size, ref_count) VALUES (NEW.checksum, NEW.size, 1)
+        ON CONFLICT (checksum) DO UPDATE
+        SET ref_count = blobs.ref_count + 1, updated_at = CURRENT_TIMESTAMP;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS count_files_blob_refs ON files;
+CREATE TRIGGER count_files_blob_refs AFTER INSERT OR UPDATE OF storage_key, checksum OR DELETE ON files
+    FOR EACH ROW EXECUTE FUNCTION count_blob_refs();
+
+DROP TRIGGER IF EXISTS count_file_versions_blob_refs ON file_versions;
+CREATE TRIGGER count_file_versions_blob_refs AFTER INSERT OR UPDATE OF storage_key, checksum OR DELETE ON file_versions
+    FOR EACH ROW EXECUTE FUNCTION count_blob_refs();
+
+ALTER TABLE blobs ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON blobs;
+CREATE POLICY file_service_all ON blobs
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON blobs TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('007_add_blob_dedup', 'Deduplicate file content')
+ON CONFLICT (version) DO NOTHING;
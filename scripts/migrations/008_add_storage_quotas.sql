@@ -0,0 +1,62 @@
+-- Migration: Track storage usage for quotas
+-- Version: 008_add_storage_quotas
+-- Description: Keep a running total of the bytes each user stores so quotas can be enforced
+
+CREATE TABLE IF NOT EXISTS storage_usage (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    used_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Function to keep each user's storage usage in step with their files and file versions
+CREATE OR REPLACE FUNCTION track_storage_usage()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        UPDATE storage_usage SET used_bytes = used_bytes - OLD.size, updated_at = CURRENT_TIMESTAMP
+        WHERE user_id = OLD.user_id;
+    END IF;
+
+    IF TG_OP <> 'DELETE' THEN
+        INSERT INTO storage_usage (user_id, used_bytes) VALUES (NEW.user_id, NEW.size)
+        ON CONFLICT (user_id) DO UPDATE
+        SET used_bytes = storage_usage.used_bytes + NEW.size, updated_at = CURRENT_TIMESTAMP;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS track_files_storage_usage ON files;
+CREATE TRIGGER track_files_storage_usage AFTER INSERT OR UPDATE OF size OR DELETE ON files
+    FOR EACH ROW EXECUTE FUNCTION track_storage_usage();
+
+DROP TRIGGER IF EXISTS track_file_versions_storage_usage ON file_versions;
+CREATE TRIGGER track_file_versions_storage_usage AFTER INSERT OR DELETE ON file_versions
+    FOR EACH ROW EXECUTE FUNCTION track_storage_usage();
+
+-- Count what is already stored, including the trash
+INSERT INTO storage_usage (user_id, used_bytes)
+SELECT user_id, SUM(size) FROM (
+    SELECT user_id, size FROM files
+    UNION ALL
+    SELECT user_id, size FROM file_versions
+) stored
+GROUP BY user_id
+ON CONFLICT (user_id) DO UPDATE SET used_bytes = EXCLUDED.used_bytes;
+
+ALTER TABLE storage_usage ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON storage_usage;
+CREATE POLICY file_service_all ON storage_usage
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON storage_usage TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('008_add_storage_quotas', 'Track storage usage for quotas')
+ON CONFLICT (version) DO NOTHING;
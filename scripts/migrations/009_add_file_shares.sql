@@ -0,0 +1,185 @@
+-- Migration: Share files and folders between users
+-- Version: 009_add_file_shares
+-- Description: Grant other users viewer, commenter or editor access to a file or folder,
+-- inherited by everything below a shared folder, and enforce it with RLS for the acting user.
+
+-- Files and folders shared with other users. A folder share covers everything below the folder.
+CREATE TABLE IF NOT EXISTS file_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    grantee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_id UUID REFERENCES files(id) ON DELETE CASCADE,
+    folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT file_shares_role_check CHECK (role IN ('viewer', 'commenter', 'editor')),
+    CONSTRAINT file_shares_target_check CHECK ((file_id IS NULL) <> (folder_id IS NULL)),
+    CONSTRAINT file_shares_not_owner_check CHECK (grantee_id <> owner_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_shares_owner_id ON file_shares(owner_id);
+CREATE INDEX IF NOT EXISTS idx_file_shares_grantee_id ON file_shares(grantee_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_file_shares_unique_file ON file_shares(file_id, grantee_id) WHERE file_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_file_shares_unique_folder ON file_shares(folder_id, grantee_id) WHERE folder_id IS NOT NULL;
+
+DROP TRIGGER IF EXISTS update_file_shares_updated_at ON file_shares;
+CREATE TRIGGER update_file_shares_updated_at BEFORE UPDATE ON file_shares
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Function to rank access roles, from none (0) to owner (4)
+CREATE OR REPLACE FUNCTION share_role_rank(role VARCHAR)
+RETURNS INTEGER AS $$
+    SELECT CASE role
+        WHEN 'viewer' THEN 1
+        WHEN 'commenter' THEN 2
+        WHEN 'editor' THEN 3
+        WHEN 'owner' THEN 4
+        ELSE 0
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Functions returning a user's role on a folder or file: owner, the best role shared on the
+-- item or any folder above it, or NULL without access. They run as their owner so that the
+-- RLS policies below can call them without recursing into themselves.
+CREATE OR REPLACE FUNCTION folder_access_role(p_user_id UUID, p_folder_id UUID)
+RETURNS VARCHAR AS $$
+    WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id, user_id FROM folders WHERE id = p_folder_id
+        UNION ALL
+        SELECT f.id, f.parent_id, f.user_id FROM folders f JOIN ancestors a ON f.id = a.parent_id
+    )
+    SELECT CASE
+        WHEN bool_or(a.user_id = p_user_id) THEN 'owner'
+        ELSE (array_agg(s.role ORDER BY share_role_rank(s.role) DESC) FILTER (WHERE s.role IS NOT NULL))[1]
+    END
+    FROM ancestors a
+    LEFT JOIN file_shares s ON s.folder_id = a.id AND s.grantee_id = p_user_id;
+$$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public;
+
+CREATE OR REPLACE FUNCTION file_access_role(p_user_id UUID, p_file_id UUID)
+RETURNS VARCHAR AS $$
+    SELECT CASE
+        WHEN f.user_id = p_user_id THEN 'owner'
+        ELSE (
+            SELECT role FROM (
+                SELECT s.role FROM file_shares s WHERE s.file_id = f.id AND s.grantee_id = p_user_id
+                UNION ALL
+                SELECT folder_access_role(p_user_id, f.folder_id)
+            ) roles
+            WHERE role IS NOT NULL
+            ORDER BY share_role_rank(role) DESC
+            LIMIT 1
+        )
+    END
+    FROM files f
+    WHERE f.id = p_file_id;
+$$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public;
+
+-- Function returning the user a transaction acts for, set with set_config('app.user_id', ..., true).
+-- NULL when the file service acts on its own behalf, e.g. when purging the trash.
+CREATE OR REPLACE FUNCTION current_drive_user()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.user_id', true), '')::uuid;
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE file_shares ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON file_shares;
+DROP POLICY IF EXISTS acting_user_all ON file_shares;
+DROP POLICY IF EXISTS acting_user_read ON files;
+DROP POLICY IF EXISTS acting_user_insert ON files;
+DROP POLICY IF EXISTS acting_user_update ON files;
+DROP POLICY IF EXISTS acting_user_delete ON files;
+DROP POLICY IF EXISTS acting_user_read ON folders;
+DROP POLICY IF EXISTS acting_user_insert ON folders;
+DROP POLICY IF EXISTS acting_user_update ON folders;
+DROP POLICY IF EXISTS acting_user_delete ON folders;
+DROP POLICY IF EXISTS acting_user_read ON file_versions;
+DROP POLICY IF EXISTS acting_user_insert ON file_versions;
+DROP POLICY IF EXISTS acting_user_delete ON file_versions;
+
+-- RLS Policies for sharing
+-- File service has full access to shares
+CREATE POLICY file_service_all ON file_shares
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- When the file service acts for a user, it only reaches what that user owns or was shared.
+-- These policies are restrictive: they narrow file_service_all instead of adding to it.
+CREATE POLICY acting_user_read ON files AS RESTRICTIVE
+    FOR SELECT
+    TO file_service
+    USING (current_drive_user() IS NULL OR file_access_role(current_drive_user(), id) IS NOT NULL);
+
+CREATE POLICY acting_user_insert ON files AS RESTRICTIVE
+    FOR INSERT
+    TO file_service
+    WITH CHECK (current_drive_user() IS NULL OR user_id = current_drive_user()
+        OR share_role_rank(folder_access_role(current_drive_user(), folder_id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_update ON files AS RESTRICTIVE
+    FOR UPDATE
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR share_role_rank(file_access_role(current_drive_user(), id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_delete ON files AS RESTRICTIVE
+    FOR DELETE
+    TO file_service
+    USING (current_drive_user() IS NULL OR file_access_role(current_drive_user(), id) = 'owner');
+
+CREATE POLICY acting_user_read ON folders AS RESTRICTIVE
+    FOR SELECT
+    TO file_service
+    USING (current_drive_user() IS NULL OR folder_access_role(current_drive_user(), id) IS NOT NULL);
+
+CREATE POLICY acting_user_insert ON folders AS RESTRICTIVE
+    FOR INSERT
+    TO file_service
+    WITH CHECK (current_drive_user() IS NULL OR user_id = current_drive_user()
+        OR share_role_rank(folder_access_role(current_drive_user(), parent_id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_update ON folders AS RESTRICTIVE
+    FOR UPDATE
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR share_role_rank(folder_access_role(current_drive_user(), id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_delete ON folders AS RESTRICTIVE
+    FOR DELETE
+    TO file_service
+    USING (current_drive_user() IS NULL OR folder_access_role(current_drive_user(), id) = 'owner');
+
+CREATE POLICY acting_user_read ON file_versions AS RESTRICTIVE
+    FOR SELECT
+    TO file_service
+    USING (current_drive_user() IS NULL OR file_access_role(current_drive_user(), file_id) IS NOT NULL);
+
+CREATE POLICY acting_user_insert ON file_versions AS RESTRICTIVE
+    FOR INSERT
+    TO file_service
+    WITH CHECK (current_drive_user() IS NULL
+        OR share_role_rank(file_access_role(current_drive_user(), file_id)) >= share_role_rank('editor'));
+
+CREATE POLICY acting_user_delete ON file_versions AS RESTRICTIVE
+    FOR DELETE
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR share_role_rank(file_access_role(current_drive_user(), file_id)) >= share_role_rank('editor'));
+
+-- Users see the shares they granted or received, and only owners grant them
+CREATE POLICY acting_user_all ON file_shares AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR owner_id = current_drive_user() OR grantee_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR owner_id = current_drive_user());
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON file_shares TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('009_add_file_shares', 'Share files and folders between users')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,56 @@
+-- Migration: Public share links
+-- Version: 010_add_share_links
+-- Description: Token-based links to a file or folder for people without an account, with an
+-- optional expiry, password and download limit, and a preview-only mode.
+
+-- Public links to a file or folder for people without an account. Only a SHA-256 hash of
+-- the token and a bcrypt hash of the optional password are stored.
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_id UUID REFERENCES files(id) ON DELETE CASCADE,
+    folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    password_hash VARCHAR(60),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    max_downloads INTEGER NOT NULL DEFAULT 0,
+    download_count INTEGER NOT NULL DEFAULT 0,
+    preview_only BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT share_links_target_check CHECK ((file_id IS NULL) <> (folder_id IS NULL)),
+    CONSTRAINT share_links_max_downloads_check CHECK (max_downloads >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_owner_id ON share_links(owner_id, created_at DESC);
+
+DROP TRIGGER IF EXISTS update_share_links_updated_at ON share_links;
+CREATE TRIGGER update_share_links_updated_at BEFORE UPDATE ON share_links
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE share_links ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON share_links;
+DROP POLICY IF EXISTS acting_user_all ON share_links;
+
+-- RLS Policies for share links
+-- File service has full access; links are resolved without an acting user
+CREATE POLICY file_service_all ON share_links
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- Users only see and manage the links they created
+CREATE POLICY acting_user_all ON share_links AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR owner_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR owner_id = current_drive_user());
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON share_links TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('010_add_share_links', 'Public share links with expiry, password and download limits')
+ON CONFLICT (version) DO NOTHING;
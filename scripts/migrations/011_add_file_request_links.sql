@@ -0,0 +1,80 @@
+-- Migration: File request links
+-- Version: 011_add_file_request_links
+-- Description: Upload-only links that let people without an account drop files into a folder
+-- before a deadline, within a size limit and a list of allowed MIME types, recording who sent each file.
+
+-- Upload-only links that let people without an account drop files into a folder.
+-- allowed_mime_types is a comma-separated list; empty allows any type.
+CREATE TABLE IF NOT EXISTS file_request_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    folder_id UUID NOT NULL REFERENCES folders(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    title VARCHAR(255),
+    deadline TIMESTAMP WITH TIME ZONE,
+    max_file_size BIGINT NOT NULL DEFAULT 0,
+    allowed_mime_types TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT file_request_links_max_file_size_check CHECK (max_file_size >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_request_links_owner_id ON file_request_links(owner_id, created_at DESC);
+
+-- Who uploaded each file received through a file request link
+CREATE TABLE IF NOT EXISTS file_request_submissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES file_request_links(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL UNIQUE REFERENCES files(id) ON DELETE CASCADE,
+    uploader_name VARCHAR(255) NOT NULL,
+    uploader_email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_request_submissions_request_id ON file_request_submissions(request_id, created_at DESC);
+
+DROP TRIGGER IF EXISTS update_file_request_links_updated_at ON file_request_links;
+CREATE TRIGGER update_file_request_links_updated_at BEFORE UPDATE ON file_request_links
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE file_request_links ENABLE ROW LEVEL SECURITY;
+ALTER TABLE file_request_submissions ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON file_request_links;
+DROP POLICY IF EXISTS file_service_all ON file_request_submissions;
+DROP POLICY IF EXISTS acting_user_all ON file_request_links;
+DROP POLICY IF EXISTS acting_user_all ON file_request_submissions;
+
+-- RLS Policies for file request links
+-- File service has full access; uploads through a link have no acting user
+CREATE POLICY file_service_all ON file_request_links
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+CREATE POLICY file_service_all ON file_request_submissions
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- Users only see and manage their own file requests and what was uploaded through them
+CREATE POLICY acting_user_all ON file_request_links AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR owner_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR owner_id = current_drive_user());
+
+CREATE POLICY acting_user_all ON file_request_submissions AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL
+        OR request_id IN (SELECT id FROM file_request_links WHERE owner_id = current_drive_user()));
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON file_request_links, file_request_submissions TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('011_add_file_request_links', 'Upload-only file request links')
+ON CONFLICT (version) DO NOTHING;
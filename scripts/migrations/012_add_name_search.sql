@@ -0,0 +1,35 @@
+-- Migration: Add name search
+-- Version: 012_add_name_search
+-- Description: Index file and folder names for word and fuzzy search
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Words of a name; dots, dashes and underscores separate words, so report_2026-final.pdf
+-- yields report, 2026, final and pdf
+CREATE OR REPLACE FUNCTION name_search_vector(name TEXT)
+RETURNS tsvector AS $$
+    SELECT to_tsvector('simple'::regconfig, translate(name, '._-', '   '));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Word matches
+CREATE INDEX IF NOT EXISTS idx_files_name_search
+ON files USING GIN (name_search_vector(name))
+WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_folders_name_search
+ON folders USING GIN (name_search_vector(name))
+WHERE deleted_at IS NULL;
+
+-- Substring and similarity matches
+CREATE INDEX IF NOT EXISTS idx_files_name_trgm
+ON files USING GIN (name gin_trgm_ops)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_folders_name_trgm
+ON folders USING GIN (name gin_trgm_ops)
+WHERE deleted_at IS NULL;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('012_add_name_search', 'Add name search')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,76 @@
+-- Migration: Add content index
+-- Version: 013_add_content_index
+-- Description: Extract the text of text, Markdown, CSV and PDF files in the background and
+-- index it so that search matches file content as well as names.
+
+-- Searchable text of each file's current content. The file service fills in content,
+-- retrying failed attempts once next_attempt_at has passed.
+CREATE TABLE IF NOT EXISTS file_content_index (
+    file_id UUID PRIMARY KEY REFERENCES files(id) ON DELETE CASCADE,
+    checksum VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    indexed_at TIMESTAMP WITH TIME ZONE,
+    content TSVECTOR,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Work queue of the indexer
+CREATE INDEX IF NOT EXISTS idx_file_content_index_pending
+ON file_content_index(next_attempt_at NULLS FIRST)
+WHERE status = 'pending';
+
+-- Content matches
+CREATE INDEX IF NOT EXISTS idx_file_content_index_content
+ON file_content_index USING GIN (content);
+
+DROP TRIGGER IF EXISTS update_file_content_index_updated_at ON file_content_index;
+CREATE TRIGGER update_file_content_index_updated_at BEFORE UPDATE ON file_content_index
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Queue a file's content for indexing when it is set or replaced
+CREATE OR REPLACE FUNCTION queue_content_index()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.checksum, '') = '' THEN
+        DELETE FROM file_content_index WHERE file_id = NEW.id;
+    ELSIF TG_OP = 'INSERT' OR NEW.checksum IS DISTINCT FROM OLD.checksum THEN
+        INSERT INTO file_content_index (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+        ON CONFLICT (file_id) DO UPDATE
+        SET checksum = EXCLUDED.checksum, status = 'pending', attempts = 0, last_error = NULL,
+            next_attempt_at = NULL, indexed_at = NULL, content = NULL;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS queue_files_content_index ON files;
+CREATE TRIGGER queue_files_content_index AFTER INSERT OR UPDATE OF checksum ON files
+    FOR EACH ROW EXECUTE FUNCTION queue_content_index();
+
+-- Queue the content uploaded before this migration
+INSERT INTO file_content_index (file_id, checksum)
+SELECT id, checksum FROM files WHERE checksum <> ''
+ON CONFLICT (file_id) DO NOTHING;
+
+ALTER TABLE file_content_index ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON file_content_index;
+
+-- File service has full access; the index is written by triggers and the background indexer
+CREATE POLICY file_service_all ON file_content_index
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON file_content_index TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('013_add_content_index', 'Add content index')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,71 @@
+-- Migration: Add thumbnails
+-- Version: 014_add_thumbnails
+-- Description: Render JPEG thumbnails of image files in the background. The thumbnails are
+-- stored as blobs next to the content; this table tracks which are ready.
+
+-- Thumbnail state of each image file's current content. The file service renders the
+-- thumbnails, retrying failed attempts once next_attempt_at has passed.
+CREATE TABLE IF NOT EXISTS file_thumbnails (
+    file_id UUID PRIMARY KEY REFERENCES files(id) ON DELETE CASCADE,
+    checksum VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    generated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Work queue of the thumbnailer
+CREATE INDEX IF NOT EXISTS idx_file_thumbnails_pending
+ON file_thumbnails(next_attempt_at NULLS FIRST)
+WHERE status = 'pending';
+
+DROP TRIGGER IF EXISTS update_file_thumbnails_updated_at ON file_thumbnails;
+CREATE TRIGGER update_file_thumbnails_updated_at BEFORE UPDATE ON file_thumbnails
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Queue thumbnails of an image when its content is set or replaced
+CREATE OR REPLACE FUNCTION queue_thumbnails()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.checksum, '') = '' OR COALESCE(NEW.mime_type, '') NOT LIKE 'image/%' THEN
+        DELETE FROM file_thumbnails WHERE file_id = NEW.id;
+    ELSIF TG_OP = 'INSERT' OR NEW.checksum IS DISTINCT FROM OLD.checksum THEN
+        INSERT INTO file_thumbnails (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+        ON CONFLICT (file_id) DO UPDATE
+        SET checksum = EXCLUDED.checksum, status = 'pending', attempts = 0, last_error = NULL,
+            next_attempt_at = NULL, generated_at = NULL;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS queue_files_thumbnails ON files;
+CREATE TRIGGER queue_files_thumbnails AFTER INSERT OR UPDATE OF checksum ON files
+    FOR EACH ROW EXECUTE FUNCTION queue_thumbnails();
+
+-- Queue the images uploaded before this migration
+INSERT INTO file_thumbnails (file_id, checksum)
+SELECT id, checksum FROM files WHERE checksum <> '' AND mime_type LIKE 'image/%'
+ON CONFLICT (file_id) DO NOTHING;
+
+ALTER TABLE file_thumbnails ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON file_thumbnails;
+
+-- File service has full access; rows are written by triggers and the background thumbnailer
+CREATE POLICY file_service_all ON file_thumbnails
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON file_thumbnails TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('014_add_thumbnails', 'Add thumbnails')
+ON CONFLICT (version) DO NOTHING;
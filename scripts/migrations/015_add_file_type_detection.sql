@@ -0,0 +1,17 @@
+-- Migration: Add file type detection
+-- Version: 015_add_file_type_detection
+-- Description: Keep the MIME type clients declare for a file apart from the type detected
+-- from its content, flag content that does not match, and quarantine dangerous types.
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS declared_mime_type VARCHAR(100);
+ALTER TABLE files ADD COLUMN IF NOT EXISTS mime_mismatch BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS quarantined BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS quarantine_reason TEXT;
+
+-- Files uploaded before this migration only have the declared type
+UPDATE files SET declared_mime_type = mime_type WHERE declared_mime_type IS NULL;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('015_add_file_type_detection', 'Add file type detection')
+ON CONFLICT (version) DO NOTHING;
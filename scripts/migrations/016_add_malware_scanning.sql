@@ -0,0 +1,103 @@
+-- Migration: Add malware scanning
+-- Version: 016_add_malware_scanning
+-- Description: Scan uploaded content for malware in the background. Content can only be
+-- downloaded or shared once its scan came back clean; infected content is quarantined.
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE file_versions ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+-- Scan state of each content a file holds, current or kept as a version. The file service
+-- scans it, retrying failed attempts once next_attempt_at has passed, and copies the outcome
+-- to files.scan_status and file_versions.scan_status.
+CREATE TABLE IF NOT EXISTS file_scans (
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    checksum VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    scanned_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (file_id, checksum)
+);
+
+-- Work queue of the scanner
+CREATE INDEX IF NOT EXISTS idx_file_scans_pending
+ON file_scans(next_attempt_at NULLS FIRST)
+WHERE status = 'pending';
+
+-- Quarantined files are listed for admins
+CREATE INDEX IF NOT EXISTS idx_files_quarantined
+ON files(updated_at DESC)
+WHERE quarantined;
+
+DROP TRIGGER IF EXISTS update_file_scans_updated_at ON file_scans;
+CREATE TRIGGER update_file_scans_updated_at BEFORE UPDATE ON file_scans
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Queue a scan when content is committed pending one. Content committed while scanning is
+-- turned off is unscanned right away. Scans of content the file no longer holds, as its current
+-- content or as a version, are dropped; replaced content keeps its scan.
+CREATE OR REPLACE FUNCTION queue_file_scan()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.checksum, '') <> '' AND NEW.scan_status = 'pending' THEN
+        INSERT INTO file_scans (file_id, checksum) VALUES (NEW.id, NEW.checksum)
+        ON CONFLICT (file_id, checksum) DO UPDATE
+        SET status = 'pending', attempts = 0, last_error = NULL, next_attempt_at = NULL, scanned_at = NULL;
+    END IF;
+
+    DELETE FROM file_scans s
+    WHERE s.file_id = NEW.id AND s.checksum <> COALESCE(NEW.checksum, '')
+        AND NOT EXISTS (SELECT 1 FROM file_versions v WHERE v.file_id = s.file_id AND v.checksum = s.checksum);
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS queue_files_scan ON files;
+CREATE TRIGGER queue_files_scan AFTER INSERT OR UPDATE OF checksum, scan_status ON files
+    FOR EACH ROW EXECUTE FUNCTION queue_file_scan();
+
+-- Drop the scan of a deleted version's content once the file no longer holds it
+CREATE OR REPLACE FUNCTION drop_version_scan()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM file_scans s
+    WHERE s.file_id = OLD.file_id AND s.checksum = OLD.checksum
+        AND NOT EXISTS (SELECT 1 FROM files f WHERE f.id = s.file_id AND f.checksum = s.checksum)
+        AND NOT EXISTS (SELECT 1 FROM file_versions v WHERE v.file_id = s.file_id AND v.checksum = s.checksum);
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS drop_file_versions_scan ON file_versions;
+CREATE TRIGGER drop_file_versions_scan AFTER DELETE ON file_versions
+    FOR EACH ROW EXECUTE FUNCTION drop_version_scan();
+
+-- Queue the content uploaded before this migration, current and kept as versions
+INSERT INTO file_scans (file_id, checksum)
+SELECT id, checksum FROM files WHERE checksum <> '' AND scan_status = 'pending'
+UNION
+SELECT file_id, checksum FROM file_versions WHERE checksum <> ''
+ON CONFLICT (file_id, checksum) DO NOTHING;
+
+ALTER TABLE file_scans ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON file_scans;
+
+-- File service has full access; rows are written by triggers and the background scanner
+CREATE POLICY file_service_all ON file_scans
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON file_scans TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('016_add_malware_scanning', 'Add malware scanning')
+ON CONFLICT (version) DO NOTHING;
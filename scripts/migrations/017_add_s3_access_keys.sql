@@ -0,0 +1,45 @@
+-- Migration: Add access keys for the S3-compatible API
+-- Version: 017_add_s3_access_keys
+-- Description: Per-user access keys that sign requests to the S3-compatible API with SigV4.
+-- Signatures can only be checked with the secret itself, so it is kept: wrapped with the
+-- master key named by secret_master_key_id when an encryption keyring is configured, and as it
+-- is otherwise.
+
+CREATE TABLE IF NOT EXISTS access_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    access_key_id VARCHAR(32) NOT NULL UNIQUE,
+    secret_key TEXT NOT NULL,
+    secret_master_key_id VARCHAR(64) NOT NULL DEFAULT '',
+    name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_keys_user_id ON access_keys(user_id, created_at DESC);
+
+ALTER TABLE access_keys ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS file_service_all ON access_keys;
+DROP POLICY IF EXISTS acting_user_all ON access_keys;
+
+-- RLS Policies for access keys
+-- File service has full access; keys are looked up without an acting user to check signatures
+CREATE POLICY file_service_all ON access_keys
+    FOR ALL
+    TO file_service
+    USING (true)
+    WITH CHECK (true);
+
+-- Users only see and manage their own keys
+CREATE POLICY acting_user_all ON access_keys AS RESTRICTIVE
+    FOR ALL
+    TO file_service
+    USING (current_drive_user() IS NULL OR user_id = current_drive_user())
+    WITH CHECK (current_drive_user() IS NULL OR user_id = current_drive_user());
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON access_keys TO file_service;
+
+-- Record migration
+INSERT INTO schema_migrations (version, description)
+VALUES ('017_add_s3_access_keys', 'Access keys for the S3-compatible API')
+ON CONFLICT (version) DO NOTHING;
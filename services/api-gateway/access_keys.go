@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	filepb "go-drive/proto/file"
+)
+
+// accessKeysPath manages the caller's access keys for the S3-compatible API:
+// POST /api/v1/access-keys creates one, GET /api/v1/access-keys?page=&page_size= lists them
+// and DELETE /api/v1/access-keys/{id} revokes one
+const accessKeysPath = "/api/v1/access-keys"
+
+// maxAccessKeyBody bounds the JSON body of a request to create an access key
+const maxAccessKeyBody = 4 << 10
+
+// createAccessKeyBody is the JSON body of a request to create an access key
+type createAccessKeyBody struct {
+	Name string `json:"name"`
+}
+
+func (gw *APIGateway) handleAccessKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, accessKeysPath), "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		gw.handleCreateAccessKey(w, r, userID)
+	case id == "" && r.Method == http.MethodGet:
+		gw.handleListAccessKeys(w, r, userID)
+	case id != "" && r.Method == http.MethodDelete:
+		gw.handleRevokeAccessKey(w, r, userID, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCreateAccessKey creates an access key. The response holds the secret, which is
+// not shown again.
+func (gw *APIGateway) handleCreateAccessKey(w http.ResponseWriter, r *http.Request, userID string) {
+	var body createAccessKeyBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAccessKeyBody)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.CreateAccessKey(ctx, &filepb.CreateAccessKeyRequest{
+		UserId: userID,
+		Name:   body.Name,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (gw *APIGateway) handleListAccessKeys(w http.ResponseWriter, r *http.Request, userID string) {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.ListAccessKeys(ctx, &filepb.ListAccessKeysRequest{
+		UserId:   userID,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (gw *APIGateway) handleRevokeAccessKey(w http.ResponseWriter, r *http.Request, userID, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if _, err := gw.fileClient.RevokeAccessKey(ctx, &filepb.RevokeAccessKeyRequest{
+		Id:     id,
+		UserId: userID,
+	}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
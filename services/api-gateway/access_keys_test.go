@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	filepb "go-drive/proto/file"
+)
+
+func TestAPIGateway_HandleAccessKeys(t *testing.T) {
+	const keyID = "523e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		userID         string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "create returns the secret",
+			method: http.MethodPost,
+			path:   accessKeysPath,
+			body:   `{"name":"rclone"}`,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("CreateAccessKey", mock.Anything, &filepb.CreateAccessKeyRequest{UserId: testUserID, Name: "rclone"}).
+					Return(&filepb.CreateAccessKeyResponse{
+						Key:             &filepb.AccessKey{Id: keyID, AccessKeyId: "GDEXAMPLEKEY", Name: "rclone"},
+						SecretAccessKey: "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY",
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"secret_access_key":"wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"`,
+		},
+		{
+			name:   "create without a body",
+			method: http.MethodPost,
+			path:   accessKeysPath,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("CreateAccessKey", mock.Anything, &filepb.CreateAccessKeyRequest{UserId: testUserID}).
+					Return(&filepb.CreateAccessKeyResponse{Key: &filepb.AccessKey{Id: keyID}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "create with a malformed body",
+			method:         http.MethodPost,
+			path:           accessKeysPath,
+			body:           `{"name":`,
+			userID:         testUserID,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   accessKeysPath + "?page=2&page_size=10",
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ListAccessKeys", mock.Anything, &filepb.ListAccessKeysRequest{UserId: testUserID, Page: 2, PageSize: 10}).
+					Return(&filepb.ListAccessKeysResponse{
+						Keys:       []*filepb.AccessKey{{Id: keyID, AccessKeyId: "GDEXAMPLEKEY", Name: "rclone"}},
+						TotalCount: 11,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"access_key_id":"GDEXAMPLEKEY"`,
+		},
+		{
+			name:   "revoke",
+			method: http.MethodDelete,
+			path:   accessKeysPath + "/" + keyID,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("RevokeAccessKey", mock.Anything, &filepb.RevokeAccessKeyRequest{Id: keyID, UserId: testUserID}).
+					Return(&filepb.RevokeAccessKeyResponse{}, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "revoke someone else's key",
+			method: http.MethodDelete,
+			path:   accessKeysPath + "/" + keyID,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("RevokeAccessKey", mock.Anything, &filepb.RevokeAccessKeyRequest{Id: keyID, UserId: testUserID}).
+					Return(nil, status.Error(codes.NotFound, "access key not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "revoke without an id",
+			method:         http.MethodDelete,
+			path:           accessKeysPath,
+			userID:         testUserID,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "unauthenticated",
+			method:         http.MethodGet,
+			path:           accessKeysPath,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.userID != "" {
+				req.Header.Set(userIDHeader, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			gw.handleAccessKeys(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
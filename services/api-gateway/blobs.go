@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/signedurl"
+	"go-drive/internal/storage"
+	filepb "go-drive/proto/file"
+)
+
+// handleBlob serves signed upload and download URLs issued by the file service
+func (gw *APIGateway) handleBlob(w http.ResponseWriter, r *http.Request) {
+	grant, err := gw.urls.Verify(r, time.Now())
+	switch {
+	case errors.Is(err, signedurl.ErrMethodNotAllowed):
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	case errors.Is(err, signedurl.ErrExpired):
+		http.Error(w, "URL has expired", http.StatusForbidden)
+		return
+	case err != nil:
+		http.Error(w, "Invalid signature", http.StatusForbidden)
+		return
+	}
+
+	clearDeadlines(w)
+
+	switch grant.Method {
+	case http.MethodGet:
+		gw.serveBlob(w, r, grant)
+	case http.MethodPut:
+		gw.receiveBlob(w, r, grant)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveBlob streams the content a download grant refers to. The file's current metadata
+// is fetched so that validators reflect uploads made after the URL was signed; a grant for
+// an earlier version is validated against that version instead. A URL issued for content
+// the file has since replaced is refused, as its key no longer carries the file's validators
+// or scan status.
+func (gw *APIGateway) serveBlob(w http.ResponseWriter, r *http.Request, grant signedurl.Grant) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if grant.VersionID != "" {
+		resp, err := gw.fileClient.GetFileVersion(ctx, &filepb.GetFileVersionRequest{
+			FileId:    grant.FileID,
+			VersionId: grant.VersionID,
+			UserId:    grant.UserID,
+		})
+		if err != nil {
+			writeGRPCError(w, err)
+			return
+		}
+		cancel()
+
+		version := resp.GetVersion()
+		gw.serveFile(w, r, &filepb.File{
+			Id:         version.GetFileId(),
+			Checksum:   version.GetChecksum(),
+			UpdatedAt:  version.GetCreatedAt(),
+			ScanStatus: version.GetScanStatus(),
+		}, grant.Key, grant.ContentType)
+		return
+	}
+
+	meta, err := gw.fileClient.GetFile(ctx, &filepb.GetFileRequest{Id: grant.FileID, UserId: grant.UserID})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	cancel()
+
+	if meta.GetFile().GetStorageKey() != grant.Key {
+		http.Error(w, "File content has changed since the URL was issued", http.StatusGone)
+		return
+	}
+	gw.serveFile(w, r, meta.GetFile(), grant.Key, grant.ContentType)
+}
+
+// serveFile streams the content stored under key through http.ServeContent, which answers
+// Range requests (including multipart byteranges) and If-None-Match/If-Modified-Since/If-Range
+// conditions. The file's checksum is its strong ETag and its update time its Last-Modified date.
+// Quarantined files and files whose malware scan has not come back clean are refused.
+func (gw *APIGateway) serveFile(w http.ResponseWriter, r *http.Request, file *filepb.File, key, contentType string) {
+	if code, reason := unservable(file); code != 0 {
+		if code == http.StatusConflict {
+			w.Header().Set("Retry-After", "5")
+		}
+		http.Error(w, reason, code)
+		return
+	}
+
+	info, err := gw.blobs.Stat(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, "File content not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to read file content", http.StatusInternalServerError)
+		return
+	}
+
+	gw.streamContent(w, r, file, key, info.Size, contentType)
+}
+
+// streamContent streams size bytes of content stored under key, once the file has been
+// found servable. Content types come from uploaders, so browsers are kept from sniffing or
+// running the content, and anything that could not be previewed is sent as an attachment.
+func (gw *APIGateway) streamContent(w http.ResponseWriter, r *http.Request, file *filepb.File, key string, size int64, contentType string) {
+	content := &blobReadSeeker{ctx: r.Context(), blobs: gw.blobs, key: key, size: size}
+	defer content.Close()
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	if _, ok := previewType(contentType); !ok && w.Header().Get("Content-Disposition") == "" {
+		disposition := "attachment"
+		if name := file.GetName(); name != "" {
+			disposition = mime.FormatMediaType(disposition, map[string]string{"filename": name})
+		}
+		w.Header().Set("Content-Disposition", disposition)
+	}
+	if checksum := file.GetChecksum(); checksum != "" {
+		w.Header().Set("ETag", `"`+checksum+`"`)
+	}
+	var modified time.Time
+	if updated := file.GetUpdatedAt(); updated != nil {
+		modified = updated.AsTime()
+	}
+
+	http.ServeContent(w, r, "", modified, content)
+	if content.err != nil {
+		log.Printf("Failed to stream file %s: %v", file.GetId(), content.err)
+	}
+}
+
+// unservable returns the status and reason for refusing to serve a file's content, or zero
+// when the content may be served. Content committed while scanning was turned off is served.
+func unservable(file *filepb.File) (int, string) {
+	switch {
+	case file.GetQuarantined():
+		return http.StatusForbidden, "File is quarantined"
+	case file.GetChecksum() == "" || file.GetScanStatus() == domain.ScanClean || file.GetScanStatus() == domain.ScanSkipped:
+		return 0, ""
+	case file.GetScanStatus() == domain.ScanPending:
+		return http.StatusConflict, "File has not been scanned for malware yet"
+	default:
+		return http.StatusForbidden, "File could not be scanned for malware"
+	}
+}
+
+func (gw *APIGateway) receiveBlob(w http.ResponseWriter, r *http.Request, grant signedurl.Grant) bool {
+	resp, ok := gw.storeBlob(w, r, grant)
+	if !ok {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+	return true
+}
+
+// storeBlob stores a request body as the content a grant allows and completes the upload.
+// On failure it writes the error response and reports false.
+func (gw *APIGateway) storeBlob(w http.ResponseWriter, r *http.Request, grant signedurl.Grant) (*filepb.CompleteUploadResponse, bool) {
+	if grant.ContentType != "" {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != grant.ContentType {
+			http.Error(w, "Content-Type must be "+grant.ContentType, http.StatusUnsupportedMediaType)
+			return nil, false
+		}
+	}
+
+	body := io.Reader(r.Body)
+	if grant.MaxSize > 0 {
+		if r.ContentLength > grant.MaxSize {
+			http.Error(w, "File exceeds the upload size limit", http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		body = http.MaxBytesReader(w, r.Body, grant.MaxSize)
+	}
+
+	hash := sha256.New()
+	if _, err := gw.blobs.Put(r.Context(), grant.Key, io.TeeReader(body, hash), r.ContentLength); err != nil {
+		gw.discardBlob(context.WithoutCancel(r.Context()), grant.Key)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "File exceeds the upload size limit", http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		http.Error(w, "Failed to store file content", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.CompleteUpload(ctx, &filepb.CompleteUploadRequest{
+		Id:         grant.FileID,
+		UserId:     grant.UserID,
+		Checksum:   hex.EncodeToString(hash.Sum(nil)),
+		StorageKey: grant.Key,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return nil, false
+	}
+	return resp, true
+}
+
+// discardBlob removes staged content that will not be committed
+func (gw *APIGateway) discardBlob(ctx context.Context, key string) {
+	if err := gw.blobs.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete staged content %s: %v", key, err)
+	}
+}
+
+// discardFile deletes a file created for an upload that failed. The file service removes
+// files whose first upload never completed for good, so nothing is left in the trash; it
+// may have removed the file already when it refused the content.
+func (gw *APIGateway) discardFile(fileID, userID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := gw.fileClient.DeleteFile(ctx, &filepb.DeleteFileRequest{Id: fileID, UserId: userID})
+	if err != nil && status.Code(err) != codes.NotFound {
+		log.Printf("Failed to discard file %s after a failed upload: %v", fileID, err)
+	}
+}
+
+// blobReadSeeker adapts ranged backend reads to the io.ReadSeeker expected by http.ServeContent.
+// Seeking only records the position; the next Read opens the object from there.
+type blobReadSeeker struct {
+	ctx   context.Context
+	blobs storage.Backend
+	key   string
+	size  int64
+
+	offset int64
+	body   io.ReadCloser
+	// err records the first read failure, since ServeContent cannot report it once headers are sent
+	err error
+}
+
+func (b *blobReadSeeker) Read(p []byte) (int, error) {
+	if b.offset >= b.size {
+		return 0, io.EOF
+	}
+	if b.body == nil {
+		body, _, err := b.blobs.GetRange(b.ctx, b.key, b.offset, -1)
+		if err != nil {
+			b.err = err
+			return 0, err
+		}
+		b.body = body
+	}
+
+	n, err := b.body.Read(p)
+	b.offset += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+func (b *blobReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.offset
+	case io.SeekEnd:
+		offset += b.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if offset != b.offset {
+		b.Close()
+		b.offset = offset
+	}
+	return offset, nil
+}
+
+func (b *blobReadSeeker) Close() error {
+	if b.body == nil {
+		return nil
+	}
+	err := b.body.Close()
+	b.body = nil
+	return err
+}
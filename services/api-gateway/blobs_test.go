@@ -0,0 +1,529 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/signedurl"
+	"go-drive/internal/storage"
+	filepb "go-drive/proto/file"
+)
+
+const (
+	testUserID = "123e4567-e89b-12d3-a456-426614174000"
+	testFileID = "223e4567-e89b-12d3-a456-426614174000"
+	testKey    = testUserID + "/" + testFileID
+)
+
+// newBlobGateway creates a gateway with temporary local storage and a test signer
+func newBlobGateway(t *testing.T, fileClient filepb.FileServiceClient) *APIGateway {
+	t.Helper()
+	blobs, err := storage.NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+	urls, err := signedurl.NewSigner("test-secret", "http://localhost:8080")
+	require.NoError(t, err)
+
+	return &APIGateway{
+		fileClient: fileClient,
+		blobs:      blobs,
+		urls:       urls,
+	}
+}
+
+func TestAPIGateway_HandleBlobUpload(t *testing.T) {
+	uploadGrant := signedurl.Grant{
+		Method:      http.MethodPut,
+		FileID:      testFileID,
+		UserID:      testUserID,
+		Key:         testKey,
+		MaxSize:     16,
+		ContentType: "text/plain",
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	tests := []struct {
+		name           string
+		grant          signedurl.Grant
+		method         string
+		body           string
+		contentType    string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectStored   bool
+	}{
+		{
+			name:        "successful upload",
+			grant:       uploadGrant,
+			method:      http.MethodPut,
+			body:        "hello world",
+			contentType: "text/plain; charset=utf-8",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("CompleteUpload", mock.Anything, &filepb.CompleteUploadRequest{
+					Id:         testFileID,
+					UserId:     testUserID,
+					Checksum:   "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+					StorageKey: testKey,
+				}).Return(&filepb.CompleteUploadResponse{File: &filepb.File{Id: testFileID, Size: 11}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectStored:   true,
+		},
+		{
+			name:           "body over size limit",
+			grant:          uploadGrant,
+			method:         http.MethodPut,
+			body:           strings.Repeat("x", 17),
+			contentType:    "text/plain",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "wrong content type",
+			grant:          uploadGrant,
+			method:         http.MethodPut,
+			body:           "hello",
+			contentType:    "image/png",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "download url used for upload",
+			grant:          signedurl.Grant{Method: http.MethodGet, FileID: testFileID, UserID: testUserID, Key: testKey, ExpiresAt: time.Now().Add(time.Minute)},
+			method:         http.MethodPut,
+			body:           "hello",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name: "expired url",
+			grant: func() signedurl.Grant {
+				g := uploadGrant
+				g.ExpiresAt = time.Now().Add(-time.Minute)
+				return g
+			}(),
+			method:         http.MethodPut,
+			body:           "hello",
+			contentType:    "text/plain",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := httptest.NewRequest(tt.method, gw.urls.Sign(tt.grant), strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			gw.handleBlob(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			_, err := gw.blobs.Stat(context.Background(), testKey)
+			if tt.expectStored {
+				assert.NoError(t, err)
+				var resp filepb.CompleteUploadResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, int64(11), resp.File.Size)
+			} else {
+				assert.ErrorIs(t, err, storage.ErrNotFound)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIGateway_HandleBlobDownload(t *testing.T) {
+	const (
+		content  = "hello world"
+		checksum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+		etag     = `"` + checksum + `"`
+	)
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mockClient := new(MockFileServiceClient)
+	mockClient.On("GetFile", mock.Anything, &filepb.GetFileRequest{Id: testFileID, UserId: testUserID}).
+		Return(&filepb.GetFileResponse{File: &filepb.File{
+			Id:         testFileID,
+			Size:       int64(len(content)),
+			StorageKey: testKey,
+			Checksum:   checksum,
+			UpdatedAt:  timestamppb.New(updatedAt),
+			ScanStatus: domain.ScanClean,
+		}}, nil)
+	gw := newBlobGateway(t, mockClient)
+	_, err := gw.blobs.Put(context.Background(), testKey, strings.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+
+	download := gw.urls.Sign(signedurl.Grant{
+		Method:      http.MethodGet,
+		FileID:      testFileID,
+		UserID:      testUserID,
+		Key:         testKey,
+		ContentType: "text/plain",
+		ExpiresAt:   time.Now().Add(time.Minute),
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		headers        map[string]string
+		expectedStatus int
+		expectedBody   string
+		validate       func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "streams content",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+				assert.Equal(t, "11", rec.Header().Get("Content-Length"))
+				assert.Equal(t, etag, rec.Header().Get("ETag"))
+				assert.Equal(t, updatedAt.Format(http.TimeFormat), rec.Header().Get("Last-Modified"))
+				assert.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+			},
+		},
+		{
+			name:           "head omits body",
+			method:         http.MethodHead,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "single range",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Range": "bytes=6-"},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "world",
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "bytes 6-10/11", rec.Header().Get("Content-Range"))
+			},
+		},
+		{
+			name:           "suffix range",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Range": "bytes=-3"},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "rld",
+		},
+		{
+			name:           "multiple ranges",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Range": "bytes=0-4,6-10"},
+			expectedStatus: http.StatusPartialContent,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+				require.NoError(t, err)
+				assert.Equal(t, "multipart/byteranges", mediaType)
+
+				reader := multipart.NewReader(rec.Body, params["boundary"])
+				var parts []string
+				for {
+					part, err := reader.NextPart()
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+					data, err := io.ReadAll(part)
+					require.NoError(t, err)
+					parts = append(parts, part.Header.Get("Content-Range")+" "+string(data))
+				}
+				assert.Equal(t, []string{"bytes 0-4/11 hello", "bytes 6-10/11 world"}, parts)
+			},
+		},
+		{
+			name:           "unsatisfiable range",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Range": "bytes=20-30"},
+			expectedStatus: http.StatusRequestedRangeNotSatisfiable,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "bytes */11", rec.Header().Get("Content-Range"))
+			},
+		},
+		{
+			name:           "matching etag",
+			method:         http.MethodGet,
+			headers:        map[string]string{"If-None-Match": etag},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "stale etag",
+			method:         http.MethodGet,
+			headers:        map[string]string{"If-None-Match": `"0000"`},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "not modified since",
+			method:         http.MethodGet,
+			headers:        map[string]string{"If-Modified-Since": updatedAt.Add(time.Hour).Format(http.TimeFormat)},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "modified since",
+			method:         http.MethodGet,
+			headers:        map[string]string{"If-Modified-Since": updatedAt.Add(-time.Hour).Format(http.TimeFormat)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "if-range with stale etag returns full content",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Range": "bytes=6-", "If-Range": `"0000"`},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "if-range with current etag returns range",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Range": "bytes=6-", "If-Range": etag},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, download, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+
+			gw.handleBlob(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" || tt.validate == nil {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+			if tt.validate != nil {
+				tt.validate(t, rec)
+			}
+		})
+	}
+
+	t.Run("tampered url", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, strings.Replace(download, "text%2Fplain", "text%2Fhtml", 1), nil))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("active content sent as attachment", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:      http.MethodGet,
+			FileID:      testFileID,
+			UserID:      testUserID,
+			Key:         testKey,
+			ContentType: "text/html",
+			ExpiresAt:   time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "attachment", rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "sandbox", rec.Header().Get("Content-Security-Policy"))
+	})
+
+	t.Run("missing content", func(t *testing.T) {
+		const missingID = "523e4567-e89b-12d3-a456-426614174000"
+		missingKey := testUserID + "/missing"
+		mockClient.On("GetFile", mock.Anything, &filepb.GetFileRequest{Id: missingID, UserId: testUserID}).
+			Return(&filepb.GetFileResponse{File: &filepb.File{Id: missingID, StorageKey: missingKey}}, nil)
+
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:    http.MethodGet,
+			FileID:    missingID,
+			UserID:    testUserID,
+			Key:       missingKey,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("content replaced since the url was issued", func(t *testing.T) {
+		staleKey := testKey + ".stale"
+		_, err := gw.blobs.Put(context.Background(), staleKey, strings.NewReader("hello"), 5)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:    http.MethodGet,
+			FileID:    testFileID,
+			UserID:    testUserID,
+			Key:       staleKey,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.Empty(t, rec.Header().Get("ETag"), "the current content's validators are not sent for stale content")
+		assert.NotContains(t, rec.Body.String(), "hello")
+	})
+
+	t.Run("deleted file", func(t *testing.T) {
+		const deletedID = "623e4567-e89b-12d3-a456-426614174000"
+		mockClient.On("GetFile", mock.Anything, &filepb.GetFileRequest{Id: deletedID, UserId: testUserID}).
+			Return(nil, status.Error(codes.NotFound, "file not found"))
+
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:    http.MethodGet,
+			FileID:    deletedID,
+			UserID:    testUserID,
+			Key:       testKey,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("quarantined file", func(t *testing.T) {
+		const quarantinedID = "823e4567-e89b-12d3-a456-426614174000"
+		mockClient.On("GetFile", mock.Anything, &filepb.GetFileRequest{Id: quarantinedID, UserId: testUserID}).
+			Return(&filepb.GetFileResponse{File: &filepb.File{Id: quarantinedID, StorageKey: testKey, Quarantined: true}}, nil)
+
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:    http.MethodGet,
+			FileID:    quarantinedID,
+			UserID:    testUserID,
+			Key:       testKey,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.NotContains(t, rec.Body.String(), content)
+	})
+
+	t.Run("not scanned clean", func(t *testing.T) {
+		for id, scan := range map[string]struct {
+			status         string
+			expectedStatus int
+		}{
+			"923e4567-e89b-12d3-a456-426614174000": {domain.ScanPending, http.StatusConflict},
+			"a23e4567-e89b-12d3-a456-426614174000": {domain.ScanError, http.StatusForbidden},
+		} {
+			mockClient.On("GetFile", mock.Anything, &filepb.GetFileRequest{Id: id, UserId: testUserID}).
+				Return(&filepb.GetFileResponse{File: &filepb.File{Id: id, StorageKey: testKey, Checksum: checksum, ScanStatus: scan.status}}, nil)
+
+			rec := httptest.NewRecorder()
+			gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+				Method:    http.MethodGet,
+				FileID:    id,
+				UserID:    testUserID,
+				Key:       testKey,
+				ExpiresAt: time.Now().Add(time.Minute),
+			}), nil))
+
+			assert.Equal(t, scan.expectedStatus, rec.Code, scan.status)
+			assert.NotContains(t, rec.Body.String(), content)
+		}
+	})
+
+	t.Run("scanning turned off", func(t *testing.T) {
+		const unscannedID = "b23e4567-e89b-12d3-a456-426614174000"
+		mockClient.On("GetFile", mock.Anything, &filepb.GetFileRequest{Id: unscannedID, UserId: testUserID}).
+			Return(&filepb.GetFileResponse{File: &filepb.File{Id: unscannedID, StorageKey: testKey, Checksum: checksum, ScanStatus: domain.ScanSkipped}}, nil)
+
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:    http.MethodGet,
+			FileID:    unscannedID,
+			UserID:    testUserID,
+			Key:       testKey,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, content, rec.Body.String())
+	})
+
+	t.Run("earlier version", func(t *testing.T) {
+		const (
+			versionID  = "723e4567-e89b-12d3-a456-426614174000"
+			oldContent = "hello"
+			oldSum     = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+		)
+		versionKey := testKey + ".1"
+		replacedAt := updatedAt.Add(-time.Hour)
+		_, err := gw.blobs.Put(context.Background(), versionKey, strings.NewReader(oldContent), int64(len(oldContent)))
+		require.NoError(t, err)
+		mockClient.On("GetFileVersion", mock.Anything, &filepb.GetFileVersionRequest{FileId: testFileID, VersionId: versionID, UserId: testUserID}).
+			Return(&filepb.GetFileVersionResponse{Version: &filepb.FileVersion{
+				Id:         versionID,
+				FileId:     testFileID,
+				Checksum:   oldSum,
+				CreatedAt:  timestamppb.New(replacedAt),
+				ScanStatus: domain.ScanClean,
+			}}, nil)
+
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:    http.MethodGet,
+			FileID:    testFileID,
+			UserID:    testUserID,
+			Key:       versionKey,
+			VersionID: versionID,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, oldContent, rec.Body.String())
+		assert.Equal(t, `"`+oldSum+`"`, rec.Header().Get("ETag"))
+		assert.Equal(t, replacedAt.Format(http.TimeFormat), rec.Header().Get("Last-Modified"))
+	})
+
+	t.Run("earlier version not scanned yet", func(t *testing.T) {
+		const versionID = "823e4567-e89b-12d3-a456-426614174000"
+		versionKey := testKey + ".2"
+		_, err := gw.blobs.Put(context.Background(), versionKey, strings.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		mockClient.On("GetFileVersion", mock.Anything, &filepb.GetFileVersionRequest{FileId: testFileID, VersionId: versionID, UserId: testUserID}).
+			Return(&filepb.GetFileVersionResponse{Version: &filepb.FileVersion{
+				Id:         versionID,
+				FileId:     testFileID,
+				Checksum:   checksum,
+				ScanStatus: domain.ScanPending,
+			}}, nil)
+
+		rec := httptest.NewRecorder()
+		gw.handleBlob(rec, httptest.NewRequest(http.MethodGet, gw.urls.Sign(signedurl.Grant{
+			Method:    http.MethodGet,
+			FileID:    testFileID,
+			UserID:    testUserID,
+			Key:       versionKey,
+			VersionID: versionID,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}), nil))
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.NotContains(t, rec.Body.String(), content)
+	})
+}
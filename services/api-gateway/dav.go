@@ -0,0 +1,641 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/webdav"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	filepb "go-drive/proto/file"
+)
+
+// davPathPrefix serves the caller's drive over WebDAV, for file managers and office tools
+// to mount, e.g. https://drive.example.com/api/v1/dav/
+const davPathPrefix = "/api/v1/dav"
+
+// davPageSize is the page size folders are listed with
+const davPageSize = 100
+
+// davFiniteDepth answers a PROPFIND of a whole tree, as RFC 4918 section 9.1 prescribes
+const davFiniteDepth = `<?xml version="1.0" encoding="utf-8"?>` +
+	`<D:error xmlns:D="DAV:"><D:propfind-finite-depth/></D:error>`
+
+// handleDAV serves the caller's drive over WebDAV. Content is downloaded like from the REST
+// API; everything else goes through the webdav package, over a davFS of the caller's drive
+// and a lock system of their own.
+func (gw *APIGateway) handleDAV(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	fs := &davFS{gw: gw, userID: userID}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		// Folders are left to the webdav package, which refuses to download them
+		if info, err := fs.stat(r.Context(), strings.TrimPrefix(r.URL.Path, davPathPrefix)); err == nil && !info.IsDir() {
+			clearDeadlines(w)
+			gw.serveFile(w, r, info.file, info.file.StorageKey, info.file.MimeType)
+			return
+		}
+	case "PROPFIND":
+		if depth := r.Header.Get("Depth"); depth != "0" && depth != "1" {
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, davFiniteDepth)
+			return
+		}
+	case http.MethodPut:
+		if r.ContentLength > domain.MaxUploadSize {
+			http.Error(w, "File exceeds the upload size limit", http.StatusRequestEntityTooLarge)
+			return
+		}
+		fs.putSize = r.ContentLength
+		r.Body = &davBody{ReadCloser: http.MaxBytesReader(w, r.Body, domain.MaxUploadSize), fs: fs}
+	}
+
+	clearDeadlines(w)
+	handler := &webdav.Handler{
+		Prefix:     davPathPrefix,
+		FileSystem: fs,
+		LockSystem: gw.davLockSystem(userID),
+	}
+	handler.ServeHTTP(&davResponseWriter{ResponseWriter: w, fs: fs}, r)
+}
+
+// davLockSystem returns the user's WebDAV locks. Locks are held in memory, so only the
+// gateway instance that granted a lock enforces it.
+func (gw *APIGateway) davLockSystem(userID string) webdav.LockSystem {
+	if ls, ok := gw.davLocks.Load(userID); ok {
+		return ls.(webdav.LockSystem)
+	}
+	ls, _ := gw.davLocks.LoadOrStore(userID, webdav.NewMemLS())
+	return ls.(webdav.LockSystem)
+}
+
+// davFS is one user's drive as a webdav.FileSystem, for the duration of a request. Every
+// operation is a call to the file service on the user's behalf, so the permission checks
+// of the REST API apply.
+type davFS struct {
+	gw     *APIGateway
+	userID string
+	// entries caches resolved paths, since the webdav package looks up every entry of a
+	// folder listing again; any change clears it
+	entries map[string]*davFileInfo
+	// failure is the status to answer a failed request with, when the webdav package could
+	// only guess it from the error
+	failure *davFailure
+	// putSize is the declared size of a PUT body, checked against the file's size limit
+	// before any content is stored
+	putSize int64
+}
+
+type davFailure struct {
+	status  int
+	message string
+}
+
+func (fs *davFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	return fs.stat(ctx, name)
+}
+
+// stat looks up the file or folder at name
+func (fs *davFS) stat(ctx context.Context, name string) (*davFileInfo, error) {
+	name = path.Clean("/" + name)
+	if info, ok := fs.entries[name]; ok {
+		return info, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entry, err := fs.gw.fileClient.ResolvePath(ctx, &filepb.ResolvePathRequest{UserId: fs.userID, Path: name})
+	if err != nil {
+		return nil, fs.fail(err)
+	}
+	return fs.remember(name, &davFileInfo{name: path.Base(name), folder: entry.GetFolder(), file: entry.GetFile()}), nil
+}
+
+// parent returns the ID of the folder that holds name, empty for the root, or
+// os.ErrNotExist when there is no such folder
+func (fs *davFS) parent(ctx context.Context, name string) (string, error) {
+	info, err := fs.stat(ctx, path.Dir(path.Clean("/"+name)))
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", os.ErrNotExist
+	}
+	return info.folder.GetId(), nil
+}
+
+func (fs *davFS) Mkdir(ctx context.Context, name string, _ os.FileMode) error {
+	if path.Clean("/"+name) == "/" {
+		return os.ErrExist
+	}
+	parentID, err := fs.parent(ctx, name)
+	if err != nil {
+		return err
+	}
+	fs.forget()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := fs.gw.fileClient.CreateFolder(ctx, &filepb.CreateFolderRequest{
+		Name:     path.Base(name),
+		UserId:   fs.userID,
+		ParentId: parentID,
+	}); err != nil {
+		return fs.fail(err)
+	}
+	return nil
+}
+
+func (fs *davFS) OpenFile(ctx context.Context, name string, flag int, _ os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		return fs.create(ctx, name, flag&os.O_CREATE != 0)
+	}
+	info, err := fs.stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &davFile{fs: fs, ctx: ctx, path: path.Clean("/" + name), info: info}, nil
+}
+
+// create opens the file at name for new content, creating the file if it is missing and
+// mayCreate is set. An existing file keeps the content it replaces as a version.
+func (fs *davFS) create(ctx context.Context, name string, mayCreate bool) (webdav.File, error) {
+	info, err := fs.stat(ctx, name)
+
+	rpcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var upload *davUpload
+	switch {
+	case err == nil && info.IsDir():
+		return nil, fs.refuse(http.StatusMethodNotAllowed, "Path is a folder")
+	case err == nil:
+		resp, err := fs.gw.fileClient.GetUploadURL(rpcCtx, &filepb.GetUploadURLRequest{UserId: fs.userID, FileId: info.file.Id})
+		if err != nil {
+			return nil, fs.fail(err)
+		}
+		upload = &davUpload{info: info, fileID: resp.FileId, key: resp.StorageKey, maxSize: resp.MaxSize}
+	case errors.Is(err, os.ErrNotExist) && mayCreate:
+		folderID, err := fs.parent(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		mimeType, _, _ := strings.Cut(mime.TypeByExtension(path.Ext(name)), ";")
+		created, err := fs.gw.fileClient.CreateFile(rpcCtx, &filepb.CreateFileRequest{
+			Name:     path.Base(name),
+			UserId:   fs.userID,
+			FolderId: folderID,
+			MimeType: mimeType,
+		})
+		if err != nil {
+			return nil, fs.fail(err)
+		}
+		upload = &davUpload{
+			info:    &davFileInfo{name: created.File.Name, file: created.File},
+			fileID:  created.File.Id,
+			key:     created.File.StorageKey,
+			maxSize: created.MaxSize,
+			created: true,
+		}
+	default:
+		return nil, err
+	}
+
+	fs.forget()
+	if upload.maxSize > 0 && fs.putSize > upload.maxSize {
+		if upload.created {
+			fs.gw.discardFile(upload.fileID, fs.userID)
+		}
+		return nil, fs.refuse(http.StatusRequestEntityTooLarge, "File exceeds the upload size limit")
+	}
+	upload.start(ctx, fs)
+	return upload, nil
+}
+
+func (fs *davFS) RemoveAll(ctx context.Context, name string) error {
+	info, err := fs.stat(ctx, name)
+	if err != nil {
+		return err
+	}
+	if info.root() {
+		return fs.refuse(http.StatusForbidden, "The root folder cannot be deleted")
+	}
+	fs.forget()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if info.IsDir() {
+		_, err = fs.gw.fileClient.DeleteFolder(ctx, &filepb.DeleteFolderRequest{Id: info.folder.Id, UserId: fs.userID})
+	} else {
+		_, err = fs.gw.fileClient.DeleteFile(ctx, &filepb.DeleteFileRequest{Id: info.file.Id, UserId: fs.userID})
+	}
+	if err != nil {
+		return fs.fail(err)
+	}
+	return nil
+}
+
+// Rename moves a file or folder. A folder is moved and renamed in two steps, so a failed
+// rename can leave it moved under its old name.
+func (fs *davFS) Rename(ctx context.Context, oldName, newName string) error {
+	info, err := fs.stat(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	if info.root() {
+		return fs.refuse(http.StatusForbidden, "The root folder cannot be moved")
+	}
+	parentID, err := fs.parent(ctx, newName)
+	if errors.Is(err, os.ErrNotExist) {
+		return fs.refuse(http.StatusConflict, "Destination folder not found")
+	}
+	if err != nil {
+		return err
+	}
+	name := path.Base(path.Clean("/" + newName))
+	fs.forget()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if !info.IsDir() {
+		_, err = fs.gw.fileClient.MoveFile(ctx, &filepb.MoveFileRequest{
+			Id:       info.file.Id,
+			UserId:   fs.userID,
+			FolderId: parentID,
+			Name:     name,
+		})
+		if err != nil {
+			return fs.fail(err)
+		}
+		return nil
+	}
+
+	if parentID != info.folder.ParentId {
+		if _, err := fs.gw.fileClient.MoveFolder(ctx, &filepb.MoveFolderRequest{
+			Id:       info.folder.Id,
+			UserId:   fs.userID,
+			ParentId: parentID,
+		}); err != nil {
+			return fs.fail(err)
+		}
+	}
+	if name != info.folder.Name {
+		if _, err := fs.gw.fileClient.RenameFolder(ctx, &filepb.RenameFolderRequest{
+			Id:     info.folder.Id,
+			UserId: fs.userID,
+			Name:   name,
+		}); err != nil {
+			return fs.fail(err)
+		}
+	}
+	return nil
+}
+
+// list returns the entries of the folder at dir, remembering them for the lookups that follow
+func (fs *davFS) list(ctx context.Context, dir, folderID string) ([]os.FileInfo, error) {
+	var infos []os.FileInfo
+	for page := int32(1); ; page++ {
+		pageCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		resp, err := fs.gw.fileClient.ListFolder(pageCtx, &filepb.ListFolderRequest{
+			UserId:   fs.userID,
+			FolderId: folderID,
+			Page:     page,
+			PageSize: davPageSize,
+		})
+		cancel()
+		if err != nil {
+			return nil, fs.fail(err)
+		}
+
+		for _, folder := range resp.Folders {
+			infos = append(infos, fs.remember(path.Join(dir, folder.Name), &davFileInfo{name: folder.Name, folder: folder}))
+		}
+		for _, file := range resp.Files {
+			infos = append(infos, fs.remember(path.Join(dir, file.Name), &davFileInfo{name: file.Name, file: file}))
+		}
+		if len(resp.Folders)+len(resp.Files) < davPageSize {
+			return infos, nil
+		}
+	}
+}
+
+func (fs *davFS) remember(name string, info *davFileInfo) *davFileInfo {
+	if fs.entries == nil {
+		fs.entries = make(map[string]*davFileInfo)
+	}
+	fs.entries[name] = info
+	return info
+}
+
+func (fs *davFS) forget() {
+	fs.entries = nil
+}
+
+// fail translates a file service error for the webdav package, which tells missing and
+// existing entries apart by os.ErrNotExist and os.ErrExist. Other errors are answered with
+// the status the REST API would use, except that an exhausted quota is WebDAV's 507.
+func (fs *davFS) fail(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return os.ErrNotExist
+	case codes.AlreadyExists:
+		return os.ErrExist
+	case codes.ResourceExhausted:
+		return fs.refuse(http.StatusInsufficientStorage, status.Convert(err).Message())
+	}
+	return fs.refuse(httpStatusFromGRPC(err), status.Convert(err).Message())
+}
+
+// refuse records the status to answer the request with and returns the failure
+func (fs *davFS) refuse(code int, message string) error {
+	fs.failure = &davFailure{status: code, message: message}
+	return errors.New(message)
+}
+
+// davFileInfo describes a file, a folder, or the root when neither is set
+type davFileInfo struct {
+	name   string
+	folder *filepb.Folder
+	file   *filepb.File
+}
+
+func (i *davFileInfo) Name() string { return i.name }
+func (i *davFileInfo) Size() int64  { return i.file.GetSize() }
+func (i *davFileInfo) IsDir() bool  { return i.file == nil }
+func (i *davFileInfo) Sys() any     { return nil }
+func (i *davFileInfo) root() bool   { return i.file == nil && i.folder == nil }
+
+func (i *davFileInfo) Mode() os.FileMode {
+	if i.IsDir() {
+		return os.ModeDir | 0o755
+	}
+	return 0o644
+}
+
+func (i *davFileInfo) ModTime() time.Time {
+	updated := i.file.GetUpdatedAt()
+	if i.IsDir() {
+		updated = i.folder.GetUpdatedAt()
+	}
+	if updated == nil {
+		return time.Time{}
+	}
+	return updated.AsTime()
+}
+
+// ContentType spares the webdav package sniffing the content for PROPFIND
+func (i *davFileInfo) ContentType(context.Context) (string, error) {
+	if i.file.GetMimeType() == "" {
+		return "application/octet-stream", nil
+	}
+	return i.file.GetMimeType(), nil
+}
+
+// ETag matches the ETag the content is downloaded with
+func (i *davFileInfo) ETag(context.Context) (string, error) {
+	if i.file.GetChecksum() == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return `"` + i.file.GetChecksum() + `"`, nil
+}
+
+// davFile is a file or folder opened for reading. The webdav package reads files to copy
+// them; content is only fetched once read, under the same checks as a download.
+type davFile struct {
+	fs      *davFS
+	ctx     context.Context
+	path    string
+	info    *davFileInfo
+	content *blobReadSeeker
+	// children holds the folder's entries that Readdir has yet to return
+	children []os.FileInfo
+	listed   bool
+}
+
+func (f *davFile) Read(p []byte) (int, error) {
+	content, err := f.open()
+	if err != nil {
+		return 0, err
+	}
+	n, err := content.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, f.fs.refuse(http.StatusInternalServerError, "Failed to read file content")
+	}
+	return n, err
+}
+
+func (f *davFile) Seek(offset int64, whence int) (int64, error) {
+	content, err := f.open()
+	if err != nil {
+		return 0, err
+	}
+	return content.Seek(offset, whence)
+}
+
+func (f *davFile) open() (*blobReadSeeker, error) {
+	if f.content != nil {
+		return f.content, nil
+	}
+	if f.info.IsDir() {
+		return nil, f.fs.refuse(http.StatusMethodNotAllowed, "Path is a folder")
+	}
+	if code, reason := unservable(f.info.file); code != 0 {
+		return nil, f.fs.refuse(code, reason)
+	}
+	f.content = &blobReadSeeker{ctx: f.ctx, blobs: f.fs.gw.blobs, key: f.info.file.StorageKey, size: f.info.file.Size}
+	return f.content, nil
+}
+
+func (f *davFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.info.IsDir() {
+		return nil, f.fs.refuse(http.StatusMethodNotAllowed, "Path is a file")
+	}
+	if !f.listed {
+		children, err := f.fs.list(f.ctx, f.path, f.info.folder.GetId())
+		if err != nil {
+			return nil, err
+		}
+		f.children, f.listed = children, true
+	}
+
+	if count <= 0 {
+		children := f.children
+		f.children = nil
+		return children, nil
+	}
+	if len(f.children) == 0 {
+		return nil, io.EOF
+	}
+	n := min(count, len(f.children))
+	children := f.children[:n]
+	f.children = f.children[n:]
+	return children, nil
+}
+
+func (f *davFile) Stat() (os.FileInfo, error) { return f.info, nil }
+
+func (f *davFile) Write([]byte) (int, error) { return 0, os.ErrPermission }
+
+func (f *davFile) Close() error {
+	if f.content == nil {
+		return nil
+	}
+	return f.content.Close()
+}
+
+// davUpload streams what is written to it into the blob store and records it as the
+// file's content on Close. Should anything fail while the request is served, such as
+// reading the request body or the file being copied, Close discards the content instead.
+type davUpload struct {
+	fs     *davFS
+	ctx    context.Context
+	info   *davFileInfo
+	fileID string
+	key    string
+	// maxSize is the largest content the file service accepts for the file
+	maxSize int64
+	// created is set when the file was created for this upload, so that a failed upload
+	// deletes it again
+	created bool
+	written int64
+	pw      *io.PipeWriter
+	hash    hash.Hash
+	stored  chan error
+}
+
+// start streams what is written to the upload into storage under its staging key
+func (u *davUpload) start(ctx context.Context, fs *davFS) {
+	pr, pw := io.Pipe()
+	u.fs, u.ctx, u.pw, u.hash, u.stored = fs, ctx, pw, sha256.New(), make(chan error, 1)
+	go func() {
+		_, err := fs.gw.blobs.Put(ctx, u.key, io.TeeReader(pr, u.hash), -1)
+		pr.CloseWithError(err)
+		u.stored <- err
+	}()
+}
+
+func (u *davUpload) Write(p []byte) (int, error) {
+	if u.maxSize > 0 && u.written+int64(len(p)) > u.maxSize {
+		return 0, u.fs.refuse(http.StatusRequestEntityTooLarge, "File exceeds the upload size limit")
+	}
+	n, err := u.pw.Write(p)
+	u.written += int64(n)
+	return n, err
+}
+
+func (u *davUpload) Close() error {
+	if failure := u.fs.failure; failure != nil {
+		u.pw.CloseWithError(errors.New(failure.message))
+		<-u.stored
+		u.discard(true)
+		return errors.New(failure.message)
+	}
+	u.pw.Close()
+	if err := <-u.stored; err != nil {
+		u.discard(true)
+		return u.fs.refuse(http.StatusInternalServerError, "Failed to store file content")
+	}
+
+	ctx, cancel := context.WithTimeout(u.ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := u.fs.gw.fileClient.CompleteUpload(ctx, &filepb.CompleteUploadRequest{
+		Id:         u.fileID,
+		UserId:     u.fs.userID,
+		Checksum:   hex.EncodeToString(u.hash.Sum(nil)),
+		StorageKey: u.key,
+	})
+	if err != nil {
+		u.discard(false)
+		return u.fs.fail(err)
+	}
+	// The webdav package takes the ETag of the new content from here
+	u.info.file = resp.File
+	return nil
+}
+
+// discard cleans up after a failed upload: the staged content when it never reached the
+// file service, which drops content it refuses, and the file when it was created for the upload
+func (u *davUpload) discard(staged bool) {
+	if staged {
+		u.fs.gw.discardBlob(context.WithoutCancel(u.ctx), u.key)
+	}
+	if u.created {
+		u.fs.gw.discardFile(u.fileID, u.fs.userID)
+	}
+}
+
+func (u *davUpload) Stat() (os.FileInfo, error) { return u.info, nil }
+
+func (u *davUpload) Read([]byte) (int, error) { return 0, os.ErrPermission }
+
+func (u *davUpload) Seek(int64, int) (int64, error) { return 0, os.ErrPermission }
+
+func (u *davUpload) Readdir(int) ([]os.FileInfo, error) { return nil, os.ErrPermission }
+
+// davBody records a failure to read a request body, so that the upload it feeds is discarded
+type davBody struct {
+	io.ReadCloser
+	fs *davFS
+}
+
+func (b *davBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return n, b.fs.refuse(http.StatusRequestEntityTooLarge, "File exceeds the upload size limit")
+		}
+		return n, b.fs.refuse(http.StatusBadRequest, "Failed to read request body")
+	}
+	return n, err
+}
+
+// davResponseWriter answers a request the webdav package fails with the status recorded by
+// the request's davFS, where there is one
+type davResponseWriter struct {
+	http.ResponseWriter
+	fs       *davFS
+	replaced bool
+}
+
+func (w *davResponseWriter) WriteHeader(code int) {
+	if failure := w.fs.failure; code >= http.StatusBadRequest && failure != nil {
+		w.replaced = true
+		http.Error(w.ResponseWriter, failure.message, failure.status)
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *davResponseWriter) Write(p []byte) (int, error) {
+	if w.replaced {
+		return len(p), nil
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *davResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	filepb "go-drive/proto/file"
+)
+
+const helloChecksum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+func newDAVRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, davPathPrefix+path, strings.NewReader(body))
+	req.Header.Set(userIDHeader, testUserID)
+	return req
+}
+
+// resolves sets up the file service to resolve a path to a file or folder, or to fail to
+func resolves(client *MockFileServiceClient, path string, resp *filepb.ResolvePathResponse) *mock.Call {
+	call := client.On("ResolvePath", mock.Anything, &filepb.ResolvePathRequest{UserId: testUserID, Path: path})
+	if resp == nil {
+		return call.Return(nil, status.Error(codes.NotFound, "path not found"))
+	}
+	return call.Return(resp, nil)
+}
+
+func TestAPIGateway_HandleDAV(t *testing.T) {
+	report := &filepb.File{Id: testFileID, Name: "report.txt", StorageKey: testKey, MimeType: "text/plain", Size: 11, Checksum: helloChecksum, ScanStatus: domain.ScanClean}
+	projects := &filepb.Folder{Id: testFolderID, Name: "projects"}
+	const archiveID = "423e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name           string
+		request        func() *http.Request
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedBody   []string
+		expectedHeader map[string]string
+	}{
+		{
+			name: "listing a folder looks its entries up once",
+			request: func() *http.Request {
+				req := newDAVRequest("PROPFIND", "/projects/", "")
+				req.Header.Set("Depth", "1")
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/projects", &filepb.ResolvePathResponse{Folder: projects}).Once()
+				client.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, FolderId: testFolderID, Page: 1, PageSize: davPageSize}).
+					Return(&filepb.ListFolderResponse{Files: []*filepb.File{report}, TotalCount: 1}, nil).Once()
+			},
+			expectedStatus: http.StatusMultiStatus,
+			expectedBody: []string{
+				"<D:href>/api/v1/dav/projects/report.txt</D:href>",
+				`<D:getetag>"` + helloChecksum + `"</D:getetag>`,
+				"<D:getcontenttype>text/plain</D:getcontenttype>",
+			},
+		},
+		{
+			name:           "listing a whole tree is refused",
+			request:        func() *http.Request { return newDAVRequest("PROPFIND", "/", "") },
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   []string{"propfind-finite-depth"},
+		},
+		{
+			name:    "download",
+			request: func() *http.Request { return newDAVRequest(http.MethodGet, "/projects/report.txt", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/projects/report.txt", &filepb.ResolvePathResponse{File: report})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"hello world"},
+			expectedHeader: map[string]string{"ETag": `"` + helloChecksum + `"`},
+		},
+		{
+			name:    "upload of a new file",
+			request: func() *http.Request { return newDAVRequest(http.MethodPut, "/projects/notes.txt", "hello world") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/projects/notes.txt", nil)
+				resolves(client, "/projects", &filepb.ResolvePathResponse{Folder: projects})
+				client.On("CreateFile", mock.Anything, &filepb.CreateFileRequest{
+					Name: "notes.txt", UserId: testUserID, FolderId: testFolderID, MimeType: "text/plain",
+				}).Return(&filepb.CreateFileResponse{File: &filepb.File{Id: testFileID, Name: "notes.txt", StorageKey: testKey}}, nil)
+				client.On("CompleteUpload", mock.Anything, &filepb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: helloChecksum, StorageKey: testKey}).
+					Return(&filepb.CompleteUploadResponse{File: report}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedHeader: map[string]string{"ETag": `"` + helloChecksum + `"`},
+		},
+		{
+			name:    "upload over the file size limit",
+			request: func() *http.Request { return newDAVRequest(http.MethodPut, "/projects/notes.txt", "hello world") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/projects/notes.txt", nil)
+				resolves(client, "/projects", &filepb.ResolvePathResponse{Folder: projects})
+				client.On("CreateFile", mock.Anything, mock.Anything).
+					Return(&filepb.CreateFileResponse{File: &filepb.File{Id: testFileID, Name: "notes.txt", StorageKey: testKey}, MaxSize: 4}, nil)
+				client.On("DeleteFile", mock.Anything, &filepb.DeleteFileRequest{Id: testFileID, UserId: testUserID}).
+					Return(&filepb.DeleteFileResponse{}, nil)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "upload of unknown size over the file size limit",
+			request: func() *http.Request {
+				req := newDAVRequest(http.MethodPut, "/projects/notes.txt", "hello world")
+				req.ContentLength = -1
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/projects/notes.txt", nil)
+				resolves(client, "/projects", &filepb.ResolvePathResponse{Folder: projects})
+				client.On("CreateFile", mock.Anything, mock.Anything).
+					Return(&filepb.CreateFileResponse{File: &filepb.File{Id: testFileID, Name: "notes.txt", StorageKey: testKey}, MaxSize: 4}, nil)
+				client.On("DeleteFile", mock.Anything, &filepb.DeleteFileRequest{Id: testFileID, UserId: testUserID}).
+					Return(&filepb.DeleteFileResponse{}, nil)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "upload into a missing folder",
+			request: func() *http.Request { return newDAVRequest(http.MethodPut, "/missing/notes.txt", "hello world") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/missing/notes.txt", nil)
+				resolves(client, "/missing", nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:    "upload over the storage quota",
+			request: func() *http.Request { return newDAVRequest(http.MethodPut, "/projects/report.txt", "hello world") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/projects/report.txt", &filepb.ResolvePathResponse{File: report})
+				client.On("GetUploadURL", mock.Anything, &filepb.GetUploadURLRequest{UserId: testUserID, FileId: testFileID}).
+					Return(&filepb.GetUploadURLResponse{FileId: testFileID, StorageKey: testKey + ".2"}, nil)
+				client.On("CompleteUpload", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.ResourceExhausted, "storage quota exceeded"))
+			},
+			expectedStatus: http.StatusInsufficientStorage,
+			expectedBody:   []string{"storage quota exceeded"},
+		},
+		{
+			name:    "new folder",
+			request: func() *http.Request { return newDAVRequest("MKCOL", "/docs", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/", &filepb.ResolvePathResponse{})
+				client.On("CreateFolder", mock.Anything, &filepb.CreateFolderRequest{Name: "docs", UserId: testUserID}).
+					Return(&filepb.CreateFolderResponse{Folder: &filepb.Folder{Name: "docs"}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "move of a file",
+			request: func() *http.Request {
+				req := newDAVRequest("MOVE", "/projects/report.txt", "")
+				req.Header.Set("Destination", "http://example.com"+davPathPrefix+"/archive/final.txt")
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/archive/final.txt", nil)
+				resolves(client, "/projects/report.txt", &filepb.ResolvePathResponse{File: report})
+				resolves(client, "/archive", &filepb.ResolvePathResponse{Folder: &filepb.Folder{Id: archiveID, Name: "archive"}})
+				client.On("MoveFile", mock.Anything, &filepb.MoveFileRequest{Id: testFileID, UserId: testUserID, FolderId: archiveID, Name: "final.txt"}).
+					Return(&filepb.MoveFileResponse{File: report}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "rename of a folder",
+			request: func() *http.Request {
+				req := newDAVRequest("MOVE", "/projects", "")
+				req.Header.Set("Destination", davPathPrefix+"/work")
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/work", nil)
+				resolves(client, "/projects", &filepb.ResolvePathResponse{Folder: projects})
+				resolves(client, "/", &filepb.ResolvePathResponse{})
+				client.On("RenameFolder", mock.Anything, &filepb.RenameFolderRequest{Id: testFolderID, UserId: testUserID, Name: "work"}).
+					Return(&filepb.RenameFolderResponse{Folder: projects}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "copy of a quarantined file stores nothing",
+			request: func() *http.Request {
+				req := newDAVRequest("COPY", "/projects/report.txt", "")
+				req.Header.Set("Destination", davPathPrefix+"/copy.txt")
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				quarantined := &filepb.File{Id: testFileID, Name: "report.txt", StorageKey: testKey, Size: 11, Quarantined: true}
+				resolves(client, "/projects/report.txt", &filepb.ResolvePathResponse{File: quarantined})
+				resolves(client, "/copy.txt", nil)
+				resolves(client, "/", &filepb.ResolvePathResponse{})
+				client.On("CreateFile", mock.Anything, mock.Anything).
+					Return(&filepb.CreateFileResponse{File: &filepb.File{Id: testFileID, Name: "copy.txt", StorageKey: testKey + ".copy"}}, nil)
+				client.On("DeleteFile", mock.Anything, &filepb.DeleteFileRequest{Id: testFileID, UserId: testUserID}).
+					Return(&filepb.DeleteFileResponse{}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   []string{"File is quarantined"},
+		},
+		{
+			name:           "deleting the root is refused",
+			request:        func() *http.Request { return newDAVRequest(http.MethodDelete, "/", "") },
+			mockSetup:      func(client *MockFileServiceClient) { resolves(client, "/", &filepb.ResolvePathResponse{}) },
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "unauthenticated",
+			request: func() *http.Request {
+				req := newDAVRequest("PROPFIND", "/", "")
+				req.Header.Del(userIDHeader)
+				return req
+			},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+			_, err := gw.blobs.Put(context.Background(), testKey, strings.NewReader("hello world"), 11)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			gw.handleDAV(rec, tt.request())
+
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			for _, body := range tt.expectedBody {
+				assert.Contains(t, rec.Body.String(), body)
+			}
+			for header, value := range tt.expectedHeader {
+				assert.Equal(t, value, rec.Header().Get(header))
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIGateway_HandleDAV_Locks(t *testing.T) {
+	mockClient := new(MockFileServiceClient)
+	resolves(mockClient, "/report.txt", &filepb.ResolvePathResponse{File: &filepb.File{Id: testFileID, Name: "report.txt", StorageKey: testKey}})
+	gw := newBlobGateway(t, mockClient)
+
+	lock := newDAVRequest("LOCK", "/report.txt", `<?xml version="1.0" encoding="utf-8"?>
+<D:lockinfo xmlns:D="DAV:"><D:lockscope><D:exclusive/></D:lockscope><D:locktype><D:write/></D:locktype></D:lockinfo>`)
+	rec := httptest.NewRecorder()
+	gw.handleDAV(rec, lock)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	token := rec.Header().Get("Lock-Token")
+	require.NotEmpty(t, token)
+
+	rec = httptest.NewRecorder()
+	gw.handleDAV(rec, newDAVRequest(http.MethodPut, "/report.txt", "hello world"))
+	assert.Equal(t, http.StatusLocked, rec.Code, "writes without the lock token are refused")
+
+	unlock := newDAVRequest("UNLOCK", "/report.txt", "")
+	unlock.Header.Set("Lock-Token", token)
+	rec = httptest.NewRecorder()
+	gw.handleDAV(rec, unlock)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestDAVFile_Readdir(t *testing.T) {
+	mockClient := new(MockFileServiceClient)
+	files := make([]*filepb.File, davPageSize)
+	for i := range files {
+		files[i] = &filepb.File{Id: testFileID, Name: strings.Repeat("f", i+1)}
+	}
+	mockClient.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, Page: 1, PageSize: davPageSize}).
+		Return(&filepb.ListFolderResponse{Files: files, TotalCount: davPageSize + 1}, nil)
+	mockClient.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, Page: 2, PageSize: davPageSize}).
+		Return(&filepb.ListFolderResponse{Folders: []*filepb.Folder{{Name: "last"}}, TotalCount: davPageSize + 1}, nil)
+	resolves(mockClient, "/", &filepb.ResolvePathResponse{})
+	fs := &davFS{gw: newBlobGateway(t, mockClient), userID: testUserID}
+
+	f, err := fs.OpenFile(context.Background(), "/", 0, 0)
+	require.NoError(t, err)
+	first, err := f.Readdir(davPageSize)
+	require.NoError(t, err)
+	assert.Len(t, first, davPageSize)
+	rest, err := f.Readdir(davPageSize)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.True(t, rest[0].IsDir())
+	_, err = f.Readdir(davPageSize)
+	assert.ErrorIs(t, err, io.EOF)
+
+	info, err := fs.Stat(context.Background(), "/last")
+	require.NoError(t, err, "listed entries are not looked up again")
+	assert.Equal(t, "last", info.Name())
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/signedurl"
+	filepb "go-drive/proto/file"
+)
+
+// fsPathPrefix addresses files and folders by their path in the caller's drive,
+// e.g. /api/v1/fs/projects/2026/report.pdf
+const fsPathPrefix = "/api/v1/fs"
+
+func (gw *APIGateway) handleFS(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	filePath := strings.TrimPrefix(r.URL.Path, fsPathPrefix)
+	if filePath == "" {
+		filePath = "/"
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		gw.handleFSGet(w, r, userID, filePath)
+	case http.MethodPut:
+		gw.handleFSPut(w, r, userID, filePath)
+	case http.MethodPost:
+		gw.handleFSMkdir(w, r, userID, filePath)
+	case http.MethodDelete:
+		gw.handleFSDelete(w, r, userID, filePath)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleFSGet streams a file's content, or lists a folder as JSON
+func (gw *APIGateway) handleFSGet(w http.ResponseWriter, r *http.Request, userID, filePath string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	entry, err := gw.fileClient.ResolvePath(ctx, &filepb.ResolvePathRequest{UserId: userID, Path: filePath})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	if file := entry.GetFile(); file != nil {
+		clearDeadlines(w)
+		gw.serveFile(w, r, file, file.StorageKey, file.MimeType)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	resp, err := gw.fileClient.ListFolder(ctx, &filepb.ListFolderRequest{
+		UserId:   userID,
+		FolderId: entry.GetFolder().GetId(),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodHead {
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleFSPut stores the request body at a path, creating missing parent folders.
+// An existing file at the path gets new content; the content it replaces is kept as a version.
+func (gw *APIGateway) handleFSPut(w http.ResponseWriter, r *http.Request, userID, filePath string) {
+	dir, name := path.Split(strings.TrimSuffix(filePath, "/"))
+	if name == "" {
+		http.Error(w, "Path must name a file", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	// The file service sets the size limit, as it does for the upload URLs it signs
+	grant := signedurl.Grant{Method: http.MethodPut, UserID: userID}
+	var created bool
+	entry, err := gw.fileClient.ResolvePath(ctx, &filepb.ResolvePathRequest{UserId: userID, Path: filePath})
+	switch {
+	case err == nil && entry.GetFile() == nil:
+		http.Error(w, "Path is a folder", http.StatusConflict)
+		return
+	case err == nil:
+		upload, err := gw.fileClient.GetUploadURL(ctx, &filepb.GetUploadURLRequest{UserId: userID, FileId: entry.GetFile().Id})
+		if err != nil {
+			writeGRPCError(w, err)
+			return
+		}
+		grant.FileID, grant.Key, grant.MaxSize = upload.FileId, upload.StorageKey, upload.MaxSize
+	case status.Code(err) == codes.NotFound:
+		var folderID string
+		if strings.Trim(dir, "/") != "" {
+			folder, err := gw.fileClient.CreateFolderPath(ctx, &filepb.CreateFolderPathRequest{UserId: userID, Path: dir})
+			if err != nil {
+				writeGRPCError(w, err)
+				return
+			}
+			folderID = folder.Folder.Id
+		}
+
+		mimeType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		file, err := gw.fileClient.CreateFile(ctx, &filepb.CreateFileRequest{
+			Name:     name,
+			UserId:   userID,
+			FolderId: folderID,
+			MimeType: mimeType,
+		})
+		if err != nil {
+			writeGRPCError(w, err)
+			return
+		}
+		grant.FileID, grant.Key, grant.MaxSize = file.File.Id, file.File.StorageKey, file.MaxSize
+		created = true
+	default:
+		writeGRPCError(w, err)
+		return
+	}
+	cancel()
+
+	clearDeadlines(w)
+	if !gw.receiveBlob(w, r, grant) && created {
+		gw.discardFile(grant.FileID, userID)
+	}
+}
+
+// handleFSMkdir creates the folder at a path together with any missing parents
+func (gw *APIGateway) handleFSMkdir(w http.ResponseWriter, r *http.Request, userID, filePath string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.CreateFolderPath(ctx, &filepb.CreateFolderPathRequest{UserId: userID, Path: filePath})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleFSDelete deletes the file or folder at a path
+func (gw *APIGateway) handleFSDelete(w http.ResponseWriter, r *http.Request, userID, filePath string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	entry, err := gw.fileClient.ResolvePath(ctx, &filepb.ResolvePathRequest{UserId: userID, Path: filePath})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	var resp any
+	switch {
+	case entry.GetFile() != nil:
+		resp, err = gw.fileClient.DeleteFile(ctx, &filepb.DeleteFileRequest{Id: entry.GetFile().Id, UserId: userID})
+	case entry.GetFolder() != nil:
+		resp, err = gw.fileClient.DeleteFolder(ctx, &filepb.DeleteFolderRequest{Id: entry.GetFolder().Id, UserId: userID})
+	default:
+		http.Error(w, "The root folder cannot be deleted", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
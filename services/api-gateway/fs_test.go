@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	filepb "go-drive/proto/file"
+)
+
+const testFolderID = "323e4567-e89b-12d3-a456-426614174000"
+
+func newFSRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, fsPathPrefix+path, strings.NewReader(body))
+	req.Header.Set(userIDHeader, testUserID)
+	return req
+}
+
+func TestAPIGateway_HandleFSGet(t *testing.T) {
+	file := &filepb.File{Id: testFileID, Name: "report.txt", StorageKey: testKey, MimeType: "text/plain"}
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "file content",
+			path: "/projects/report.txt",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, &filepb.ResolvePathRequest{UserId: testUserID, Path: "/projects/report.txt"}).
+					Return(&filepb.ResolvePathResponse{File: file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello world",
+		},
+		{
+			name: "folder listing",
+			path: "/projects",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(&filepb.ResolvePathResponse{Folder: &filepb.Folder{Id: testFolderID}}, nil)
+				client.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, FolderId: testFolderID}).
+					Return(&filepb.ListFolderResponse{Files: []*filepb.File{file}, TotalCount: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"total_count":1`,
+		},
+		{
+			name: "root listing",
+			path: "",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, &filepb.ResolvePathRequest{UserId: testUserID, Path: "/"}).
+					Return(&filepb.ResolvePathResponse{}, nil)
+				client.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID}).
+					Return(&filepb.ListFolderResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing path",
+			path: "/nope",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.NotFound, "path not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+			_, err := gw.blobs.Put(context.Background(), testKey, strings.NewReader("hello world"), 11)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			gw.handleFS(rec, newFSRequest(http.MethodGet, tt.path, ""))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIGateway_HandleFSPut(t *testing.T) {
+	const checksum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	file := &filepb.File{Id: testFileID, Name: "report.txt", StorageKey: testKey}
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectStored   bool
+		storedKey      string
+	}{
+		{
+			name: "new file with missing parents",
+			path: "/projects/2026/report.txt",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.NotFound, "path not found"))
+				client.On("CreateFolderPath", mock.Anything, &filepb.CreateFolderPathRequest{UserId: testUserID, Path: "/projects/2026/"}).
+					Return(&filepb.CreateFolderPathResponse{Folder: &filepb.Folder{Id: testFolderID}}, nil)
+				client.On("CreateFile", mock.Anything, &filepb.CreateFileRequest{
+					Name: "report.txt", UserId: testUserID, FolderId: testFolderID, MimeType: "text/plain",
+				}).Return(&filepb.CreateFileResponse{File: file}, nil)
+				client.On("CompleteUpload", mock.Anything, &filepb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: checksum, StorageKey: testKey}).
+					Return(&filepb.CompleteUploadResponse{File: file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectStored:   true,
+		},
+		{
+			name: "new file at root",
+			path: "/report.txt",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.NotFound, "path not found"))
+				client.On("CreateFile", mock.Anything, &filepb.CreateFileRequest{
+					Name: "report.txt", UserId: testUserID, MimeType: "text/plain",
+				}).Return(&filepb.CreateFileResponse{File: file}, nil)
+				client.On("CompleteUpload", mock.Anything, mock.Anything).
+					Return(&filepb.CompleteUploadResponse{File: file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectStored:   true,
+		},
+		{
+			name: "existing file gets a new version",
+			path: "/report.txt",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(&filepb.ResolvePathResponse{File: file}, nil)
+				client.On("GetUploadURL", mock.Anything, &filepb.GetUploadURLRequest{UserId: testUserID, FileId: testFileID}).
+					Return(&filepb.GetUploadURLResponse{FileId: testFileID, StorageKey: testKey + ".2"}, nil)
+				client.On("CompleteUpload", mock.Anything, &filepb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: checksum, StorageKey: testKey + ".2"}).
+					Return(&filepb.CompleteUploadResponse{File: file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectStored:   true,
+			storedKey:      testKey + ".2",
+		},
+		{
+			name: "new file over the file size limit is discarded",
+			path: "/report.txt",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.NotFound, "path not found"))
+				client.On("CreateFile", mock.Anything, mock.Anything).
+					Return(&filepb.CreateFileResponse{File: file, MaxSize: 4}, nil)
+				client.On("DeleteFile", mock.Anything, &filepb.DeleteFileRequest{Id: testFileID, UserId: testUserID}).
+					Return(&filepb.DeleteFileResponse{}, nil)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "existing file over the file size limit keeps its content",
+			path: "/report.txt",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(&filepb.ResolvePathResponse{File: file}, nil)
+				client.On("GetUploadURL", mock.Anything, mock.Anything).
+					Return(&filepb.GetUploadURLResponse{FileId: testFileID, StorageKey: testKey + ".2", MaxSize: 4}, nil)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			storedKey:      testKey + ".2",
+		},
+		{
+			name: "path is a folder",
+			path: "/projects",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(&filepb.ResolvePathResponse{Folder: &filepb.Folder{Id: testFolderID}}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "over quota",
+			path: "/report.txt",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ResolvePath", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.NotFound, "path not found"))
+				client.On("CreateFile", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.ResourceExhausted, "storage quota exceeded"))
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "root",
+			path:           "/",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := newFSRequest(http.MethodPut, tt.path, "hello world")
+			req.Header.Set("Content-Type", "text/plain")
+			rec := httptest.NewRecorder()
+			gw.handleFS(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			key := testKey
+			if tt.storedKey != "" {
+				key = tt.storedKey
+			}
+			_, err := gw.blobs.Stat(context.Background(), key)
+			assert.Equal(t, tt.expectStored, err == nil)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIGateway_HandleFSMkdirAndDelete(t *testing.T) {
+	t.Run("mkdir", func(t *testing.T) {
+		mockClient := new(MockFileServiceClient)
+		mockClient.On("CreateFolderPath", mock.Anything, &filepb.CreateFolderPathRequest{UserId: testUserID, Path: "/a/b/c"}).
+			Return(&filepb.CreateFolderPathResponse{Folder: &filepb.Folder{Id: testFolderID, Name: "c"}}, nil)
+		gw := newBlobGateway(t, mockClient)
+
+		rec := httptest.NewRecorder()
+		gw.handleFS(rec, newFSRequest(http.MethodPost, "/a/b/c", ""))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var resp filepb.CreateFolderPathResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, testFolderID, resp.Folder.Id)
+	})
+
+	t.Run("delete folder", func(t *testing.T) {
+		mockClient := new(MockFileServiceClient)
+		mockClient.On("ResolvePath", mock.Anything, mock.Anything).
+			Return(&filepb.ResolvePathResponse{Folder: &filepb.Folder{Id: testFolderID}}, nil)
+		mockClient.On("DeleteFolder", mock.Anything, &filepb.DeleteFolderRequest{Id: testFolderID, UserId: testUserID}).
+			Return(&filepb.DeleteFolderResponse{DeletedFolders: 1}, nil)
+		gw := newBlobGateway(t, mockClient)
+
+		rec := httptest.NewRecorder()
+		gw.handleFS(rec, newFSRequest(http.MethodDelete, "/a", ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		gw := newBlobGateway(t, new(MockFileServiceClient))
+
+		rec := httptest.NewRecorder()
+		gw.handleFS(rec, httptest.NewRequest(http.MethodGet, fsPathPrefix+"/a", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
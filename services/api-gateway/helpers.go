@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userIDHeader carries the ID of the authenticated caller. It is set by the
+// authenticating proxy in front of the gateway, which strips it from client requests.
+const userIDHeader = "X-User-ID"
+
+// requireUser returns the caller's user ID, or writes a 401 and reports false
+func requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := r.Header.Get(userIDHeader)
+	if _, err := uuid.Parse(userID); err != nil {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return "", false
+	}
+	return userID, true
+}
+
+// clearDeadlines lifts the server's read and write timeouts for a request,
+// since content transfers can outlast them
+func clearDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
+
+// httpStatusFromGRPC maps an error returned by a backend service to an HTTP status code
+func httpStatusFromGRPC(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusRequestEntityTooLarge
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeGRPCError writes the message of a backend service error with the matching HTTP status
+func writeGRPCError(w http.ResponseWriter, err error) {
+	http.Error(w, status.Convert(err).Message(), httpStatusFromGRPC(err))
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	filepb "go-drive/proto/file"
+)
+
+// linkPathPrefix serves public share links to visitors without an account:
+// /s/{token} describes the link, /s/{token}/preview shows a file of a passive type inline and
+// /s/{token}/download downloads it. Folder links pick a file with ?file_id=.
+const linkPathPrefix = "/s/"
+
+// linkPasswordHeader carries the password of a protected link. HTTP Basic credentials
+// are accepted too, so that browsers can prompt for it; the user name is ignored.
+const linkPasswordHeader = "X-Share-Password"
+
+// sharedItem is what visitors of a link see of a file or folder. Owners, storage keys
+// and checksums stay private.
+type sharedItem struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size,omitempty"`
+	MimeType  string    `json:"mime_type,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// sharedLink is the JSON description of a public link and what it opens
+type sharedLink struct {
+	ExpiresAt     *time.Time   `json:"expires_at,omitempty"`
+	PreviewOnly   bool         `json:"preview_only"`
+	MaxDownloads  int32        `json:"max_downloads,omitempty"`
+	DownloadCount int32        `json:"download_count"`
+	File          *sharedItem  `json:"file,omitempty"`
+	Folder        *sharedItem  `json:"folder,omitempty"`
+	Folders       []sharedItem `json:"folders,omitempty"`
+	Files         []sharedItem `json:"files,omitempty"`
+	TotalCount    int32        `json:"total_count,omitempty"`
+}
+
+func (gw *APIGateway) handleShareLink(w http.ResponseWriter, r *http.Request) {
+	token, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, linkPathPrefix), "/")
+	if token == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	password := r.Header.Get(linkPasswordHeader)
+	if password == "" {
+		_, password, _ = r.BasicAuth()
+	}
+
+	switch action {
+	case "":
+		gw.handleLinkOpen(w, r, token, password)
+	case "preview":
+		gw.handleLinkPreview(w, r, token, password)
+	case "download":
+		gw.handleLinkDownload(w, r, token, password)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleLinkOpen describes a link: the linked file, or a page of a folder below a linked folder
+func (gw *APIGateway) handleLinkOpen(w http.ResponseWriter, r *http.Request, token, password string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+	resp, err := gw.fileClient.OpenShareLink(ctx, &filepb.OpenShareLinkRequest{
+		Token:    token,
+		Password: password,
+		FileId:   query.Get("file_id"),
+		FolderId: query.Get("folder_id"),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+	if err != nil {
+		writeLinkError(w, err)
+		return
+	}
+
+	link := resp.GetLink()
+	view := sharedLink{
+		PreviewOnly:   link.GetPreviewOnly(),
+		MaxDownloads:  link.GetMaxDownloads(),
+		DownloadCount: link.GetDownloadCount(),
+		TotalCount:    resp.GetTotalCount(),
+	}
+	if link.GetExpiresAt() != nil {
+		expiresAt := link.GetExpiresAt().AsTime()
+		view.ExpiresAt = &expiresAt
+	}
+	if file := resp.GetFile(); file != nil {
+		view.File = sharedFile(file)
+	}
+	if folder := resp.GetFolder(); folder != nil {
+		view.Folder = &sharedItem{ID: folder.Id, Name: folder.Name, UpdatedAt: folder.GetUpdatedAt().AsTime()}
+	}
+	for _, folder := range resp.GetFolders() {
+		view.Folders = append(view.Folders, sharedItem{ID: folder.Id, Name: folder.Name, UpdatedAt: folder.GetUpdatedAt().AsTime()})
+	}
+	for _, file := range resp.GetFiles() {
+		view.Files = append(view.Files, *sharedFile(file))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if r.Method == http.MethodHead {
+		return
+	}
+	json.NewEncoder(w).Encode(view)
+}
+
+// handleLinkPreview shows a linked file inline. Only types browsers display passively can
+// be previewed. Previews are allowed for preview-only links; on links with a download limit
+// a preview counts as a download like a GET of /download does, so that previews cannot get
+// around it.
+func (gw *APIGateway) handleLinkPreview(w http.ResponseWriter, r *http.Request, token, password string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	fileID := r.URL.Query().Get("file_id")
+	resp, err := gw.fileClient.OpenShareLink(ctx, &filepb.OpenShareLinkRequest{
+		Token:    token,
+		Password: password,
+		FileId:   fileID,
+	})
+	if err != nil {
+		writeLinkError(w, err)
+		return
+	}
+
+	file := resp.GetFile()
+	if file == nil {
+		http.Error(w, "file_id is required for folder links", http.StatusBadRequest)
+		return
+	}
+	mediaType, ok := previewType(file.MimeType)
+	if !ok {
+		http.Error(w, "This type of file cannot be previewed; download it instead", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if link := resp.GetLink(); !link.GetPreviewOnly() && link.GetMaxDownloads() > 0 && r.Method == http.MethodGet {
+		counted, err := gw.fileClient.DownloadShareLink(ctx, &filepb.DownloadShareLinkRequest{
+			Token:    token,
+			Password: password,
+			FileId:   fileID,
+			Resume:   resumesDownload(r, file),
+		})
+		if err != nil {
+			writeLinkError(w, err)
+			return
+		}
+		file = counted.GetFile()
+	}
+	cancel()
+
+	clearDeadlines(w)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": file.Name}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	gw.serveFile(w, r, file, file.StorageKey, mediaType)
+}
+
+// handleLinkDownload sends a linked file as an attachment. Every GET that sends the file from
+// its first byte counts against the link's download limit; ranged requests that resume a
+// download from a later offset are not counted again.
+func (gw *APIGateway) handleLinkDownload(w http.ResponseWriter, r *http.Request, token, password string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	fileID := r.URL.Query().Get("file_id")
+	var resume bool
+	if r.Header.Get("Range") != "" {
+		opened, err := gw.fileClient.OpenShareLink(ctx, &filepb.OpenShareLinkRequest{
+			Token:    token,
+			Password: password,
+			FileId:   fileID,
+		})
+		if err != nil {
+			writeLinkError(w, err)
+			return
+		}
+		if opened.GetFile() == nil {
+			http.Error(w, "file_id is required for folder links", http.StatusBadRequest)
+			return
+		}
+		resume = resumesDownload(r, opened.GetFile())
+	}
+
+	resp, err := gw.fileClient.DownloadShareLink(ctx, &filepb.DownloadShareLinkRequest{
+		Token:    token,
+		Password: password,
+		FileId:   fileID,
+		Resume:   resume,
+	})
+	if err != nil {
+		writeLinkError(w, err)
+		return
+	}
+	cancel()
+
+	file := resp.GetFile()
+	clearDeadlines(w)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	gw.serveFile(w, r, file, file.StorageKey, file.MimeType)
+}
+
+// resumesDownload reports whether a GET of a file continues a download rather than starting
+// one: every range it asks for starts past the first byte, and together they do not cover more
+// than the file, which would get the whole file sent. An If-Range naming other content gets
+// the whole file too. The checks follow those of http.ServeContent.
+func resumesDownload(r *http.Request, file *filepb.File) bool {
+	spec, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes=")
+	if !ok {
+		return false
+	}
+	if ifRange := r.Header.Get("If-Range"); ifRange != "" && !currentIfRange(ifRange, file) {
+		return false
+	}
+
+	size := file.GetSize()
+	var sent int64
+	for _, ra := range strings.Split(spec, ",") {
+		first, last, ok := strings.Cut(strings.TrimSpace(ra), "-")
+		if !ok {
+			return false
+		}
+		var start, length int64
+		if first == "" {
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return false
+			}
+			start, length = max(size-n, 0), min(n, size)
+		} else {
+			n, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || n < 0 {
+				return false
+			}
+			start, length = n, size-n
+			if last != "" {
+				end, err := strconv.ParseInt(last, 10, 64)
+				if err != nil || end < start {
+					return false
+				}
+				length = min(end-start+1, size-start)
+			}
+		}
+		if start == 0 {
+			return false
+		}
+		sent += max(length, 0)
+	}
+	return sent <= size
+}
+
+// currentIfRange reports whether an If-Range validator matches a file's current content, the
+// strong ETag or Last-Modified date the file is served with
+func currentIfRange(ifRange string, file *filepb.File) bool {
+	if strings.HasPrefix(ifRange, `"`) {
+		return file.GetChecksum() != "" && ifRange == `"`+file.GetChecksum()+`"`
+	}
+	modified, err := http.ParseTime(ifRange)
+	return err == nil && file.GetUpdatedAt() != nil && modified.Unix() == file.GetUpdatedAt().AsTime().Unix()
+}
+
+// previewType returns the media type a file of the given type is previewed as, and false
+// when it cannot be previewed. Active content such as HTML, SVG and scripts would run on the
+// gateway's origin if shown inline, so it is only ever sent as an attachment.
+func previewType(mimeType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return "", false
+	}
+	switch {
+	case previewImageTypes[mediaType], mediaType == "application/pdf":
+		return mediaType, true
+	case mediaType == "text/plain":
+		return mediaType + "; charset=utf-8", true
+	case strings.HasPrefix(mediaType, "audio/"), strings.HasPrefix(mediaType, "video/"):
+		return mediaType, true
+	}
+	return "", false
+}
+
+// previewImageTypes are the image types previews show; SVG is left out as it can hold script
+var previewImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/avif": true,
+	"image/bmp":  true,
+}
+
+func sharedFile(file *filepb.File) *sharedItem {
+	return &sharedItem{
+		ID:        file.Id,
+		Name:      file.Name,
+		Size:      file.Size,
+		MimeType:  file.MimeType,
+		UpdatedAt: file.GetUpdatedAt().AsTime(),
+	}
+}
+
+// writeLinkError writes a link error for an anonymous visitor. A missing or wrong password
+// asks the browser for one; expired and used-up links are gone for good.
+func writeLinkError(w http.ResponseWriter, err error) {
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		w.Header().Set("WWW-Authenticate", `Basic realm="share link", charset="UTF-8"`)
+		http.Error(w, status.Convert(err).Message(), http.StatusUnauthorized)
+	case codes.FailedPrecondition, codes.ResourceExhausted:
+		http.Error(w, status.Convert(err).Message(), http.StatusGone)
+	default:
+		writeGRPCError(w, err)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	filepb "go-drive/proto/file"
+)
+
+const testLinkToken = "q0bxjW3sT5yq1oMZ0yQ8cN2xvYd7tJm4aL6pKe9hR1s"
+
+func TestAPIGateway_HandleShareLink(t *testing.T) {
+	file := &filepb.File{Id: testFileID, Name: "report.txt", UserId: testUserID, Size: 11, StorageKey: testKey, MimeType: "text/plain"}
+
+	tests := []struct {
+		name           string
+		path           string
+		password       string
+		headers        map[string]string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedBody   string
+		validate       func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "describe a file link",
+			path: testLinkToken,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenShareLink", mock.Anything, &filepb.OpenShareLinkRequest{Token: testLinkToken}).
+					Return(&filepb.OpenShareLinkResponse{Link: &filepb.ShareLink{FileId: testFileID}, File: file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"name":"report.txt"`,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.NotContains(t, rec.Body.String(), testKey)
+				assert.NotContains(t, rec.Body.String(), testUserID)
+			},
+		},
+		{
+			name:     "password checked",
+			path:     testLinkToken,
+			password: "wrong",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenShareLink", mock.Anything, &filepb.OpenShareLinkRequest{Token: testLinkToken, Password: "wrong"}).
+					Return(nil, status.Error(codes.Unauthenticated, "incorrect share link password"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Basic")
+			},
+		},
+		{
+			name: "expired link",
+			path: testLinkToken,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenShareLink", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.FailedPrecondition, "share link has expired"))
+			},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name: "preview a file inline",
+			path: testLinkToken + "/preview",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenShareLink", mock.Anything, &filepb.OpenShareLinkRequest{Token: testLinkToken}).
+					Return(&filepb.OpenShareLinkResponse{Link: &filepb.ShareLink{PreviewOnly: true}, File: file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello world",
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, `inline; filename=report.txt`, rec.Header().Get("Content-Disposition"))
+				assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+				assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+				assert.Equal(t, "sandbox", rec.Header().Get("Content-Security-Policy"))
+			},
+		},
+		{
+			name: "preview refused for active content",
+			path: testLinkToken + "/preview",
+			mockSetup: func(client *MockFileServiceClient) {
+				page := &filepb.File{Id: testFileID, Name: "page.html", Size: 11, StorageKey: testKey, MimeType: "text/html"}
+				client.On("OpenShareLink", mock.Anything, &filepb.OpenShareLinkRequest{Token: testLinkToken}).
+					Return(&filepb.OpenShareLinkResponse{Link: &filepb.ShareLink{PreviewOnly: true}, File: page}, nil)
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.NotContains(t, rec.Body.String(), "hello world")
+			},
+		},
+		{
+			name: "preview counted against the download limit",
+			path: testLinkToken + "/preview",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenShareLink", mock.Anything, &filepb.OpenShareLinkRequest{Token: testLinkToken}).
+					Return(&filepb.OpenShareLinkResponse{Link: &filepb.ShareLink{MaxDownloads: 1, DownloadCount: 1}, File: file}, nil)
+				client.On("DownloadShareLink", mock.Anything, &filepb.DownloadShareLinkRequest{Token: testLinkToken}).
+					Return(nil, status.Error(codes.ResourceExhausted, "share link download limit reached"))
+			},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name: "download a file from a folder link",
+			path: testLinkToken + "/download?file_id=" + testFileID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("DownloadShareLink", mock.Anything, &filepb.DownloadShareLinkRequest{Token: testLinkToken, FileId: testFileID}).
+					Return(&filepb.DownloadShareLinkResponse{Link: &filepb.ShareLink{DownloadCount: 1}, File: file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello world",
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, `attachment; filename=report.txt`, rec.Header().Get("Content-Disposition"))
+			},
+		},
+		{
+			name: "download refused for preview-only links",
+			path: testLinkToken + "/download",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("DownloadShareLink", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.PermissionDenied, "this link only allows previews"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "download limit reached",
+			path: testLinkToken + "/download",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("DownloadShareLink", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.ResourceExhausted, "share link download limit reached"))
+			},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:    "resumed download not counted again",
+			path:    testLinkToken + "/download",
+			headers: map[string]string{"Range": "bytes=6-"},
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenShareLink", mock.Anything, &filepb.OpenShareLinkRequest{Token: testLinkToken}).
+					Return(&filepb.OpenShareLinkResponse{Link: &filepb.ShareLink{MaxDownloads: 1, DownloadCount: 1}, File: file}, nil)
+				client.On("DownloadShareLink", mock.Anything, &filepb.DownloadShareLinkRequest{Token: testLinkToken, Resume: true}).
+					Return(&filepb.DownloadShareLinkResponse{Link: &filepb.ShareLink{MaxDownloads: 1, DownloadCount: 1}, File: file}, nil)
+			},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "world",
+		},
+		{
+			name:    "ranged download from the first byte counted",
+			path:    testLinkToken + "/download",
+			headers: map[string]string{"Range": "bytes=0-4"},
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenShareLink", mock.Anything, &filepb.OpenShareLinkRequest{Token: testLinkToken}).
+					Return(&filepb.OpenShareLinkResponse{Link: &filepb.ShareLink{MaxDownloads: 2, DownloadCount: 1}, File: file}, nil)
+				client.On("DownloadShareLink", mock.Anything, &filepb.DownloadShareLinkRequest{Token: testLinkToken}).
+					Return(&filepb.DownloadShareLinkResponse{Link: &filepb.ShareLink{MaxDownloads: 2, DownloadCount: 2}, File: file}, nil)
+			},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "hello",
+		},
+		{
+			name:           "unknown action",
+			path:           testLinkToken + "/edit",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+			_, err := gw.blobs.Put(context.Background(), testKey, strings.NewReader("hello world"), 11)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, linkPathPrefix+tt.path, nil)
+			if tt.password != "" {
+				req.SetBasicAuth("", tt.password)
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			gw.handleShareLink(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			if tt.validate != nil {
+				tt.validate(t, rec)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResumesDownload(t *testing.T) {
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	file := &filepb.File{Id: testFileID, Size: 11, Checksum: "abcd", UpdatedAt: timestamppb.New(updatedAt)}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected bool
+	}{
+		{name: "no range", expected: false},
+		{name: "range from the first byte", headers: map[string]string{"Range": "bytes=0-"}, expected: false},
+		{name: "range from a later offset", headers: map[string]string{"Range": "bytes=6-"}, expected: true},
+		{name: "bounded range", headers: map[string]string{"Range": "bytes=6-8"}, expected: true},
+		{name: "one of several ranges from the first byte", headers: map[string]string{"Range": "bytes=6-8, 0-2"}, expected: false},
+		{name: "suffix range", headers: map[string]string{"Range": "bytes=-5"}, expected: true},
+		{name: "suffix range covering the file", headers: map[string]string{"Range": "bytes=-11"}, expected: false},
+		{name: "ranges covering more than the file", headers: map[string]string{"Range": "bytes=1-, 1-"}, expected: false},
+		{name: "malformed range", headers: map[string]string{"Range": "bytes=six-"}, expected: false},
+		{name: "current etag", headers: map[string]string{"Range": "bytes=6-", "If-Range": `"abcd"`}, expected: true},
+		{name: "stale etag", headers: map[string]string{"Range": "bytes=6-", "If-Range": `"ef01"`}, expected: false},
+		{name: "current date", headers: map[string]string{"Range": "bytes=6-", "If-Range": updatedAt.Format(http.TimeFormat)}, expected: true},
+		{name: "stale date", headers: map[string]string{"Range": "bytes=6-", "If-Range": updatedAt.Add(-time.Hour).Format(http.TimeFormat)}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, linkPathPrefix+testLinkToken+"/download", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			assert.Equal(t, tt.expected, resumesDownload(req, file))
+		})
+	}
+}
@@ -8,28 +8,47 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"go-drive/internal/keyring"
+	"go-drive/internal/signedurl"
+	"go-drive/internal/storage"
+	filepb "go-drive/proto/file"
 	pb "go-drive/proto/user"
 )
 
 type APIGateway struct {
 	userClient pb.UserServiceClient
+	fileClient filepb.FileServiceClient
+	blobs      storage.Backend
+	urls       *signedurl.Signer
+	// davLocks holds each user's WebDAV lock system, by user ID
+	davLocks sync.Map
 }
 
-func NewAPIGateway(userServiceAddr string) (*APIGateway, error) {
+func NewAPIGateway(userServiceAddr, fileServiceAddr string, blobs storage.Backend, urls *signedurl.Signer) (*APIGateway, error) {
 	// Connect to user service
 	userConn, err := grpc.NewClient(userServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to user service: %w", err)
 	}
 
+	// Connect to file service
+	fileConn, err := grpc.NewClient(fileServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to file service: %w", err)
+	}
+
 	return &APIGateway{
 		userClient: pb.NewUserServiceClient(userConn),
+		fileClient: filepb.NewFileServiceClient(fileConn),
+		blobs:      blobs,
+		urls:       urls,
 	}, nil
 }
 
@@ -42,11 +61,19 @@ func corsMiddleware(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("Access-Control-Allow-Origin", origin)
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH, HEAD, "+
+			"PROPFIND, PROPPATCH, MKCOL, COPY, MOVE, LOCK, UNLOCK")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, "+
+			"Range, If-None-Match, If-Modified-Since, If-Range, X-Share-Password, "+
+			"Tus-Resumable, Upload-Length, Upload-Offset, Upload-Metadata, Upload-Checksum, X-HTTP-Method-Override, "+
+			"Depth, Destination, Overwrite, If, Lock-Token, Timeout")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, Content-Range, Accept-Ranges, Content-Disposition, "+
+			"Location, X-Skipped-Files, DAV, Lock-Token, Tus-Resumable, Tus-Version, Tus-Extension, "+
+			"Tus-Max-Size, Tus-Checksum-Algorithm, Upload-Offset, Upload-Length, Upload-Metadata")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
-		if r.Method == http.MethodOptions {
+		// Answer CORS preflights here; other OPTIONS requests (tus discovery) reach the handler
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
@@ -193,12 +220,46 @@ func main() {
 		userServiceAddr = "localhost:50051"
 	}
 
+	fileServiceAddr := os.Getenv("FILE_SERVICE_ADDR")
+	if fileServiceAddr == "" {
+		fileServiceAddr = "localhost:50052"
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	gw, err := NewAPIGateway(userServiceAddr)
+	// Content is encrypted at rest when a keyring is configured
+	keys, err := keyring.Load(getEnv("ENCRYPTION_KEYRING_FILE", ""), getEnv("ENCRYPTION_KEYS", ""))
+	if err != nil {
+		log.Fatalf("Failed to load encryption keyring: %v", err)
+	}
+	if keys == nil {
+		log.Println("No encryption keyring configured; content is stored unencrypted")
+	}
+
+	blobs, err := storage.New(context.Background(), storage.Config{
+		Driver:      getEnv("STORAGE_DRIVER", storage.DriverLocal),
+		LocalRoot:   getEnv("STORAGE_LOCAL_ROOT", "/var/lib/go-drive/blobs"),
+		S3Endpoint:  getEnv("S3_ENDPOINT", "minio:9000"),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    getEnv("S3_BUCKET", "go-drive"),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:    getEnv("S3_USE_SSL", "false") == "true",
+		Keyring:     keys,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	urls, err := signedurl.NewSigner(os.Getenv("SIGNED_URL_SECRET"), getEnv("PUBLIC_URL", "http://localhost:8080"))
+	if err != nil {
+		log.Fatalf("Failed to initialize URL signer: %v", err)
+	}
+
+	gw, err := NewAPIGateway(userServiceAddr, fileServiceAddr, blobs, urls)
 	if err != nil {
 		log.Fatalf("Failed to create API gateway: %v", err)
 	}
@@ -224,6 +285,22 @@ func main() {
 		}
 	})
 
+	mux.HandleFunc(signedurl.PathPrefix, gw.handleBlob)
+	mux.HandleFunc(tusPathPrefix, gw.handleTus)
+	mux.HandleFunc(tusPathPrefix+"/", gw.handleTus)
+	mux.HandleFunc(fsPathPrefix, gw.handleFS)
+	mux.HandleFunc(fsPathPrefix+"/", gw.handleFS)
+	mux.HandleFunc(linkPathPrefix, gw.handleShareLink)
+	mux.HandleFunc(requestPathPrefix, gw.handleFileRequest)
+	mux.HandleFunc(searchPath, gw.handleSearch)
+	mux.HandleFunc(thumbnailPathPrefix, gw.handleThumbnail)
+	mux.HandleFunc(quarantinePath, gw.handleQuarantine)
+	mux.HandleFunc(accessKeysPath, gw.handleAccessKeys)
+	mux.HandleFunc(accessKeysPath+"/", gw.handleAccessKeys)
+	mux.HandleFunc(zipPath, gw.handleZip)
+	mux.HandleFunc(davPathPrefix, gw.handleDAV)
+	mux.HandleFunc(davPathPrefix+"/", gw.handleDAV)
+
 	handler := corsMiddleware(mux)
 
 	server := &http.Server{
@@ -234,25 +311,59 @@ func main() {
 		IdleTimeout:  time.Minute,
 	}
 
+	// The S3-compatible API authenticates requests by their signature, so it has a
+	// listener of its own that trusts no X-User-ID header
+	s3Port := getEnv("S3_API_PORT", "8081")
+	s3API := newS3Server(gw, getEnv("S3_API_REGION", "us-east-1"))
+	s3Server := &http.Server{
+		Addr:         fmt.Sprintf(":%s", s3Port),
+		Handler:      s3API,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  time.Minute,
+	}
+
+	// Multipart uploads that are never completed or aborted are purged in the background
+	purgeCtx, stopPurger := context.WithCancel(context.Background())
+	defer stopPurger()
+	go s3API.runUploadPurger(purgeCtx, s3UploadPurgeInterval)
+
 	log.Printf("API Gateway starting on port %s", port)
+	log.Printf("S3-compatible API starting on port %s", s3Port)
 
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
+	go func() {
+		if err := s3Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start S3-compatible API: %v", err)
+		}
+	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down API gateway...")
+	stopPurger()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if err := s3Server.Shutdown(ctx); err != nil {
+		log.Fatalf("S3-compatible API forced to shutdown: %v", err)
+	}
 
 	log.Println("API gateway stopped")
 }
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
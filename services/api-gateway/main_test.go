@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	filepb "go-drive/proto/file"
 	pb "go-drive/proto/user"
 )
 
@@ -71,6 +72,269 @@ func (m *MockUserServiceClient) VerifyEmail(ctx context.Context, in *pb.VerifyEm
 	return args.Get(0).(*pb.VerifyEmailResponse), args.Error(1)
 }
 
+// MockFileServiceClient is a mock implementation of the gRPC file service client.
+// Methods a test does not stub fall through to the nil embedded client and panic.
+type MockFileServiceClient struct {
+	mock.Mock
+	filepb.FileServiceClient
+}
+
+func (m *MockFileServiceClient) GetFile(ctx context.Context, in *filepb.GetFileRequest, opts ...grpc.CallOption) (*filepb.GetFileResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.GetFileResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) CreateFile(ctx context.Context, in *filepb.CreateFileRequest, opts ...grpc.CallOption) (*filepb.CreateFileResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.CreateFileResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) ListFolder(ctx context.Context, in *filepb.ListFolderRequest, opts ...grpc.CallOption) (*filepb.ListFolderResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.ListFolderResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) DeleteFile(ctx context.Context, in *filepb.DeleteFileRequest, opts ...grpc.CallOption) (*filepb.DeleteFileResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.DeleteFileResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) DeleteFolder(ctx context.Context, in *filepb.DeleteFolderRequest, opts ...grpc.CallOption) (*filepb.DeleteFolderResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.DeleteFolderResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) ResolvePath(ctx context.Context, in *filepb.ResolvePathRequest, opts ...grpc.CallOption) (*filepb.ResolvePathResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.ResolvePathResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) CreateFolderPath(ctx context.Context, in *filepb.CreateFolderPathRequest, opts ...grpc.CallOption) (*filepb.CreateFolderPathResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.CreateFolderPathResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) GetUploadURL(ctx context.Context, in *filepb.GetUploadURLRequest, opts ...grpc.CallOption) (*filepb.GetUploadURLResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.GetUploadURLResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) GetUsage(ctx context.Context, in *filepb.GetUsageRequest, opts ...grpc.CallOption) (*filepb.GetUsageResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.GetUsageResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) GetFileVersion(ctx context.Context, in *filepb.GetFileVersionRequest, opts ...grpc.CallOption) (*filepb.GetFileVersionResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.GetFileVersionResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) CompleteUpload(ctx context.Context, in *filepb.CompleteUploadRequest, opts ...grpc.CallOption) (*filepb.CompleteUploadResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.CompleteUploadResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) CreateUpload(ctx context.Context, in *filepb.CreateUploadRequest, opts ...grpc.CallOption) (*filepb.CreateUploadResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.CreateUploadResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) GetUpload(ctx context.Context, in *filepb.GetUploadRequest, opts ...grpc.CallOption) (*filepb.GetUploadResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.GetUploadResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) CommitUploadChunk(ctx context.Context, in *filepb.CommitUploadChunkRequest, opts ...grpc.CallOption) (*filepb.CommitUploadChunkResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.CommitUploadChunkResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) FinishUpload(ctx context.Context, in *filepb.FinishUploadRequest, opts ...grpc.CallOption) (*filepb.FinishUploadResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.FinishUploadResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) DeleteUpload(ctx context.Context, in *filepb.DeleteUploadRequest, opts ...grpc.CallOption) (*filepb.DeleteUploadResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.DeleteUploadResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) OpenShareLink(ctx context.Context, in *filepb.OpenShareLinkRequest, opts ...grpc.CallOption) (*filepb.OpenShareLinkResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.OpenShareLinkResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) DownloadShareLink(ctx context.Context, in *filepb.DownloadShareLinkRequest, opts ...grpc.CallOption) (*filepb.DownloadShareLinkResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.DownloadShareLinkResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) OpenFileRequestLink(ctx context.Context, in *filepb.OpenFileRequestLinkRequest, opts ...grpc.CallOption) (*filepb.OpenFileRequestLinkResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.OpenFileRequestLinkResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) SubmitFileRequest(ctx context.Context, in *filepb.SubmitFileRequestRequest, opts ...grpc.CallOption) (*filepb.SubmitFileRequestResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.SubmitFileRequestResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) ListQuarantine(ctx context.Context, in *filepb.ListQuarantineRequest, opts ...grpc.CallOption) (*filepb.ListQuarantineResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.ListQuarantineResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) ListDownloadEntries(ctx context.Context, in *filepb.ListDownloadEntriesRequest, opts ...grpc.CallOption) (*filepb.ListDownloadEntriesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.ListDownloadEntriesResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) GetThumbnail(ctx context.Context, in *filepb.GetThumbnailRequest, opts ...grpc.CallOption) (*filepb.GetThumbnailResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.GetThumbnailResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) SearchFiles(ctx context.Context, in *filepb.SearchFilesRequest, opts ...grpc.CallOption) (*filepb.SearchFilesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.SearchFilesResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) CreateFolder(ctx context.Context, in *filepb.CreateFolderRequest, opts ...grpc.CallOption) (*filepb.CreateFolderResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.CreateFolderResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) MoveFile(ctx context.Context, in *filepb.MoveFileRequest, opts ...grpc.CallOption) (*filepb.MoveFileResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.MoveFileResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) MoveFolder(ctx context.Context, in *filepb.MoveFolderRequest, opts ...grpc.CallOption) (*filepb.MoveFolderResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.MoveFolderResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) RenameFolder(ctx context.Context, in *filepb.RenameFolderRequest, opts ...grpc.CallOption) (*filepb.RenameFolderResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.RenameFolderResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) GetSigningKey(ctx context.Context, in *filepb.GetSigningKeyRequest, opts ...grpc.CallOption) (*filepb.GetSigningKeyResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.GetSigningKeyResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) CreateAccessKey(ctx context.Context, in *filepb.CreateAccessKeyRequest, opts ...grpc.CallOption) (*filepb.CreateAccessKeyResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.CreateAccessKeyResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) ListAccessKeys(ctx context.Context, in *filepb.ListAccessKeysRequest, opts ...grpc.CallOption) (*filepb.ListAccessKeysResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.ListAccessKeysResponse), args.Error(1)
+}
+
+func (m *MockFileServiceClient) RevokeAccessKey(ctx context.Context, in *filepb.RevokeAccessKeyRequest, opts ...grpc.CallOption) (*filepb.RevokeAccessKeyResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filepb.RevokeAccessKeyResponse), args.Error(1)
+}
+
 func TestAPIGateway_HandleCreateUser(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	filepb "go-drive/proto/file"
+)
+
+func TestAPIGateway_HandleQuarantine(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		userID         string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "admin lists quarantined files",
+			method: http.MethodGet,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ListQuarantine", mock.Anything, &filepb.ListQuarantineRequest{UserId: testUserID, Page: 2, PageSize: 10}).
+					Return(&filepb.ListQuarantineResponse{
+						Files:      []*filepb.File{{Id: testFileID, Quarantined: true, QuarantineReason: "malware Eicar-Test-Signature"}},
+						TotalCount: 11,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"quarantine_reason":"malware Eicar-Test-Signature"`,
+		},
+		{
+			name:   "not an admin",
+			method: http.MethodGet,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ListQuarantine", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.PermissionDenied, "only admins can review quarantined files"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "wrong method",
+			method:         http.MethodDelete,
+			userID:         testUserID,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "unauthenticated",
+			method:         http.MethodGet,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := httptest.NewRequest(tt.method, quarantinePath+"?page=2&page_size=10", nil)
+			if tt.userID != "" {
+				req.Header.Set(userIDHeader, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			gw.handleQuarantine(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
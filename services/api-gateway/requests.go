@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-drive/internal/signedurl"
+	filepb "go-drive/proto/file"
+)
+
+// requestPathPrefix serves file request links to people without an account: GET /r/{token}
+// describes what the request accepts and POST /r/{token}?name=&uploader_name=&uploader_email=
+// uploads the request body as one file, typed by its Content-Type.
+const requestPathPrefix = "/r/"
+
+// requestView is what uploaders see of a file request. The folder and its contents stay private.
+type requestView struct {
+	Title            string     `json:"title,omitempty"`
+	Deadline         *time.Time `json:"deadline,omitempty"`
+	MaxFileSize      int64      `json:"max_file_size,omitempty"`
+	AllowedMimeTypes []string   `json:"allowed_mime_types,omitempty"`
+}
+
+// submittedFile is the reply to an upload through a file request
+type submittedFile struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type,omitempty"`
+}
+
+func (gw *APIGateway) handleFileRequest(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, requestPathPrefix)
+	if token == "" || strings.Contains(token, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		gw.handleRequestOpen(w, r, token)
+	case http.MethodPost:
+		gw.handleRequestSubmit(w, r, token)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (gw *APIGateway) handleRequestOpen(w http.ResponseWriter, r *http.Request, token string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.OpenFileRequestLink(ctx, &filepb.OpenFileRequestLinkRequest{Token: token})
+	if err != nil {
+		writeLinkError(w, err)
+		return
+	}
+
+	link := resp.GetLink()
+	view := requestView{
+		Title:            link.GetTitle(),
+		MaxFileSize:      link.GetMaxFileSize(),
+		AllowedMimeTypes: link.GetAllowedMimeTypes(),
+	}
+	if link.GetDeadline() != nil {
+		deadline := link.GetDeadline().AsTime()
+		view.Deadline = &deadline
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if r.Method == http.MethodHead {
+		return
+	}
+	json.NewEncoder(w).Encode(view)
+}
+
+// handleRequestSubmit stores an upload in the file request's folder. The file belongs to the
+// folder's owner; if its content is refused, the file is deleted again.
+func (gw *APIGateway) handleRequestSubmit(w http.ResponseWriter, r *http.Request, token string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	size := r.ContentLength
+	if size < 0 {
+		size = 0
+	}
+	resp, err := gw.fileClient.SubmitFileRequest(ctx, &filepb.SubmitFileRequestRequest{
+		Token:         token,
+		Name:          query.Get("name"),
+		Size:          size,
+		MimeType:      r.Header.Get("Content-Type"),
+		UploaderName:  query.Get("uploader_name"),
+		UploaderEmail: query.Get("uploader_email"),
+	})
+	if err != nil {
+		writeLinkError(w, err)
+		return
+	}
+	cancel()
+
+	file := resp.GetFile()
+	clearDeadlines(w)
+	completed, ok := gw.storeBlob(w, r, signedurl.Grant{
+		Method:      http.MethodPut,
+		FileID:      file.Id,
+		UserID:      file.UserId,
+		Key:         file.StorageKey,
+		MaxSize:     resp.GetMaxSize(),
+		ContentType: file.MimeType,
+	})
+	if !ok {
+		gw.discardFile(file.Id, file.UserId)
+		return
+	}
+
+	file = completed.GetFile()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(submittedFile{
+		ID:       file.GetId(),
+		Name:     file.GetName(),
+		Size:     file.GetSize(),
+		MimeType: file.GetMimeType(),
+	})
+}
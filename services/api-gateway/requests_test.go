@@ -0,0 +1,161 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	filepb "go-drive/proto/file"
+)
+
+func TestAPIGateway_HandleFileRequest(t *testing.T) {
+	file := &filepb.File{Id: testFileID, Name: "scan.txt", UserId: testUserID, StorageKey: testKey, MimeType: "text/plain"}
+	submitQuery := "?name=scan.txt&uploader_name=Ada&uploader_email=ada%40example.com"
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedBody   string
+		validate       func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "describe a file request",
+			method: http.MethodGet,
+			path:   testLinkToken,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenFileRequestLink", mock.Anything, &filepb.OpenFileRequestLinkRequest{Token: testLinkToken}).
+					Return(&filepb.OpenFileRequestLinkResponse{Link: &filepb.FileRequestLink{
+						OwnerId: testUserID, FolderId: testFolderID, Title: "Scans", AllowedMimeTypes: []string{"text/plain"},
+					}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"title":"Scans"`,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.NotContains(t, rec.Body.String(), testUserID)
+				assert.NotContains(t, rec.Body.String(), testFolderID)
+			},
+		},
+		{
+			name:   "closed file request",
+			method: http.MethodGet,
+			path:   testLinkToken,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("OpenFileRequestLink", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.FailedPrecondition, "file request is closed"))
+			},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:   "upload a file",
+			method: http.MethodPost,
+			path:   testLinkToken + submitQuery,
+			body:   "hello world",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("SubmitFileRequest", mock.Anything, &filepb.SubmitFileRequestRequest{
+					Token: testLinkToken, Name: "scan.txt", Size: 11, MimeType: "text/plain",
+					UploaderName: "Ada", UploaderEmail: "ada@example.com",
+				}).Return(&filepb.SubmitFileRequestResponse{File: file, MaxSize: 11}, nil)
+				client.On("CompleteUpload", mock.Anything, mock.MatchedBy(func(req *filepb.CompleteUploadRequest) bool {
+					return req.Id == testFileID && req.UserId == testUserID && req.StorageKey == testKey
+				})).Return(&filepb.CompleteUploadResponse{File: &filepb.File{
+					Id: testFileID, Name: "scan.txt", UserId: testUserID, Size: 11, StorageKey: testKey, MimeType: "text/plain",
+				}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"size":11`,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.NotContains(t, rec.Body.String(), testUserID)
+				assert.NotContains(t, rec.Body.String(), testKey)
+			},
+		},
+		{
+			name:   "refused content discards the file",
+			method: http.MethodPost,
+			path:   testLinkToken + submitQuery,
+			body:   "hello world, and then some",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("SubmitFileRequest", mock.Anything, mock.Anything).
+					Return(&filepb.SubmitFileRequestResponse{File: file, MaxSize: 11}, nil)
+				client.On("DeleteFile", mock.Anything, &filepb.DeleteFileRequest{Id: testFileID, UserId: testUserID}).
+					Return(&filepb.DeleteFileResponse{}, nil)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "type not accepted",
+			method: http.MethodPost,
+			path:   testLinkToken + submitQuery,
+			body:   "hello world",
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("SubmitFileRequest", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.InvalidArgument, "this file request only accepts image/*"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodDelete,
+			path:           testLinkToken,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, requestPathPrefix+tt.path, body)
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "text/plain")
+			}
+			rec := httptest.NewRecorder()
+			gw.handleFileRequest(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			if tt.validate != nil {
+				tt.validate(t, rec)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+
+	t.Run("stored content", func(t *testing.T) {
+		mockClient := new(MockFileServiceClient)
+		mockClient.On("SubmitFileRequest", mock.Anything, mock.Anything).
+			Return(&filepb.SubmitFileRequestResponse{File: file, MaxSize: 11}, nil)
+		mockClient.On("CompleteUpload", mock.Anything, mock.Anything).
+			Return(&filepb.CompleteUploadResponse{File: file}, nil)
+		gw := newBlobGateway(t, mockClient)
+
+		req := httptest.NewRequest(http.MethodPost, requestPathPrefix+testLinkToken+submitQuery, strings.NewReader("hello world"))
+		req.Header.Set("Content-Type", "text/plain")
+		gw.handleFileRequest(httptest.NewRecorder(), req)
+
+		content, _, err := gw.blobs.GetRange(req.Context(), testKey, 0, -1)
+		require.NoError(t, err)
+		defer content.Close()
+		stored, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(stored))
+	})
+}
@@ -0,0 +1,924 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/sigv4"
+	"go-drive/internal/storage"
+	filepb "go-drive/proto/file"
+)
+
+// The S3-compatible API serves each user's drive to S3 tooling such as backup agents and
+// rclone. Buckets are the caller's top-level folders and object keys are the paths below
+// them, so s3://photos/2026/beach.jpg is the file /photos/2026/beach.jpg. Requests are
+// signed with AWS Signature Version 4, using access keys created with the file service's
+// CreateAccessKey. The API has a listener of its own, since S3 clients address buckets
+// from the root of the endpoint; only path-style requests are supported.
+const (
+	s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+	// s3Service is the service of the credential scope requests are signed for
+	s3Service = "s3"
+	// s3MaxSkew is how far a request's X-Amz-Date may be from the gateway's clock
+	s3MaxSkew = 15 * time.Minute
+	// s3MaxKeys caps the entries of a listing and the keys of a DeleteObjects request
+	s3MaxKeys = 1000
+	// s3MaxXMLSize caps the XML documents requests carry
+	s3MaxXMLSize = 1 << 20
+	// s3PageSize is the page size folders are listed with
+	s3PageSize = 100
+	// s3TimeFormat is the layout of timestamps in XML responses
+	s3TimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// s3Error is an error answered in the S3 error response format
+type s3Error struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *s3Error) Error() string { return e.code + ": " + e.message }
+
+var (
+	errS3NoSuchBucket          = &s3Error{http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist"}
+	errS3NoSuchKey             = &s3Error{http.StatusNotFound, "NoSuchKey", "The specified key does not exist"}
+	errS3InvalidKey            = &s3Error{http.StatusBadRequest, "InvalidArgument", `Object keys cannot contain empty, "." or ".." segments`}
+	errS3EntityTooLarge        = &s3Error{http.StatusBadRequest, "EntityTooLarge", "Your proposed upload exceeds the maximum allowed size"}
+	errS3QuotaExceeded         = &s3Error{http.StatusForbidden, "QuotaExceeded", "Your upload exceeds the storage quota"}
+	errS3MalformedXML          = &s3Error{http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema"}
+	errS3MethodNotAllowed      = &s3Error{http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource"}
+	errS3NotImplemented        = &s3Error{http.StatusNotImplemented, "NotImplemented", "A header or query you provided implies functionality that is not implemented"}
+	errS3InternalError         = &s3Error{http.StatusInternalServerError, "InternalError", "We encountered an internal error. Please try again."}
+	errS3SignatureDoesNotMatch = &s3Error{http.StatusForbidden, "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided"}
+)
+
+// s3ErrorFromGRPC maps an error returned by the file service to an S3 error
+func s3ErrorFromGRPC(err error) *s3Error {
+	message := status.Convert(err).Message()
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return &s3Error{http.StatusBadRequest, "InvalidArgument", message}
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return &s3Error{http.StatusForbidden, "AccessDenied", message}
+	case codes.NotFound:
+		return &s3Error{http.StatusNotFound, "NoSuchKey", message}
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return &s3Error{http.StatusConflict, "OperationAborted", message}
+	case codes.ResourceExhausted:
+		return &s3Error{http.StatusForbidden, "QuotaExceeded", message}
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return &s3Error{http.StatusServiceUnavailable, "ServiceUnavailable", message}
+	default:
+		return errS3InternalError
+	}
+}
+
+// toS3Error returns the S3 error a request failed with
+func toS3Error(err error) *s3Error {
+	var s3Err *s3Error
+	if errors.As(err, &s3Err) {
+		return s3Err
+	}
+	if _, ok := status.FromError(err); ok {
+		return s3ErrorFromGRPC(err)
+	}
+	log.Printf("S3 request failed: %v", err)
+	return errS3InternalError
+}
+
+type s3ErrorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string
+	Message   string
+	Resource  string
+	RequestID string `xml:"RequestId"`
+}
+
+// writeS3Error answers a failed request with an S3 error document
+func writeS3Error(w http.ResponseWriter, r *http.Request, requestID string, err error) {
+	s3Err := toS3Error(err)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(s3Err.status)
+		return
+	}
+	writeS3XML(w, s3Err.status, s3ErrorResponse{
+		Code:      s3Err.code,
+		Message:   s3Err.message,
+		Resource:  r.URL.Path,
+		RequestID: requestID,
+	})
+}
+
+func writeS3XML(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(code)
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(v)
+}
+
+// readS3XML decodes the XML document of a request body
+func readS3XML(req *s3Request, v any) error {
+	data, err := io.ReadAll(io.LimitReader(req.body, s3MaxXMLSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > s3MaxXMLSize || xml.Unmarshal(data, v) != nil {
+		return errS3MalformedXML
+	}
+	return nil
+}
+
+// s3Server serves the S3-compatible API
+type s3Server struct {
+	gw     *APIGateway
+	region string
+	now    func() time.Time
+}
+
+func newS3Server(gw *APIGateway, region string) *s3Server {
+	return &s3Server{gw: gw, region: region, now: time.Now}
+}
+
+// s3Request is an authenticated request to the S3-compatible API
+type s3Request struct {
+	r      *http.Request
+	userID string
+	bucket string
+	key    string
+	// body is the decoded request body, checked against the digests the client sent
+	body io.Reader
+	// size is the length of the decoded body, or -1 when it is not known
+	size int64
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := make([]byte, 8)
+	rand.Read(id)
+	requestID := strings.ToUpper(hex.EncodeToString(id))
+	w.Header().Set("x-amz-request-id", requestID)
+
+	req, err := s.authenticate(w, r)
+	if err == nil {
+		err = s.route(w, req)
+	}
+	if err != nil {
+		writeS3Error(w, r, requestID, err)
+	}
+}
+
+// route dispatches a request to the operation its method, path and query name
+func (s *s3Server) route(w http.ResponseWriter, req *s3Request) error {
+	method := req.r.Method
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPost, http.MethodDelete:
+	default:
+		return errS3MethodNotAllowed
+	}
+
+	query := req.r.URL.Query()
+	// AWS SDKs name the operation in the query
+	query.Del("x-id")
+
+	if req.bucket == "" {
+		if method != http.MethodGet || len(query) > 0 {
+			return errS3MethodNotAllowed
+		}
+		return s.listBuckets(w, req)
+	}
+
+	if req.key == "" {
+		switch {
+		case method == http.MethodGet && query.Has("location"):
+			return s.getBucketLocation(w, req)
+		case method == http.MethodGet && s3OnlyParameters(query, s3ListParameters...):
+			return s.listObjects(w, req)
+		case method == http.MethodHead && len(query) == 0:
+			return s.headBucket(w, req)
+		case method == http.MethodPut && len(query) == 0:
+			return s.createBucket(w, req)
+		case method == http.MethodDelete && len(query) == 0:
+			return s.deleteBucket(w, req)
+		case method == http.MethodPost && query.Has("delete"):
+			return s.deleteObjects(w, req)
+		}
+		return errS3NotImplemented
+	}
+
+	switch {
+	case (method == http.MethodGet || method == http.MethodHead) && len(query) == 0:
+		return s.getObject(w, req)
+	case method == http.MethodPut && req.r.Header.Get("X-Amz-Copy-Source") != "":
+		return errS3NotImplemented
+	case method == http.MethodPut && len(query) == 0:
+		return s.putObject(w, req)
+	case method == http.MethodPut && query.Has("uploadId") && s3OnlyParameters(query, "uploadId", "partNumber"):
+		return s.uploadPart(w, req)
+	case method == http.MethodPost && query.Has("uploads"):
+		return s.createMultipartUpload(w, req)
+	case method == http.MethodPost && query.Has("uploadId"):
+		return s.completeMultipartUpload(w, req)
+	case method == http.MethodDelete && len(query) == 0:
+		return s.deleteObject(w, req)
+	case method == http.MethodDelete && query.Has("uploadId"):
+		return s.abortMultipartUpload(w, req)
+	}
+	return errS3NotImplemented
+}
+
+// s3OnlyParameters reports whether a query has no parameters other than names
+func s3OnlyParameters(query url.Values, names ...string) bool {
+	for name := range query {
+		known := false
+		for _, allowed := range names {
+			known = known || name == allowed
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
+
+// authenticate checks a request's signature against the access key it names, and prepares
+// its body to be read: aws-chunked bodies are decoded, and any body is checked against the
+// payload hash and Content-MD5 the client sent.
+func (s *s3Server) authenticate(w http.ResponseWriter, r *http.Request) (*s3Request, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		if r.URL.Query().Has("X-Amz-Signature") {
+			return nil, &s3Error{http.StatusNotImplemented, "NotImplemented", "Presigned URLs are not supported"}
+		}
+		return nil, &s3Error{http.StatusForbidden, "AccessDenied", "Anonymous access is not allowed"}
+	}
+	auth, err := sigv4.ParseAuthorization(header)
+	if err != nil {
+		return nil, &s3Error{http.StatusBadRequest, "AuthorizationHeaderMalformed", "The authorization header is not an AWS Signature Version 4 signature"}
+	}
+	if auth.Region != s.region || auth.Service != s3Service {
+		return nil, &s3Error{http.StatusBadRequest, "AuthorizationHeaderMalformed",
+			fmt.Sprintf("The authorization header is malformed; the region '%s' is wrong; expecting '%s'", auth.Region, s.region)}
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	signedAt, err := time.Parse(sigv4.TimeFormat, amzDate)
+	if err != nil {
+		return nil, &s3Error{http.StatusForbidden, "AccessDenied", "X-Amz-Date is missing or malformed"}
+	}
+	if signedAt.Format(sigv4.DateFormat) != auth.Date {
+		return nil, &s3Error{http.StatusBadRequest, "AuthorizationHeaderMalformed", "The credential date does not match X-Amz-Date"}
+	}
+	if skew := s.now().Sub(signedAt); skew > s3MaxSkew || skew < -s3MaxSkew {
+		return nil, &s3Error{http.StatusForbidden, "RequestTimeTooSkewed", "The difference between the request time and the current time is too large"}
+	}
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		return nil, &s3Error{http.StatusBadRequest, "InvalidRequest", "Missing required header for this request: x-amz-content-sha256"}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	key, err := s.gw.fileClient.GetSigningKey(ctx, &filepb.GetSigningKeyRequest{
+		AccessKeyId: auth.AccessKey,
+		Date:        auth.Date,
+		Region:      auth.Region,
+		Service:     auth.Service,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound, codes.InvalidArgument:
+		return nil, &s3Error{http.StatusForbidden, "InvalidAccessKeyId", "The AWS access key ID you provided does not exist in our records"}
+	default:
+		return nil, err
+	}
+	if !sigv4.VerifyRequest(r, auth, key.SigningKey) {
+		return nil, errS3SignatureDoesNotMatch
+	}
+
+	req := &s3Request{r: r, userID: key.UserId, size: r.ContentLength}
+	req.bucket, req.key, _ = strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	body := &s3Body{r: r.Body}
+	switch payloadHash {
+	case sigv4.UnsignedPayload:
+	case sigv4.StreamingPayload, sigv4.StreamingUnsignedPayloadTrailer:
+		size, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64)
+		if err != nil || size < 0 {
+			return nil, &s3Error{http.StatusLengthRequired, "MissingContentLength", "You must provide the X-Amz-Decoded-Content-Length header"}
+		}
+		var signer *sigv4.ChunkSigner
+		if payloadHash == sigv4.StreamingPayload {
+			signer = &sigv4.ChunkSigner{SigningKey: key.SigningKey, AmzDate: amzDate, Scope: auth.Scope(), Previous: auth.Signature}
+		}
+		req.size, body.r = size, sigv4.NewChunkedReader(r.Body, signer)
+	default:
+		if strings.HasPrefix(payloadHash, "STREAMING-") {
+			return nil, &s3Error{http.StatusNotImplemented, "NotImplemented", "The payload signing mode " + payloadHash + " is not supported"}
+		}
+		expected, err := hex.DecodeString(payloadHash)
+		if err != nil || len(expected) != sha256.Size {
+			return nil, &s3Error{http.StatusBadRequest, "InvalidArgument", "x-amz-content-sha256 must be UNSIGNED-PAYLOAD, a streaming mode or a SHA-256 hash"}
+		}
+		body.digests = append(body.digests, s3Digest{
+			hash:     sha256.New(),
+			expected: expected,
+			mismatch: &s3Error{http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed"},
+		})
+	}
+	if contentMD5 := r.Header.Get("Content-MD5"); contentMD5 != "" {
+		expected, err := base64.StdEncoding.DecodeString(contentMD5)
+		if err != nil || len(expected) != md5.Size {
+			return nil, &s3Error{http.StatusBadRequest, "InvalidDigest", "The Content-MD5 you specified is not valid"}
+		}
+		body.digests = append(body.digests, s3Digest{
+			hash:     md5.New(),
+			expected: expected,
+			mismatch: &s3Error{http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received"},
+		})
+	}
+	body.r = http.MaxBytesReader(w, io.NopCloser(body.r), domain.MaxUploadSize)
+	req.body = body
+	return req, nil
+}
+
+// s3Body reads a request body, checking it against the digests the client sent. A body
+// that does not match them, or cannot be read, fails with the S3 error to answer.
+type s3Body struct {
+	r       io.Reader
+	digests []s3Digest
+	failure *s3Error
+}
+
+type s3Digest struct {
+	hash     hash.Hash
+	expected []byte
+	mismatch *s3Error
+}
+
+func (b *s3Body) Read(p []byte) (int, error) {
+	if b.failure != nil {
+		return 0, b.failure
+	}
+	n, err := b.r.Read(p)
+	for _, digest := range b.digests {
+		digest.hash.Write(p[:n])
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, io.EOF):
+		for _, digest := range b.digests {
+			if !bytes.Equal(digest.hash.Sum(nil), digest.expected) {
+				b.failure = digest.mismatch
+			}
+		}
+	case errors.As(err, &tooLarge):
+		b.failure = errS3EntityTooLarge
+	case errors.Is(err, sigv4.ErrChunkSignature):
+		b.failure = errS3SignatureDoesNotMatch
+	case errors.Is(err, sigv4.ErrMalformedChunk):
+		b.failure = &s3Error{http.StatusBadRequest, "InvalidRequest", "The aws-chunked request body is malformed"}
+	case err != nil:
+		b.failure = &s3Error{http.StatusBadRequest, "IncompleteBody", "The request body ended before all of its content was received"}
+	}
+	if b.failure != nil {
+		return n, b.failure
+	}
+	return n, err
+}
+
+// objectPath returns the drive path of an object key. A key that ends in a slash names a
+// folder; keys with segments a path cannot hold are refused.
+func objectPath(bucket, key string) (string, error) {
+	if bucket == "." || bucket == ".." {
+		return "", errS3NoSuchBucket
+	}
+	for _, segment := range strings.Split(strings.TrimSuffix(key, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", errS3InvalidKey
+		}
+	}
+	return "/" + bucket + "/" + key, nil
+}
+
+// bucket returns the top-level folder a bucket names
+func (s *s3Server) bucket(ctx context.Context, userID, bucket string) (*filepb.Folder, error) {
+	if bucket == "." || bucket == ".." {
+		return nil, errS3NoSuchBucket
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entry, err := s.gw.fileClient.ResolvePath(ctx, &filepb.ResolvePathRequest{UserId: userID, Path: "/" + bucket})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		return nil, errS3NoSuchBucket
+	case err != nil:
+		return nil, err
+	case entry.GetFolder() == nil:
+		return nil, errS3NoSuchBucket
+	}
+	return entry.GetFolder(), nil
+}
+
+// resolve looks up the file or folder at an object key. A missing key is told apart from a
+// missing bucket, since S3 clients act on the difference.
+func (s *s3Server) resolve(ctx context.Context, userID, bucket, key string) (*filepb.ResolvePathResponse, error) {
+	objectPath, err := objectPath(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entry, err := s.gw.fileClient.ResolvePath(resolveCtx, &filepb.ResolvePathRequest{UserId: userID, Path: strings.TrimSuffix(objectPath, "/")})
+	if status.Code(err) == codes.NotFound {
+		if _, err := s.bucket(ctx, userID, bucket); err != nil {
+			return nil, err
+		}
+		return nil, errS3NoSuchKey
+	}
+	return entry, err
+}
+
+// list returns the subfolders and files of a folder, or of the root for an empty ID
+func (s *s3Server) list(ctx context.Context, userID, folderID string) ([]*filepb.Folder, []*filepb.File, error) {
+	var folders []*filepb.Folder
+	var files []*filepb.File
+	for page := int32(1); ; page++ {
+		pageCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		resp, err := s.gw.fileClient.ListFolder(pageCtx, &filepb.ListFolderRequest{
+			UserId:   userID,
+			FolderId: folderID,
+			Page:     page,
+			PageSize: s3PageSize,
+		})
+		cancel()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		folders = append(folders, resp.Folders...)
+		files = append(files, resp.Files...)
+		if len(resp.Folders)+len(resp.Files) < s3PageSize {
+			return folders, files, nil
+		}
+	}
+}
+
+type s3Owner struct {
+	ID string
+}
+
+type s3Bucket struct {
+	Name         string
+	CreationDate string
+}
+
+type s3ListBucketsResult struct {
+	XMLName xml.Name   `xml:"ListAllMyBucketsResult"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	Owner   s3Owner    `xml:"Owner"`
+	Buckets []s3Bucket `xml:"Buckets>Bucket"`
+}
+
+// listBuckets lists the caller's top-level folders
+func (s *s3Server) listBuckets(w http.ResponseWriter, req *s3Request) error {
+	folders, _, err := s.list(req.r.Context(), req.userID, "")
+	if err != nil {
+		return err
+	}
+
+	result := s3ListBucketsResult{Xmlns: s3Namespace, Owner: s3Owner{ID: req.userID}, Buckets: []s3Bucket{}}
+	for _, folder := range folders {
+		result.Buckets = append(result.Buckets, s3Bucket{
+			Name:         folder.Name,
+			CreationDate: folder.GetCreatedAt().AsTime().UTC().Format(s3TimeFormat),
+		})
+	}
+	writeS3XML(w, http.StatusOK, result)
+	return nil
+}
+
+type s3LocationConstraint struct {
+	XMLName xml.Name `xml:"LocationConstraint"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Region  string   `xml:",chardata"`
+}
+
+func (s *s3Server) getBucketLocation(w http.ResponseWriter, req *s3Request) error {
+	if _, err := s.bucket(req.r.Context(), req.userID, req.bucket); err != nil {
+		return err
+	}
+
+	// As in S3, the default region reads as no location constraint
+	region := s.region
+	if region == "us-east-1" {
+		region = ""
+	}
+	writeS3XML(w, http.StatusOK, s3LocationConstraint{Xmlns: s3Namespace, Region: region})
+	return nil
+}
+
+func (s *s3Server) headBucket(w http.ResponseWriter, req *s3Request) error {
+	if _, err := s.bucket(req.r.Context(), req.userID, req.bucket); err != nil {
+		return err
+	}
+
+	w.Header().Set("x-amz-bucket-region", s.region)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// createBucket creates a top-level folder. Any configuration in the request body is ignored.
+func (s *s3Server) createBucket(w http.ResponseWriter, req *s3Request) error {
+	if req.bucket == "." || req.bucket == ".." {
+		return &s3Error{http.StatusBadRequest, "InvalidBucketName", "The specified bucket is not valid"}
+	}
+
+	ctx, cancel := context.WithTimeout(req.r.Context(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.gw.fileClient.CreateFolder(ctx, &filepb.CreateFolderRequest{Name: req.bucket, UserId: req.userID})
+	if status.Code(err) == codes.AlreadyExists {
+		if _, err := s.bucket(ctx, req.userID, req.bucket); err == nil {
+			return &s3Error{http.StatusConflict, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it"}
+		}
+		return &s3Error{http.StatusConflict, "BucketAlreadyExists", "The requested bucket name is not available"}
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Location", "/"+req.bucket)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// deleteBucket moves an empty top-level folder to the trash
+func (s *s3Server) deleteBucket(w http.ResponseWriter, req *s3Request) error {
+	folder, err := s.bucket(req.r.Context(), req.userID, req.bucket)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(req.r.Context(), 5*time.Second)
+	defer cancel()
+
+	if empty, err := s.empty(ctx, req.userID, folder.Id); err != nil {
+		return err
+	} else if !empty {
+		return &s3Error{http.StatusConflict, "BucketNotEmpty", "The bucket you tried to delete is not empty"}
+	}
+	if _, err := s.gw.fileClient.DeleteFolder(ctx, &filepb.DeleteFolderRequest{Id: folder.Id, UserId: req.userID}); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// empty reports whether a folder holds no subfolders or files
+func (s *s3Server) empty(ctx context.Context, userID, folderID string) (bool, error) {
+	resp, err := s.gw.fileClient.ListFolder(ctx, &filepb.ListFolderRequest{UserId: userID, FolderId: folderID, Page: 1, PageSize: 1})
+	if err != nil {
+		return false, err
+	}
+	return resp.TotalCount == 0, nil
+}
+
+// getObject streams a file's content. A key ending in a slash reads its folder as an empty
+// object, the way S3 tools mark directories.
+func (s *s3Server) getObject(w http.ResponseWriter, req *s3Request) error {
+	entry, err := s.resolve(req.r.Context(), req.userID, req.bucket, req.key)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(req.key, "/") {
+		folder := entry.GetFolder()
+		if folder == nil {
+			return errS3NoSuchKey
+		}
+		w.Header().Set("Content-Type", "application/x-directory")
+		w.Header().Set("Content-Length", "0")
+		w.Header().Set("ETag", `"`+sigv4.EmptyPayloadHash+`"`)
+		w.Header().Set("Last-Modified", folder.GetUpdatedAt().AsTime().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	// Files still waiting for their first upload have no content to serve
+	file := entry.GetFile()
+	if file == nil || file.Checksum == "" {
+		return errS3NoSuchKey
+	}
+	switch code, reason := unservable(file); code {
+	case 0:
+	case http.StatusConflict:
+		return &s3Error{http.StatusConflict, "InvalidObjectState", reason}
+	default:
+		return &s3Error{http.StatusForbidden, "AccessDenied", reason}
+	}
+
+	info, err := s.gw.blobs.Stat(req.r.Context(), file.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return errS3NoSuchKey
+	}
+	if err != nil {
+		return err
+	}
+
+	clearDeadlines(w)
+	s.gw.streamContent(w, req.r, file, file.StorageKey, info.Size, file.MimeType)
+	return nil
+}
+
+// putObject stores the request body as the file at a key, creating missing folders. An
+// existing file gets new content; the content it replaces is kept as a version. A key
+// ending in a slash creates its folder, the way S3 tools create empty directories.
+func (s *s3Server) putObject(w http.ResponseWriter, req *s3Request) error {
+	if strings.HasSuffix(req.key, "/") {
+		return s.putFolder(w, req)
+	}
+	if req.size > domain.MaxUploadSize {
+		return errS3EntityTooLarge
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(req.r.Header.Get("Content-Type"))
+	res, err := s.reserve(req.r.Context(), req.userID, req.bucket, req.key, mimeType)
+	if err != nil {
+		return err
+	}
+
+	clearDeadlines(w)
+	checksum, err := s.store(req.r.Context(), req.userID, res, req.body, req.size)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", `"`+checksum+`"`)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (s *s3Server) putFolder(w http.ResponseWriter, req *s3Request) error {
+	if req.size != 0 {
+		return &s3Error{http.StatusBadRequest, "InvalidArgument", "An object whose key ends in a slash must be empty"}
+	}
+	folderPath, err := objectPath(req.bucket, req.key)
+	if err != nil {
+		return err
+	}
+	if _, err := s.bucket(req.r.Context(), req.userID, req.bucket); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(req.r.Context(), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.gw.fileClient.CreateFolderPath(ctx, &filepb.CreateFolderPathRequest{UserId: req.userID, Path: folderPath}); err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", `"`+sigv4.EmptyPayloadHash+`"`)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// s3Reservation is the file an object is stored as and the storage key its new content goes to
+type s3Reservation struct {
+	fileID string
+	key    string
+	// maxSize is the largest content the file service accepts for the file
+	maxSize int64
+	// created is set when the file was created for the object, so that a failed upload
+	// deletes it again
+	created bool
+}
+
+// reserve returns where the content of an object goes, creating the file and any missing
+// folders when there is none at the key
+func (s *s3Server) reserve(ctx context.Context, userID, bucket, key, mimeType string) (*s3Reservation, error) {
+	objectPath, err := objectPath(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.resolve(ctx, userID, bucket, key)
+	switch {
+	case err == nil && entry.GetFile() == nil:
+		return nil, &s3Error{http.StatusConflict, "OperationAborted", "A folder exists at this key"}
+	case err == nil:
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		upload, err := s.gw.fileClient.GetUploadURL(ctx, &filepb.GetUploadURLRequest{UserId: userID, FileId: entry.GetFile().Id})
+		if err != nil {
+			return nil, err
+		}
+		return &s3Reservation{fileID: upload.FileId, key: upload.StorageKey, maxSize: upload.MaxSize}, nil
+	case !errors.Is(err, errS3NoSuchKey):
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	folderID := ""
+	dir, name := path.Split(objectPath)
+	if dir == "/"+bucket+"/" {
+		folder, err := s.bucket(ctx, userID, bucket)
+		if err != nil {
+			return nil, err
+		}
+		folderID = folder.Id
+	} else {
+		folder, err := s.gw.fileClient.CreateFolderPath(ctx, &filepb.CreateFolderPathRequest{UserId: userID, Path: dir})
+		if err != nil {
+			return nil, err
+		}
+		folderID = folder.Folder.Id
+	}
+
+	created, err := s.gw.fileClient.CreateFile(ctx, &filepb.CreateFileRequest{
+		Name:     name,
+		UserId:   userID,
+		FolderId: folderID,
+		MimeType: mimeType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3Reservation{fileID: created.File.Id, key: created.File.StorageKey, maxSize: created.MaxSize, created: true}, nil
+}
+
+// store writes the content of a reserved file and completes its upload, returning the
+// content's checksum. Content over the file's size limit is refused. When the upload
+// fails, its staged content goes, and so does the file if it was created for the upload.
+func (s *s3Server) store(ctx context.Context, userID string, res *s3Reservation, content io.Reader, size int64) (string, error) {
+	if res.maxSize > 0 {
+		if size > res.maxSize {
+			s.discard(ctx, userID, res, false)
+			return "", errS3EntityTooLarge
+		}
+		content = io.LimitReader(content, res.maxSize+1)
+	}
+
+	hash := sha256.New()
+	info, err := s.gw.blobs.Put(ctx, res.key, io.TeeReader(content, hash), size)
+	if err == nil && res.maxSize > 0 && info.Size > res.maxSize {
+		err = errS3EntityTooLarge
+	}
+	if err != nil {
+		s.discard(ctx, userID, res, true)
+		var failure *s3Error
+		if errors.As(err, &failure) {
+			return "", failure
+		}
+		return "", fmt.Errorf("failed to store object content: %w", err)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	completeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := s.gw.fileClient.CompleteUpload(completeCtx, &filepb.CompleteUploadRequest{
+		Id:         res.fileID,
+		UserId:     userID,
+		Checksum:   checksum,
+		StorageKey: res.key,
+	}); err != nil {
+		s.discard(ctx, userID, res, false)
+		return "", err
+	}
+	return checksum, nil
+}
+
+// discard cleans up after a failed upload: the staged content when it never reached the
+// file service, which drops content it refuses, and the file when it was created for the upload
+func (s *s3Server) discard(ctx context.Context, userID string, res *s3Reservation, staged bool) {
+	if staged {
+		s.gw.discardBlob(context.WithoutCancel(ctx), res.key)
+	}
+	if res.created {
+		s.gw.discardFile(res.fileID, userID)
+	}
+}
+
+// deleteObject moves the file at a key to the trash
+func (s *s3Server) deleteObject(w http.ResponseWriter, req *s3Request) error {
+	if err := s.delete(req.r.Context(), req.userID, req.bucket, req.key); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// delete moves the file at a key to the trash. As in S3, deleting a key that holds nothing
+// succeeds; a key ending in a slash removes its folder, but only while it is empty.
+func (s *s3Server) delete(ctx context.Context, userID, bucket, key string) error {
+	entry, err := s.resolve(ctx, userID, bucket, key)
+	if errors.Is(err, errS3NoSuchKey) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	folder := strings.HasSuffix(key, "/")
+	switch {
+	case entry.GetFile() != nil && !folder:
+		_, err = s.gw.fileClient.DeleteFile(ctx, &filepb.DeleteFileRequest{Id: entry.GetFile().Id, UserId: userID})
+	case entry.GetFolder() != nil && folder:
+		empty, err := s.empty(ctx, userID, entry.GetFolder().Id)
+		if err != nil || !empty {
+			return err
+		}
+		_, err = s.gw.fileClient.DeleteFolder(ctx, &filepb.DeleteFolderRequest{Id: entry.GetFolder().Id, UserId: userID})
+		return err
+	}
+	return err
+}
+
+type s3DeleteRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool     `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type s3DeletedObject struct {
+	Key string
+}
+
+type s3DeleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+type s3DeleteResult struct {
+	XMLName xml.Name          `xml:"DeleteResult"`
+	Xmlns   string            `xml:"xmlns,attr"`
+	Deleted []s3DeletedObject `xml:"Deleted"`
+	Errors  []s3DeleteError   `xml:"Error"`
+}
+
+// deleteObjects deletes the keys a request lists, reporting the outcome for each
+func (s *s3Server) deleteObjects(w http.ResponseWriter, req *s3Request) error {
+	var request s3DeleteRequest
+	if err := readS3XML(req, &request); err != nil {
+		return err
+	}
+	if len(request.Objects) == 0 || len(request.Objects) > s3MaxKeys {
+		return errS3MalformedXML
+	}
+	if _, err := s.bucket(req.r.Context(), req.userID, req.bucket); err != nil {
+		return err
+	}
+
+	clearDeadlines(w)
+	result := s3DeleteResult{Xmlns: s3Namespace}
+	for _, object := range request.Objects {
+		if err := s.delete(req.r.Context(), req.userID, req.bucket, object.Key); err != nil {
+			s3Err := toS3Error(err)
+			result.Errors = append(result.Errors, s3DeleteError{Key: object.Key, Code: s3Err.code, Message: s3Err.message})
+			continue
+		}
+		if !request.Quiet {
+			result.Deleted = append(result.Deleted, s3DeletedObject{Key: object.Key})
+		}
+	}
+	writeS3XML(w, http.StatusOK, result)
+	return nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	filepb "go-drive/proto/file"
+)
+
+// s3ListParameters are the query parameters of ListObjects and ListObjectsV2
+var s3ListParameters = []string{
+	"list-type", "prefix", "delimiter", "max-keys", "encoding-type",
+	"marker", "continuation-token", "start-after", "fetch-owner",
+}
+
+// errListingFull stops a listing once it has found an entry beyond its limit
+var errListingFull = errors.New("listing is full")
+
+type s3Object struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int64
+	Owner        *s3Owner `xml:",omitempty"`
+	StorageClass string
+}
+
+type s3CommonPrefix struct {
+	Prefix string
+}
+
+// s3ListResult is the response of ListObjects
+type s3ListResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	Name           string
+	Prefix         string
+	Marker         string
+	NextMarker     string `xml:",omitempty"`
+	MaxKeys        int
+	Delimiter      string `xml:",omitempty"`
+	EncodingType   string `xml:",omitempty"`
+	IsTruncated    bool
+	Contents       []s3Object
+	CommonPrefixes []s3CommonPrefix
+}
+
+// s3ListV2Result is the response of ListObjectsV2
+type s3ListV2Result struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Xmlns                 string   `xml:"xmlns,attr"`
+	Name                  string
+	Prefix                string
+	StartAfter            string `xml:",omitempty"`
+	ContinuationToken     string `xml:",omitempty"`
+	NextContinuationToken string `xml:",omitempty"`
+	KeyCount              int
+	MaxKeys               int
+	Delimiter             string `xml:",omitempty"`
+	EncodingType          string `xml:",omitempty"`
+	IsTruncated           bool
+	Contents              []s3Object
+	CommonPrefixes        []s3CommonPrefix
+}
+
+// listObjects answers ListObjects, and ListObjectsV2 when the query asks for list type 2.
+// ListObjectsV2 continuation tokens are the last key listed, encoded.
+func (s *s3Server) listObjects(w http.ResponseWriter, req *s3Request) error {
+	query := req.r.URL.Query()
+	v2 := query.Get("list-type") == "2"
+
+	maxKeys := s3MaxKeys
+	if value := query.Get("max-keys"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return &s3Error{http.StatusBadRequest, "InvalidArgument", "max-keys must be a non-negative integer"}
+		}
+		maxKeys = min(n, s3MaxKeys)
+	}
+	encodingType := query.Get("encoding-type")
+	if encodingType != "" && encodingType != "url" {
+		return &s3Error{http.StatusBadRequest, "InvalidArgument", "Invalid Encoding Method specified in Request"}
+	}
+
+	after := query.Get("marker")
+	if v2 {
+		after = query.Get("start-after")
+		if token := query.Get("continuation-token"); token != "" {
+			decoded, err := base64.RawURLEncoding.DecodeString(token)
+			if err != nil {
+				return &s3Error{http.StatusBadRequest, "InvalidArgument", "The continuation token provided is incorrect"}
+			}
+			after = string(decoded)
+		}
+	}
+
+	bucket, err := s.bucket(req.r.Context(), req.userID, req.bucket)
+	if err != nil {
+		return err
+	}
+
+	clearDeadlines(w)
+	listing := &s3Listing{
+		s:         s,
+		ctx:       req.r.Context(),
+		userID:    req.userID,
+		bucket:    req.bucket,
+		prefix:    query.Get("prefix"),
+		delimiter: query.Get("delimiter"),
+		after:     after,
+		max:       maxKeys,
+	}
+	if err := listing.run(bucket.Id); err != nil {
+		return err
+	}
+
+	encode := func(value string) string {
+		if encodingType == "url" {
+			return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+		}
+		return value
+	}
+	var owner *s3Owner
+	if !v2 || query.Get("fetch-owner") == "true" {
+		owner = &s3Owner{ID: req.userID}
+	}
+	contents := make([]s3Object, 0, len(listing.files))
+	for _, listed := range listing.files {
+		contents = append(contents, s3Object{
+			Key:          encode(listed.key),
+			LastModified: listed.file.GetUpdatedAt().AsTime().UTC().Format(s3TimeFormat),
+			ETag:         `"` + listed.file.Checksum + `"`,
+			Size:         listed.file.Size,
+			Owner:        owner,
+			StorageClass: "STANDARD",
+		})
+	}
+	prefixes := make([]s3CommonPrefix, 0, len(listing.prefixes))
+	for _, prefix := range listing.prefixes {
+		prefixes = append(prefixes, s3CommonPrefix{Prefix: encode(prefix)})
+	}
+
+	if !v2 {
+		result := s3ListResult{
+			Xmlns:          s3Namespace,
+			Name:           req.bucket,
+			Prefix:         encode(listing.prefix),
+			Marker:         encode(after),
+			MaxKeys:        maxKeys,
+			Delimiter:      encode(listing.delimiter),
+			EncodingType:   encodingType,
+			IsTruncated:    listing.truncated,
+			Contents:       contents,
+			CommonPrefixes: prefixes,
+		}
+		if listing.truncated {
+			result.NextMarker = encode(listing.last)
+		}
+		writeS3XML(w, http.StatusOK, result)
+		return nil
+	}
+
+	result := s3ListV2Result{
+		Xmlns:             s3Namespace,
+		Name:              req.bucket,
+		Prefix:            encode(listing.prefix),
+		StartAfter:        encode(query.Get("start-after")),
+		ContinuationToken: query.Get("continuation-token"),
+		KeyCount:          len(contents) + len(prefixes),
+		MaxKeys:           maxKeys,
+		Delimiter:         encode(listing.delimiter),
+		EncodingType:      encodingType,
+		IsTruncated:       listing.truncated,
+		Contents:          contents,
+		CommonPrefixes:    prefixes,
+	}
+	if listing.truncated {
+		result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(listing.last))
+	}
+	writeS3XML(w, http.StatusOK, result)
+	return nil
+}
+
+// s3Listing lists the objects of a bucket in key order, the way S3 does. Folders are walked
+// depth first with each folder's entries sorted by key, a folder's key being its name and a
+// slash, which visits keys in lexicographic order. Only folders that can hold keys under the
+// prefix and after the marker are read.
+type s3Listing struct {
+	s      *s3Server
+	ctx    context.Context
+	userID string
+	bucket string
+
+	prefix    string
+	delimiter string
+	after     string
+	max       int
+
+	files    []s3ListedFile
+	prefixes []string
+	// last is the key or common prefix listed last; truncated is set when more follow it
+	last      string
+	truncated bool
+}
+
+type s3ListedFile struct {
+	key  string
+	file *filepb.File
+}
+
+// s3Entry is a file or folder of a listing, under its key
+type s3Entry struct {
+	key    string
+	file   *filepb.File
+	folder *filepb.Folder
+}
+
+// run lists the bucket, starting at the deepest folder the prefix names
+func (l *s3Listing) run(bucketID string) error {
+	folderID := bucketID
+	dir := l.prefix[:strings.LastIndex(l.prefix, "/")+1]
+	if dir != "" {
+		// No key lies under a prefix a path cannot hold
+		folderPath, err := objectPath(l.bucket, dir)
+		if err != nil {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(l.ctx, 5*time.Second)
+		entry, err := l.s.gw.fileClient.ResolvePath(ctx, &filepb.ResolvePathRequest{UserId: l.userID, Path: strings.TrimSuffix(folderPath, "/")})
+		cancel()
+		if status.Code(err) == codes.NotFound || (err == nil && entry.GetFolder() == nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		folderID = entry.GetFolder().Id
+	}
+
+	err := l.walk(folderID, dir)
+	if errors.Is(err, errListingFull) {
+		l.truncated = true
+		return nil
+	}
+	return err
+}
+
+// walk lists the entries of a folder whose keys start with base
+func (l *s3Listing) walk(folderID, base string) error {
+	folders, files, err := l.s.list(l.ctx, l.userID, folderID)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]s3Entry, 0, len(folders)+len(files))
+	for _, folder := range folders {
+		entries = append(entries, s3Entry{key: base + folder.Name + "/", folder: folder})
+	}
+	for _, file := range files {
+		// Files still waiting for their first upload have no content to list
+		if file.Checksum != "" {
+			entries = append(entries, s3Entry{key: base + file.Name, file: file})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.key, l.prefix) {
+			continue
+		}
+
+		// Everything under an entry whose key holds the delimiter rolls up into one prefix
+		rest := entry.key[len(l.prefix):]
+		if i := strings.Index(rest, l.delimiter); l.delimiter != "" && i >= 0 {
+			err = l.add(l.prefix+rest[:i+len(l.delimiter)], nil)
+		} else if entry.folder != nil {
+			if entry.key <= l.after && !strings.HasPrefix(l.after, entry.key) {
+				continue
+			}
+			err = l.walk(entry.folder.Id, entry.key)
+		} else {
+			err = l.add(entry.key, entry.file)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add lists a file under its key, or a common prefix when file is nil, unless it comes
+// before the marker. It fails with errListingFull once the listing holds max entries.
+func (l *s3Listing) add(key string, file *filepb.File) error {
+	if key <= l.after {
+		return nil
+	}
+	if file == nil && len(l.prefixes) > 0 && l.prefixes[len(l.prefixes)-1] == key {
+		return nil
+	}
+	if len(l.files)+len(l.prefixes) >= l.max {
+		return errListingFull
+	}
+
+	if file == nil {
+		l.prefixes = append(l.prefixes, key)
+	} else {
+		l.files = append(l.files, s3ListedFile{key: key, file: file})
+	}
+	l.last = key
+	return nil
+}
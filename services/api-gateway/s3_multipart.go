@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/storage"
+	filepb "go-drive/proto/file"
+)
+
+// Multipart uploads stage their parts as blobs under s3UploadPrefix, each named by its
+// part number and MD5 (the part's ETag), next to a record of the key the upload is for.
+// Completing an upload joins the parts it lists into the content of a file. Staged parts
+// are held against the storage quota like stored content, and uploads that are neither
+// completed nor aborted are purged once they are s3UploadExpiry old.
+const (
+	s3UploadPrefix  = "s3-uploads/"
+	s3MaxPartNumber = 10000
+	// s3UploadRecord is the blob of an upload that records its key
+	s3UploadRecord = "upload"
+	// s3UploadExpiry is how long a multipart upload is kept before it is purged
+	s3UploadExpiry = 7 * 24 * time.Hour
+	// s3UploadPurgeInterval is how often expired multipart uploads are looked for
+	s3UploadPurgeInterval = time.Hour
+)
+
+// s3Upload records what a multipart upload was created for
+type s3Upload struct {
+	Path        string    `json:"path"`
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// uploadDir returns the prefix of the blobs of a user's multipart upload
+func uploadDir(userID, uploadID string) string {
+	return s3UploadPrefix + userID + "/" + uploadID + "/"
+}
+
+// partPrefix returns the prefix of the blobs a part with the given number is staged as
+func partPrefix(dir string, number int) string {
+	return fmt.Sprintf("%s%05d-", dir, number)
+}
+
+// partKey returns the blob a part with the given number and MD5 is staged as
+func partKey(dir string, number int, etag string) string {
+	return partPrefix(dir, number) + etag
+}
+
+type s3InitiateResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string
+	Key      string
+	UploadID string `xml:"UploadId"`
+}
+
+func (s *s3Server) createMultipartUpload(w http.ResponseWriter, req *s3Request) error {
+	objectPath, err := objectPath(req.bucket, req.key)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(req.key, "/") {
+		return &s3Error{http.StatusBadRequest, "InvalidArgument", "A multipart upload cannot be made to a key that ends in a slash"}
+	}
+	if _, err := s.bucket(req.r.Context(), req.userID, req.bucket); err != nil {
+		return err
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(req.r.Header.Get("Content-Type"))
+	record, err := json.Marshal(s3Upload{Path: objectPath, ContentType: mimeType, CreatedAt: s.now().UTC()})
+	if err != nil {
+		return err
+	}
+	uploadID := uuid.NewString()
+	if _, err := s.gw.blobs.Put(req.r.Context(), uploadDir(req.userID, uploadID)+s3UploadRecord, bytes.NewReader(record), int64(len(record))); err != nil {
+		return fmt.Errorf("failed to record multipart upload: %w", err)
+	}
+
+	writeS3XML(w, http.StatusOK, s3InitiateResult{Xmlns: s3Namespace, Bucket: req.bucket, Key: req.key, UploadID: uploadID})
+	return nil
+}
+
+// upload returns the multipart upload a request names, which must be for the request's key
+func (s *s3Server) upload(ctx context.Context, req *s3Request) (*s3Upload, error) {
+	objectPath, err := objectPath(req.bucket, req.key)
+	if err != nil {
+		return nil, err
+	}
+	noSuchUpload := &s3Error{http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist"}
+	uploadID := req.r.URL.Query().Get("uploadId")
+	if id, err := uuid.Parse(uploadID); err != nil || id.String() != uploadID {
+		return nil, noSuchUpload
+	}
+
+	upload, err := s.readUpload(ctx, uploadDir(req.userID, uploadID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, noSuchUpload
+	}
+	if err != nil {
+		return nil, err
+	}
+	if upload.Path != objectPath {
+		return nil, noSuchUpload
+	}
+	return upload, nil
+}
+
+// readUpload reads the record of the multipart upload staged under dir
+func (s *s3Server) readUpload(ctx context.Context, dir string) (*s3Upload, error) {
+	body, _, err := s.gw.blobs.Get(ctx, dir+s3UploadRecord)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read multipart upload: %w", err)
+	}
+	defer body.Close()
+
+	var upload s3Upload
+	if err := json.NewDecoder(body).Decode(&upload); err != nil {
+		return nil, fmt.Errorf("failed to read multipart upload: %w", err)
+	}
+	return &upload, nil
+}
+
+// uploadPart stages the request body as a part of a multipart upload. A part uploaded
+// again under the same number replaces the one staged before.
+func (s *s3Server) uploadPart(w http.ResponseWriter, req *s3Request) error {
+	number, err := strconv.Atoi(req.r.URL.Query().Get("partNumber"))
+	if err != nil || number < 1 || number > s3MaxPartNumber {
+		return &s3Error{http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000, inclusive"}
+	}
+	if req.size > domain.MaxUploadSize {
+		return errS3EntityTooLarge
+	}
+	if _, err := s.upload(req.r.Context(), req); err != nil {
+		return err
+	}
+	dir := uploadDir(req.userID, req.r.URL.Query().Get("uploadId"))
+	allowance, err := s.partAllowance(req.r.Context(), req.userID, partPrefix(dir, number))
+	if err != nil {
+		return err
+	}
+	body := req.body
+	if allowance >= 0 {
+		if req.size > allowance {
+			return errS3QuotaExceeded
+		}
+		body = io.LimitReader(body, allowance+1)
+	}
+
+	// The part is named by its MD5 once all of it has arrived
+	clearDeadlines(w)
+	staging := fmt.Sprintf("%s%05d.%s", dir, number, uuid.NewString())
+	hash := md5.New()
+	info, err := s.gw.blobs.Put(req.r.Context(), staging, io.TeeReader(body, hash), req.size)
+	if err == nil && allowance >= 0 && info.Size > allowance {
+		s.gw.discardBlob(req.r.Context(), staging)
+		return errS3QuotaExceeded
+	}
+	if err != nil {
+		var failure *s3Error
+		if errors.As(err, &failure) {
+			return failure
+		}
+		return fmt.Errorf("failed to store part: %w", err)
+	}
+	etag := hex.EncodeToString(hash.Sum(nil))
+	key := partKey(dir, number, etag)
+	if err := s.gw.blobs.Move(req.r.Context(), staging, key); err != nil {
+		s.gw.discardBlob(req.r.Context(), staging)
+		return fmt.Errorf("failed to store part: %w", err)
+	}
+	s.dropEarlierParts(req.r.Context(), dir, number, key)
+
+	w.Header().Set("ETag", `"`+etag+`"`)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// partAllowance returns how many bytes a part may take before the user's storage quota,
+// less the parts the user has staged, is exhausted, or -1 when storage is unlimited. Parts
+// staged under replaced, which the new part takes the place of, are not counted.
+func (s *s3Server) partAllowance(ctx context.Context, userID, replaced string) (int64, error) {
+	usageCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	usage, err := s.gw.fileClient.GetUsage(usageCtx, &filepb.GetUsageRequest{UserId: userID})
+	if err != nil {
+		return 0, err
+	}
+	if usage.RemainingBytes < 0 {
+		return -1, nil
+	}
+
+	staged, err := s.gw.blobs.List(ctx, s3UploadPrefix+userID+"/")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list staged parts: %w", err)
+	}
+	allowance := usage.RemainingBytes
+	for _, blob := range staged {
+		if !strings.HasSuffix(blob.Key, "/"+s3UploadRecord) && !strings.HasPrefix(blob.Key, replaced) {
+			allowance -= blob.Size
+		}
+	}
+	return max(allowance, 0), nil
+}
+
+// dropEarlierParts deletes the parts staged under a number before the one stored as key
+func (s *s3Server) dropEarlierParts(ctx context.Context, dir string, number int, key string) {
+	stored, err := s.gw.blobs.Stat(ctx, key)
+	if err != nil {
+		log.Printf("Failed to replace part %d of %s: %v", number, dir, err)
+		return
+	}
+	parts, err := s.gw.blobs.List(ctx, partPrefix(dir, number))
+	if err != nil {
+		log.Printf("Failed to replace part %d of %s: %v", number, dir, err)
+		return
+	}
+	for _, part := range parts {
+		if part.Key != key && part.LastModified.Before(stored.LastModified) {
+			s.gw.discardBlob(ctx, part.Key)
+		}
+	}
+}
+
+type s3CompleteRequest struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type s3CompleteResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string
+	Bucket   string
+	Key      string
+	ETag     string
+}
+
+// completeMultipartUpload joins the parts a request lists, in order, into the content of
+// the file at the upload's key, and discards the upload. The file is stored like one put
+// whole, so its ETag is the checksum of the joined content.
+func (s *s3Server) completeMultipartUpload(w http.ResponseWriter, req *s3Request) error {
+	upload, err := s.upload(req.r.Context(), req)
+	if err != nil {
+		return err
+	}
+	var request s3CompleteRequest
+	if err := readS3XML(req, &request); err != nil {
+		return err
+	}
+	if len(request.Parts) == 0 {
+		return errS3MalformedXML
+	}
+
+	uploadID := req.r.URL.Query().Get("uploadId")
+	dir := uploadDir(req.userID, uploadID)
+	parts := &s3Parts{ctx: req.r.Context(), blobs: s.gw.blobs}
+	defer parts.Close()
+	var size int64
+	for i, part := range request.Parts {
+		if i > 0 && part.PartNumber <= request.Parts[i-1].PartNumber {
+			return &s3Error{http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order"}
+		}
+		etag := strings.ToLower(strings.Trim(part.ETag, `"`))
+		if _, err := hex.DecodeString(etag); err != nil || len(etag) != 2*md5.Size || part.PartNumber < 1 || part.PartNumber > s3MaxPartNumber {
+			return &s3Error{http.StatusBadRequest, "InvalidPart", fmt.Sprintf("Part %d could not be found", part.PartNumber)}
+		}
+
+		staged := partKey(dir, part.PartNumber, etag)
+		info, err := s.gw.blobs.Stat(req.r.Context(), staged)
+		if errors.Is(err, storage.ErrNotFound) {
+			return &s3Error{http.StatusBadRequest, "InvalidPart", fmt.Sprintf("Part %d could not be found", part.PartNumber)}
+		}
+		if err != nil {
+			return err
+		}
+		parts.keys = append(parts.keys, staged)
+		size += info.Size
+	}
+
+	res, err := s.reserve(req.r.Context(), req.userID, req.bucket, req.key, upload.ContentType)
+	if err != nil {
+		return err
+	}
+	clearDeadlines(w)
+	checksum, err := s.store(req.r.Context(), req.userID, res, parts, size)
+	if err != nil {
+		return err
+	}
+	if err := s.discardUpload(req.r.Context(), req.userID, uploadID); err != nil {
+		log.Printf("Failed to discard multipart upload %s: %v", uploadID, err)
+	}
+
+	writeS3XML(w, http.StatusOK, s3CompleteResult{
+		Xmlns:    s3Namespace,
+		Location: "/" + req.bucket + "/" + req.key,
+		Bucket:   req.bucket,
+		Key:      req.key,
+		ETag:     `"` + checksum + `"`,
+	})
+	return nil
+}
+
+// abortMultipartUpload discards a multipart upload and its staged parts
+func (s *s3Server) abortMultipartUpload(w http.ResponseWriter, req *s3Request) error {
+	if _, err := s.upload(req.r.Context(), req); err != nil {
+		return err
+	}
+	if err := s.discardUpload(req.r.Context(), req.userID, req.r.URL.Query().Get("uploadId")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// discardUpload deletes the blobs of a multipart upload, its record last so that a
+// failure can be retried
+func (s *s3Server) discardUpload(ctx context.Context, userID, uploadID string) error {
+	dir := uploadDir(userID, uploadID)
+	blobs, err := s.gw.blobs.List(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to list multipart upload: %w", err)
+	}
+	for _, blob := range blobs {
+		if blob.Key == dir+s3UploadRecord {
+			continue
+		}
+		if err := s.gw.blobs.Delete(ctx, blob.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to delete part: %w", err)
+		}
+	}
+	if err := s.gw.blobs.Delete(ctx, dir+s3UploadRecord); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to delete multipart upload: %w", err)
+	}
+	return nil
+}
+
+// purgeUploads discards the multipart uploads created more than s3UploadExpiry ago, and
+// parts left behind by uploads that were aborted while they were being sent. It returns
+// the number of uploads purged.
+func (s *s3Server) purgeUploads(ctx context.Context) (int, error) {
+	blobs, err := s.gw.blobs.List(ctx, s3UploadPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list multipart uploads: %w", err)
+	}
+
+	// An upload is as old as its record, or as its newest part once the record is gone
+	type staged struct {
+		userID, uploadID string
+		record           bool
+		recorded         time.Time
+		updated          time.Time
+	}
+	var uploads []*staged
+	byDir := make(map[string]*staged)
+	for _, blob := range blobs {
+		userID, rest, _ := strings.Cut(strings.TrimPrefix(blob.Key, s3UploadPrefix), "/")
+		uploadID, name, ok := strings.Cut(rest, "/")
+		if !ok {
+			continue
+		}
+		dir := uploadDir(userID, uploadID)
+		upload := byDir[dir]
+		if upload == nil {
+			upload = &staged{userID: userID, uploadID: uploadID}
+			byDir[dir] = upload
+			uploads = append(uploads, upload)
+		}
+		if name == s3UploadRecord {
+			upload.record, upload.recorded = true, blob.LastModified
+		} else if blob.LastModified.After(upload.updated) {
+			upload.updated = blob.LastModified
+		}
+	}
+
+	cutoff := s.now().Add(-s3UploadExpiry)
+	var purged int
+	for _, upload := range uploads {
+		dir := uploadDir(upload.userID, upload.uploadID)
+		if upload.record {
+			record, err := s.readUpload(ctx, dir)
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				log.Printf("Failed to read multipart upload %s: %v", dir, err)
+				continue
+			}
+			// Uploads recorded before creation times were kept are as old as their record
+			upload.updated = record.CreatedAt
+			if upload.updated.IsZero() {
+				upload.updated = upload.recorded
+			}
+		}
+		if !upload.updated.Before(cutoff) {
+			continue
+		}
+		if err := s.discardUpload(ctx, upload.userID, upload.uploadID); err != nil {
+			log.Printf("Failed to purge multipart upload %s: %v", dir, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// runUploadPurger purges expired multipart uploads every interval until ctx is cancelled
+func (s *s3Server) runUploadPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.purgeUploads(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to purge multipart uploads: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d expired multipart uploads", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// s3Parts reads the staged parts of a multipart upload one after the other
+type s3Parts struct {
+	ctx     context.Context
+	blobs   storage.Backend
+	keys    []string
+	current io.ReadCloser
+}
+
+func (p *s3Parts) Read(b []byte) (int, error) {
+	for {
+		if p.current == nil {
+			if len(p.keys) == 0 {
+				return 0, io.EOF
+			}
+			body, _, err := p.blobs.Get(p.ctx, p.keys[0])
+			if err != nil {
+				return 0, fmt.Errorf("failed to read part %s: %w", p.keys[0], err)
+			}
+			p.current, p.keys = body, p.keys[1:]
+		}
+
+		n, err := p.current.Read(b)
+		if errors.Is(err, io.EOF) {
+			p.current.Close()
+			p.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (p *s3Parts) Close() error {
+	if p.current == nil {
+		return nil
+	}
+	err := p.current.Close()
+	p.current = nil
+	return err
+}
@@ -0,0 +1,588 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/sigv4"
+	filepb "go-drive/proto/file"
+)
+
+const (
+	testAccessKeyID = "GDTESTACCESSKEY00000"
+	testSecretKey   = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	testRegion      = "us-east-1"
+)
+
+var testSignedAt = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+// newTestS3Server creates an S3 server over temporary storage holding "hello world" under
+// testKey, for which the file service knows the test access key
+func newTestS3Server(t *testing.T, client *MockFileServiceClient) *s3Server {
+	t.Helper()
+	client.On("GetSigningKey", mock.Anything, &filepb.GetSigningKeyRequest{
+		AccessKeyId: testAccessKeyID, Date: "20261016", Region: testRegion, Service: "s3",
+	}).Return(&filepb.GetSigningKeyResponse{
+		UserId:     testUserID,
+		SigningKey: sigv4.SigningKey(testSecretKey, testSignedAt, testRegion, "s3"),
+	}, nil).Maybe()
+
+	gw := newBlobGateway(t, client)
+	_, err := gw.blobs.Put(context.Background(), testKey, strings.NewReader("hello world"), 11)
+	require.NoError(t, err)
+
+	s := newS3Server(gw, testRegion)
+	s.now = func() time.Time { return testSignedAt }
+	return s
+}
+
+// newS3Request creates a request signed with the test access key
+func newS3Request(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, "http://s3.example.com"+target, strings.NewReader(body))
+	return signS3Request(req, testSecretKey, body)
+}
+
+func signS3Request(req *http.Request, secretKey, body string) *http.Request {
+	hash := sha256.Sum256([]byte(body))
+	sigv4.SignRequest(req, sigv4.Credentials{AccessKey: testAccessKeyID, SecretKey: secretKey}, testRegion, "s3", hex.EncodeToString(hash[:]), testSignedAt)
+	return req
+}
+
+// newStreamingS3Request creates a PUT whose body is sent in signed aws-chunked encoding,
+// in chunks of chunkSize bytes
+func newStreamingS3Request(target, content string, chunkSize int) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "http://s3.example.com"+target, nil)
+	req.Header.Set("Content-Encoding", "aws-chunked")
+	req.Header.Set("X-Amz-Decoded-Content-Length", strconv.Itoa(len(content)))
+	sigv4.SignRequest(req, sigv4.Credentials{AccessKey: testAccessKeyID, SecretKey: testSecretKey}, testRegion, "s3", sigv4.StreamingPayload, testSignedAt)
+
+	auth, _ := sigv4.ParseAuthorization(req.Header.Get("Authorization"))
+	key := sigv4.SigningKey(testSecretKey, testSignedAt, testRegion, "s3")
+	previous := auth.Signature
+	var body strings.Builder
+	for offset := 0; ; offset += chunkSize {
+		chunk := content[min(offset, len(content)):min(offset+chunkSize, len(content))]
+		hash := sha256.Sum256([]byte(chunk))
+		previous = sigv4.Sign(key, strings.Join([]string{
+			"AWS4-HMAC-SHA256-PAYLOAD", req.Header.Get("X-Amz-Date"), auth.Scope(), previous, sigv4.EmptyPayloadHash, hex.EncodeToString(hash[:]),
+		}, "\n"))
+		fmt.Fprintf(&body, "%x;chunk-signature=%s\r\n%s\r\n", len(chunk), previous, chunk)
+		if chunk == "" {
+			break
+		}
+	}
+	body.WriteString("\r\n")
+
+	req.Body = io.NopCloser(strings.NewReader(body.String()))
+	req.ContentLength = int64(body.Len())
+	return req
+}
+
+func s3ErrorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp s3ErrorResponse
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+	return resp.Code
+}
+
+func TestS3Server(t *testing.T) {
+	photos := &filepb.Folder{Id: testFolderID, Name: "photos", CreatedAt: timestamppb.New(testSignedAt)}
+	beach := &filepb.File{Id: testFileID, Name: "beach.jpg", StorageKey: testKey, MimeType: "image/jpeg", Size: 11, Checksum: helloChecksum, ScanStatus: domain.ScanClean}
+	createsBeach := func(client *MockFileServiceClient) {
+		resolves(client, "/photos/beach.jpg", nil)
+		resolves(client, "/photos", &filepb.ResolvePathResponse{Folder: photos})
+		client.On("CreateFile", mock.Anything, &filepb.CreateFileRequest{Name: "beach.jpg", UserId: testUserID, FolderId: testFolderID, MimeType: "image/jpeg"}).
+			Return(&filepb.CreateFileResponse{File: &filepb.File{Id: testFileID, Name: "beach.jpg", StorageKey: testKey + ".new"}}, nil)
+	}
+
+	// discards expects the file created for a failed upload to be deleted again
+	discards := func(client *MockFileServiceClient) {
+		client.On("DeleteFile", mock.Anything, &filepb.DeleteFileRequest{Id: testFileID, UserId: testUserID}).
+			Return(&filepb.DeleteFileResponse{}, nil)
+	}
+
+	tests := []struct {
+		name           string
+		request        func() *http.Request
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedCode   string
+		expectedBody   []string
+		expectedHeader map[string]string
+	}{
+		{
+			name:    "list buckets",
+			request: func() *http.Request { return newS3Request(http.MethodGet, "/", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, Page: 1, PageSize: s3PageSize}).
+					Return(&filepb.ListFolderResponse{Folders: []*filepb.Folder{photos}, Files: []*filepb.File{beach}, TotalCount: 2}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"<Bucket><Name>photos</Name><CreationDate>2026-10-16T12:00:00.000Z</CreationDate></Bucket>"},
+		},
+		{
+			name: "anonymous request",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://s3.example.com/", nil)
+			},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "AccessDenied",
+		},
+		{
+			name: "unknown access key",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://s3.example.com/", nil)
+				sigv4.SignRequest(req, sigv4.Credentials{AccessKey: "GDREVOKED", SecretKey: testSecretKey}, testRegion, "s3", sigv4.EmptyPayloadHash, testSignedAt)
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("GetSigningKey", mock.Anything, mock.MatchedBy(func(req *filepb.GetSigningKeyRequest) bool { return req.AccessKeyId == "GDREVOKED" })).
+					Return(nil, status.Error(codes.NotFound, "access key not found"))
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "InvalidAccessKeyId",
+		},
+		{
+			name: "wrong secret key",
+			request: func() *http.Request {
+				return signS3Request(httptest.NewRequest(http.MethodGet, "http://s3.example.com/", nil), "not-the-secret", "")
+			},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "SignatureDoesNotMatch",
+		},
+		{
+			name: "another region",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://s3.example.com/", nil)
+				sigv4.SignRequest(req, sigv4.Credentials{AccessKey: testAccessKeyID, SecretKey: testSecretKey}, "eu-west-1", "s3", sigv4.EmptyPayloadHash, testSignedAt)
+				return req
+			},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "AuthorizationHeaderMalformed",
+		},
+		{
+			name: "stale signature",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://s3.example.com/", nil)
+				sigv4.SignRequest(req, sigv4.Credentials{AccessKey: testAccessKeyID, SecretKey: testSecretKey}, testRegion, "s3", sigv4.EmptyPayloadHash, testSignedAt.Add(-time.Hour))
+				return req
+			},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "RequestTimeTooSkewed",
+		},
+		{
+			name:    "get object",
+			request: func() *http.Request { return newS3Request(http.MethodGet, "/photos/beach.jpg", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/photos/beach.jpg", &filepb.ResolvePathResponse{File: beach})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"hello world"},
+			expectedHeader: map[string]string{"ETag": `"` + helloChecksum + `"`, "Content-Type": "image/jpeg"},
+		},
+		{
+			name:    "get object in a missing bucket",
+			request: func() *http.Request { return newS3Request(http.MethodGet, "/videos/beach.jpg", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/videos/beach.jpg", nil)
+				resolves(client, "/videos", nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "NoSuchBucket",
+		},
+		{
+			name:    "get missing object",
+			request: func() *http.Request { return newS3Request(http.MethodGet, "/photos/missing.jpg", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/photos/missing.jpg", nil)
+				resolves(client, "/photos", &filepb.ResolvePathResponse{Folder: photos})
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "NoSuchKey",
+		},
+		{
+			name:    "get quarantined object",
+			request: func() *http.Request { return newS3Request(http.MethodGet, "/photos/beach.jpg", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				quarantined := &filepb.File{Id: testFileID, StorageKey: testKey, Checksum: helloChecksum, Quarantined: true}
+				resolves(client, "/photos/beach.jpg", &filepb.ResolvePathResponse{File: quarantined})
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "AccessDenied",
+		},
+		{
+			name: "put object",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, "http://s3.example.com/photos/beach.jpg", strings.NewReader("hello world"))
+				req.Header.Set("Content-Type", "image/jpeg")
+				return signS3Request(req, testSecretKey, "hello world")
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				createsBeach(client)
+				client.On("CompleteUpload", mock.Anything, &filepb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: helloChecksum, StorageKey: testKey + ".new"}).
+					Return(&filepb.CompleteUploadResponse{File: beach}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeader: map[string]string{"ETag": `"` + helloChecksum + `"`},
+		},
+		{
+			name: "put object in signed chunks",
+			request: func() *http.Request {
+				req := newStreamingS3Request("/photos/beach.jpg", "hello world", 4)
+				req.Header.Set("Content-Type", "image/jpeg")
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				createsBeach(client)
+				client.On("CompleteUpload", mock.Anything, &filepb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: helloChecksum, StorageKey: testKey + ".new"}).
+					Return(&filepb.CompleteUploadResponse{File: beach}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeader: map[string]string{"ETag": `"` + helloChecksum + `"`},
+		},
+		{
+			name: "put object with a tampered chunk",
+			request: func() *http.Request {
+				req := newStreamingS3Request("/photos/beach.jpg", "hello world", 4)
+				req.Header.Set("Content-Type", "image/jpeg")
+				body, _ := io.ReadAll(req.Body)
+				req.Body = io.NopCloser(strings.NewReader(strings.Replace(string(body), "hell", "HELL", 1)))
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				createsBeach(client)
+				discards(client)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "SignatureDoesNotMatch",
+		},
+		{
+			name: "put object over the file size limit",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, "http://s3.example.com/photos/beach.jpg", strings.NewReader("hello world"))
+				req.Header.Set("Content-Type", "image/jpeg")
+				return signS3Request(req, testSecretKey, "hello world")
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/photos/beach.jpg", nil)
+				resolves(client, "/photos", &filepb.ResolvePathResponse{Folder: photos})
+				client.On("CreateFile", mock.Anything, mock.Anything).
+					Return(&filepb.CreateFileResponse{File: &filepb.File{Id: testFileID, Name: "beach.jpg", StorageKey: testKey + ".new"}, MaxSize: 4}, nil)
+				discards(client)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EntityTooLarge",
+		},
+		{
+			name: "put object whose body does not match its hash",
+			request: func() *http.Request {
+				req := newS3Request(http.MethodPut, "/photos/beach.jpg", "hello world")
+				req.Body = io.NopCloser(strings.NewReader("HELLO WORLD"))
+				return req
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/photos/beach.jpg", &filepb.ResolvePathResponse{File: beach})
+				client.On("GetUploadURL", mock.Anything, &filepb.GetUploadURLRequest{UserId: testUserID, FileId: testFileID}).
+					Return(&filepb.GetUploadURLResponse{FileId: testFileID, StorageKey: testKey + ".2"}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "XAmzContentSHA256Mismatch",
+		},
+		{
+			name:           "put object under a dot segment",
+			request:        func() *http.Request { return newS3Request(http.MethodPut, "/photos/a/../beach.jpg", "hello world") },
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "InvalidArgument",
+		},
+		{
+			name:    "delete missing object",
+			request: func() *http.Request { return newS3Request(http.MethodDelete, "/photos/missing.jpg", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/photos/missing.jpg", nil)
+				resolves(client, "/photos", &filepb.ResolvePathResponse{Folder: photos})
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:    "delete non-empty bucket",
+			request: func() *http.Request { return newS3Request(http.MethodDelete, "/photos", "") },
+			mockSetup: func(client *MockFileServiceClient) {
+				resolves(client, "/photos", &filepb.ResolvePathResponse{Folder: photos})
+				client.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, FolderId: testFolderID, Page: 1, PageSize: 1}).
+					Return(&filepb.ListFolderResponse{Files: []*filepb.File{beach}, TotalCount: 1}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "BucketNotEmpty",
+		},
+		{
+			name: "copy object",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, "http://s3.example.com/photos/copy.jpg", nil)
+				req.Header.Set("X-Amz-Copy-Source", "/photos/beach.jpg")
+				return signS3Request(req, testSecretKey, "")
+			},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusNotImplemented,
+			expectedCode:   "NotImplemented",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			s := newTestS3Server(t, mockClient)
+
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, tt.request())
+
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			if tt.expectedCode != "" {
+				assert.Equal(t, tt.expectedCode, s3ErrorCode(t, rec))
+			}
+			for _, body := range tt.expectedBody {
+				assert.Contains(t, rec.Body.String(), body)
+			}
+			for header, value := range tt.expectedHeader {
+				assert.Equal(t, value, rec.Header().Get(header))
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestS3Server_ListObjects(t *testing.T) {
+	const yearID = "423e4567-e89b-12d3-a456-426614174000"
+	photos := &filepb.Folder{Id: testFolderID, Name: "photos"}
+	year := &filepb.Folder{Id: yearID, Name: "2026"}
+	photo := func(name string) *filepb.File {
+		return &filepb.File{Name: name, Size: 11, Checksum: helloChecksum, UpdatedAt: timestamppb.New(testSignedAt)}
+	}
+	setup := func(client *MockFileServiceClient) {
+		resolves(client, "/photos", &filepb.ResolvePathResponse{Folder: photos})
+		resolves(client, "/photos/2026", &filepb.ResolvePathResponse{Folder: year})
+		resolves(client, "/photos/2027", nil)
+		client.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, FolderId: testFolderID, Page: 1, PageSize: s3PageSize}).
+			Return(&filepb.ListFolderResponse{
+				Folders: []*filepb.Folder{year},
+				Files:   []*filepb.File{photo("z.jpg"), photo("a.jpg"), {Name: "uploading.jpg"}},
+			}, nil)
+		client.On("ListFolder", mock.Anything, &filepb.ListFolderRequest{UserId: testUserID, FolderId: yearID, Page: 1, PageSize: s3PageSize}).
+			Return(&filepb.ListFolderResponse{Files: []*filepb.File{photo("beach.jpg"), photo("beach plan.jpg")}}, nil)
+	}
+	list := func(t *testing.T, query string) s3ListV2Result {
+		t.Helper()
+		mockClient := new(MockFileServiceClient)
+		setup(mockClient)
+		s := newTestS3Server(t, mockClient)
+
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, newS3Request(http.MethodGet, "/photos?list-type=2&"+query, ""))
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var result s3ListV2Result
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &result))
+		return result
+	}
+	keys := func(result s3ListV2Result) []string {
+		var keys []string
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+		return keys
+	}
+
+	t.Run("whole bucket in key order", func(t *testing.T) {
+		result := list(t, "")
+
+		assert.Equal(t, []string{"2026/beach plan.jpg", "2026/beach.jpg", "a.jpg", "z.jpg"}, keys(result))
+		assert.Equal(t, `"`+helloChecksum+`"`, result.Contents[0].ETag)
+		assert.Equal(t, "2026-10-16T12:00:00.000Z", result.Contents[0].LastModified)
+		assert.False(t, result.IsTruncated)
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		first := list(t, "max-keys=3")
+		require.True(t, first.IsTruncated)
+		assert.Equal(t, []string{"2026/beach plan.jpg", "2026/beach.jpg", "a.jpg"}, keys(first))
+
+		second := list(t, "max-keys=3&continuation-token="+first.NextContinuationToken)
+		assert.False(t, second.IsTruncated)
+		assert.Equal(t, []string{"z.jpg"}, keys(second))
+	})
+
+	t.Run("folders as common prefixes", func(t *testing.T) {
+		result := list(t, "delimiter=%2F")
+
+		assert.Equal(t, []string{"a.jpg", "z.jpg"}, keys(result))
+		assert.Equal(t, []s3CommonPrefix{{Prefix: "2026/"}}, result.CommonPrefixes)
+		assert.Equal(t, 3, result.KeyCount)
+	})
+
+	t.Run("prefix within a folder", func(t *testing.T) {
+		result := list(t, "prefix=2026%2Fbeach+&encoding-type=url")
+
+		assert.Equal(t, []string{"2026%2Fbeach%20plan.jpg"}, keys(result))
+	})
+
+	t.Run("prefix of a missing folder", func(t *testing.T) {
+		result := list(t, "prefix=2027%2F")
+
+		assert.Empty(t, result.Contents)
+	})
+}
+
+func TestS3Server_MultipartUpload(t *testing.T) {
+	photos := &filepb.Folder{Id: testFolderID, Name: "photos"}
+	mockClient := new(MockFileServiceClient)
+	s := newTestS3Server(t, mockClient)
+	resolves(mockClient, "/photos", &filepb.ResolvePathResponse{Folder: photos})
+	mockClient.On("GetUsage", mock.Anything, &filepb.GetUsageRequest{UserId: testUserID}).
+		Return(&filepb.GetUsageResponse{RemainingBytes: 100}, nil)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+	etag := func(part string) string {
+		sum := md5.Sum([]byte(part))
+		return `"` + hex.EncodeToString(sum[:]) + `"`
+	}
+
+	rec := serve(newS3Request(http.MethodPost, "/photos/video.mp4?uploads", ""))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var initiated s3InitiateResult
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &initiated))
+	uploadID := initiated.UploadID
+
+	for number, part := range []string{"hello ", "earth"} {
+		rec := serve(newS3Request(http.MethodPut, fmt.Sprintf("/photos/video.mp4?partNumber=%d&uploadId=%s", number+1, uploadID), part))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, etag(part), rec.Header().Get("ETag"))
+	}
+
+	t.Run("part uploaded again replaces the earlier one", func(t *testing.T) {
+		rec := serve(newS3Request(http.MethodPut, "/photos/video.mp4?partNumber=2&uploadId="+uploadID, "world"))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, etag("world"), rec.Header().Get("ETag"))
+
+		staged, err := s.gw.blobs.List(context.Background(), partPrefix(uploadDir(testUserID, uploadID), 2))
+		require.NoError(t, err)
+		require.Len(t, staged, 1)
+		assert.Equal(t, partKey(uploadDir(testUserID, uploadID), 2, strings.Trim(etag("world"), `"`)), staged[0].Key)
+	})
+
+	t.Run("part over the quota", func(t *testing.T) {
+		// 100 bytes remain, of which the staged parts take 11
+		rec := serve(newS3Request(http.MethodPut, "/photos/video.mp4?partNumber=3&uploadId="+uploadID, strings.Repeat("a", 90)))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "QuotaExceeded", s3ErrorCode(t, rec))
+		staged, err := s.gw.blobs.List(context.Background(), partPrefix(uploadDir(testUserID, uploadID), 3))
+		require.NoError(t, err)
+		assert.Empty(t, staged)
+	})
+
+	t.Run("unknown part", func(t *testing.T) {
+		complete := "<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>" + etag("hello ") + "</ETag></Part>" +
+			"<Part><PartNumber>2</PartNumber><ETag>" + etag("earth") + "</ETag></Part></CompleteMultipartUpload>"
+		rec := serve(newS3Request(http.MethodPost, "/photos/video.mp4?uploadId="+uploadID, complete))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "InvalidPart", s3ErrorCode(t, rec))
+	})
+
+	t.Run("upload of another key", func(t *testing.T) {
+		rec := serve(newS3Request(http.MethodPut, "/photos/other.mp4?partNumber=1&uploadId="+uploadID, "hello"))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "NoSuchUpload", s3ErrorCode(t, rec))
+	})
+
+	t.Run("complete joins the parts", func(t *testing.T) {
+		resolves(mockClient, "/photos/video.mp4", nil)
+		mockClient.On("CreateFile", mock.Anything, &filepb.CreateFileRequest{Name: "video.mp4", UserId: testUserID, FolderId: testFolderID}).
+			Return(&filepb.CreateFileResponse{File: &filepb.File{Id: testFileID, Name: "video.mp4", StorageKey: testKey + ".video"}}, nil)
+		mockClient.On("CompleteUpload", mock.Anything, &filepb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: helloChecksum, StorageKey: testKey + ".video"}).
+			Return(&filepb.CompleteUploadResponse{}, nil)
+
+		complete := "<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>" + etag("hello ") + "</ETag></Part>" +
+			"<Part><PartNumber>2</PartNumber><ETag>" + etag("world") + "</ETag></Part></CompleteMultipartUpload>"
+		rec := serve(newS3Request(http.MethodPost, "/photos/video.mp4?uploadId="+uploadID, complete))
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Contains(t, rec.Body.String(), "<ETag>&#34;"+helloChecksum+"&#34;</ETag>")
+		stored, _, err := s.gw.blobs.Get(context.Background(), testKey+".video")
+		require.NoError(t, err)
+		defer stored.Close()
+		data, err := io.ReadAll(stored)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(data))
+
+		staged, err := s.gw.blobs.List(context.Background(), s3UploadPrefix)
+		require.NoError(t, err)
+		assert.Empty(t, staged, "the upload is discarded")
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("abort of a completed upload", func(t *testing.T) {
+		rec := serve(newS3Request(http.MethodDelete, "/photos/video.mp4?uploadId="+uploadID, ""))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "NoSuchUpload", s3ErrorCode(t, rec))
+	})
+}
+
+func TestS3Server_PurgeUploads(t *testing.T) {
+	ctx := context.Background()
+	s := newTestS3Server(t, new(MockFileServiceClient))
+	stage := func(uploadID string, createdAt time.Time) string {
+		dir := uploadDir(testUserID, uploadID)
+		record, err := json.Marshal(s3Upload{Path: "/photos/video.mp4", CreatedAt: createdAt})
+		require.NoError(t, err)
+		_, err = s.gw.blobs.Put(ctx, dir+s3UploadRecord, bytes.NewReader(record), int64(len(record)))
+		require.NoError(t, err)
+		_, err = s.gw.blobs.Put(ctx, partKey(dir, 1, strings.Repeat("0", 32)), strings.NewReader("hello"), 5)
+		require.NoError(t, err)
+		return dir
+	}
+	expired := stage("6f0c3a5e-4d7b-4f3e-9a51-0b7e2c1d8f90", testSignedAt.Add(-s3UploadExpiry-time.Hour))
+	open := stage("7a1d4b6f-5e8c-4a4f-8b62-1c8f3d2e9a01", testSignedAt.Add(-time.Hour))
+
+	purged, err := s.purgeUploads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	staged, err := s.gw.blobs.List(ctx, expired)
+	require.NoError(t, err)
+	assert.Empty(t, staged, "the expired upload is purged with its parts")
+	staged, err = s.gw.blobs.List(ctx, open)
+	require.NoError(t, err)
+	assert.Len(t, staged, 2, "uploads within the expiry are kept")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	filepb "go-drive/proto/file"
+)
+
+// searchPath searches the names of the caller's files and folders:
+// /api/v1/search?q=report&mime_type=application/pdf&min_size=&max_size=
+// &modified_after=&modified_before=&folder_id=&cursor=&limit=
+// Times are RFC 3339; mime_type may be repeated.
+const searchPath = "/api/v1/search"
+
+func (gw *APIGateway) handleSearch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	req := &filepb.SearchFilesRequest{
+		UserId:    userID,
+		Query:     query.Get("q"),
+		MimeTypes: query["mime_type"],
+		FolderId:  query.Get("folder_id"),
+		Cursor:    query.Get("cursor"),
+		Limit:     int32(limit),
+	}
+	for param, size := range map[string]**int64{"min_size": &req.MinSize, "max_size": &req.MaxSize} {
+		if value := query.Get(param); value != "" {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				http.Error(w, param+" must be a number of bytes", http.StatusBadRequest)
+				return
+			}
+			*size = &n
+		}
+	}
+	for param, ts := range map[string]**timestamppb.Timestamp{"modified_after": &req.ModifiedAfter, "modified_before": &req.ModifiedBefore} {
+		if value := query.Get(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				http.Error(w, param+" must be an RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+			*ts = timestamppb.New(t)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.SearchFiles(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
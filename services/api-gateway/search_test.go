@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	filepb "go-drive/proto/file"
+)
+
+func TestAPIGateway_HandleSearch(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		userID         string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "search with filters",
+			query:  "?q=report&mime_type=application/pdf&mime_type=image/*&min_size=10&modified_after=2026-01-01T00:00:00Z&limit=5",
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("SearchFiles", mock.Anything, mock.MatchedBy(func(req *filepb.SearchFilesRequest) bool {
+					return req.UserId == testUserID && req.Query == "report" &&
+						assert.ObjectsAreEqual([]string{"application/pdf", "image/*"}, req.MimeTypes) &&
+						req.MinSize != nil && *req.MinSize == 10 && req.MaxSize == nil &&
+						req.ModifiedAfter.AsTime().Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+						req.ModifiedBefore == nil && req.Limit == 5
+				})).Return(&filepb.SearchFilesResponse{
+					Hits: []*filepb.SearchHit{{
+						File:       &filepb.File{Id: testFileID, Name: "report.pdf"},
+						Highlights: []*filepb.Highlight{{Start: 0, End: 6}},
+					}},
+					NextCursor: "next",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"next_cursor":"next"`,
+		},
+		{
+			name:           "invalid size",
+			query:          "?q=report&max_size=big",
+			userID:         testUserID,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid time",
+			query:          "?q=report&modified_before=yesterday",
+			userID:         testUserID,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "missing query",
+			query:  "",
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("SearchFiles", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.InvalidArgument, "query is required"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthenticated",
+			query:          "?q=report",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := httptest.NewRequest(http.MethodGet, searchPath+tt.query, nil)
+			if tt.userID != "" {
+				req.Header.Set(userIDHeader, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			gw.handleSearch(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-drive/internal/domain"
+	filepb "go-drive/proto/file"
+)
+
+// thumbnailPathPrefix serves JPEG thumbnails of image files:
+// /api/v1/thumbnails/{file_id}?size=256
+const thumbnailPathPrefix = "/api/v1/thumbnails/"
+
+// thumbnailMaxAge is how long clients may reuse a thumbnail without revalidating it.
+// The ETag changes with the file's content, so a stale thumbnail is cheap to refresh.
+const thumbnailMaxAge = time.Hour
+
+// thumbnailRetryAfter is the delay suggested to clients while thumbnails are rendered
+const thumbnailRetryAfter = 5 * time.Second
+
+func (gw *APIGateway) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fileID := strings.TrimPrefix(r.URL.Path, thumbnailPathPrefix)
+	var size int64
+	if value := r.URL.Query().Get("size"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || n <= 0 {
+			http.Error(w, "size must be a positive number of pixels", http.StatusBadRequest)
+			return
+		}
+		size = n
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.GetThumbnail(ctx, &filepb.GetThumbnailRequest{
+		Id:     fileID,
+		UserId: userID,
+		Size:   int32(size),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	switch resp.Status {
+	case domain.ThumbnailReady:
+	case domain.ThumbnailPending:
+		w.Header().Set("Retry-After", strconv.Itoa(int(thumbnailRetryAfter.Seconds())))
+		w.Header().Set("Cache-Control", "no-store")
+		http.Error(w, "Thumbnail is being generated", http.StatusAccepted)
+		return
+	default:
+		http.Error(w, "No thumbnail is available for this file", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", resp.ContentType)
+	w.Header().Set("ETag", `"`+resp.Etag+`"`)
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(thumbnailMaxAge.Seconds())))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(resp.Content))
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	filepb "go-drive/proto/file"
+)
+
+func TestAPIGateway_HandleThumbnail(t *testing.T) {
+	ready := &filepb.GetThumbnailResponse{
+		Status:      domain.ThumbnailReady,
+		Size:        256,
+		ContentType: "image/jpeg",
+		Content:     []byte("jpeg data"),
+		Etag:        "abc-256",
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		userID          string
+		headers         map[string]string
+		mockSetup       func(*MockFileServiceClient)
+		expectedStatus  int
+		expectedBody    string
+		expectedHeaders map[string]string
+	}{
+		{
+			name:   "ready",
+			query:  "?size=200",
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("GetThumbnail", mock.Anything, &filepb.GetThumbnailRequest{Id: testFileID, UserId: testUserID, Size: 200}).
+					Return(ready, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "jpeg data",
+			expectedHeaders: map[string]string{
+				"Content-Type":  "image/jpeg",
+				"ETag":          `"abc-256"`,
+				"Cache-Control": "private, max-age=3600",
+			},
+		},
+		{
+			name:    "not modified",
+			userID:  testUserID,
+			headers: map[string]string{"If-None-Match": `"abc-256"`},
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("GetThumbnail", mock.Anything, mock.Anything).Return(ready, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:   "still rendering",
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("GetThumbnail", mock.Anything, mock.Anything).
+					Return(&filepb.GetThumbnailResponse{Status: domain.ThumbnailPending}, nil)
+			},
+			expectedStatus:  http.StatusAccepted,
+			expectedHeaders: map[string]string{"Retry-After": "5", "Cache-Control": "no-store"},
+		},
+		{
+			name:   "cannot be rendered",
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("GetThumbnail", mock.Anything, mock.Anything).
+					Return(&filepb.GetThumbnailResponse{Status: domain.ThumbnailUnsupported}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "not an image",
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("GetThumbnail", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.NotFound, "file has no thumbnails"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid size",
+			query:          "?size=large",
+			userID:         testUserID,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthenticated",
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := httptest.NewRequest(http.MethodGet, thumbnailPathPrefix+testFileID+tt.query, nil)
+			if tt.userID != "" {
+				req.Header.Set(userIDHeader, tt.userID)
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			gw.handleThumbnail(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			for key, value := range tt.expectedHeaders {
+				assert.Equal(t, value, rec.Header().Get(key), key)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	filepb "go-drive/proto/file"
+)
+
+// Resumable uploads follow the tus 1.0 protocol (https://tus.io/protocols/resumable-upload)
+// with the creation, expiration, termination and checksum extensions. Upload state lives in
+// the file service; each PATCH body is stored as a separate blob until the upload completes.
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,expiration,termination,checksum"
+	tusPathPrefix = "/api/v1/uploads"
+	// statusChecksumMismatch is the tus status for a chunk whose Upload-Checksum does not match
+	statusChecksumMismatch = 460
+	tusContentType         = "application/offset+octet-stream"
+)
+
+// tusChecksums maps the supported Upload-Checksum algorithms to their hash constructors
+var tusChecksums = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+}
+
+func (gw *APIGateway) handleTus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+
+	// Some environments only allow GET and POST
+	if override := r.Header.Get("X-HTTP-Method-Override"); override != "" {
+		r.Method = override
+	}
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Tus-Version", tusVersion)
+		w.Header().Set("Tus-Extension", tusExtensions)
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(domain.MaxUploadSize, 10))
+		w.Header().Set("Tus-Checksum-Algorithm", tusChecksumAlgorithms())
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		http.Error(w, "Unsupported tus version", http.StatusPreconditionFailed)
+		return
+	}
+
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, tusPathPrefix), "/")
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		gw.createTusUpload(w, r, userID)
+	case id != "" && r.Method == http.MethodHead:
+		gw.headTusUpload(w, r, userID, id)
+	case id != "" && r.Method == http.MethodPatch:
+		gw.patchTusUpload(w, r, userID, id)
+	case id != "" && r.Method == http.MethodDelete:
+		gw.deleteTusUpload(w, r, userID, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (gw *APIGateway) createTusUpload(w http.ResponseWriter, r *http.Request, userID string) {
+	size, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || size < 0 {
+		http.Error(w, "Upload-Length header is required", http.StatusBadRequest)
+		return
+	}
+	if size > domain.MaxUploadSize {
+		http.Error(w, "Upload exceeds the maximum size", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	metadata, err := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		http.Error(w, "Invalid Upload-Metadata header", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.CreateUpload(ctx, &filepb.CreateUploadRequest{
+		UserId:   userID,
+		Name:     metadata["filename"],
+		FolderId: metadata["folder_id"],
+		MimeType: metadata["filetype"],
+		Size:     size,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	// An empty upload is complete as soon as it exists
+	if size == 0 {
+		if _, err := gw.fileClient.FinishUpload(ctx, &filepb.FinishUploadRequest{Id: resp.Upload.Id, UserId: userID}); err != nil {
+			writeGRPCError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Location", tusPathPrefix+"/"+resp.Upload.Id)
+	w.Header().Set("Upload-Offset", "0")
+	if size > 0 {
+		setUploadExpires(w, resp.Upload)
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (gw *APIGateway) headTusUpload(w http.ResponseWriter, r *http.Request, userID, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.GetUpload(ctx, &filepb.GetUploadRequest{Id: id, UserId: userID})
+	if err != nil {
+		w.WriteHeader(httpStatusFromGRPC(err))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(reportedOffset(resp.Upload), 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(resp.Upload.Size, 10))
+	setUploadExpires(w, resp.Upload)
+	w.Header().Set("Upload-Metadata", formatTusMetadata(map[string]string{
+		"filename":  resp.Upload.Name,
+		"filetype":  resp.Upload.MimeType,
+		"folder_id": resp.Upload.FolderId,
+	}))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (gw *APIGateway) patchTusUpload(w http.ResponseWriter, r *http.Request, userID, id string) {
+	if r.Header.Get("Content-Type") != tusContentType {
+		http.Error(w, "Content-Type must be "+tusContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		http.Error(w, "Upload-Offset header is required", http.StatusBadRequest)
+		return
+	}
+
+	var checksum hash.Hash
+	var expectedSum []byte
+	if header := r.Header.Get("Upload-Checksum"); header != "" {
+		algorithm, value, _ := strings.Cut(header, " ")
+		newHash, ok := tusChecksums[algorithm]
+		if !ok {
+			http.Error(w, "Unsupported checksum algorithm", http.StatusBadRequest)
+			return
+		}
+		if expectedSum, err = base64.StdEncoding.DecodeString(value); err != nil {
+			http.Error(w, "Invalid Upload-Checksum header", http.StatusBadRequest)
+			return
+		}
+		checksum = newHash()
+	}
+
+	// Storage writes must finish even if the client drops mid-chunk,
+	// so that the bytes that did arrive can be kept
+	ctx := context.WithoutCancel(r.Context())
+	clearDeadlines(w)
+
+	current, err := gw.fileClient.GetUpload(ctx, &filepb.GetUploadRequest{Id: id, UserId: userID})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	upload := current.Upload
+	if upload.Size > 0 && upload.Offset == upload.Size && (offset == upload.Size || offset == reportedOffset(upload)) {
+		gw.retryTusFinish(ctx, w, r, userID, upload, offset)
+		return
+	}
+	if offset != upload.Offset {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(reportedOffset(upload), 10))
+		http.Error(w, "Upload-Offset does not match the current offset", http.StatusConflict)
+		return
+	}
+
+	remaining := upload.Size - upload.Offset
+	if r.ContentLength > remaining {
+		http.Error(w, "Chunk exceeds the upload length", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	body := &partialReader{r: http.MaxBytesReader(w, r.Body, remaining)}
+	var content io.Reader = body
+	if checksum != nil {
+		content = io.TeeReader(body, checksum)
+	}
+
+	key := fmt.Sprintf("uploads/%s/%020d-%s", id, offset, uuid.NewString())
+	info, err := gw.blobs.Put(ctx, key, content, -1)
+	if err != nil {
+		http.Error(w, "Failed to store chunk", http.StatusInternalServerError)
+		return
+	}
+
+	// Decide whether the bytes received can be committed
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(body.err, &tooLarge):
+		gw.discardBlob(ctx, key)
+		http.Error(w, "Chunk exceeds the upload length", http.StatusRequestEntityTooLarge)
+		return
+	case checksum != nil && (body.err != nil || !bytes.Equal(checksum.Sum(nil), expectedSum)):
+		gw.discardBlob(ctx, key)
+		http.Error(w, "Checksum mismatch", statusChecksumMismatch)
+		return
+	case info.Size == 0:
+		gw.discardBlob(ctx, key)
+		if body.err != nil {
+			return
+		}
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		setUploadExpires(w, upload)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	committed, err := gw.fileClient.CommitUploadChunk(ctx, &filepb.CommitUploadChunkRequest{
+		Id:         id,
+		UserId:     userID,
+		Offset:     offset,
+		Size:       info.Size,
+		StorageKey: key,
+	})
+	if err != nil {
+		gw.discardBlob(ctx, key)
+		if status.Code(err) == codes.FailedPrecondition {
+			http.Error(w, "Upload-Offset does not match the current offset", http.StatusConflict)
+			return
+		}
+		writeGRPCError(w, err)
+		return
+	}
+	upload = committed.Upload
+
+	if upload.Offset == upload.Size {
+		gw.finishTusUpload(ctx, w, userID, upload)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	setUploadExpires(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// retryTusFinish assembles an upload that has received all of its bytes but could not be
+// assembled before. The request may send the last byte again, which is already stored and
+// is dropped.
+func (gw *APIGateway) retryTusFinish(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string, upload *filepb.Upload, offset int64) {
+	if _, err := io.Copy(io.Discard, http.MaxBytesReader(w, r.Body, upload.Size-offset)); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Chunk exceeds the upload length", http.StatusRequestEntityTooLarge)
+		}
+		return
+	}
+	gw.finishTusUpload(ctx, w, userID, upload)
+}
+
+// finishTusUpload assembles an upload that has received all of its bytes into a file
+func (gw *APIGateway) finishTusUpload(ctx context.Context, w http.ResponseWriter, userID string, upload *filepb.Upload) {
+	if _, err := gw.fileClient.FinishUpload(ctx, &filepb.FinishUploadRequest{Id: upload.Id, UserId: userID}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Size, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setUploadExpires tells the client until when an upload is kept without receiving more of it
+func setUploadExpires(w http.ResponseWriter, upload *filepb.Upload) {
+	if upload.ExpiresAt != nil {
+		w.Header().Set("Upload-Expires", upload.ExpiresAt.AsTime().UTC().Format(http.TimeFormat))
+	}
+}
+
+// reportedOffset is the offset an upload is reported at. An upload that has received all of
+// its bytes is only still there because assembling it failed, so it is reported one byte
+// short: clients resuming it send the last byte again, which retries the assembly, instead of
+// taking the upload as done.
+func reportedOffset(upload *filepb.Upload) int64 {
+	if upload.Size > 0 && upload.Offset == upload.Size {
+		return upload.Offset - 1
+	}
+	return upload.Offset
+}
+
+func (gw *APIGateway) deleteTusUpload(w http.ResponseWriter, r *http.Request, userID, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if _, err := gw.fileClient.DeleteUpload(ctx, &filepb.DeleteUploadRequest{Id: id, UserId: userID}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// partialReader ends the stream at the first read error and records it,
+// so a chunk interrupted by a dropped connection keeps the bytes that arrived
+type partialReader struct {
+	r   io.Reader
+	err error
+}
+
+func (p *partialReader) Read(b []byte) (int, error) {
+	if p.err != nil {
+		return 0, io.EOF
+	}
+	n, err := p.r.Read(b)
+	if err != nil && !errors.Is(err, io.EOF) {
+		p.err = err
+		err = io.EOF
+	}
+	return n, err
+}
+
+// parseTusMetadata decodes an Upload-Metadata header of comma-separated "key base64(value)" pairs
+func parseTusMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("empty metadata key")
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for metadata key %s: %w", key, err)
+		}
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+// formatTusMetadata encodes the non-empty entries of metadata as an Upload-Metadata header
+func formatTusMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + " " + base64.StdEncoding.EncodeToString([]byte(metadata[key]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func tusChecksumAlgorithms() string {
+	names := make([]string, 0, len(tusChecksums))
+	for name := range tusChecksums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
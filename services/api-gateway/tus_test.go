@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	filepb "go-drive/proto/file"
+)
+
+const testUploadID = "423e4567-e89b-12d3-a456-426614174000"
+
+// testUploadExpiry is when the uploads of these tests expire
+var testUploadExpiry = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+func newTusRequest(method, path string, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Tus-Resumable", tusVersion)
+	req.Header.Set(userIDHeader, testUserID)
+	return req
+}
+
+func TestAPIGateway_HandleTusOptions(t *testing.T) {
+	gw := newBlobGateway(t, new(MockFileServiceClient))
+
+	rec := httptest.NewRecorder()
+	gw.handleTus(rec, httptest.NewRequest(http.MethodOptions, tusPathPrefix, nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, tusVersion, rec.Header().Get("Tus-Version"))
+	assert.Equal(t, "creation,expiration,termination,checksum", rec.Header().Get("Tus-Extension"))
+	assert.Equal(t, "md5,sha1,sha256", rec.Header().Get("Tus-Checksum-Algorithm"))
+}
+
+func TestAPIGateway_HandleTusCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		location       string
+		expires        string
+	}{
+		{
+			name: "successful creation",
+			headers: map[string]string{
+				"Upload-Length":   "1048576",
+				"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("movie.mp4")) + ",filetype " + base64.StdEncoding.EncodeToString([]byte("video/mp4")),
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("CreateUpload", mock.Anything, &filepb.CreateUploadRequest{
+					UserId:   testUserID,
+					Name:     "movie.mp4",
+					MimeType: "video/mp4",
+					Size:     1048576,
+				}).Return(&filepb.CreateUploadResponse{Upload: &filepb.Upload{Id: testUploadID, Size: 1048576, ExpiresAt: timestamppb.New(testUploadExpiry)}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			location:       tusPathPrefix + "/" + testUploadID,
+			expires:        "Mon, 02 Mar 2026 12:00:00 GMT",
+		},
+		{
+			name: "empty upload finishes immediately",
+			headers: map[string]string{
+				"Upload-Length":   "0",
+				"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("empty.txt")),
+			},
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("CreateUpload", mock.Anything, mock.Anything).
+					Return(&filepb.CreateUploadResponse{Upload: &filepb.Upload{Id: testUploadID}}, nil)
+				client.On("FinishUpload", mock.Anything, &filepb.FinishUploadRequest{Id: testUploadID, UserId: testUserID}).
+					Return(&filepb.FinishUploadResponse{File: &filepb.File{Id: testFileID}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			location:       tusPathPrefix + "/" + testUploadID,
+		},
+		{
+			name:           "missing upload length",
+			headers:        map[string]string{},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too large",
+			headers:        map[string]string{"Upload-Length": "99999999999999"},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "invalid metadata",
+			headers:        map[string]string{"Upload-Length": "10", "Upload-Metadata": "filename !!!"},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "rejected by file service",
+			headers: map[string]string{"Upload-Length": "10"},
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("CreateUpload", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.InvalidArgument, "name is required"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := newTusRequest(http.MethodPost, tusPathPrefix, "")
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			gw.handleTus(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tusVersion, rec.Header().Get("Tus-Resumable"))
+			if tt.location != "" {
+				assert.Equal(t, tt.location, rec.Header().Get("Location"))
+			}
+			assert.Equal(t, tt.expires, rec.Header().Get("Upload-Expires"))
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIGateway_HandleTusProtocolErrors(t *testing.T) {
+	gw := newBlobGateway(t, new(MockFileServiceClient))
+
+	t.Run("unsupported version", func(t *testing.T) {
+		req := newTusRequest(http.MethodPost, tusPathPrefix, "")
+		req.Header.Set("Tus-Resumable", "0.2.2")
+		rec := httptest.NewRecorder()
+
+		gw.handleTus(rec, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		assert.Equal(t, tusVersion, rec.Header().Get("Tus-Version"))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		req := newTusRequest(http.MethodPost, tusPathPrefix, "")
+		req.Header.Del(userIDHeader)
+		rec := httptest.NewRecorder()
+
+		gw.handleTus(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("patch without upload id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		gw.handleTus(rec, newTusRequest(http.MethodPatch, tusPathPrefix, ""))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestAPIGateway_HandleTusHead(t *testing.T) {
+	mockClient := new(MockFileServiceClient)
+	mockClient.On("GetUpload", mock.Anything, &filepb.GetUploadRequest{Id: testUploadID, UserId: testUserID}).
+		Return(&filepb.GetUploadResponse{Upload: &filepb.Upload{Id: testUploadID, Name: "movie.mp4", Size: 100, Offset: 40, ExpiresAt: timestamppb.New(testUploadExpiry)}}, nil)
+	gw := newBlobGateway(t, mockClient)
+
+	rec := httptest.NewRecorder()
+	gw.handleTus(rec, newTusRequest(http.MethodHead, tusPathPrefix+"/"+testUploadID, ""))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "40", rec.Header().Get("Upload-Offset"))
+	assert.Equal(t, "100", rec.Header().Get("Upload-Length"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "Mon, 02 Mar 2026 12:00:00 GMT", rec.Header().Get("Upload-Expires"))
+	assert.Equal(t, "filename "+base64.StdEncoding.EncodeToString([]byte("movie.mp4")), rec.Header().Get("Upload-Metadata"))
+
+	t.Run("all bytes received but not assembled", func(t *testing.T) {
+		const unassembledID = "523e4567-e89b-12d3-a456-426614174000"
+		mockClient.On("GetUpload", mock.Anything, &filepb.GetUploadRequest{Id: unassembledID, UserId: testUserID}).
+			Return(&filepb.GetUploadResponse{Upload: &filepb.Upload{Id: unassembledID, Name: "movie.mp4", Size: 100, Offset: 100}}, nil)
+
+		rec := httptest.NewRecorder()
+		gw.handleTus(rec, newTusRequest(http.MethodHead, tusPathPrefix+"/"+unassembledID, ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "99", rec.Header().Get("Upload-Offset"), "the upload is not reported as done")
+		assert.Equal(t, "100", rec.Header().Get("Upload-Length"))
+	})
+
+	t.Run("expired upload", func(t *testing.T) {
+		const expiredID = "623e4567-e89b-12d3-a456-426614174000"
+		mockClient.On("GetUpload", mock.Anything, &filepb.GetUploadRequest{Id: expiredID, UserId: testUserID}).
+			Return(nil, status.Error(codes.NotFound, "upload has expired"))
+
+		rec := httptest.NewRecorder()
+		gw.handleTus(rec, newTusRequest(http.MethodHead, tusPathPrefix+"/"+expiredID, ""))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestAPIGateway_HandleTusPatch(t *testing.T) {
+	path := tusPathPrefix + "/" + testUploadID
+	getUpload := func(client *MockFileServiceClient, offset int64) {
+		client.On("GetUpload", mock.Anything, &filepb.GetUploadRequest{Id: testUploadID, UserId: testUserID}).
+			Return(&filepb.GetUploadResponse{Upload: &filepb.Upload{Id: testUploadID, Size: 11, Offset: offset}}, nil)
+	}
+	sha1Header := func(content string) string {
+		sum := sha1.Sum([]byte(content))
+		return "sha1 " + base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	tests := []struct {
+		name           string
+		offset         string
+		body           string
+		headers        map[string]string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+		expectedOffset string
+		expires        string
+		expectChunk    bool
+	}{
+		{
+			name:   "first chunk",
+			offset: "0",
+			body:   "hello ",
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 0)
+				client.On("CommitUploadChunk", mock.Anything, mock.MatchedBy(func(req *filepb.CommitUploadChunkRequest) bool {
+					return req.Offset == 0 && req.Size == 6 && strings.HasPrefix(req.StorageKey, "uploads/"+testUploadID+"/")
+				})).Return(&filepb.CommitUploadChunkResponse{Upload: &filepb.Upload{Id: testUploadID, Size: 11, Offset: 6, ExpiresAt: timestamppb.New(testUploadExpiry)}}, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedOffset: "6",
+			expires:        "Mon, 02 Mar 2026 12:00:00 GMT",
+			expectChunk:    true,
+		},
+		{
+			name:    "final chunk with checksum",
+			offset:  "6",
+			body:    "world",
+			headers: map[string]string{"Upload-Checksum": sha1Header("world")},
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 6)
+				client.On("CommitUploadChunk", mock.Anything, mock.Anything).
+					Return(&filepb.CommitUploadChunkResponse{Upload: &filepb.Upload{Id: testUploadID, Size: 11, Offset: 11}}, nil)
+				client.On("FinishUpload", mock.Anything, &filepb.FinishUploadRequest{Id: testUploadID, UserId: testUserID}).
+					Return(&filepb.FinishUploadResponse{File: &filepb.File{Id: testFileID}}, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedOffset: "11",
+			expectChunk:    true,
+		},
+		{
+			name:   "empty chunk retries a failed assembly",
+			offset: "11",
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 11)
+				client.On("FinishUpload", mock.Anything, &filepb.FinishUploadRequest{Id: testUploadID, UserId: testUserID}).
+					Return(&filepb.FinishUploadResponse{File: &filepb.File{Id: testFileID}}, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedOffset: "11",
+		},
+		{
+			name:   "last byte resent retries a failed assembly",
+			offset: "10",
+			body:   "d",
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 11)
+				client.On("FinishUpload", mock.Anything, &filepb.FinishUploadRequest{Id: testUploadID, UserId: testUserID}).
+					Return(&filepb.FinishUploadResponse{File: &filepb.File{Id: testFileID}}, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedOffset: "11",
+		},
+		{
+			name:   "assembly failing again",
+			offset: "11",
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 11)
+				client.On("FinishUpload", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.Internal, "failed to assemble upload"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "checksum mismatch",
+			offset:  "6",
+			body:    "world",
+			headers: map[string]string{"Upload-Checksum": sha1Header("other")},
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 6)
+			},
+			expectedStatus: statusChecksumMismatch,
+		},
+		{
+			name:           "unsupported checksum algorithm",
+			offset:         "0",
+			body:           "hello",
+			headers:        map[string]string{"Upload-Checksum": "crc32 AAAA"},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "stale offset",
+			offset: "0",
+			body:   "hello",
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 6)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedOffset: "6",
+		},
+		{
+			name:   "concurrent writer committed first",
+			offset: "0",
+			body:   "hello ",
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 0)
+				client.On("CommitUploadChunk", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.FailedPrecondition, "upload offset mismatch"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "chunk past upload length",
+			offset: "6",
+			body:   "world and more",
+			mockSetup: func(client *MockFileServiceClient) {
+				getUpload(client, 6)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "wrong content type",
+			offset:         "0",
+			body:           "hello",
+			headers:        map[string]string{"Content-Type": "text/plain"},
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := newTusRequest(http.MethodPatch, path, tt.body)
+			req.Header.Set("Content-Type", tusContentType)
+			req.Header.Set("Upload-Offset", tt.offset)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			gw.handleTus(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedOffset != "" {
+				assert.Equal(t, tt.expectedOffset, rec.Header().Get("Upload-Offset"))
+			}
+			assert.Equal(t, tt.expires, rec.Header().Get("Upload-Expires"))
+
+			chunks, err := gw.blobs.List(context.Background(), "uploads/"+testUploadID+"/")
+			require.NoError(t, err)
+			if tt.expectChunk {
+				assert.Len(t, chunks, 1)
+			} else {
+				assert.Empty(t, chunks, "rejected chunks must not be kept")
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIGateway_HandleTusDelete(t *testing.T) {
+	mockClient := new(MockFileServiceClient)
+	mockClient.On("DeleteUpload", mock.Anything, &filepb.DeleteUploadRequest{Id: testUploadID, UserId: testUserID}).
+		Return(&filepb.DeleteUploadResponse{Message: "Upload deleted successfully"}, nil)
+	gw := newBlobGateway(t, mockClient)
+
+	rec := httptest.NewRecorder()
+	gw.handleTus(rec, newTusRequest(http.MethodDelete, tusPathPrefix+"/"+testUploadID, ""))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockClient.AssertExpectations(t)
+}
+
+func TestParseTusMetadata(t *testing.T) {
+	metadata, err := parseTusMetadata("filename d29ybGRfZG9taW5hdGlvbl9wbGFuLnBkZg==, is_confidential")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"filename":        "world_domination_plan.pdf",
+		"is_confidential": "",
+	}, metadata)
+
+	metadata, err = parseTusMetadata("")
+	require.NoError(t, err)
+	assert.Empty(t, metadata)
+
+	_, err = parseTusMetadata("filename not-base64!")
+	assert.Error(t, err)
+}
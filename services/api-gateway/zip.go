@@ -0,0 +1,124 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-drive/internal/storage"
+	filepb "go-drive/proto/file"
+)
+
+// zipPath downloads a folder with everything below it, or a selection of files, as a ZIP
+// archive: /api/v1/zip?folder_id= or /api/v1/zip?file_id=&file_id=
+// Long selections can be POSTed as a form with the same fields.
+const zipPath = "/api/v1/zip"
+
+// skippedFilesHeader reports how many files were left out of an archive because the caller
+// cannot access them or they cannot be downloaded
+const skippedFilesHeader = "X-Skipped-Files"
+
+func (gw *APIGateway) handleZip(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	resp, err := gw.fileClient.ListDownloadEntries(ctx, &filepb.ListDownloadEntriesRequest{
+		UserId:   userID,
+		FolderId: r.Form.Get("folder_id"),
+		FileIds:  r.Form["file_id"],
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	clearDeadlines(w)
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": resp.Name + ".zip"}))
+	w.Header().Set(skippedFilesHeader, strconv.Itoa(int(resp.Skipped)))
+
+	// The archive is written straight to the response, so a failure part way through can
+	// only be reported by cutting the connection; the client sees a truncated download
+	// rather than a valid archive with files missing.
+	if err := gw.writeZip(r.Context(), w, resp.Entries); err != nil {
+		log.Printf("Failed to stream archive %q for user %s: %v", resp.Name, userID, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// writeZip writes entries as a ZIP archive. archive/zip switches to ZIP64 records for
+// archives and files beyond 4GiB, so neither size nor count is limited here. Files whose
+// content has gone missing are logged and left out.
+func (gw *APIGateway) writeZip(ctx context.Context, w io.Writer, entries []*filepb.DownloadEntry) error {
+	archive := zip.NewWriter(w)
+	for _, entry := range entries {
+		file := entry.GetFile()
+		if file == nil {
+			if _, err := archive.CreateHeader(&zip.FileHeader{Name: entry.Path, Method: zip.Store}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, _, err := gw.blobs.Get(ctx, file.StorageKey)
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Content of file %s not found; leaving it out of the archive", file.Id)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", file.Id, err)
+		}
+
+		header := &zip.FileHeader{Name: entry.Path, Method: zipMethod(file.MimeType)}
+		if updated := file.GetUpdatedAt(); updated != nil {
+			header.Modified = updated.AsTime()
+		}
+		fw, err := archive.CreateHeader(header)
+		if err == nil {
+			_, err = io.Copy(fw, content)
+		}
+		content.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write file %s: %w", file.Id, err)
+		}
+	}
+	return archive.Close()
+}
+
+// zipMethod stores content that is already compressed and deflates the rest
+func zipMethod(mimeType string) uint16 {
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	switch {
+	case strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml",
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"):
+		return zip.Store
+	}
+	switch mediaType {
+	case "application/zip", "application/gzip", "application/x-7z-compressed", "application/x-rar-compressed",
+		"application/x-bzip2", "application/x-xz", "application/zstd":
+		return zip.Store
+	}
+	return zip.Deflate
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	filepb "go-drive/proto/file"
+)
+
+// readZip opens an archive and returns its entries by name
+func readZip(t *testing.T, body []byte) map[string]*zip.File {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	return files
+}
+
+func TestAPIGateway_HandleZip_Folder(t *testing.T) {
+	mockClient := new(MockFileServiceClient)
+	gw := newBlobGateway(t, mockClient)
+	ctx := context.Background()
+	modified := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	notes := strings.Repeat("meeting notes\n", 100)
+	_, err := gw.blobs.Put(ctx, testUserID+"/notes", strings.NewReader(notes), -1)
+	require.NoError(t, err)
+	_, err = gw.blobs.Put(ctx, testUserID+"/beach", strings.NewReader("jpeg bytes"), -1)
+	require.NoError(t, err)
+
+	mockClient.On("ListDownloadEntries", mock.Anything, &filepb.ListDownloadEntriesRequest{UserId: testUserID, FolderId: testFileID}).
+		Return(&filepb.ListDownloadEntriesResponse{
+			Name: "Photos",
+			Entries: []*filepb.DownloadEntry{
+				{Path: "Photos/"},
+				{Path: "Photos/Trip/"},
+				{Path: "Photos/Trip/beach.jpg", File: &filepb.File{Id: "beach", StorageKey: testUserID + "/beach", MimeType: "image/jpeg"}},
+				{Path: "Photos/Trip/lost.txt", File: &filepb.File{Id: "lost", StorageKey: testUserID + "/lost", MimeType: "text/plain"}},
+				{Path: "Photos/notes.txt", File: &filepb.File{Id: "notes", StorageKey: testUserID + "/notes", MimeType: "text/plain", UpdatedAt: timestamppb.New(modified)}},
+			},
+			Skipped: 2,
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, zipPath+"?folder_id="+testFileID, nil)
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+	gw.handleZip(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=Photos.zip`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "2", rec.Header().Get(skippedFilesHeader))
+
+	files := readZip(t, rec.Body.Bytes())
+	assert.Len(t, files, 4, "files whose content is missing are left out")
+	assert.Contains(t, files, "Photos/Trip/")
+	assert.True(t, files["Photos/Trip/"].FileInfo().IsDir())
+
+	assert.Equal(t, zip.Store, files["Photos/Trip/beach.jpg"].Method, "images are already compressed")
+	note := files["Photos/notes.txt"]
+	assert.Equal(t, zip.Deflate, note.Method)
+	assert.True(t, note.Modified.Equal(modified))
+	rc, err := note.Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, notes, string(content))
+	mockClient.AssertExpectations(t)
+}
+
+func TestAPIGateway_HandleZip_Selection(t *testing.T) {
+	mockClient := new(MockFileServiceClient)
+	gw := newBlobGateway(t, mockClient)
+	_, err := gw.blobs.Put(context.Background(), testKey, strings.NewReader("quarterly report"), -1)
+	require.NoError(t, err)
+
+	const otherID = "323e4567-e89b-12d3-a456-426614174000"
+	mockClient.On("ListDownloadEntries", mock.Anything, &filepb.ListDownloadEntriesRequest{UserId: testUserID, FileIds: []string{testFileID, otherID}}).
+		Return(&filepb.ListDownloadEntriesResponse{
+			Name:    "download",
+			Entries: []*filepb.DownloadEntry{{Path: "report.pdf", File: &filepb.File{Id: testFileID, StorageKey: testKey}}},
+			Skipped: 1,
+		}, nil)
+
+	form := url.Values{"file_id": {testFileID, otherID}}
+	req := httptest.NewRequest(http.MethodPost, zipPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+	gw.handleZip(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `attachment; filename=download.zip`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "1", rec.Header().Get(skippedFilesHeader))
+	assert.Contains(t, readZip(t, rec.Body.Bytes()), "report.pdf")
+	mockClient.AssertExpectations(t)
+}
+
+func TestAPIGateway_HandleZip_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		userID         string
+		mockSetup      func(*MockFileServiceClient)
+		expectedStatus int
+	}{
+		{
+			name:   "folder not found",
+			method: http.MethodGet,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ListDownloadEntries", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.NotFound, "folder not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "folder too large",
+			method: http.MethodGet,
+			userID: testUserID,
+			mockSetup: func(client *MockFileServiceClient) {
+				client.On("ListDownloadEntries", mock.Anything, mock.Anything).
+					Return(nil, status.Error(codes.FailedPrecondition, "folder holds more than 10000 files"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "wrong method",
+			method:         http.MethodDelete,
+			userID:         testUserID,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "unauthenticated",
+			method:         http.MethodGet,
+			mockSetup:      func(client *MockFileServiceClient) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFileServiceClient)
+			tt.mockSetup(mockClient)
+			gw := newBlobGateway(t, mockClient)
+
+			req := httptest.NewRequest(tt.method, zipPath+"?folder_id="+testFileID, nil)
+			if tt.userID != "" {
+				req.Header.Set(userIDHeader, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			gw.handleZip(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIGateway_HandleZip_AbortsOnReadFailure(t *testing.T) {
+	mockClient := new(MockFileServiceClient)
+	gw := newBlobGateway(t, mockClient)
+	mockClient.On("ListDownloadEntries", mock.Anything, mock.Anything).
+		Return(&filepb.ListDownloadEntriesResponse{
+			Name:    "download",
+			Entries: []*filepb.DownloadEntry{{Path: "broken.txt", File: &filepb.File{Id: testFileID, StorageKey: "../outside"}}},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, zipPath+"?file_id="+testFileID, nil)
+	req.Header.Set(userIDHeader, testUserID)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		gw.handleZip(httptest.NewRecorder(), req)
+	}, "a half-written archive should cut the connection")
+}
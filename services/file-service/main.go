@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"go-drive/internal/antivirus"
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	"go-drive/internal/keyring"
+	"go-drive/internal/signedurl"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+	"go-drive/services/file-service/service"
+)
+
+func main() {
+	// Get configuration from environment
+	port := getEnv("GRPC_PORT", "50052")
+	dbHost := getEnv("DB_HOST", "postgres")
+	dbPort := getEnv("DB_PORT", "5432")
+	dbName := getEnv("DB_NAME", "postgres")
+	dbUser := getEnv("DB_USER", "file_service")
+	dbPassword := getEnv("DB_PASSWORD", "file_service_password")
+	sslMode := getEnv("DB_SSLMODE", "disable")
+
+	// Initialize database connection using shared package
+	dbConfig := database.Config{
+		Host:            dbHost,
+		Port:            dbPort,
+		User:            dbUser,
+		Password:        dbPassword,
+		DBName:          dbName,
+		SSLMode:         sslMode,
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+
+	log.Printf("Connecting to database: %s@%s:%s/%s", dbUser, dbHost, dbPort, dbName)
+
+	// Use GORM repositories sharing one connection
+	conn, err := database.NewGormConnection(dbConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	repo := repository.NewGormFileRepositoryFromConnection(conn)
+	defer repo.Close()
+	uploads := repository.NewGormUploadRepositoryFromConnection(conn)
+	folders := repository.NewGormFolderRepositoryFromConnection(conn)
+	trash := repository.NewGormTrashRepositoryFromConnection(conn)
+	versions := repository.NewGormVersionRepositoryFromConnection(conn)
+	blobRefs := repository.NewGormBlobRepositoryFromConnection(conn)
+	usage := repository.NewGormUsageRepositoryFromConnection(conn)
+	shares := repository.NewGormShareRepositoryFromConnection(conn)
+	links := repository.NewGormShareLinkRepositoryFromConnection(conn)
+	requests := repository.NewGormFileRequestRepositoryFromConnection(conn)
+	search := repository.NewGormSearchRepositoryFromConnection(conn)
+	contentIndex := repository.NewGormContentIndexRepositoryFromConnection(conn)
+	thumbnails := repository.NewGormThumbnailRepositoryFromConnection(conn)
+	scans := repository.NewGormScanRepositoryFromConnection(conn)
+	accessKeys := repository.NewGormAccessKeyRepositoryFromConnection(conn)
+
+	log.Println("Database connection established successfully")
+
+	// Content and access key secrets are encrypted at rest when a keyring is configured
+	keys, err := keyring.Load(getEnv("ENCRYPTION_KEYRING_FILE", ""), getEnv("ENCRYPTION_KEYS", ""))
+	if err != nil {
+		log.Fatalf("Failed to load encryption keyring: %v", err)
+	}
+	if keys == nil {
+		log.Println("No encryption keyring configured; content is stored unencrypted")
+	}
+
+	// Initialize blob storage
+	blobs, err := storage.New(context.Background(), storage.Config{
+		Driver:      getEnv("STORAGE_DRIVER", storage.DriverLocal),
+		LocalRoot:   getEnv("STORAGE_LOCAL_ROOT", "/var/lib/go-drive/blobs"),
+		S3Endpoint:  getEnv("S3_ENDPOINT", "minio:9000"),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    getEnv("S3_BUCKET", "go-drive"),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:    getEnv("S3_USE_SSL", "false") == "true",
+		Keyring:     keys,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Upload and download URLs are served by the API gateway
+	publicURL := getEnv("PUBLIC_URL", "http://localhost:8080")
+	urls, err := signedurl.NewSigner(getEnv("SIGNED_URL_SECRET", ""), publicURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize URL signer: %v", err)
+	}
+
+	// Deleted items stay in the trash for the retention period, then are purged for good
+	trashRetention, err := time.ParseDuration(getEnv("TRASH_RETENTION", "720h"))
+	if err != nil {
+		log.Fatalf("Invalid TRASH_RETENTION: %v", err)
+	}
+	purgeInterval, err := time.ParseDuration(getEnv("TRASH_PURGE_INTERVAL", "1h"))
+	if err != nil || purgeInterval <= 0 {
+		log.Fatalf("Invalid TRASH_PURGE_INTERVAL: %q", getEnv("TRASH_PURGE_INTERVAL", "1h"))
+	}
+
+	// New content is indexed as soon as it is committed; the interval picks up retries
+	indexInterval, err := time.ParseDuration(getEnv("CONTENT_INDEX_INTERVAL", "1m"))
+	if err != nil || indexInterval <= 0 {
+		log.Fatalf("Invalid CONTENT_INDEX_INTERVAL: %q", getEnv("CONTENT_INDEX_INTERVAL", "1m"))
+	}
+	thumbnailInterval, err := time.ParseDuration(getEnv("THUMBNAIL_INTERVAL", "1m"))
+	if err != nil || thumbnailInterval <= 0 {
+		log.Fatalf("Invalid THUMBNAIL_INTERVAL: %q", getEnv("THUMBNAIL_INTERVAL", "1m"))
+	}
+	scanInterval, err := time.ParseDuration(getEnv("SCAN_INTERVAL", "1m"))
+	if err != nil || scanInterval <= 0 {
+		log.Fatalf("Invalid SCAN_INTERVAL: %q", getEnv("SCAN_INTERVAL", "1m"))
+	}
+
+	// Create gRPC server
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(service.ActingUserInterceptor))
+
+	// Register file service
+	fileService := service.NewFileService(service.Repositories{
+		Files:      repo,
+		Uploads:    uploads,
+		Folders:    folders,
+		Trash:      trash,
+		Versions:   versions,
+		Blobs:      blobRefs,
+		Usage:      usage,
+		Shares:     shares,
+		Links:      links,
+		Requests:   requests,
+		Search:     search,
+		Content:    contentIndex,
+		Thumbnails: thumbnails,
+		Scans:      scans,
+		AccessKeys: accessKeys,
+	}, blobs, urls, service.Options{
+		TrashRetention: trashRetention,
+		Quotas:         loadQuotas(),
+		PublicURL:      publicURL,
+		FileTypes:      loadFileTypePolicy(),
+		Scanner:        loadScanner(),
+		SkipScans:      getEnvBool("SKIP_MALWARE_SCAN", false),
+		Keyring:        keys,
+	})
+	pb.RegisterFileServiceServer(grpcServer, fileService)
+
+	// Access key secrets move to the active master key, so that rotated keys can be
+	// removed from the keyring
+	if resealed, err := fileService.ResealAccessKeys(context.Background()); err != nil {
+		log.Printf("Failed to reseal access key secrets: %v", err)
+	} else if resealed > 0 {
+		log.Printf("Resealed %d access key secrets under master key %q", resealed, keys.Active())
+	}
+
+	// Purge expired trash in the background
+	purgeCtx, stopPurger := context.WithCancel(context.Background())
+	defer stopPurger()
+	go fileService.RunTrashPurger(purgeCtx, purgeInterval)
+
+	// Extract and index the text of uploaded content in the background
+	indexCtx, stopIndexer := context.WithCancel(context.Background())
+	defer stopIndexer()
+	go fileService.RunContentIndexer(indexCtx, indexInterval)
+
+	// Render thumbnails of uploaded images in the background
+	thumbnailCtx, stopThumbnailer := context.WithCancel(context.Background())
+	defer stopThumbnailer()
+	go fileService.RunThumbnailer(thumbnailCtx, thumbnailInterval)
+
+	// Scan uploaded content for malware in the background
+	scanCtx, stopScanner := context.WithCancel(context.Background())
+	defer stopScanner()
+	go fileService.RunScanner(scanCtx, scanInterval)
+
+	// Register health service
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+	healthServer.SetServingStatus("file.FileService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	// Register reflection service for debugging
+	reflection.Register(grpcServer)
+
+	log.Printf("File service starting on port %s", port)
+
+	// Start server in goroutine
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal for graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down file service...")
+	stopPurger()
+	stopIndexer()
+	stopThumbnailer()
+	stopScanner()
+	grpcServer.GracefulStop()
+	log.Println("File service stopped")
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// loadQuotas reads the quota of each user type from QUOTA_<TYPE>_STORAGE and
+// QUOTA_<TYPE>_MAX_FILE_SIZE, in bytes. Unset variables keep the default and 0 is unlimited.
+func loadQuotas() map[string]service.Quota {
+	quotas := make(map[string]service.Quota)
+	for _, userType := range []string{domain.UserTypeStandard, domain.UserTypePremium, domain.UserTypeAdmin} {
+		quota := service.DefaultQuotas[userType]
+		prefix := "QUOTA_" + strings.ToUpper(userType)
+		quota.Storage = getEnvBytes(prefix+"_STORAGE", quota.Storage)
+		quota.MaxFileSize = getEnvBytes(prefix+"_MAX_FILE_SIZE", quota.MaxFileSize)
+		quotas[userType] = quota
+	}
+	return quotas
+}
+
+// loadFileTypePolicy reads the blocked and quarantined file types from the comma-separated
+// BLOCKED_FILE_TYPES and QUARANTINED_FILE_TYPES. Unset variables keep the default; an
+// empty one lists nothing.
+func loadFileTypePolicy() *service.FileTypePolicy {
+	policy := service.DefaultFileTypePolicy
+	policy.Blocked = getEnvFileTypes("BLOCKED_FILE_TYPES", policy.Blocked)
+	policy.Quarantined = getEnvFileTypes("QUARANTINED_FILE_TYPES", policy.Quarantined)
+	return &policy
+}
+
+// loadScanner connects to the clamd at CLAMD_ADDRESS (host:port). Without one, content
+// stays pending and cannot be downloaded, unless SKIP_MALWARE_SCAN lets it through unscanned.
+func loadScanner() antivirus.Scanner {
+	addr := getEnv("CLAMD_ADDRESS", "")
+	if addr == "" {
+		if getEnvBool("SKIP_MALWARE_SCAN", false) {
+			log.Println("SKIP_MALWARE_SCAN is set; uploads are not scanned for malware")
+		} else {
+			log.Println("CLAMD_ADDRESS is not set; uploads stay pending a malware scan and cannot be downloaded")
+		}
+		return nil
+	}
+	clamd := antivirus.NewClamd(addr, 0)
+	// clamd takes a while to load its signatures, so an unreachable daemon is not fatal;
+	// scans are retried until it is up
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clamd.Ping(ctx); err != nil {
+		log.Printf("clamd at %s is not reachable yet: %v", addr, err)
+	}
+	return clamd
+}
+
+func getEnvFileTypes(key string, defaultValue []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	entries, err := service.ParseFileTypes(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return entries
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %q", key, value)
+	}
+	return b
+}
+
+func getEnvBytes(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid %s: %q", key, value)
+	}
+	return n
+}
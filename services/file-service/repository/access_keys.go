@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrAccessKeyNotFound is returned when no access key has the access key ID or ID
+var ErrAccessKeyNotFound = errors.New("access key not found")
+
+// SealedSecret is the secret of an access key as it is stored
+type SealedSecret struct {
+	// Secret is the secret itself, or its encoded ciphertext when MasterKeyID is set
+	Secret string
+	// MasterKeyID names the master key the secret is wrapped with; empty when it is not
+	MasterKeyID string
+}
+
+// StoredAccessKey is an access key together with its secret
+type StoredAccessKey struct {
+	Key    *pb.AccessKey
+	Secret SealedSecret
+}
+
+type AccessKeyRepository interface {
+	Create(ctx context.Context, userID, name, accessKeyID string, secret SealedSecret) (*pb.AccessKey, error)
+	GetByAccessKeyID(ctx context.Context, accessKeyID string) (*StoredAccessKey, error)
+	List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.AccessKey, int32, error)
+	Revoke(ctx context.Context, id, userID string) error
+	Reseal(ctx context.Context, masterKeyID string, limit int, reseal func(key *StoredAccessKey) (SealedSecret, error)) (int, error)
+}
+
+type gormAccessKeyRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormAccessKeyRepositoryFromConnection creates an access key repository from an existing GORM connection
+func NewGormAccessKeyRepositoryFromConnection(conn *database.GormConnection) AccessKeyRepository {
+	return &gormAccessKeyRepository{conn: conn}
+}
+
+// Create stores a new access key of a user
+func (r *gormAccessKeyRepository) Create(ctx context.Context, userID, name, accessKeyID string, secret SealedSecret) (*pb.AccessKey, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	key := &domain.AccessKey{
+		UserID:            uid,
+		AccessKeyID:       accessKeyID,
+		SecretKey:         secret.Secret,
+		SecretMasterKeyID: secret.MasterKeyID,
+		Name:              name,
+	}
+	err = r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, uid); err != nil {
+			return err
+		}
+		return tx.Create(key).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access key: %w", err)
+	}
+
+	return domainAccessKeyToProto(key), nil
+}
+
+// GetByAccessKeyID returns the key with a public access key ID, with its secret
+func (r *gormAccessKeyRepository) GetByAccessKeyID(ctx context.Context, accessKeyID string) (*StoredAccessKey, error) {
+	var key domain.AccessKey
+	if err := r.conn.DB.WithContext(ctx).
+		Where("access_key_id = ?", accessKeyID).
+		First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccessKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get access key: %w", err)
+	}
+
+	return storedAccessKey(&key), nil
+}
+
+// List pages through a user's access keys, newest first
+func (r *gormAccessKeyRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.AccessKey, int32, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	query := r.conn.DB.WithContext(ctx).Model(&domain.AccessKey{}).Where("user_id = ?", uid)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count access keys: %w", err)
+	}
+
+	var keys []domain.AccessKey
+	if err := query.
+		Order("created_at DESC, id").
+		Limit(int(pageSize)).
+		Offset(int((page - 1) * pageSize)).
+		Find(&keys).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list access keys: %w", err)
+	}
+
+	pbKeys := make([]*pb.AccessKey, len(keys))
+	for i := range keys {
+		pbKeys[i] = domainAccessKeyToProto(&keys[i])
+	}
+	return pbKeys, int32(totalCount), nil
+}
+
+// Revoke deletes one of a user's access keys
+func (r *gormAccessKeyRepository) Revoke(ctx context.Context, id, userID string) error {
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid access key ID: %w", err)
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, uid); err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND user_id = ?", keyID, uid).Delete(&domain.AccessKey{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke access key: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrAccessKeyNotFound
+		}
+		return nil
+	})
+}
+
+// Reseal replaces the secrets of up to limit access keys that are not wrapped with
+// masterKeyID by what reseal returns for them. It returns the number of keys resealed.
+func (r *gormAccessKeyRepository) Reseal(ctx context.Context, masterKeyID string, limit int, reseal func(key *StoredAccessKey) (SealedSecret, error)) (int, error) {
+	var keys []domain.AccessKey
+	err := r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT * FROM access_keys WHERE secret_master_key_id <> ? ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED", masterKeyID, limit).
+			Scan(&keys).Error; err != nil {
+			return fmt.Errorf("failed to select access keys: %w", err)
+		}
+
+		for i := range keys {
+			secret, err := reseal(storedAccessKey(&keys[i]))
+			if err != nil {
+				return fmt.Errorf("failed to reseal access key %s: %w", keys[i].AccessKeyID, err)
+			}
+			if err := tx.Model(&domain.AccessKey{}).Where("id = ?", keys[i].ID).Updates(map[string]interface{}{
+				"secret_key":           secret.Secret,
+				"secret_master_key_id": secret.MasterKeyID,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update access key: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(keys), nil
+}
+
+// storedAccessKey converts a domain.AccessKey to a StoredAccessKey
+func storedAccessKey(key *domain.AccessKey) *StoredAccessKey {
+	return &StoredAccessKey{
+		Key:    domainAccessKeyToProto(key),
+		Secret: SealedSecret{Secret: key.SecretKey, MasterKeyID: key.SecretMasterKeyID},
+	}
+}
+
+// domainAccessKeyToProto converts a domain.AccessKey to pb.AccessKey
+func domainAccessKeyToProto(key *domain.AccessKey) *pb.AccessKey {
+	return &pb.AccessKey{
+		Id:          key.ID.String(),
+		UserId:      key.UserID.String(),
+		AccessKeyId: key.AccessKeyID,
+		Name:        key.Name,
+		CreatedAt:   timestamppb.New(key.CreatedAt),
+	}
+}
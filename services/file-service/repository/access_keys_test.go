@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+)
+
+var accessKeyColumns = []string{"id", "user_id", "access_key_id", "secret_key", "secret_master_key_id", "name", "created_at"}
+
+func TestGormAccessKeyRepository_GetByAccessKeyID(t *testing.T) {
+	keyID := uuid.New()
+	userID := uuid.New()
+	query := regexp.QuoteMeta(`SELECT * FROM "access_keys" WHERE access_key_id = $1`)
+
+	tests := []struct {
+		name          string
+		rows          *sqlmock.Rows
+		expectedError error
+	}{
+		{
+			name: "key found",
+			rows: sqlmock.NewRows(accessKeyColumns).AddRow(keyID, userID, "GDKEY", "sealed", "k1", "rclone", time.Now()),
+		},
+		{
+			name:          "unknown key",
+			rows:          sqlmock.NewRows(accessKeyColumns),
+			expectedError: ErrAccessKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			mock.ExpectQuery(query).WithArgs("GDKEY", 1).WillReturnRows(tt.rows)
+
+			repo := NewGormAccessKeyRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			stored, err := repo.GetByAccessKeyID(context.Background(), "GDKEY")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, SealedSecret{Secret: "sealed", MasterKeyID: "k1"}, stored.Secret)
+				assert.Equal(t, userID.String(), stored.Key.UserId)
+				assert.Equal(t, "rclone", stored.Key.Name)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormAccessKeyRepository_Revoke(t *testing.T) {
+	userID := uuid.New()
+	keyID := uuid.New()
+
+	tests := []struct {
+		name          string
+		rowsAffected  int64
+		expectedError error
+	}{
+		{name: "revoked", rowsAffected: 1},
+		{name: "key not found", rowsAffected: 0, expectedError: ErrAccessKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('app.user_id', $1, true)`)).
+				WithArgs(userID.String()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "access_keys" WHERE id = $1 AND user_id = $2`)).
+				WithArgs(keyID, userID).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			if tt.expectedError != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			repo := NewGormAccessKeyRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			err := repo.Revoke(context.Background(), keyID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormAccessKeyRepository_Reseal(t *testing.T) {
+	keyID := uuid.New()
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM access_keys WHERE secret_master_key_id <> $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED`)).
+		WithArgs("k2", 100).
+		WillReturnRows(sqlmock.NewRows(accessKeyColumns).AddRow(keyID, uuid.New(), "GDKEY", "sealed", "k1", "rclone", time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "access_keys" SET "secret_key"=$1,"secret_master_key_id"=$2 WHERE id = $3`)).
+		WithArgs("resealed", "k2", keyID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewGormAccessKeyRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+	n, err := repo.Reseal(context.Background(), "k2", 100, func(key *StoredAccessKey) (SealedSecret, error) {
+		assert.Equal(t, "GDKEY", key.Key.AccessKeyId)
+		assert.Equal(t, SealedSecret{Secret: "sealed", MasterKeyID: "k1"}, key.Secret)
+		return SealedSecret{Secret: "resealed", MasterKeyID: "k2"}, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
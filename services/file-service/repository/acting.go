@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// actingUserKey is the context key of the user repository calls act for
+type actingUserKey struct{}
+
+// ActingAs returns a context in which file, folder and version repository calls act for a
+// user, so that the database's row level security policies limit them to what the user may
+// access. Calls without an acting user act for the file service itself, as background jobs
+// and the RPCs of anonymous link visitors do.
+func ActingAs(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actingUserKey{}, userID)
+}
+
+// actAs makes the rest of the transaction act for a user, so that the database's
+// row level security policies limit it to what the user may access
+func actAs(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Exec("SELECT set_config('app.user_id', ?, true)", userID.String()).Error; err != nil {
+		return fmt.Errorf("failed to set acting user: %w", err)
+	}
+	return nil
+}
+
+// actingTransaction runs fn in a transaction that acts for the user of ctx, if it has one
+func actingTransaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if userID, ok := ctx.Value(actingUserKey{}).(string); ok {
+			uid, err := uuid.Parse(userID)
+			if err != nil {
+				return fmt.Errorf("invalid acting user ID: %w", err)
+			}
+			if err := actAs(tx, uid); err != nil {
+				return err
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// acting runs fn for the user of ctx: in a transaction that acts for them, or straight on
+// db when ctx has no acting user, since set_config only lasts for a transaction
+func acting(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if _, ok := ctx.Value(actingUserKey{}).(string); !ok {
+		return fn(db.WithContext(ctx))
+	}
+	return actingTransaction(ctx, db, fn)
+}
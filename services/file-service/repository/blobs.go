@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+)
+
+// BlobRepository tracks content shared by files and file versions with the same checksum.
+// References are counted by database triggers as rows point at blob keys or stop doing so.
+type BlobRepository interface {
+	// Collect deletes up to limit blobs that nothing references any more. remove is called
+	// with the storage key of each blob while its row is locked, so the content cannot be
+	// referenced again until it is gone. It returns the number of blobs collected.
+	Collect(ctx context.Context, limit int, remove func(key string) error) (int, error)
+}
+
+type gormBlobRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormBlobRepositoryFromConnection creates a blob repository from an existing GORM connection
+func NewGormBlobRepositoryFromConnection(conn *database.GormConnection) BlobRepository {
+	return &gormBlobRepository{conn: conn}
+}
+
+func (r *gormBlobRepository) Collect(ctx context.Context, limit int, remove func(key string) error) (int, error) {
+	var checksums []string
+	err := r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT checksum FROM blobs WHERE ref_count = 0 ORDER BY updated_at LIMIT ? FOR UPDATE SKIP LOCKED", limit).
+			Scan(&checksums).Error; err != nil {
+			return fmt.Errorf("failed to select unreferenced blobs: %w", err)
+		}
+		if len(checksums) == 0 {
+			return nil
+		}
+
+		for _, checksum := range checksums {
+			if err := remove(BlobKey(checksum)); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("checksum IN ?", checksums).Delete(&domain.Blob{}).Error; err != nil {
+			return fmt.Errorf("failed to delete blobs: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(checksums), nil
+}
+
+// BlobKey returns the storage key of the content with the given SHA-256 checksum
+func BlobKey(checksum string) string {
+	return fmt.Sprintf("blobs/%s/%s", checksum[:2], checksum)
+}
+
+// storedContent is the content a deleted file or file version pointed at
+type storedContent struct {
+	StorageKey string
+	Checksum   string
+}
+
+// unsharedKeys returns the storage keys of deleted content that no blob accounts for: uploads
+// that never completed and content stored before deduplication. Nothing else uses these keys,
+// so the caller deletes them directly.
+func unsharedKeys(contents []storedContent) []string {
+	var keys []string
+	for _, content := range contents {
+		if content.Checksum == "" || content.StorageKey != BlobKey(content.Checksum) {
+			keys = append(keys, content.StorageKey)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+)
+
+func TestGormBlobRepository_Collect(t *testing.T) {
+	first := strings.Repeat("01", 32)
+	second := strings.Repeat("02", 32)
+
+	expectUnreferenced := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT checksum FROM blobs WHERE ref_count = 0 ORDER BY updated_at LIMIT $1 FOR UPDATE SKIP LOCKED`)).
+			WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"checksum"}).AddRow(first).AddRow(second))
+	}
+
+	tests := []struct {
+		name          string
+		removeErr     error
+		mockSetup     func(sqlmock.Sqlmock)
+		expected      int
+		expectedError bool
+	}{
+		{
+			name: "deletes removed blobs",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectUnreferenced(mock)
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "blobs" WHERE checksum IN ($1,$2)`)).
+					WithArgs(first, second).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expected: 2,
+		},
+		{
+			name:      "keeps rows when content cannot be removed",
+			removeErr: errors.New("storage unavailable"),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectUnreferenced(mock)
+				mock.ExpectRollback()
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			var removed []string
+			repo := &gormBlobRepository{conn: &database.GormConnection{DB: gormDB}}
+			collected, err := repo.Collect(context.Background(), 10, func(key string) error {
+				removed = append(removed, key)
+				return tt.removeErr
+			})
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, collected)
+				assert.Equal(t, []string{BlobKey(first), BlobKey(second)}, removed)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBlobKey(t *testing.T) {
+	checksum := strings.Repeat("ab", 32)
+	assert.Equal(t, "blobs/ab/"+checksum, BlobKey(checksum))
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+// ErrContentIndexNotFound is returned for files without content, which have nothing to index
+var ErrContentIndexNotFound = errors.New("content index not found")
+
+// ContentJob is a file whose content is due to be processed in the background, such as
+// indexed for search or rendered as thumbnails
+type ContentJob struct {
+	FileID     uuid.UUID
+	Checksum   string
+	Name       string
+	MimeType   string
+	StorageKey string
+	Size       int64
+	// Attempts includes the attempt the job was claimed for
+	Attempts int32
+}
+
+// ContentIndexRepository keeps the searchable text of file content. Files are queued by a
+// database trigger whenever their checksum changes; results for content that has been
+// replaced since it was claimed are dropped.
+type ContentIndexRepository interface {
+	// Get returns the index state of a file's current content
+	Get(ctx context.Context, fileID string) (*pb.ContentIndex, error)
+	// Claim picks up to limit live files that are pending and due at now, and counts an
+	// attempt for each. They are not picked again before retryAt, so the work of an
+	// indexer that stopped halfway is retried.
+	Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]ContentJob, error)
+	// Store records the text extracted from a file's content
+	Store(ctx context.Context, job ContentJob, text string, indexedAt time.Time) error
+	// Skip records why a file's content cannot be indexed
+	Skip(ctx context.Context, job ContentJob, reason string) error
+	// Fail records a failed attempt. The file is retried at retryAt, or given up on when
+	// retryAt is nil.
+	Fail(ctx context.Context, job ContentJob, reason string, retryAt *time.Time) error
+}
+
+type gormContentIndexRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormContentIndexRepositoryFromConnection creates a content index repository from an existing GORM connection
+func NewGormContentIndexRepositoryFromConnection(conn *database.GormConnection) ContentIndexRepository {
+	return &gormContentIndexRepository{conn: conn}
+}
+
+func (r *gormContentIndexRepository) Get(ctx context.Context, fileID string) (*pb.ContentIndex, error) {
+	id, err := uuid.Parse(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+
+	var index domain.ContentIndex
+	if err := r.conn.DB.WithContext(ctx).
+		Select("file_id", "checksum", "status", "attempts", "last_error", "indexed_at").
+		First(&index, "file_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContentIndexNotFound
+		}
+		return nil, fmt.Errorf("failed to get content index: %w", err)
+	}
+
+	return domainContentIndexToProto(&index), nil
+}
+
+func (r *gormContentIndexRepository) Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]ContentJob, error) {
+	jobs, err := claimContentJobs(r.conn.DB.WithContext(ctx), "file_content_index", domain.ContentIndexPending, now, retryAt, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim files to index: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *gormContentIndexRepository) Store(ctx context.Context, job ContentJob, text string, indexedAt time.Time) error {
+	if err := r.conn.DB.WithContext(ctx).Exec(`UPDATE file_content_index
+		SET status = ?, content = to_tsvector('simple'::regconfig, ?::text), last_error = NULL,
+			next_attempt_at = NULL, indexed_at = ?
+		WHERE file_id = ? AND checksum = ?`,
+		domain.ContentIndexIndexed, text, indexedAt, job.FileID, job.Checksum).Error; err != nil {
+		return fmt.Errorf("failed to store content index: %w", err)
+	}
+	return nil
+}
+
+func (r *gormContentIndexRepository) Skip(ctx context.Context, job ContentJob, reason string) error {
+	return r.finish(ctx, job, domain.ContentIndexUnsupported, reason, nil)
+}
+
+func (r *gormContentIndexRepository) Fail(ctx context.Context, job ContentJob, reason string, retryAt *time.Time) error {
+	status := domain.ContentIndexPending
+	if retryAt == nil {
+		status = domain.ContentIndexFailed
+	}
+	return r.finish(ctx, job, status, reason, retryAt)
+}
+
+// finish records the outcome of an attempt that produced no text
+func (r *gormContentIndexRepository) finish(ctx context.Context, job ContentJob, status, reason string, retryAt *time.Time) error {
+	if err := r.conn.DB.WithContext(ctx).Exec(`UPDATE file_content_index
+		SET status = ?, content = NULL, last_error = ?, next_attempt_at = ?
+		WHERE file_id = ? AND checksum = ?`,
+		status, reason, retryAt, job.FileID, job.Checksum).Error; err != nil {
+		return fmt.Errorf("failed to update content index: %w", err)
+	}
+	return nil
+}
+
+// claimContentJobs claims pending rows of a per-file work queue. The table is keyed by file_id
+// and tracks status, attempts and next_attempt_at for the checksum it was queued for.
+func claimContentJobs(db *gorm.DB, table, pending string, now, retryAt time.Time, limit int) ([]ContentJob, error) {
+	var jobs []ContentJob
+	err := db.Raw(fmt.Sprintf(`UPDATE %[1]s c
+		SET attempts = c.attempts + 1, next_attempt_at = ?
+		FROM files f
+		WHERE f.id = c.file_id AND c.file_id IN (
+			SELECT i.file_id FROM %[1]s i JOIN files live ON live.id = i.file_id
+			WHERE i.status = ? AND (i.next_attempt_at IS NULL OR i.next_attempt_at <= ?) AND live.deleted_at IS NULL
+			ORDER BY i.next_attempt_at NULLS FIRST
+			LIMIT ?
+			FOR UPDATE OF i SKIP LOCKED
+		)
+		RETURNING c.file_id, c.checksum, c.attempts, f.name, f.mime_type, f.storage_key, f.size`, table),
+		retryAt, pending, now, limit).
+		Scan(&jobs).Error
+	return jobs, err
+}
+
+func domainContentIndexToProto(index *domain.ContentIndex) *pb.ContentIndex {
+	pbIndex := &pb.ContentIndex{
+		Status:   index.Status,
+		Attempts: index.Attempts,
+		Error:    index.LastError,
+	}
+	if index.IndexedAt != nil {
+		pbIndex.IndexedAt = timestamppb.New(*index.IndexedAt)
+	}
+	return pbIndex
+}
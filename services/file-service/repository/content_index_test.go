@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+)
+
+func TestGormContentIndexRepository_Get(t *testing.T) {
+	fileID := uuid.New()
+	indexedAt := time.Now()
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "indexed",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT "file_id","checksum","status","attempts","last_error","indexed_at" FROM "file_content_index" WHERE file_id = $1`)).
+					WithArgs(fileID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"file_id", "checksum", "status", "attempts", "last_error", "indexed_at"}).
+						AddRow(fileID, "abc", domain.ContentIndexIndexed, 2, nil, indexedAt))
+			},
+		},
+		{
+			name: "no content",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`FROM "file_content_index"`)).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrContentIndexNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+			tt.mockSetup(mock)
+
+			repo := NewGormContentIndexRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			index, err := repo.Get(context.Background(), fileID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, domain.ContentIndexIndexed, index.Status)
+				assert.Equal(t, int32(2), index.Attempts)
+				assert.True(t, index.IndexedAt.AsTime().Equal(indexedAt))
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormContentIndexRepository_Claim(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	fileID := uuid.New()
+	checksum := strings.Repeat("ab", 32)
+	now := time.Now()
+	retryAt := now.Add(10 * time.Minute)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE file_content_index c`)).
+		WithArgs(retryAt, domain.ContentIndexPending, now, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"file_id", "checksum", "attempts", "name", "mime_type", "storage_key", "size"}).
+			AddRow(fileID, checksum, 1, "notes.md", "text/markdown", BlobKey(checksum), 42))
+
+	repo := NewGormContentIndexRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+	jobs, err := repo.Claim(context.Background(), now, retryAt, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, []ContentJob{{
+		FileID: fileID, Checksum: checksum, Name: "notes.md", MimeType: "text/markdown",
+		StorageKey: BlobKey(checksum), Size: 42, Attempts: 1,
+	}}, jobs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormContentIndexRepository_Results(t *testing.T) {
+	job := ContentJob{FileID: uuid.New(), Checksum: strings.Repeat("cd", 32)}
+	now := time.Now()
+	retryAt := now.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		run       func(ContentIndexRepository) error
+		mockSetup func(sqlmock.Sqlmock)
+	}{
+		{
+			name: "store",
+			run: func(repo ContentIndexRepository) error {
+				return repo.Store(context.Background(), job, "quarterly report", now)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`content = to_tsvector('simple'::regconfig, $2::text)`)).
+					WithArgs(domain.ContentIndexIndexed, "quarterly report", now, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "skip",
+			run: func(repo ContentIndexRepository) error {
+				return repo.Skip(context.Background(), job, "content type cannot be indexed")
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_content_index`)).
+					WithArgs(domain.ContentIndexUnsupported, "content type cannot be indexed", nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "fail and retry",
+			run: func(repo ContentIndexRepository) error {
+				return repo.Fail(context.Background(), job, "storage unavailable", &retryAt)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_content_index`)).
+					WithArgs(domain.ContentIndexPending, "storage unavailable", &retryAt, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "fail for good",
+			run: func(repo ContentIndexRepository) error {
+				return repo.Fail(context.Background(), job, "malformed PDF", nil)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_content_index`)).
+					WithArgs(domain.ContentIndexFailed, "malformed PDF", nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+			tt.mockSetup(mock)
+
+			repo := NewGormContentIndexRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			require.NoError(t, tt.run(repo))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
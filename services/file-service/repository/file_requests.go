@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrFileRequestNotFound is returned when a file request link does not exist, belongs to
+// another user or points at a folder in the trash
+var ErrFileRequestNotFound = errors.New("file request not found")
+
+type FileRequestRepository interface {
+	Create(ctx context.Context, req *pb.CreateFileRequestLinkRequest, tokenHash string) (*pb.FileRequestLink, error)
+	GetByToken(ctx context.Context, tokenHash string) (*pb.FileRequestLink, error)
+	GetBySubmission(ctx context.Context, fileID string) (*pb.FileRequestLink, error)
+	List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.FileRequestLink, int32, error)
+	Revoke(ctx context.Context, id, userID string) error
+	RecordSubmission(ctx context.Context, requestID, fileID, uploaderName, uploaderEmail string) error
+	ListSubmissions(ctx context.Context, id, userID string, page, pageSize int32) ([]*pb.FileRequestSubmission, int32, error)
+}
+
+type gormFileRequestRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormFileRequestRepositoryFromConnection creates a file request repository from an existing GORM connection
+func NewGormFileRequestRepositoryFromConnection(conn *database.GormConnection) FileRequestRepository {
+	return &gormFileRequestRepository{conn: conn}
+}
+
+// liveFolder limits file request links to those whose folder is not in the trash
+const liveFolder = "folder_id IN (SELECT id FROM folders WHERE deleted_at IS NULL)"
+
+// Create stores a file request link into a folder owned by req.UserId
+func (r *gormFileRequestRepository) Create(ctx context.Context, req *pb.CreateFileRequestLinkRequest, tokenHash string) (*pb.FileRequestLink, error) {
+	ownerID, folderID, err := parseFolderIDs(req.FolderId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &domain.FileRequestLink{
+		OwnerID:          ownerID,
+		FolderID:         folderID,
+		TokenHash:        tokenHash,
+		Title:            req.Title,
+		MaxFileSize:      req.MaxFileSize,
+		AllowedMimeTypes: strings.Join(req.AllowedMimeTypes, ","),
+	}
+	if req.Deadline != nil {
+		deadline := req.Deadline.AsTime()
+		link.Deadline = &deadline
+	}
+
+	err = r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, ownerID); err != nil {
+			return err
+		}
+		return tx.Create(link).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file request: %w", err)
+	}
+
+	return domainFileRequestLinkToProto(link), nil
+}
+
+// GetByToken returns the file request link with a token hash, provided its folder is live
+func (r *gormFileRequestRepository) GetByToken(ctx context.Context, tokenHash string) (*pb.FileRequestLink, error) {
+	var link domain.FileRequestLink
+	if err := r.conn.DB.WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		Where(liveFolder).
+		First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFileRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get file request: %w", err)
+	}
+
+	return domainFileRequestLinkToProto(&link), nil
+}
+
+// GetBySubmission returns the file request link a file was submitted through, even one
+// whose folder has been moved to the trash since
+func (r *gormFileRequestRepository) GetBySubmission(ctx context.Context, fileID string) (*pb.FileRequestLink, error) {
+	fid, err := uuid.Parse(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+
+	var link domain.FileRequestLink
+	if err := r.conn.DB.WithContext(ctx).
+		Where("id = (SELECT request_id FROM file_request_submissions WHERE file_id = ?)", fid).
+		First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFileRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get file request: %w", err)
+	}
+
+	return domainFileRequestLinkToProto(&link), nil
+}
+
+// List pages through the file request links a user has created, newest first
+func (r *gormFileRequestRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.FileRequestLink, int32, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	query := r.conn.DB.WithContext(ctx).Model(&domain.FileRequestLink{}).
+		Where("owner_id = ?", uid).
+		Where(liveFolder)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count file requests: %w", err)
+	}
+
+	var links []domain.FileRequestLink
+	if err := query.
+		Order("created_at DESC, id").
+		Limit(int(pageSize)).
+		Offset(int((page - 1) * pageSize)).
+		Find(&links).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list file requests: %w", err)
+	}
+
+	pbLinks := make([]*pb.FileRequestLink, len(links))
+	for i := range links {
+		pbLinks[i] = domainFileRequestLinkToProto(&links[i])
+	}
+	return pbLinks, int32(totalCount), nil
+}
+
+// Revoke deletes a file request link created by the user. The submission records go with
+// it; the files received stay in the folder.
+func (r *gormFileRequestRepository) Revoke(ctx context.Context, id, userID string) error {
+	linkID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid file request ID: %w", err)
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, uid); err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND owner_id = ?", linkID, uid).Delete(&domain.FileRequestLink{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke file request: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrFileRequestNotFound
+		}
+		return nil
+	})
+}
+
+// RecordSubmission records who uploaded a file through a file request link
+func (r *gormFileRequestRepository) RecordSubmission(ctx context.Context, requestID, fileID, uploaderName, uploaderEmail string) error {
+	rid, err := uuid.Parse(requestID)
+	if err != nil {
+		return fmt.Errorf("invalid file request ID: %w", err)
+	}
+	fid, err := uuid.Parse(fileID)
+	if err != nil {
+		return fmt.Errorf("invalid file ID: %w", err)
+	}
+
+	submission := &domain.FileRequestSubmission{
+		RequestID:     rid,
+		FileID:        fid,
+		UploaderName:  uploaderName,
+		UploaderEmail: uploaderEmail,
+	}
+	if err := r.conn.DB.WithContext(ctx).Create(submission).Error; err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrFileRequestNotFound
+		}
+		return fmt.Errorf("failed to record submission: %w", err)
+	}
+	return nil
+}
+
+// ListSubmissions pages through the live files received through a user's file request link, newest first
+func (r *gormFileRequestRepository) ListSubmissions(ctx context.Context, id, userID string, page, pageSize int32) ([]*pb.FileRequestSubmission, int32, error) {
+	linkID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid file request ID: %w", err)
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var owned int64
+	if err := r.conn.DB.WithContext(ctx).Model(&domain.FileRequestLink{}).
+		Where("id = ? AND owner_id = ?", linkID, uid).
+		Count(&owned).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get file request: %w", err)
+	}
+	if owned == 0 {
+		return nil, 0, ErrFileRequestNotFound
+	}
+
+	query := r.conn.DB.WithContext(ctx).Model(&domain.FileRequestSubmission{}).
+		Where("request_id = ?", linkID).
+		Where("file_id IN (SELECT id FROM files WHERE deleted_at IS NULL)")
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count submissions: %w", err)
+	}
+
+	var submissions []domain.FileRequestSubmission
+	if err := query.
+		Preload("File").
+		Order("created_at DESC, id").
+		Limit(int(pageSize)).
+		Offset(int((page - 1) * pageSize)).
+		Find(&submissions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list submissions: %w", err)
+	}
+
+	pbSubmissions := make([]*pb.FileRequestSubmission, 0, len(submissions))
+	for i := range submissions {
+		submission := &submissions[i]
+		if submission.File == nil {
+			// Deleted since it was counted
+			continue
+		}
+		pbSubmissions = append(pbSubmissions, &pb.FileRequestSubmission{
+			Id:            submission.ID.String(),
+			RequestId:     submission.RequestID.String(),
+			File:          domainFileToProto(submission.File),
+			UploaderName:  submission.UploaderName,
+			UploaderEmail: submission.UploaderEmail,
+			CreatedAt:     timestamppb.New(submission.CreatedAt),
+		})
+	}
+
+	return pbSubmissions, int32(totalCount), nil
+}
+
+// domainFileRequestLinkToProto converts a domain.FileRequestLink to pb.FileRequestLink
+func domainFileRequestLinkToProto(link *domain.FileRequestLink) *pb.FileRequestLink {
+	pbLink := &pb.FileRequestLink{
+		Id:          link.ID.String(),
+		OwnerId:     link.OwnerID.String(),
+		FolderId:    link.FolderID.String(),
+		Title:       link.Title,
+		MaxFileSize: link.MaxFileSize,
+		CreatedAt:   timestamppb.New(link.CreatedAt),
+		UpdatedAt:   timestamppb.New(link.UpdatedAt),
+	}
+	if link.AllowedMimeTypes != "" {
+		pbLink.AllowedMimeTypes = strings.Split(link.AllowedMimeTypes, ",")
+	}
+	if link.Deadline != nil {
+		pbLink.Deadline = timestamppb.New(*link.Deadline)
+	}
+
+	return pbLink
+}
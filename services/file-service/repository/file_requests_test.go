@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+)
+
+var fileRequestColumns = []string{
+	"id", "owner_id", "folder_id", "token_hash", "title", "deadline",
+	"max_file_size", "allowed_mime_types", "created_at", "updated_at",
+}
+
+func TestGormFileRequestRepository_GetByToken(t *testing.T) {
+	linkID := uuid.New()
+	ownerID := uuid.New()
+	folderID := uuid.New()
+	now := time.Now()
+	query := regexp.QuoteMeta(`SELECT * FROM "file_request_links" WHERE token_hash = $1 AND folder_id IN (SELECT id FROM folders WHERE deleted_at IS NULL)`)
+
+	tests := []struct {
+		name          string
+		rows          *sqlmock.Rows
+		expectedTypes []string
+		expectedError error
+	}{
+		{
+			name: "link with allowed types",
+			rows: sqlmock.NewRows(fileRequestColumns).
+				AddRow(linkID, ownerID, folderID, "hash", "Invoices", now.Add(time.Hour), 4096, "application/pdf,image/*", now, now),
+			expectedTypes: []string{"application/pdf", "image/*"},
+		},
+		{
+			name: "link accepting any type",
+			rows: sqlmock.NewRows(fileRequestColumns).
+				AddRow(linkID, ownerID, folderID, "hash", "", nil, 0, "", now, now),
+		},
+		{
+			name:          "unknown token",
+			rows:          sqlmock.NewRows(fileRequestColumns),
+			expectedError: ErrFileRequestNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			mock.ExpectQuery(query).WithArgs("hash", 1).WillReturnRows(tt.rows)
+
+			repo := NewGormFileRequestRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			link, err := repo.GetByToken(context.Background(), "hash")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, folderID.String(), link.FolderId)
+				assert.Equal(t, tt.expectedTypes, link.AllowedMimeTypes)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRequestRepository_GetBySubmission(t *testing.T) {
+	linkID := uuid.New()
+	fileID := uuid.New()
+	now := time.Now()
+	query := regexp.QuoteMeta(`SELECT * FROM "file_request_links" WHERE id = (SELECT request_id FROM file_request_submissions WHERE file_id = $1)`)
+
+	t.Run("submitted file", func(t *testing.T) {
+		gormDB, mock, cleanup := setupGormMock(t)
+		defer cleanup()
+
+		mock.ExpectQuery(query).WithArgs(fileID, 1).WillReturnRows(sqlmock.NewRows(fileRequestColumns).
+			AddRow(linkID, uuid.New(), uuid.New(), "hash", "", nil, 0, "image/*", now, now))
+
+		repo := NewGormFileRequestRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+		link, err := repo.GetBySubmission(context.Background(), fileID.String())
+
+		require.NoError(t, err)
+		assert.Equal(t, linkID.String(), link.Id)
+		assert.Equal(t, []string{"image/*"}, link.AllowedMimeTypes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("file not submitted", func(t *testing.T) {
+		gormDB, mock, cleanup := setupGormMock(t)
+		defer cleanup()
+
+		mock.ExpectQuery(query).WithArgs(fileID, 1).WillReturnRows(sqlmock.NewRows(fileRequestColumns))
+
+		repo := NewGormFileRequestRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+		_, err := repo.GetBySubmission(context.Background(), fileID.String())
+
+		assert.ErrorIs(t, err, ErrFileRequestNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGormFileRequestRepository_RecordSubmission(t *testing.T) {
+	requestID := uuid.New()
+	fileID := uuid.New()
+	insert := regexp.QuoteMeta(`INSERT INTO "file_request_submissions" ("request_id","file_id","uploader_name","uploader_email","created_at")`)
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "recorded",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+			},
+		},
+		{
+			name: "request revoked meanwhile",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).
+					WillReturnError(&pgconn.PgError{Code: foreignKeyViolation})
+			},
+			expectedError: ErrFileRequestNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := NewGormFileRequestRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			err := repo.RecordSubmission(context.Background(), requestID.String(), fileID.String(), "Ada", "ada@example.com")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRequestRepository_ListSubmissions(t *testing.T) {
+	requestID := uuid.New()
+	userID := uuid.New()
+
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "file_request_links" WHERE id = $1 AND owner_id = $2`)).
+		WithArgs(requestID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	repo := NewGormFileRequestRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+	_, _, err := repo.ListSubmissions(context.Background(), requestID.String(), userID.String(), 1, 20)
+
+	assert.ErrorIs(t, err, ErrFileRequestNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,508 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// ErrFolderCycle is returned when a folder would be moved into itself or one of its descendants
+	ErrFolderCycle = errors.New("folder cannot be moved into itself or one of its descendants")
+	// ErrPathNotFound is returned when no file or folder exists at a path
+	ErrPathNotFound = errors.New("path not found")
+)
+
+// ancestorsCTE selects a folder and all of its live ancestors.
+// UNION rather than UNION ALL stops the walk should the tree already contain a cycle.
+const ancestorsCTE = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM folders WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT f.id, f.parent_id FROM folders f JOIN ancestors a ON f.id = a.parent_id WHERE f.deleted_at IS NULL
+) `
+
+// subtreeCTE selects a user's folder and all of its live descendants
+const subtreeCTE = `WITH RECURSIVE subtree AS (
+	SELECT id FROM folders WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+	UNION
+	SELECT f.id FROM folders f JOIN subtree s ON f.parent_id = s.id WHERE f.deleted_at IS NULL
+) `
+
+type FolderRepository interface {
+	Create(ctx context.Context, req *pb.CreateFolderRequest) (*pb.Folder, error)
+	GetByID(ctx context.Context, id, userID string) (*pb.Folder, error)
+	Rename(ctx context.Context, id, userID, name string) (*pb.Folder, error)
+	Move(ctx context.Context, id, userID, parentID string) (*pb.Folder, error)
+	ListChildren(ctx context.Context, userID, folderID string, page, pageSize int32) ([]*pb.Folder, []*pb.File, int32, error)
+	Delete(ctx context.Context, id, userID string) (int32, int32, error)
+	// ListTree returns a user's folder with every live folder below it, and up to limit of
+	// the live files in all of them
+	ListTree(ctx context.Context, id, userID string, limit int) ([]*pb.Folder, []*pb.File, error)
+	ResolvePath(ctx context.Context, userID string, segments []string) (*pb.Folder, *pb.File, error)
+	MkdirAll(ctx context.Context, userID string, segments []string) (*pb.Folder, error)
+}
+
+type gormFolderRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormFolderRepositoryFromConnection creates a folder repository from an existing GORM connection
+func NewGormFolderRepositoryFromConnection(conn *database.GormConnection) FolderRepository {
+	return &gormFolderRepository{conn: conn}
+}
+
+func (r *gormFolderRepository) Create(ctx context.Context, req *pb.CreateFolderRequest) (*pb.Folder, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	folder := &domain.Folder{
+		ID:     uuid.New(),
+		Name:   req.Name,
+		UserID: userID,
+	}
+
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		if folder.ParentID, err = resolveFolder(ctx, tx, userID, req.ParentId); err != nil {
+			return err
+		}
+		if err := tx.Create(folder).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to create folder: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFolderToProto(folder), nil
+}
+
+func (r *gormFolderRepository) GetByID(ctx context.Context, id, userID string) (*pb.Folder, error) {
+	folderID, ownerID, err := parseFolderIDs(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var folder domain.Folder
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		return tx.Where("id = ? AND user_id = ?", folderID, ownerID).First(&folder).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+
+	return domainFolderToProto(&folder), nil
+}
+
+func (r *gormFolderRepository) Rename(ctx context.Context, id, userID, name string) (*pb.Folder, error) {
+	folderID, ownerID, err := parseFolderIDs(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var folder domain.Folder
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		result := tx.Model(&folder).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND user_id = ?", folderID, ownerID).
+			Update("name", name)
+		if result.Error != nil {
+			if isUniqueViolation(result.Error) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to rename folder: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrFolderNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFolderToProto(&folder), nil
+}
+
+// Move re-parents a folder; an empty parentID moves it to the root.
+// Moves of the same user's folders are serialised with an advisory lock so two concurrent
+// moves cannot each pass the cycle check and together create a loop (A→B, B→A).
+func (r *gormFolderRepository) Move(ctx context.Context, id, userID, parentID string) (*pb.Folder, error) {
+	folderID, ownerID, err := parseFolderIDs(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var folder domain.Folder
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", ownerID.String()).Error; err != nil {
+			return fmt.Errorf("failed to lock folder tree: %w", err)
+		}
+
+		newParent, err := resolveFolder(ctx, tx, ownerID, parentID)
+		if err != nil {
+			return err
+		}
+		if newParent != nil {
+			var cycles int64
+			if err := tx.Raw(ancestorsCTE+"SELECT count(*) FROM ancestors WHERE id = ?", *newParent, folderID).
+				Scan(&cycles).Error; err != nil {
+				return fmt.Errorf("failed to check folder ancestry: %w", err)
+			}
+			if cycles > 0 {
+				return ErrFolderCycle
+			}
+		}
+
+		result := tx.Model(&folder).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND user_id = ?", folderID, ownerID).
+			Update("parent_id", newParent)
+		if result.Error != nil {
+			if isUniqueViolation(result.Error) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to move folder: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrFolderNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFolderToProto(&folder), nil
+}
+
+// ListChildren pages through a folder's subfolders followed by its files.
+// An empty folderID lists the root of the user's drive.
+func (r *gormFolderRepository) ListChildren(ctx context.Context, userID, folderID string, page, pageSize int32) ([]*pb.Folder, []*pb.File, int32, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var folderCount, fileCount int64
+	var folders []domain.Folder
+	var files []domain.File
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		parentID, err := resolveFolder(ctx, tx, ownerID, folderID)
+		if err != nil {
+			return err
+		}
+
+		folderQuery := tx.Model(&domain.Folder{}).Where("user_id = ?", ownerID)
+		fileQuery := tx.Model(&domain.File{}).Where("user_id = ?", ownerID)
+		if parentID == nil {
+			folderQuery = folderQuery.Where("parent_id IS NULL")
+			fileQuery = fileQuery.Where("folder_id IS NULL")
+		} else {
+			folderQuery = folderQuery.Where("parent_id = ?", *parentID)
+			fileQuery = fileQuery.Where("folder_id = ?", *parentID)
+		}
+
+		if err := folderQuery.Count(&folderCount).Error; err != nil {
+			return fmt.Errorf("failed to count folders: %w", err)
+		}
+		if err := fileQuery.Count(&fileCount).Error; err != nil {
+			return fmt.Errorf("failed to count files: %w", err)
+		}
+
+		offset := int64(page-1) * int64(pageSize)
+		limit := int64(pageSize)
+
+		if offset < folderCount {
+			if err := folderQuery.
+				Order("name, id").
+				Limit(int(limit)).
+				Offset(int(offset)).
+				Find(&folders).Error; err != nil {
+				return fmt.Errorf("failed to list folders: %w", err)
+			}
+		}
+
+		if remaining := limit - int64(len(folders)); remaining > 0 && fileCount > 0 {
+			fileOffset := offset - folderCount
+			if fileOffset < 0 {
+				fileOffset = 0
+			}
+			if err := fileQuery.
+				Order("name, id").
+				Limit(int(remaining)).
+				Offset(int(fileOffset)).
+				Find(&files).Error; err != nil {
+				return fmt.Errorf("failed to list files: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	protoFolders := make([]*pb.Folder, len(folders))
+	for i := range folders {
+		protoFolders[i] = domainFolderToProto(&folders[i])
+	}
+	protoFiles := make([]*pb.File, len(files))
+	for i := range files {
+		protoFiles[i] = domainFileToProto(&files[i])
+	}
+
+	return protoFolders, protoFiles, int32(folderCount + fileCount), nil
+}
+
+// Delete soft deletes a folder, every folder below it and all of their files in one transaction.
+// Everything is stamped with the same deletion time so the subtree can be recognised as a unit.
+// It returns the number of folders and files deleted.
+func (r *gormFolderRepository) Delete(ctx context.Context, id, userID string) (int32, int32, error) {
+	folderID, ownerID, err := parseFolderIDs(id, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var deletedFolders, deletedFiles int64
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Raw(subtreeCTE+"SELECT id FROM subtree", folderID, ownerID).
+			Scan(&ids).Error; err != nil {
+			return fmt.Errorf("failed to collect folder subtree: %w", err)
+		}
+		if len(ids) == 0 {
+			return ErrFolderNotFound
+		}
+
+		now := tx.NowFunc()
+		result := tx.Model(&domain.File{}).
+			Where("folder_id IN ?", ids).
+			UpdateColumn("deleted_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete files: %w", result.Error)
+		}
+		deletedFiles = result.RowsAffected
+
+		result = tx.Model(&domain.Folder{}).
+			Where("id IN ?", ids).
+			UpdateColumn("deleted_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete folders: %w", result.Error)
+		}
+		deletedFolders = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int32(deletedFolders), int32(deletedFiles), nil
+}
+
+func (r *gormFolderRepository) ListTree(ctx context.Context, id, userID string, limit int) ([]*pb.Folder, []*pb.File, error) {
+	folderID, ownerID, err := parseFolderIDs(id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var folders []domain.Folder
+	var files []domain.File
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		if err := tx.Raw(subtreeCTE+"SELECT folders.* FROM folders JOIN subtree USING (id) ORDER BY name, id", folderID, ownerID).
+			Scan(&folders).Error; err != nil {
+			return fmt.Errorf("failed to list folder subtree: %w", err)
+		}
+		if len(folders) == 0 {
+			return ErrFolderNotFound
+		}
+
+		ids := make([]uuid.UUID, len(folders))
+		for i := range folders {
+			ids[i] = folders[i].ID
+		}
+		if err := tx.Where("user_id = ? AND folder_id IN ?", ownerID, ids).
+			Order("name, id").
+			Limit(limit).
+			Find(&files).Error; err != nil {
+			return fmt.Errorf("failed to list files: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	protoFolders := make([]*pb.Folder, len(folders))
+	for i := range folders {
+		protoFolders[i] = domainFolderToProto(&folders[i])
+	}
+	protoFiles := make([]*pb.File, len(files))
+	for i := range files {
+		protoFiles[i] = domainFileToProto(&files[i])
+	}
+
+	return protoFolders, protoFiles, nil
+}
+
+// ResolvePath walks path segments from the root of the user's drive and returns the folder or
+// file the last segment names. Both are nil for an empty path, which denotes the root.
+func (r *gormFolderRepository) ResolvePath(ctx context.Context, userID string, segments []string) (*pb.Folder, *pb.File, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var parent *domain.Folder
+	var file *domain.File
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		for i, name := range segments {
+			folder, err := childFolder(tx, ownerID, parent, name)
+			if err != nil {
+				return err
+			}
+			if folder != nil {
+				parent = folder
+				continue
+			}
+			if i < len(segments)-1 {
+				return ErrPathNotFound
+			}
+
+			file = &domain.File{}
+			if err := tx.
+				Where("user_id = ? AND COALESCE(folder_id, ?) = ? AND name = ?", ownerID, uuid.Nil, folderKey(parent), name).
+				First(file).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrPathNotFound
+				}
+				return fmt.Errorf("failed to get file: %w", err)
+			}
+			return nil
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case file != nil:
+		return nil, domainFileToProto(file), nil
+	case parent == nil:
+		return nil, nil, nil
+	}
+	return domainFolderToProto(parent), nil, nil
+}
+
+// MkdirAll returns the folder at the given path, creating it and any missing parents.
+// It runs under the same per-user lock as Move so a concurrent move cannot detach a
+// parent between the lookup and the insert of its child.
+func (r *gormFolderRepository) MkdirAll(ctx context.Context, userID string, segments []string) (*pb.Folder, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if len(segments) == 0 {
+		return nil, ErrPathNotFound
+	}
+
+	var parent *domain.Folder
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", ownerID.String()).Error; err != nil {
+			return fmt.Errorf("failed to lock folder tree: %w", err)
+		}
+
+		for _, name := range segments {
+			folder, err := childFolder(tx, ownerID, parent, name)
+			if err != nil {
+				return err
+			}
+			if folder == nil {
+				folder = &domain.Folder{ID: uuid.New(), Name: name, UserID: ownerID}
+				if parent != nil {
+					folder.ParentID = &parent.ID
+				}
+				if err := tx.Create(folder).Error; err != nil {
+					if isUniqueViolation(err) {
+						return ErrNameConflict
+					}
+					return fmt.Errorf("failed to create folder: %w", err)
+				}
+			}
+			parent = folder
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFolderToProto(parent), nil
+}
+
+// childFolder looks up the live folder called name inside parent, or at the root if parent is nil.
+// It returns nil without an error if there is none.
+func childFolder(db *gorm.DB, userID uuid.UUID, parent *domain.Folder, name string) (*domain.Folder, error) {
+	var folder domain.Folder
+	if err := db.
+		Where("user_id = ? AND COALESCE(parent_id, ?) = ? AND name = ?", userID, uuid.Nil, folderKey(parent), name).
+		First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+	return &folder, nil
+}
+
+// folderKey returns the value the unique name indexes use for a parent folder; the root is the nil UUID
+func folderKey(folder *domain.Folder) uuid.UUID {
+	if folder == nil {
+		return uuid.Nil
+	}
+	return folder.ID
+}
+
+func parseFolderIDs(id, userID string) (uuid.UUID, uuid.UUID, error) {
+	folderID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid folder ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	return folderID, ownerID, nil
+}
+
+// domainFolderToProto converts a domain.Folder to pb.Folder
+func domainFolderToProto(folder *domain.Folder) *pb.Folder {
+	pbFolder := &pb.Folder{
+		Id:        folder.ID.String(),
+		Name:      folder.Name,
+		UserId:    folder.UserID.String(),
+		CreatedAt: timestamppb.New(folder.CreatedAt),
+		UpdatedAt: timestamppb.New(folder.UpdatedAt),
+	}
+	if folder.ParentID != nil {
+		pbFolder.ParentId = folder.ParentID.String()
+	}
+
+	return pbFolder
+}
@@ -0,0 +1,460 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+	pb "go-drive/proto/file"
+)
+
+var folderColumns = []string{"id", "name", "user_id", "parent_id", "created_at", "updated_at", "deleted_at"}
+
+func TestGormFolderRepository_Create(t *testing.T) {
+	userID := uuid.New()
+	parentID := uuid.New()
+
+	tests := []struct {
+		name          string
+		request       *pb.CreateFolderRequest
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name:    "folder at root",
+			request: &pb.CreateFolderRequest{Name: "Documents", UserId: userID.String()},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "folders"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+			},
+		},
+		{
+			name:    "missing parent",
+			request: &pb.CreateFolderRequest{Name: "Documents", UserId: userID.String(), ParentId: parentID.String()},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "folders" WHERE (id = $1 AND user_id = $2) AND "folders"."deleted_at" IS NULL`)).
+					WithArgs(parentID, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedError: ErrFolderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+			folder, err := repo.Create(context.Background(), tt.request)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "Documents", folder.Name)
+				assert.Empty(t, folder.ParentId)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFolderRepository_Move(t *testing.T) {
+	userID := uuid.New()
+	folderID := uuid.New()
+	parentID := uuid.New()
+	now := time.Now()
+
+	expectLockedParent := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`)).
+			WithArgs(userID.String()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "folders"`)).
+			WithArgs(parentID, userID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	}
+
+	tests := []struct {
+		name          string
+		parentID      string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name:     "move below another folder",
+			parentID: parentID.String(),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectLockedParent(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM ancestors WHERE id = $2`)).
+					WithArgs(parentID, folderID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "folders" SET "parent_id"=$1,"updated_at"=$2 WHERE (id = $3 AND user_id = $4) AND "folders"."deleted_at" IS NULL RETURNING *`)).
+					WithArgs(parentID, sqlmock.AnyArg(), folderID, userID).
+					WillReturnRows(sqlmock.NewRows(folderColumns).
+						AddRow(folderID, "Photos", userID, parentID, now, now, nil))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "move below a descendant",
+			parentID: parentID.String(),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectLockedParent(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM ancestors WHERE id = $2`)).
+					WithArgs(parentID, folderID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrFolderCycle,
+		},
+		{
+			name:     "move to root",
+			parentID: "",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "folders" SET "parent_id"=$1`)).
+					WithArgs(nil, sqlmock.AnyArg(), folderID, userID).
+					WillReturnRows(sqlmock.NewRows(folderColumns).
+						AddRow(folderID, "Photos", userID, nil, now, now, nil))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "folder not found",
+			parentID: "",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "folders"`)).
+					WillReturnRows(sqlmock.NewRows(folderColumns))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrFolderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+			folder, err := repo.Move(context.Background(), folderID.String(), userID.String(), tt.parentID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.parentID, folder.ParentId)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFolderRepository_ListChildren(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	userID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "folders" WHERE user_id = $1 AND parent_id IS NULL`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "files" WHERE user_id = $1 AND folder_id IS NULL`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	// Page 2 of size 2 holds the last folder and the first file
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders" WHERE user_id = $1 AND parent_id IS NULL AND "folders"."deleted_at" IS NULL ORDER BY name, id LIMIT $2 OFFSET $3`)).
+		WithArgs(userID, 2, 2).
+		WillReturnRows(sqlmock.NewRows(folderColumns).AddRow(uuid.New(), "Work", userID, nil, now, now, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE user_id = $1 AND folder_id IS NULL AND "files"."deleted_at" IS NULL ORDER BY name, id LIMIT $2`)).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows(fileColumns).
+			AddRow(uuid.New(), "a.txt", userID, nil, 1, "text/plain", "key", "", now, now, nil))
+
+	repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+	folders, files, total, err := repo.ListChildren(context.Background(), userID.String(), "", 2, 2)
+
+	require.NoError(t, err)
+	assert.Len(t, folders, 1)
+	assert.Len(t, files, 1)
+	assert.Equal(t, int32(8), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormFolderRepository_Delete(t *testing.T) {
+	userID := uuid.New()
+	folderID := uuid.New()
+	childID := uuid.New()
+
+	tests := []struct {
+		name            string
+		mockSetup       func(sqlmock.Sqlmock)
+		expectedFolders int32
+		expectedFiles   int32
+		expectedError   error
+	}{
+		{
+			name: "subtree deleted",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM subtree`)).
+					WithArgs(folderID, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(folderID).AddRow(childID))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "files" SET "deleted_at"=$1 WHERE folder_id IN ($2,$3) AND "files"."deleted_at" IS NULL`)).
+					WithArgs(sqlmock.AnyArg(), folderID, childID).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "folders" SET "deleted_at"=$1 WHERE id IN ($2,$3) AND "folders"."deleted_at" IS NULL`)).
+					WithArgs(sqlmock.AnyArg(), folderID, childID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedFolders: 2,
+			expectedFiles:   4,
+		},
+		{
+			name: "folder not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM subtree`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrFolderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+			folders, files, err := repo.Delete(context.Background(), folderID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedFolders, folders)
+				assert.Equal(t, tt.expectedFiles, files)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFolderRepository_ListTree(t *testing.T) {
+	userID := uuid.New()
+	folderID := uuid.New()
+	childID := uuid.New()
+	now := time.Now()
+
+	t.Run("subtree listed", func(t *testing.T) {
+		gormDB, mock, cleanup := setupGormMock(t)
+		defer cleanup()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT folders.* FROM folders JOIN subtree USING (id) ORDER BY name, id`)).
+			WithArgs(folderID, userID).
+			WillReturnRows(sqlmock.NewRows(folderColumns).
+				AddRow(folderID, "Photos", userID, nil, now, now, nil).
+				AddRow(childID, "2026", userID, folderID, now, now, nil))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE (user_id = $1 AND folder_id IN ($2,$3)) AND "files"."deleted_at" IS NULL ORDER BY name, id LIMIT $4`)).
+			WithArgs(userID, folderID, childID, 11).
+			WillReturnRows(sqlmock.NewRows(fileColumns).
+				AddRow(uuid.New(), "beach.jpg", userID, childID, 1, "image/jpeg", "key", "", now, now, nil))
+
+		repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+		folders, files, err := repo.ListTree(context.Background(), folderID.String(), userID.String(), 11)
+
+		require.NoError(t, err)
+		require.Len(t, folders, 2)
+		assert.Equal(t, folderID.String(), folders[1].ParentId)
+		require.Len(t, files, 1)
+		assert.Equal(t, childID.String(), files[0].FolderId)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("folder not found", func(t *testing.T) {
+		gormDB, mock, cleanup := setupGormMock(t)
+		defer cleanup()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT folders.* FROM folders JOIN subtree USING (id)`)).
+			WillReturnRows(sqlmock.NewRows(folderColumns))
+
+		repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+		_, _, err := repo.ListTree(context.Background(), folderID.String(), userID.String(), 11)
+
+		assert.ErrorIs(t, err, ErrFolderNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGormFolderRepository_ResolvePath(t *testing.T) {
+	userID := uuid.New()
+	projectsID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		segments      []string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectFolder  bool
+		expectFile    bool
+		expectedError error
+	}{
+		{
+			name:     "file below a folder",
+			segments: []string{"projects", "report.pdf"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders" WHERE (user_id = $1 AND COALESCE(parent_id, $2) = $3 AND name = $4)`)).
+					WithArgs(userID, uuid.Nil, uuid.Nil, "projects", 1).
+					WillReturnRows(sqlmock.NewRows(folderColumns).AddRow(projectsID, "projects", userID, nil, now, now, nil))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders"`)).
+					WithArgs(userID, uuid.Nil, projectsID, "report.pdf", 1).
+					WillReturnRows(sqlmock.NewRows(folderColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE (user_id = $1 AND COALESCE(folder_id, $2) = $3 AND name = $4)`)).
+					WithArgs(userID, uuid.Nil, projectsID, "report.pdf", 1).
+					WillReturnRows(sqlmock.NewRows(fileColumns).
+						AddRow(uuid.New(), "report.pdf", userID, projectsID, 10, "application/pdf", "key", "", now, now, nil))
+			},
+			expectFile: true,
+		},
+		{
+			name:     "folder",
+			segments: []string{"projects"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders"`)).
+					WillReturnRows(sqlmock.NewRows(folderColumns).AddRow(projectsID, "projects", userID, nil, now, now, nil))
+			},
+			expectFolder: true,
+		},
+		{
+			name:      "root",
+			segments:  nil,
+			mockSetup: func(mock sqlmock.Sqlmock) {},
+		},
+		{
+			name:     "missing intermediate folder",
+			segments: []string{"missing", "report.pdf"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders"`)).
+					WillReturnRows(sqlmock.NewRows(folderColumns))
+			},
+			expectedError: ErrPathNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+			folder, file, err := repo.ResolvePath(context.Background(), userID.String(), tt.segments)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectFolder, folder != nil)
+				assert.Equal(t, tt.expectFile, file != nil)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFolderRepository_MkdirAll(t *testing.T) {
+	userID := uuid.New()
+	projectsID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "existing parent reused, missing child created",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders"`)).
+					WithArgs(userID, uuid.Nil, uuid.Nil, "projects", 1).
+					WillReturnRows(sqlmock.NewRows(folderColumns).AddRow(projectsID, "projects", userID, nil, now, now, nil))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders"`)).
+					WithArgs(userID, uuid.Nil, projectsID, "2026", 1).
+					WillReturnRows(sqlmock.NewRows(folderColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "folders"`)).
+					WithArgs("2026", userID, projectsID, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "name taken by a file",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders"`)).
+					WillReturnRows(sqlmock.NewRows(folderColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "folders"`)).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "sibling_name_unique"})
+				mock.ExpectRollback()
+			},
+			expectedError: ErrNameConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFolderRepository{conn: &database.GormConnection{DB: gormDB}}
+			folder, err := repo.MkdirAll(context.Background(), userID.String(), []string{"projects", "2026"})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "2026", folder.Name)
+				assert.Equal(t, projectsID.String(), folder.ParentId)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// ErrFileNotFound is returned when a file does not exist or is not owned by the user
+	ErrFileNotFound = errors.New("file not found")
+	// ErrFolderNotFound is returned when a folder does not exist or is not owned by the user
+	ErrFolderNotFound = errors.New("folder not found")
+	// ErrNameConflict is returned when a folder already holds a file or folder with the same name
+	ErrNameConflict = errors.New("an item with this name already exists in the folder")
+)
+
+// uniqueViolation is the Postgres error code raised by unique indexes and the sibling name trigger
+const uniqueViolation = "23505"
+
+type FileRepository interface {
+	Create(ctx context.Context, req *pb.CreateFileRequest, storageLimit int64) (*pb.File, error)
+	GetByID(ctx context.Context, id, userID string) (*pb.File, error)
+	List(ctx context.Context, userID string, folderID *string, page, pageSize int32) ([]*pb.File, int32, error)
+	Delete(ctx context.Context, id, userID string) error
+	Discard(ctx context.Context, id, userID string) ([]string, error)
+	Move(ctx context.Context, id, userID, folderID, name string) (*pb.File, error)
+	UpdateContent(ctx context.Context, id, userID, storageKey string, size int64, checksum string, typ ContentType, storageLimit int64) (*pb.File, error)
+	Close() error
+	HealthCheck(ctx context.Context) error
+}
+
+type gormFileRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormFileRepository creates a new file repository using GORM
+func NewGormFileRepository(cfg database.Config) (FileRepository, error) {
+	conn, err := database.NewGormConnection(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database connection: %w", err)
+	}
+
+	return &gormFileRepository{conn: conn}, nil
+}
+
+// NewGormFileRepositoryFromConnection creates a repository from an existing GORM connection
+func NewGormFileRepositoryFromConnection(conn *database.GormConnection) FileRepository {
+	return &gormFileRepository{conn: conn}
+}
+
+// Create records a new file. Its declared size counts towards the user's storage, which
+// must stay within storageLimit bytes unless the limit is zero.
+func (r *gormFileRepository) Create(ctx context.Context, req *pb.CreateFileRequest, storageLimit int64) (*pb.File, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	fileID := uuid.New()
+	file := &domain.File{
+		ID:         fileID,
+		Name:       req.Name,
+		UserID:     userID,
+		Size:       req.Size,
+		MimeType:   req.MimeType,
+		StorageKey: StorageKey(userID, fileID),
+		// Until content is uploaded, the declared type is all there is
+		DeclaredMimeType: req.MimeType,
+	}
+
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		if file.FolderID, err = resolveFolder(ctx, tx, userID, req.FolderId); err != nil {
+			return err
+		}
+		if err := reserveStorage(tx, userID, file.Size, storageLimit); err != nil {
+			return err
+		}
+		if err := tx.Create(file).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFileToProto(file), nil
+}
+
+func (r *gormFileRepository) GetByID(ctx context.Context, id, userID string) (*pb.File, error) {
+	fileID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var file domain.File
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		return tx.Where("id = ? AND user_id = ?", fileID, ownerID).First(&file).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	return domainFileToProto(&file), nil
+}
+
+func (r *gormFileRepository) List(ctx context.Context, userID string, folderID *string, page, pageSize int32) ([]*pb.File, int32, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+
+	var folder *uuid.UUID
+	if folderID != nil && *folderID != "" {
+		id, err := uuid.Parse(*folderID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid folder ID: %w", err)
+		}
+		folder = &id
+	}
+
+	var totalCount int64
+	var files []domain.File
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		query := tx.Model(&domain.File{}).Where("user_id = ?", ownerID)
+
+		// An empty folder ID lists the root of the drive, a missing one lists everything
+		switch {
+		case folder != nil:
+			query = query.Where("folder_id = ?", *folder)
+		case folderID != nil:
+			query = query.Where("folder_id IS NULL")
+		}
+
+		// Get total count
+		if err := query.Count(&totalCount).Error; err != nil {
+			return fmt.Errorf("failed to count files: %w", err)
+		}
+
+		// Get paginated results
+		if err := query.
+			Order("created_at DESC").
+			Limit(int(pageSize)).
+			Offset(int(offset)).
+			Find(&files).Error; err != nil {
+			return fmt.Errorf("failed to list files: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Convert to proto
+	protoFiles := make([]*pb.File, len(files))
+	for i, file := range files {
+		protoFiles[i] = domainFileToProto(&file)
+	}
+
+	return protoFiles, int32(totalCount), nil
+}
+
+func (r *gormFileRepository) Delete(ctx context.Context, id, userID string) error {
+	fileID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid file ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	// Soft delete
+	return acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", fileID, ownerID).Delete(&domain.File{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete file: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrFileNotFound
+		}
+		return nil
+	})
+}
+
+// Discard permanently deletes a file and its versions, bypassing the trash. It returns the
+// storage keys of content no blob accounts for, which the caller deletes; shared content is
+// released and left to blob collection.
+func (r *gormFileRepository) Discard(ctx context.Context, id, userID string) ([]string, error) {
+	fileID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var keys []string
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		var versions, files []storedContent
+		if err := tx.Raw("DELETE FROM file_versions WHERE file_id = ? RETURNING storage_key, COALESCE(checksum, '') AS checksum", fileID).
+			Scan(&versions).Error; err != nil {
+			return fmt.Errorf("failed to discard file versions: %w", err)
+		}
+		if err := tx.Raw("DELETE FROM files WHERE id = ? AND user_id = ? RETURNING storage_key, COALESCE(checksum, '') AS checksum", fileID, ownerID).
+			Scan(&files).Error; err != nil {
+			return fmt.Errorf("failed to discard file: %w", err)
+		}
+		if len(files) == 0 {
+			return ErrFileNotFound
+		}
+		keys = unsharedKeys(append(versions, files...))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Move puts a file under name in the folder folderID, which must belong to the file's
+// owner; an empty folderID moves it to the root.
+func (r *gormFileRepository) Move(ctx context.Context, id, userID, folderID, name string) (*pb.File, error) {
+	fileID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var file domain.File
+	err = acting(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		parentID, err := resolveFolder(ctx, tx, ownerID, folderID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&file).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND user_id = ?", fileID, ownerID).
+			Updates(map[string]interface{}{"folder_id": parentID, "name": name})
+		if result.Error != nil {
+			if isUniqueViolation(result.Error) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to move file: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrFileNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFileToProto(&file), nil
+}
+
+// ContentType is the type detected for a file's content
+type ContentType struct {
+	MimeType string
+	// Mismatch flags content that is not of the type declared for the file
+	Mismatch bool
+	// QuarantineReason, when set, keeps the content from being downloaded
+	QuarantineReason string
+	// ScanStatus is pending for content to be scanned for malware, or unscanned when scanning
+	// is explicitly turned off. Restored versions found clean before keep their status.
+	ScanStatus string
+}
+
+// UpdateContent records the storage key, size, checksum and type of a file's content. Content
+// under a different storage key replaces the current content, which is kept as a version;
+// identical content shares its blob key and leaves the file unchanged. The user's storage,
+// versions included, must stay within storageLimit bytes unless the limit is zero.
+func (r *gormFileRepository) UpdateContent(ctx context.Context, id, userID, storageKey string, size int64, checksum string, typ ContentType, storageLimit int64) (*pb.File, error) {
+	fileID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var file *domain.File
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		if file, err = lockFile(tx, fileID, ownerID); err != nil {
+			return err
+		}
+
+		// Archived content keeps counting towards the user's storage
+		archive := storageKey != file.StorageKey && file.Checksum != ""
+		delta := size - file.Size
+		if archive {
+			delta = size
+		}
+		if err := reserveStorage(tx, ownerID, delta, storageLimit); err != nil {
+			return err
+		}
+
+		if archive {
+			if err := archiveContent(tx, file); err != nil {
+				return err
+			}
+		}
+
+		return setContent(tx, file, storageKey, size, checksum, typ)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFileToProto(file), nil
+}
+
+func (r *gormFileRepository) Close() error {
+	return r.conn.Close()
+}
+
+func (r *gormFileRepository) HealthCheck(ctx context.Context) error {
+	return r.conn.HealthCheck(ctx)
+}
+
+// resolveFolder parses an optional folder ID and checks that the folder belongs to the user
+func resolveFolder(ctx context.Context, db *gorm.DB, userID uuid.UUID, id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	folderID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid folder ID: %w", err)
+	}
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&domain.Folder{}).
+		Where("id = ? AND user_id = ?", folderID, userID).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+	if count == 0 {
+		return nil, ErrFolderNotFound
+	}
+
+	return &folderID, nil
+}
+
+// lockFile loads a live file and locks its row until the transaction ends
+func lockFile(tx *gorm.DB, fileID, userID uuid.UUID) (*domain.File, error) {
+	var file domain.File
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", fileID, userID).
+		First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	return &file, nil
+}
+
+// archiveContent keeps a file's current content as a version. Files whose upload never
+// completed have no content worth keeping.
+func archiveContent(tx *gorm.DB, file *domain.File) error {
+	if file.Checksum == "" {
+		return nil
+	}
+
+	version := &domain.FileVersion{
+		FileID:     file.ID,
+		UserID:     file.UserID,
+		Size:       file.Size,
+		StorageKey: file.StorageKey,
+		Checksum:   file.Checksum,
+		ScanStatus: file.ScanStatus,
+	}
+	if err := tx.Create(version).Error; err != nil {
+		return fmt.Errorf("failed to create file version: %w", err)
+	}
+	return nil
+}
+
+// setContent points a file at new content of the given type
+func setContent(tx *gorm.DB, file *domain.File, storageKey string, size int64, checksum string, typ ContentType) error {
+	if err := tx.Model(file).
+		Clauses(clause.Returning{}).
+		Updates(map[string]interface{}{
+			"storage_key":       storageKey,
+			"size":              size,
+			"checksum":          checksum,
+			"mime_type":         typ.MimeType,
+			"mime_mismatch":     typ.Mismatch,
+			"quarantined":       typ.QuarantineReason != "",
+			"quarantine_reason": typ.QuarantineReason,
+			"scan_status":       typ.ScanStatus,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update file: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err was caused by a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// StorageKey returns the key under which a new file's first content is staged
+func StorageKey(userID, fileID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s", userID, fileID)
+}
+
+// ContentKey returns a fresh key to stage new content of an existing file under
+func ContentKey(userID, fileID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s.%s", userID, fileID, uuid.New())
+}
+
+// IsContentKey reports whether key is one of the staging keys of a file's content
+func IsContentKey(userID, fileID uuid.UUID, key string) bool {
+	base := StorageKey(userID, fileID)
+	return key == base || strings.HasPrefix(key, base+".")
+}
+
+// domainFileToProto converts a domain.File to pb.File
+func domainFileToProto(file *domain.File) *pb.File {
+	pbFile := &pb.File{
+		Id:               file.ID.String(),
+		Name:             file.Name,
+		UserId:           file.UserID.String(),
+		Size:             file.Size,
+		MimeType:         file.MimeType,
+		StorageKey:       file.StorageKey,
+		Checksum:         file.Checksum,
+		DeclaredMimeType: file.DeclaredMimeType,
+		MimeMismatch:     file.MimeMismatch,
+		Quarantined:      file.Quarantined,
+		QuarantineReason: file.QuarantineReason,
+		ScanStatus:       file.ScanStatus,
+		CreatedAt:        timestamppb.New(file.CreatedAt),
+		UpdatedAt:        timestamppb.New(file.UpdatedAt),
+	}
+	if file.FolderID != nil {
+		pbFile.FolderId = file.FolderID.String()
+	}
+
+	return pbFile
+}
@@ -0,0 +1,770 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+var fileColumns = []string{
+	"id", "name", "user_id", "folder_id", "size", "mime_type",
+	"storage_key", "checksum", "created_at", "updated_at", "deleted_at",
+}
+
+func setupGormMock(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err, "failed to create mock database")
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err, "failed to open gorm connection")
+
+	cleanup := func() {
+		sqlDB, _ := gormDB.DB()
+		sqlDB.Close()
+	}
+
+	return gormDB, mock, cleanup
+}
+
+func TestGormFileRepository_Create(t *testing.T) {
+	userID := uuid.New()
+	folderID := uuid.New()
+
+	tests := []struct {
+		name          string
+		request       *pb.CreateFileRequest
+		storageLimit  int64
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+		validate      func(*testing.T, *pb.File)
+	}{
+		{
+			name: "successful file creation in root",
+			request: &pb.CreateFileRequest{
+				Name:     "report.pdf",
+				UserId:   userID.String(),
+				Size:     1024,
+				MimeType: "application/pdf",
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "files"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			},
+			validate: func(t *testing.T, file *pb.File) {
+				assert.NotEmpty(t, file.Id)
+				assert.Equal(t, "report.pdf", file.Name)
+				assert.Equal(t, userID.String(), file.UserId)
+				assert.Empty(t, file.FolderId)
+				assert.Equal(t, int64(1024), file.Size)
+				assert.Contains(t, file.StorageKey, userID.String()+"/")
+			},
+		},
+		{
+			name: "successful file creation in folder",
+			request: &pb.CreateFileRequest{
+				Name:     "report.pdf",
+				UserId:   userID.String(),
+				FolderId: folderID.String(),
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "folders" WHERE (id = $1 AND user_id = $2)`)).
+					WithArgs(folderID, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "files"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			},
+			validate: func(t *testing.T, file *pb.File) {
+				assert.Equal(t, folderID.String(), file.FolderId)
+			},
+		},
+		{
+			name: "folder owned by another user",
+			request: &pb.CreateFileRequest{
+				Name:     "report.pdf",
+				UserId:   userID.String(),
+				FolderId: folderID.String(),
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "folders"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrFolderNotFound,
+		},
+		{
+			name: "database error",
+			request: &pb.CreateFileRequest{
+				Name:   "report.pdf",
+				UserId: userID.String(),
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "files"`)).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrConnDone,
+		},
+		{
+			name: "within the storage quota",
+			request: &pb.CreateFileRequest{
+				Name:   "report.pdf",
+				UserId: userID.String(),
+				Size:   1024,
+			},
+			storageLimit: 4096,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectUsage(mock, userID, 3072)
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "files"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "over the storage quota",
+			request: &pb.CreateFileRequest{
+				Name:   "report.pdf",
+				UserId: userID.String(),
+				Size:   1024,
+			},
+			storageLimit: 4096,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectUsage(mock, userID, 3073)
+				mock.ExpectRollback()
+			},
+			expectedError: ErrQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFileRepository{
+				conn: &database.GormConnection{DB: gormDB},
+			}
+
+			file, err := repo.Create(context.Background(), tt.request, tt.storageLimit)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				if tt.validate != nil {
+					tt.validate(t, file)
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRepository_GetByID(t *testing.T) {
+	fixedTime := time.Now()
+	userID := uuid.New()
+	fileID := uuid.New()
+	granteeID := uuid.New()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+		errorContains string
+	}{
+		{
+			name: "successful retrieval",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(fileColumns).AddRow(
+					fileID, "report.pdf", userID, nil, 1024, "application/pdf",
+					userID.String()+"/"+fileID.String(), "", fixedTime, fixedTime, nil,
+				)
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE (id = $1 AND user_id = $2) AND "files"."deleted_at" IS NULL`)).
+					WithArgs(fileID, userID, 1).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "file not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files"`)).
+					WillReturnRows(sqlmock.NewRows(fileColumns))
+			},
+			expectedError: ErrFileNotFound,
+		},
+		{
+			name: "acting for a user with a grant",
+			ctx:  ActingAs(context.Background(), granteeID.String()),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(fileColumns).AddRow(
+					fileID, "report.pdf", userID, nil, 1024, "application/pdf",
+					userID.String()+"/"+fileID.String(), "", fixedTime, fixedTime, nil,
+				)
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('app.user_id', $1, true)`)).
+					WithArgs(granteeID.String()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE (id = $1 AND user_id = $2) AND "files"."deleted_at" IS NULL`)).
+					WithArgs(fileID, userID, 1).
+					WillReturnRows(rows)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			// The row level security policies hide the files of others from users without a grant
+			name: "acting for a user without a grant",
+			ctx:  ActingAs(context.Background(), granteeID.String()),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('app.user_id', $1, true)`)).
+					WithArgs(granteeID.String()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files"`)).
+					WillReturnRows(sqlmock.NewRows(fileColumns))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrFileNotFound,
+		},
+		{
+			name: "invalid acting user",
+			ctx:  ActingAs(context.Background(), "invalid"),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			errorContains: "invalid acting user ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFileRepository{
+				conn: &database.GormConnection{DB: gormDB},
+			}
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			file, err := repo.GetByID(ctx, fileID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, fileID.String(), file.Id)
+				assert.Equal(t, "report.pdf", file.Name)
+				assert.Equal(t, int64(1024), file.Size)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRepository_List(t *testing.T) {
+	fixedTime := time.Now()
+	userID := uuid.New()
+	folderID := uuid.New()
+	rootFolder := ""
+	folder := folderID.String()
+
+	tests := []struct {
+		name          string
+		folderID      *string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedCount int32
+	}{
+		{
+			name: "all files of a user",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "files" WHERE user_id = $1 AND "files"."deleted_at" IS NULL`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE user_id = $1 AND "files"."deleted_at" IS NULL ORDER BY created_at DESC LIMIT $2`)).
+					WithArgs(userID, 10).
+					WillReturnRows(sqlmock.NewRows(fileColumns).
+						AddRow(uuid.New(), "a.txt", userID, nil, 1, "text/plain", "k1", "", fixedTime, fixedTime, nil).
+						AddRow(uuid.New(), "b.txt", userID, folderID, 2, "text/plain", "k2", "", fixedTime, fixedTime, nil))
+			},
+			expectedCount: 2,
+		},
+		{
+			name:     "root folder",
+			folderID: &rootFolder,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "files" WHERE user_id = $1 AND folder_id IS NULL`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE user_id = $1 AND folder_id IS NULL`)).
+					WillReturnRows(sqlmock.NewRows(fileColumns))
+			},
+			expectedCount: 0,
+		},
+		{
+			name:     "specific folder",
+			folderID: &folder,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "files" WHERE user_id = $1 AND folder_id = $2`)).
+					WithArgs(userID, folderID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE user_id = $1 AND folder_id = $2`)).
+					WillReturnRows(sqlmock.NewRows(fileColumns).
+						AddRow(uuid.New(), "b.txt", userID, folderID, 2, "text/plain", "k2", "", fixedTime, fixedTime, nil))
+			},
+			expectedCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFileRepository{
+				conn: &database.GormConnection{DB: gormDB},
+			}
+
+			files, totalCount, err := repo.List(context.Background(), userID.String(), tt.folderID, 1, 10)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, totalCount)
+			assert.Len(t, files, int(tt.expectedCount))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRepository_Delete(t *testing.T) {
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful deletion",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "files" SET "deleted_at"=$1 WHERE (id = $2 AND user_id = $3) AND "files"."deleted_at" IS NULL`)).
+					WithArgs(sqlmock.AnyArg(), fileID, userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "file not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "files" SET "deleted_at"=$1`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: ErrFileNotFound,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "files" SET "deleted_at"=$1`)).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFileRepository{
+				conn: &database.GormConnection{DB: gormDB},
+			}
+
+			err := repo.Delete(context.Background(), fileID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRepository_Discard(t *testing.T) {
+	userID := uuid.New()
+	fileID := uuid.New()
+	checksum := strings.Repeat("cd", 32)
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedKeys  []string
+		expectedError error
+	}{
+		{
+			name: "file without content",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM file_versions WHERE file_id = $1 RETURNING storage_key, COALESCE(checksum, '') AS checksum`)).
+					WithArgs(fileID).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}))
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM files WHERE id = $1 AND user_id = $2 RETURNING storage_key, COALESCE(checksum, '') AS checksum`)).
+					WithArgs(fileID, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow("staged", ""))
+				mock.ExpectCommit()
+			},
+			expectedKeys: []string{"staged"},
+		},
+		{
+			name: "shared content is left to blob collection",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM file_versions WHERE file_id = $1`)).
+					WithArgs(fileID).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow("legacy", checksum))
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM files WHERE id = $1 AND user_id = $2`)).
+					WithArgs(fileID, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow(BlobKey(checksum), checksum))
+				mock.ExpectCommit()
+			},
+			expectedKeys: []string{"legacy"},
+		},
+		{
+			name: "file not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM file_versions WHERE file_id = $1`)).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}))
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM files WHERE id = $1 AND user_id = $2`)).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrFileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFileRepository{
+				conn: &database.GormConnection{DB: gormDB},
+			}
+
+			keys, err := repo.Discard(context.Background(), fileID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedKeys, keys)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRepository_Move(t *testing.T) {
+	userID := uuid.New()
+	fileID := uuid.New()
+	folderID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		folderID      string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name:     "move into a folder under a new name",
+			folderID: folderID.String(),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "folders"`)).
+					WithArgs(folderID, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "files" SET "folder_id"=$1,"name"=$2,"updated_at"=$3 WHERE (id = $4 AND user_id = $5) AND "files"."deleted_at" IS NULL RETURNING *`)).
+					WithArgs(folderID, "final.pdf", sqlmock.AnyArg(), fileID, userID).
+					WillReturnRows(sqlmock.NewRows(fileColumns).
+						AddRow(fileID, "final.pdf", userID, folderID, 1024, "application/pdf", "key", "abc123", now, now, nil))
+			},
+		},
+		{
+			name: "move to root",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "files" SET "folder_id"=$1`)).
+					WithArgs(nil, "final.pdf", sqlmock.AnyArg(), fileID, userID).
+					WillReturnRows(sqlmock.NewRows(fileColumns).
+						AddRow(fileID, "final.pdf", userID, nil, 1024, "application/pdf", "key", "abc123", now, now, nil))
+			},
+		},
+		{
+			name:     "folder of someone else",
+			folderID: folderID.String(),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "folders"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedError: ErrFolderNotFound,
+		},
+		{
+			name: "name taken",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "files"`)).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			expectedError: ErrNameConflict,
+		},
+		{
+			name: "file not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "files"`)).
+					WillReturnRows(sqlmock.NewRows(fileColumns))
+			},
+			expectedError: ErrFileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFileRepository{conn: &database.GormConnection{DB: gormDB}}
+			file, err := repo.Move(context.Background(), fileID.String(), userID.String(), tt.folderID, "final.pdf")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "final.pdf", file.Name)
+				assert.Equal(t, tt.folderID, file.FolderId)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormFileRepository_UpdateContent(t *testing.T) {
+	userID := uuid.New()
+	fileID := uuid.New()
+	now := time.Now()
+	firstKey := StorageKey(userID, fileID)
+	newKey := ContentKey(userID, fileID)
+	typ := ContentType{MimeType: "application/x-elf", Mismatch: true, QuarantineReason: "file type application/x-elf", ScanStatus: domain.ScanPending}
+
+	expectLockedFile := func(mock sqlmock.Sqlmock, checksum string) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE (id = $1 AND user_id = $2) AND "files"."deleted_at" IS NULL ORDER BY "files"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(fileID, userID, 1).
+			WillReturnRows(sqlmock.NewRows(fileColumns).
+				AddRow(fileID, "report.pdf", userID, nil, 1024, "application/pdf", firstKey, checksum, now, now, nil))
+	}
+	expectUpdate := func(mock sqlmock.Sqlmock, key string) {
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "files" SET "checksum"=$1,"mime_mismatch"=$2,"mime_type"=$3,"quarantine_reason"=$4,"quarantined"=$5,"scan_status"=$6,"size"=$7,"storage_key"=$8,"updated_at"=$9 WHERE "files"."deleted_at" IS NULL AND "id" = $10 RETURNING *`)).
+			WithArgs("abc123", true, typ.MimeType, typ.QuarantineReason, true, domain.ScanPending, int64(2048), key, sqlmock.AnyArg(), fileID).
+			WillReturnRows(sqlmock.NewRows(fileColumns).
+				AddRow(fileID, "report.pdf", userID, nil, 2048, "application/pdf", key, "abc123", now, now, nil))
+	}
+
+	tests := []struct {
+		name          string
+		storageKey    string
+		storageLimit  int64
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name:       "first upload",
+			storageKey: firstKey,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLockedFile(mock, "")
+				expectUpdate(mock, firstKey)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:       "new content keeps the previous one as a version",
+			storageKey: newKey,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLockedFile(mock, "old")
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "file_versions" ("file_id","user_id","size","storage_key","checksum","scan_status","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+					WithArgs(fileID, userID, int64(1024), firstKey, "old", domain.ScanPending, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				expectUpdate(mock, newKey)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:       "new content for an incomplete upload",
+			storageKey: newKey,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLockedFile(mock, "")
+				expectUpdate(mock, newKey)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:       "file not found",
+			storageKey: firstKey,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files"`)).
+					WillReturnRows(sqlmock.NewRows(fileColumns))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrFileNotFound,
+		},
+		{
+			name:       "database error",
+			storageKey: firstKey,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLockedFile(mock, "")
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "files" SET`)).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrConnDone,
+		},
+		{
+			name:         "growth within the storage quota",
+			storageKey:   firstKey,
+			storageLimit: 4096,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLockedFile(mock, "")
+				expectUsage(mock, userID, 2048)
+				expectUpdate(mock, firstKey)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "kept version counts against the storage quota",
+			storageKey:   newKey,
+			storageLimit: 4096,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLockedFile(mock, "old")
+				expectUsage(mock, userID, 2049)
+				mock.ExpectRollback()
+			},
+			expectedError: ErrQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormFileRepository{
+				conn: &database.GormConnection{DB: gormDB},
+			}
+
+			file, err := repo.UpdateContent(context.Background(), fileID.String(), userID.String(), tt.storageKey, 2048, "abc123", typ, tt.storageLimit)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, file)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(2048), file.Size)
+				assert.Equal(t, "abc123", file.Checksum)
+				assert.Equal(t, tt.storageKey, file.StorageKey)
+				assert.Equal(t, "report.pdf", file.Name)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDomainFileToProto(t *testing.T) {
+	fileID := uuid.New()
+	userID := uuid.New()
+	folderID := uuid.New()
+	now := time.Now()
+
+	protoFile := domainFileToProto(&domain.File{
+		ID:         fileID,
+		Name:       "report.pdf",
+		UserID:     userID,
+		FolderID:   &folderID,
+		Size:       2048,
+		MimeType:   "application/pdf",
+		StorageKey: StorageKey(userID, fileID),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		// Declared as an image, but the content is a PDF
+		DeclaredMimeType: "image/png",
+		MimeMismatch:     true,
+	})
+
+	assert.Equal(t, fileID.String(), protoFile.Id)
+	assert.Equal(t, "report.pdf", protoFile.Name)
+	assert.Equal(t, userID.String(), protoFile.UserId)
+	assert.Equal(t, folderID.String(), protoFile.FolderId)
+	assert.Equal(t, int64(2048), protoFile.Size)
+	assert.Equal(t, "application/pdf", protoFile.MimeType)
+	assert.Equal(t, "image/png", protoFile.DeclaredMimeType)
+	assert.True(t, protoFile.MimeMismatch)
+	assert.False(t, protoFile.Quarantined)
+	assert.Equal(t, userID.String()+"/"+fileID.String(), protoFile.StorageKey)
+	assert.NotNil(t, protoFile.CreatedAt)
+	assert.NotNil(t, protoFile.UpdatedAt)
+}
@@ -0,0 +1,125 @@
+//go:build integration
+// +build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+// This test requires Docker
+// Run with: go test -tags=integration -v ./services/file-service/repository/...
+
+// startPostgres starts a database with the go-drive schema and returns a connection to it
+// as the file service, whose queries the row level security policies apply to
+func startPostgres(t *testing.T) *database.GormConnection {
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx, "postgres:16-alpine",
+		postgres.WithDatabase("postgres"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		postgres.WithInitScripts("../../../scripts/init.sql"),
+		postgres.BasicWaitStrategies(),
+	)
+	require.NoError(t, err, "could not start postgres container")
+	t.Cleanup(func() {
+		_ = container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	require.NoError(t, err)
+
+	connect := func(user, password string) *database.GormConnection {
+		conn, err := database.NewGormConnection(database.Config{
+			Host:     host,
+			Port:     port.Port(),
+			User:     user,
+			Password: password,
+			DBName:   "postgres",
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn
+	}
+	admin := connect("postgres", "postgres")
+	createUsers(t, admin.DB)
+
+	return connect("file_service", "file_service_password")
+}
+
+var (
+	rlsOwnerID   = uuid.New()
+	rlsGranteeID = uuid.New()
+	rlsOtherID   = uuid.New()
+)
+
+// createUsers adds the users of the test, which the file service may only read
+func createUsers(t *testing.T, db *gorm.DB) {
+	for _, id := range []uuid.UUID{rlsOwnerID, rlsGranteeID, rlsOtherID} {
+		require.NoError(t, db.Exec("INSERT INTO users (id, firstname, surname, email) VALUES (?, 'Test', 'User', ?)",
+			id, id.String()+"@example.com").Error)
+	}
+}
+
+func TestRowLevelSecurity(t *testing.T) {
+	conn := startPostgres(t)
+	files := NewGormFileRepositoryFromConnection(conn)
+	shares := NewGormShareRepositoryFromConnection(conn)
+
+	ctx := ActingAs(context.Background(), rlsOwnerID.String())
+	file, err := files.Create(ctx, &pb.CreateFileRequest{Name: "report.pdf", UserId: rlsOwnerID.String(), MimeType: "application/pdf"}, 0)
+	require.NoError(t, err)
+
+	t.Run("owner sees their file", func(t *testing.T) {
+		got, err := files.GetByID(ctx, file.Id, rlsOwnerID.String())
+		require.NoError(t, err)
+		assert.Equal(t, file.Id, got.Id)
+	})
+
+	t.Run("user without a grant sees no rows", func(t *testing.T) {
+		ctx := ActingAs(context.Background(), rlsOtherID.String())
+
+		_, err := files.GetByID(ctx, file.Id, rlsOwnerID.String())
+		assert.ErrorIs(t, err, ErrFileNotFound)
+
+		listed, total, err := files.List(ctx, rlsOwnerID.String(), nil, 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, listed)
+		assert.Zero(t, total)
+	})
+
+	t.Run("user without a grant cannot delete", func(t *testing.T) {
+		ctx := ActingAs(context.Background(), rlsOtherID.String())
+
+		_, err := files.Discard(ctx, file.Id, rlsOwnerID.String())
+		assert.ErrorIs(t, err, ErrFileNotFound)
+	})
+
+	t.Run("grantee sees the shared file", func(t *testing.T) {
+		_, err := shares.Grant(context.Background(), &pb.ShareItemRequest{
+			UserId:    rlsOwnerID.String(),
+			FileId:    file.Id,
+			GranteeId: rlsGranteeID.String(),
+			Role:      domain.ShareRoleViewer,
+		})
+		require.NoError(t, err)
+
+		got, err := files.GetByID(ActingAs(context.Background(), rlsGranteeID.String()), file.Id, rlsOwnerID.String())
+		require.NoError(t, err)
+		assert.Equal(t, file.Id, got.Id)
+	})
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+// QuarantineKey returns the storage key infected content with the given SHA-256 checksum
+// is moved to. Nothing serves content from there; it is kept for admins to review.
+func QuarantineKey(checksum string) string {
+	return fmt.Sprintf("quarantine/%s/%s", checksum[:2], checksum)
+}
+
+// ScanRepository tracks the malware scans of file content. Files are queued by a database
+// trigger whenever content is committed pending a scan, and the scan is kept when the content
+// is replaced and becomes a version. The outcome is copied to the scan status of the file
+// and its versions that still hold the scanned content.
+type ScanRepository interface {
+	// Claim picks up to limit contents of live files that are pending and due at now, and
+	// counts an attempt for each. They are not picked again before retryAt.
+	Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]ContentJob, error)
+	// Clean records that a file's content was found clean
+	Clean(ctx context.Context, job ContentJob, scannedAt time.Time) error
+	// Skip records that a file's content was let through unscanned, as scanning was turned
+	// off after it was queued
+	Skip(ctx context.Context, job ContentJob) error
+	// Infected records malware found in a file's content. Every file with the same content,
+	// whichever user it belongs to, is marked infected and quarantined.
+	Infected(ctx context.Context, job ContentJob, signature string, scannedAt time.Time) error
+	// Fail records a failed attempt. The file is retried at retryAt, or given up on with
+	// an error status when retryAt is nil.
+	Fail(ctx context.Context, job ContentJob, reason string, retryAt *time.Time) error
+	// ListQuarantined pages through the quarantined files of all users, most recently
+	// quarantined first
+	ListQuarantined(ctx context.Context, page, pageSize int32) ([]*pb.File, int32, error)
+}
+
+type gormScanRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormScanRepositoryFromConnection creates a scan repository from an existing GORM connection
+func NewGormScanRepositoryFromConnection(conn *database.GormConnection) ScanRepository {
+	return &gormScanRepository{conn: conn}
+}
+
+// Claim works like claimContentJobs, except that scans are keyed by file and checksum and
+// the content of a scan may be kept as a version of the file
+func (r *gormScanRepository) Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]ContentJob, error) {
+	var jobs []ContentJob
+	err := r.conn.DB.WithContext(ctx).Raw(`UPDATE file_scans c
+		SET attempts = c.attempts + 1, next_attempt_at = ?
+		FROM files f
+		WHERE f.id = c.file_id AND (c.file_id, c.checksum) IN (
+			SELECT s.file_id, s.checksum FROM file_scans s JOIN files live ON live.id = s.file_id
+			WHERE s.status = ? AND (s.next_attempt_at IS NULL OR s.next_attempt_at <= ?) AND live.deleted_at IS NULL
+			ORDER BY s.next_attempt_at NULLS FIRST
+			LIMIT ?
+			FOR UPDATE OF s SKIP LOCKED
+		)
+		RETURNING c.file_id, c.checksum, c.attempts, f.name, f.mime_type,
+			CASE WHEN f.checksum = c.checksum THEN f.storage_key
+				ELSE (SELECT v.storage_key FROM file_versions v WHERE v.file_id = c.file_id AND v.checksum = c.checksum LIMIT 1)
+			END AS storage_key,
+			CASE WHEN f.checksum = c.checksum THEN f.size
+				ELSE (SELECT v.size FROM file_versions v WHERE v.file_id = c.file_id AND v.checksum = c.checksum LIMIT 1)
+			END AS size`,
+		retryAt, domain.ScanPending, now, limit).
+		Scan(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim files to scan: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *gormScanRepository) Clean(ctx context.Context, job ContentJob, scannedAt time.Time) error {
+	return r.finish(ctx, job, domain.ScanClean, "", nil, &scannedAt)
+}
+
+func (r *gormScanRepository) Skip(ctx context.Context, job ContentJob) error {
+	return r.finish(ctx, job, domain.ScanSkipped, "", nil, nil)
+}
+
+func (r *gormScanRepository) Infected(ctx context.Context, job ContentJob, signature string, scannedAt time.Time) error {
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE file_scans
+			SET status = ?, last_error = ?, next_attempt_at = NULL, scanned_at = ?
+			WHERE checksum = ?`,
+			domain.ScanInfected, signature, scannedAt, job.Checksum).Error; err != nil {
+			return fmt.Errorf("failed to update scans: %w", err)
+		}
+		if err := tx.Exec(`UPDATE files
+			SET scan_status = ?, quarantined = true, quarantine_reason = ?
+			WHERE checksum = ?`,
+			domain.ScanInfected, "malware "+signature, job.Checksum).Error; err != nil {
+			return fmt.Errorf("failed to quarantine files: %w", err)
+		}
+		if err := tx.Exec("UPDATE file_versions SET scan_status = ? WHERE checksum = ?",
+			domain.ScanInfected, job.Checksum).Error; err != nil {
+			return fmt.Errorf("failed to quarantine file versions: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *gormScanRepository) Fail(ctx context.Context, job ContentJob, reason string, retryAt *time.Time) error {
+	status := domain.ScanPending
+	if retryAt == nil {
+		status = domain.ScanError
+	}
+	return r.finish(ctx, job, status, reason, retryAt, nil)
+}
+
+// finish records the outcome of an attempt and, once the scan is over, the status of the file
+// and its versions with the scanned content
+func (r *gormScanRepository) finish(ctx context.Context, job ContentJob, status, reason string, retryAt, scannedAt *time.Time) error {
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE file_scans
+			SET status = ?, last_error = NULLIF(?, ''), next_attempt_at = ?, scanned_at = ?
+			WHERE file_id = ? AND checksum = ?`,
+			status, reason, retryAt, scannedAt, job.FileID, job.Checksum).Error; err != nil {
+			return fmt.Errorf("failed to update scan: %w", err)
+		}
+		if status == domain.ScanPending {
+			return nil
+		}
+		if err := tx.Exec("UPDATE files SET scan_status = ? WHERE id = ? AND checksum = ?",
+			status, job.FileID, job.Checksum).Error; err != nil {
+			return fmt.Errorf("failed to update file scan status: %w", err)
+		}
+		if err := tx.Exec("UPDATE file_versions SET scan_status = ? WHERE file_id = ? AND checksum = ?",
+			status, job.FileID, job.Checksum).Error; err != nil {
+			return fmt.Errorf("failed to update file version scan status: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *gormScanRepository) ListQuarantined(ctx context.Context, page, pageSize int32) ([]*pb.File, int32, error) {
+	db := r.conn.DB.WithContext(ctx).Model(&domain.File{}).Where("quarantined")
+
+	var totalCount int64
+	if err := db.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count quarantined files: %w", err)
+	}
+
+	var files []domain.File
+	if err := db.Order("updated_at DESC, id").
+		Limit(int(pageSize)).
+		Offset(int((page - 1) * pageSize)).
+		Find(&files).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list quarantined files: %w", err)
+	}
+
+	pbFiles := make([]*pb.File, len(files))
+	for i := range files {
+		pbFiles[i] = domainFileToProto(&files[i])
+	}
+	return pbFiles, int32(totalCount), nil
+}
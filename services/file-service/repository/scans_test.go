@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+)
+
+func TestQuarantineKey(t *testing.T) {
+	checksum := strings.Repeat("ab", 32)
+	assert.Equal(t, "quarantine/ab/"+checksum, QuarantineKey(checksum))
+}
+
+func TestGormScanRepository_Claim(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	fileID := uuid.New()
+	checksum := strings.Repeat("ab", 32)
+	now := time.Now()
+	retryAt := now.Add(10 * time.Minute)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE file_scans c`)).
+		WithArgs(retryAt, domain.ScanPending, now, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"file_id", "checksum", "attempts", "name", "mime_type", "storage_key", "size"}).
+			AddRow(fileID, checksum, 1, "setup.zip", "application/zip", BlobKey(checksum), 42))
+
+	repo := NewGormScanRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+	jobs, err := repo.Claim(context.Background(), now, retryAt, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, []ContentJob{{
+		FileID: fileID, Checksum: checksum, Name: "setup.zip", MimeType: "application/zip",
+		StorageKey: BlobKey(checksum), Size: 42, Attempts: 1,
+	}}, jobs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormScanRepository_Results(t *testing.T) {
+	job := ContentJob{FileID: uuid.New(), Checksum: strings.Repeat("cd", 32)}
+	now := time.Now()
+	retryAt := now.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		run       func(ScanRepository) error
+		mockSetup func(sqlmock.Sqlmock)
+	}{
+		{
+			name: "clean",
+			run: func(repo ScanRepository) error {
+				return repo.Clean(context.Background(), job, now)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_scans`)).
+					WithArgs(domain.ScanClean, "", nil, &now, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE files SET scan_status = $1 WHERE id = $2 AND checksum = $3`)).
+					WithArgs(domain.ScanClean, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_versions SET scan_status = $1 WHERE file_id = $2 AND checksum = $3`)).
+					WithArgs(domain.ScanClean, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "skipped",
+			run: func(repo ScanRepository) error {
+				return repo.Skip(context.Background(), job)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_scans`)).
+					WithArgs(domain.ScanSkipped, "", nil, nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE files SET scan_status = $1 WHERE id = $2 AND checksum = $3`)).
+					WithArgs(domain.ScanSkipped, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_versions SET scan_status = $1 WHERE file_id = $2 AND checksum = $3`)).
+					WithArgs(domain.ScanSkipped, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "infected",
+			run: func(repo ScanRepository) error {
+				return repo.Infected(context.Background(), job, "Eicar-Test-Signature", now)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_scans`)).
+					WithArgs(domain.ScanInfected, "Eicar-Test-Signature", now, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE files`)).
+					WithArgs(domain.ScanInfected, "malware Eicar-Test-Signature", job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_versions SET scan_status = $1 WHERE checksum = $2`)).
+					WithArgs(domain.ScanInfected, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "fail and retry",
+			run: func(repo ScanRepository) error {
+				return repo.Fail(context.Background(), job, "clamd unreachable", &retryAt)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_scans`)).
+					WithArgs(domain.ScanPending, "clamd unreachable", &retryAt, nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "fail for good",
+			run: func(repo ScanRepository) error {
+				return repo.Fail(context.Background(), job, "clamd error: INSTREAM size limit exceeded", nil)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_scans`)).
+					WithArgs(domain.ScanError, "clamd error: INSTREAM size limit exceeded", nil, nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE files SET scan_status = $1`)).
+					WithArgs(domain.ScanError, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_versions SET scan_status = $1`)).
+					WithArgs(domain.ScanError, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+			tt.mockSetup(mock)
+
+			repo := NewGormScanRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			require.NoError(t, tt.run(repo))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormScanRepository_ListQuarantined(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	fileID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "files" WHERE quarantined AND "files"."deleted_at" IS NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE quarantined AND "files"."deleted_at" IS NULL ORDER BY updated_at DESC, id LIMIT $1`)).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows(fileColumns).
+			AddRow(fileID, "invoice.pdf", userID, nil, 68, "text/plain", BlobKey(strings.Repeat("ef", 32)), strings.Repeat("ef", 32), now, now, nil))
+
+	repo := NewGormScanRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+	files, total, err := repo.ListQuarantined(context.Background(), 1, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), total)
+	require.Len(t, files, 1)
+	assert.Equal(t, fileID.String(), files[0].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
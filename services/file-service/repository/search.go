@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+// Kinds of search results, in the order results with equal scores are listed
+const (
+	SearchKindFile   = "file"
+	SearchKindFolder = "folder"
+)
+
+// SearchFilter selects the files and folders a name search returns
+type SearchFilter struct {
+	// UserID owns the searched items
+	UserID string
+	// Query is matched as a substring and by trigram similarity
+	Query string
+	// Words are matched as word prefixes; they must consist of letters and digits only
+	Words []string
+	// MimeTypes holds exact types and major/* wildcards. Any MIME type or size filter
+	// leaves folders out.
+	MimeTypes      []string
+	MinSize        *int64
+	MaxSize        *int64
+	ModifiedAfter  *time.Time
+	ModifiedBefore *time.Time
+	// FolderID limits the search to the items below a folder of the user
+	FolderID string
+	// After continues a search after the last result of a previous page
+	After *SearchCursor
+	Limit int
+}
+
+// SearchCursor is the position of a result in the ranking of a search
+type SearchCursor struct {
+	Score float64   `json:"s"`
+	Kind  string    `json:"k"`
+	ID    uuid.UUID `json:"i"`
+}
+
+// SearchResult is a file or folder matching a search
+type SearchResult struct {
+	File   *pb.File
+	Folder *pb.Folder
+	Cursor SearchCursor
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, filter SearchFilter) ([]SearchResult, error)
+}
+
+type gormSearchRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormSearchRepositoryFromConnection creates a search repository from an existing GORM connection
+func NewGormSearchRepositoryFromConnection(conn *database.GormConnection) SearchRepository {
+	return &gormSearchRepository{conn: conn}
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search ranks the live files and folders whose names match the filter, along with the
+// files whose indexed content holds every word. Names score by word matches (ts_rank) plus
+// the similarity of the query to their closest part (word_similarity), and files add the
+// rank of their content; all of them are served by GIN indexes.
+func (r *gormSearchRepository) Search(ctx context.Context, filter SearchFilter) ([]SearchResult, error) {
+	uid, err := uuid.Parse(filter.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var (
+		sql  strings.Builder
+		args []interface{}
+	)
+	if filter.FolderID != "" {
+		folderID, err := uuid.Parse(filter.FolderID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid folder ID: %w", err)
+		}
+		sql.WriteString(subtreeCTE)
+		args = append(args, folderID, uid)
+	}
+
+	sql.WriteString("SELECT kind, id, score FROM (")
+	args = append(args, filter.branch(&sql, SearchKindFile, uid)...)
+	if filter.includeFolders() {
+		sql.WriteString(" UNION ALL ")
+		args = append(args, filter.branch(&sql, SearchKindFolder, uid)...)
+	}
+	sql.WriteString(") hits")
+	if after := filter.After; after != nil {
+		sql.WriteString(" WHERE score < ? OR (score = ? AND (kind, id) > (?, ?))")
+		args = append(args, after.Score, after.Score, after.Kind, after.ID)
+	}
+	sql.WriteString(" ORDER BY score DESC, kind, id LIMIT ?")
+	args = append(args, filter.Limit)
+
+	var results []SearchResult
+	err = acting(ctx, r.conn.DB, func(db *gorm.DB) error {
+		var hits []SearchCursor
+		if err := db.Raw(sql.String(), args...).Scan(&hits).Error; err != nil {
+			return fmt.Errorf("failed to search: %w", err)
+		}
+		results, err = load(db, hits)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// includeFolders reports whether folders can match the filter; only files have a type and size
+func (f SearchFilter) includeFolders() bool {
+	return len(f.MimeTypes) == 0 && f.MinSize == nil && f.MaxSize == nil
+}
+
+// branch writes the query selecting the matching items of one kind and returns its arguments
+func (f SearchFilter) branch(sql *strings.Builder, kind string, userID uuid.UUID) []interface{} {
+	table, parent := "files", "folder_id"
+	if kind == SearchKindFolder {
+		table, parent = "folders", "parent_id"
+	}
+	tsQuery := strings.Join(f.Words, ":* & ")
+	if tsQuery != "" {
+		tsQuery += ":*"
+	}
+
+	var args []interface{}
+	sql.WriteString("SELECT '" + kind + "' AS kind, id, (")
+	if tsQuery != "" {
+		sql.WriteString("ts_rank(name_search_vector(name), to_tsquery('simple', ?)) + ")
+		args = append(args, tsQuery)
+		if kind == SearchKindFile {
+			sql.WriteString("COALESCE((SELECT ts_rank(content, to_tsquery('simple', ?)) " +
+				"FROM file_content_index WHERE file_id = files.id), 0) + ")
+			args = append(args, tsQuery)
+		}
+	}
+	sql.WriteString("word_similarity(?, name))::float8 AS score FROM " + table +
+		" WHERE user_id = ? AND deleted_at IS NULL AND (? <% name OR name ILIKE ?")
+	args = append(args, f.Query, userID, f.Query, "%"+likeEscaper.Replace(f.Query)+"%")
+	if tsQuery != "" {
+		sql.WriteString(" OR name_search_vector(name) @@ to_tsquery('simple', ?)")
+		args = append(args, tsQuery)
+		if kind == SearchKindFile {
+			sql.WriteString(" OR id IN (SELECT file_id FROM file_content_index WHERE content @@ to_tsquery('simple', ?))")
+			args = append(args, tsQuery)
+		}
+	}
+	sql.WriteString(")")
+
+	if f.FolderID != "" {
+		sql.WriteString(" AND " + parent + " IN (SELECT id FROM subtree)")
+	}
+	if f.ModifiedAfter != nil {
+		sql.WriteString(" AND updated_at >= ?")
+		args = append(args, *f.ModifiedAfter)
+	}
+	if f.ModifiedBefore != nil {
+		sql.WriteString(" AND updated_at < ?")
+		args = append(args, *f.ModifiedBefore)
+	}
+	if kind == SearchKindFolder {
+		return args
+	}
+
+	if f.MinSize != nil {
+		sql.WriteString(" AND size >= ?")
+		args = append(args, *f.MinSize)
+	}
+	if f.MaxSize != nil {
+		sql.WriteString(" AND size <= ?")
+		args = append(args, *f.MaxSize)
+	}
+	if len(f.MimeTypes) > 0 {
+		var exact, majors []string
+		for _, mimeType := range f.MimeTypes {
+			if major, ok := strings.CutSuffix(mimeType, "/*"); ok {
+				majors = append(majors, major)
+			} else {
+				exact = append(exact, mimeType)
+			}
+		}
+		var alternatives []string
+		if len(exact) > 0 {
+			alternatives = append(alternatives, "mime_type IN ?")
+			args = append(args, exact)
+		}
+		if len(majors) > 0 {
+			alternatives = append(alternatives, "split_part(mime_type, '/', 1) IN ?")
+			args = append(args, majors)
+		}
+		sql.WriteString(" AND (" + strings.Join(alternatives, " OR ") + ")")
+	}
+	return args
+}
+
+// load fetches the files and folders of ranked hits, keeping their order. Items deleted
+// since they were ranked are left out.
+func load(db *gorm.DB, hits []SearchCursor) ([]SearchResult, error) {
+	var fileIDs, folderIDs []uuid.UUID
+	for _, hit := range hits {
+		if hit.Kind == SearchKindFolder {
+			folderIDs = append(folderIDs, hit.ID)
+		} else {
+			fileIDs = append(fileIDs, hit.ID)
+		}
+	}
+
+	files := make(map[uuid.UUID]*domain.File, len(fileIDs))
+	if len(fileIDs) > 0 {
+		var found []domain.File
+		if err := db.Where("id IN ?", fileIDs).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to load files: %w", err)
+		}
+		for i := range found {
+			files[found[i].ID] = &found[i]
+		}
+	}
+	folders := make(map[uuid.UUID]*domain.Folder, len(folderIDs))
+	if len(folderIDs) > 0 {
+		var found []domain.Folder
+		if err := db.Where("id IN ?", folderIDs).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to load folders: %w", err)
+		}
+		for i := range found {
+			folders[found[i].ID] = &found[i]
+		}
+	}
+
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		result := SearchResult{Cursor: hit}
+		if file, ok := files[hit.ID]; ok && hit.Kind == SearchKindFile {
+			result.File = domainFileToProto(file)
+		} else if folder, ok := folders[hit.ID]; ok && hit.Kind == SearchKindFolder {
+			result.Folder = domainFolderToProto(folder)
+		} else {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
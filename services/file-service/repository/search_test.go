@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+)
+
+func TestGormSearchRepository_Search(t *testing.T) {
+	userID := uuid.New()
+	fileID := uuid.New()
+	folderID := uuid.New()
+	now := time.Now()
+
+	t.Run("files and folders ranked together", func(t *testing.T) {
+		gormDB, mock, cleanup := setupGormMock(t)
+		defer cleanup()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT kind, id, score FROM (SELECT 'file' AS kind, id, `+
+			`(ts_rank(name_search_vector(name), to_tsquery('simple', $1)) + `+
+			`COALESCE((SELECT ts_rank(content, to_tsquery('simple', $2)) FROM file_content_index WHERE file_id = files.id), 0) + `+
+			`word_similarity($3, name))::float8 AS score `+
+			`FROM files WHERE user_id = $4 AND deleted_at IS NULL AND ($5 <% name OR name ILIKE $6 `+
+			`OR name_search_vector(name) @@ to_tsquery('simple', $7) `+
+			`OR id IN (SELECT file_id FROM file_content_index WHERE content @@ to_tsquery('simple', $8))) `+
+			`UNION ALL SELECT 'folder' AS kind`)).
+			WithArgs("q1:* & 100:*", "q1:* & 100:*", "q1_100%", userID, "q1_100%", `%q1\_100\%%`, "q1:* & 100:*", "q1:* & 100:*",
+				"q1:* & 100:*", "q1_100%", userID, "q1_100%", `%q1\_100\%%`, "q1:* & 100:*", 21).
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "id", "score"}).
+				AddRow(SearchKindFolder, folderID, 0.8).
+				AddRow(SearchKindFile, fileID, 0.5))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE id IN ($1)`)).
+			WithArgs(fileID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id", "size", "storage_key", "created_at", "updated_at"}).
+				AddRow(fileID, "Q1_100% report.pdf", userID, 10, "key", now, now))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders" WHERE id IN ($1)`)).
+			WithArgs(folderID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"}).
+				AddRow(folderID, "Q1 100", userID, now, now))
+
+		repo := NewGormSearchRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+		results, err := repo.Search(context.Background(), SearchFilter{
+			UserID: userID.String(),
+			Query:  "q1_100%",
+			Words:  []string{"q1", "100"},
+			Limit:  21,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, folderID.String(), results[0].Folder.Id)
+		assert.Equal(t, fileID.String(), results[1].File.Id)
+		assert.Equal(t, SearchCursor{Score: 0.5, Kind: SearchKindFile, ID: fileID}, results[1].Cursor)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("file filters below a folder after a cursor", func(t *testing.T) {
+		gormDB, mock, cleanup := setupGormMock(t)
+		defer cleanup()
+
+		minSize := int64(1)
+		after := SearchCursor{Score: 0.25, Kind: SearchKindFile, ID: fileID}
+		mock.ExpectQuery(regexp.QuoteMeta(`WITH RECURSIVE subtree AS (`)).
+			WithArgs(folderID, userID, "--", userID, "--", `%--%`, minSize, "application/pdf", "image",
+				0.25, 0.25, SearchKindFile, fileID, 11).
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "id", "score"}))
+
+		repo := NewGormSearchRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+		results, err := repo.Search(context.Background(), SearchFilter{
+			UserID:    userID.String(),
+			Query:     "--",
+			MimeTypes: []string{"application/pdf", "image/*"},
+			MinSize:   &minSize,
+			FolderID:  folderID.String(),
+			After:     &after,
+			Limit:     11,
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// ErrShareLinkNotFound is returned when no live item has a link with the token or ID
+	ErrShareLinkNotFound = errors.New("share link not found")
+	// ErrDownloadLimitReached is returned when a link has been downloaded as often as it allows
+	ErrDownloadLimitReached = errors.New("share link download limit reached")
+)
+
+// StoredShareLink is a share link together with the bcrypt hash of its password,
+// empty when it has none
+type StoredShareLink struct {
+	Link         *pb.ShareLink
+	PasswordHash string
+}
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, req *pb.CreateShareLinkRequest, tokenHash, passwordHash string) (*pb.ShareLink, error)
+	GetByToken(ctx context.Context, tokenHash string) (*StoredShareLink, error)
+	List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.ShareLink, int32, error)
+	Revoke(ctx context.Context, id, userID string) error
+	CountDownload(ctx context.Context, id string) (*pb.ShareLink, error)
+	InFolder(ctx context.Context, folderID, descendantID string) (bool, error)
+}
+
+type gormShareLinkRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormShareLinkRepositoryFromConnection creates a share link repository from an existing GORM connection
+func NewGormShareLinkRepositoryFromConnection(conn *database.GormConnection) ShareLinkRepository {
+	return &gormShareLinkRepository{conn: conn}
+}
+
+// liveTarget limits share links to those whose file or folder is not in the trash
+const liveTarget = `(file_id IN (SELECT id FROM files WHERE deleted_at IS NULL)
+	OR folder_id IN (SELECT id FROM folders WHERE deleted_at IS NULL))`
+
+// Create stores a link to an item owned by req.UserId
+func (r *gormShareLinkRepository) Create(ctx context.Context, req *pb.CreateShareLinkRequest, tokenHash, passwordHash string) (*pb.ShareLink, error) {
+	ownerID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	target, itemID := "file_id", req.FileId
+	if req.FolderId != "" {
+		target, itemID = "folder_id", req.FolderId
+	}
+	id, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", target, err)
+	}
+
+	link := &domain.ShareLink{
+		OwnerID:      ownerID,
+		TokenHash:    tokenHash,
+		PasswordHash: passwordHash,
+		MaxDownloads: req.MaxDownloads,
+		PreviewOnly:  req.PreviewOnly,
+	}
+	if req.FolderId != "" {
+		link.FolderID = &id
+	} else {
+		link.FileID = &id
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.AsTime()
+		link.ExpiresAt = &expiresAt
+	}
+
+	err = r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, ownerID); err != nil {
+			return err
+		}
+		return tx.Create(link).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return domainShareLinkToProto(link), nil
+}
+
+// GetByToken returns the link with a token hash, provided its item is live
+func (r *gormShareLinkRepository) GetByToken(ctx context.Context, tokenHash string) (*StoredShareLink, error) {
+	var link domain.ShareLink
+	if err := r.conn.DB.WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		Where(liveTarget).
+		First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShareLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	return &StoredShareLink{Link: domainShareLinkToProto(&link), PasswordHash: link.PasswordHash}, nil
+}
+
+// List pages through the links a user has created, newest first
+func (r *gormShareLinkRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.ShareLink, int32, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	query := r.conn.DB.WithContext(ctx).Model(&domain.ShareLink{}).
+		Where("owner_id = ?", uid).
+		Where(liveTarget)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count share links: %w", err)
+	}
+
+	var links []domain.ShareLink
+	if err := query.
+		Order("created_at DESC, id").
+		Limit(int(pageSize)).
+		Offset(int((page - 1) * pageSize)).
+		Find(&links).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list share links: %w", err)
+	}
+
+	pbLinks := make([]*pb.ShareLink, len(links))
+	for i := range links {
+		pbLinks[i] = domainShareLinkToProto(&links[i])
+	}
+	return pbLinks, int32(totalCount), nil
+}
+
+// Revoke deletes a link created by the user
+func (r *gormShareLinkRepository) Revoke(ctx context.Context, id, userID string) error {
+	linkID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid share link ID: %w", err)
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, uid); err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND owner_id = ?", linkID, uid).Delete(&domain.ShareLink{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke share link: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrShareLinkNotFound
+		}
+		return nil
+	})
+}
+
+// CountDownload records a download through a link. The limit is checked in the same
+// statement, so concurrent downloads cannot go over it.
+func (r *gormShareLinkRepository) CountDownload(ctx context.Context, id string) (*pb.ShareLink, error) {
+	linkID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share link ID: %w", err)
+	}
+
+	var links []domain.ShareLink
+	if err := r.conn.DB.WithContext(ctx).Raw(`UPDATE share_links SET download_count = download_count + 1
+		WHERE id = ? AND (max_downloads = 0 OR download_count < max_downloads)
+		RETURNING *`, linkID).
+		Scan(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to count download: %w", err)
+	}
+	if len(links) == 0 {
+		return nil, ErrDownloadLimitReached
+	}
+
+	return domainShareLinkToProto(&links[0]), nil
+}
+
+// InFolder reports whether descendantID is a live folder at or below folderID
+func (r *gormShareLinkRepository) InFolder(ctx context.Context, folderID, descendantID string) (bool, error) {
+	ancestor, err := uuid.Parse(folderID)
+	if err != nil {
+		return false, fmt.Errorf("invalid folder ID: %w", err)
+	}
+	descendant, err := uuid.Parse(descendantID)
+	if err != nil {
+		return false, fmt.Errorf("invalid folder ID: %w", err)
+	}
+
+	var matches int64
+	if err := r.conn.DB.WithContext(ctx).
+		Raw(ancestorsCTE+"SELECT count(*) FROM ancestors WHERE id = ?", descendant, ancestor).
+		Scan(&matches).Error; err != nil {
+		return false, fmt.Errorf("failed to check folder ancestry: %w", err)
+	}
+	return matches > 0, nil
+}
+
+// domainShareLinkToProto converts a domain.ShareLink to pb.ShareLink
+func domainShareLinkToProto(link *domain.ShareLink) *pb.ShareLink {
+	pbLink := &pb.ShareLink{
+		Id:                link.ID.String(),
+		OwnerId:           link.OwnerID.String(),
+		PasswordProtected: link.PasswordHash != "",
+		MaxDownloads:      link.MaxDownloads,
+		DownloadCount:     link.DownloadCount,
+		PreviewOnly:       link.PreviewOnly,
+		CreatedAt:         timestamppb.New(link.CreatedAt),
+		UpdatedAt:         timestamppb.New(link.UpdatedAt),
+	}
+	if link.FileID != nil {
+		pbLink.FileId = link.FileID.String()
+	}
+	if link.FolderID != nil {
+		pbLink.FolderId = link.FolderID.String()
+	}
+	if link.ExpiresAt != nil {
+		pbLink.ExpiresAt = timestamppb.New(*link.ExpiresAt)
+	}
+
+	return pbLink
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+)
+
+var shareLinkColumns = []string{
+	"id", "owner_id", "file_id", "folder_id", "token_hash", "password_hash", "expires_at",
+	"max_downloads", "download_count", "preview_only", "created_at", "updated_at",
+}
+
+func TestGormShareLinkRepository_GetByToken(t *testing.T) {
+	linkID := uuid.New()
+	ownerID := uuid.New()
+	fileID := uuid.New()
+	now := time.Now()
+	query := regexp.QuoteMeta(`SELECT * FROM "share_links" WHERE token_hash = $1 AND (file_id IN`)
+
+	tests := []struct {
+		name          string
+		rows          *sqlmock.Rows
+		expectedError error
+	}{
+		{
+			name: "password protected link",
+			rows: sqlmock.NewRows(shareLinkColumns).
+				AddRow(linkID, ownerID, fileID, nil, "hash", "$2a$10$bcrypt", now.Add(time.Hour), 5, 2, false, now, now),
+		},
+		{
+			name:          "unknown token",
+			rows:          sqlmock.NewRows(shareLinkColumns),
+			expectedError: ErrShareLinkNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			mock.ExpectQuery(query).WithArgs("hash", 1).WillReturnRows(tt.rows)
+
+			repo := NewGormShareLinkRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			stored, err := repo.GetByToken(context.Background(), "hash")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "$2a$10$bcrypt", stored.PasswordHash)
+				assert.True(t, stored.Link.PasswordProtected)
+				assert.Equal(t, fileID.String(), stored.Link.FileId)
+				assert.Equal(t, int32(2), stored.Link.DownloadCount)
+				assert.NotNil(t, stored.Link.ExpiresAt)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormShareLinkRepository_CountDownload(t *testing.T) {
+	linkID := uuid.New()
+	now := time.Now()
+	query := regexp.QuoteMeta(`UPDATE share_links SET download_count = download_count + 1
+		WHERE id = $1 AND (max_downloads = 0 OR download_count < max_downloads)`)
+
+	tests := []struct {
+		name          string
+		rows          *sqlmock.Rows
+		expectedError error
+	}{
+		{
+			name: "download counted",
+			rows: sqlmock.NewRows(shareLinkColumns).
+				AddRow(linkID, uuid.New(), uuid.New(), nil, "hash", "", nil, 3, 3, false, now, now),
+		},
+		{
+			name:          "limit reached",
+			rows:          sqlmock.NewRows(shareLinkColumns),
+			expectedError: ErrDownloadLimitReached,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			mock.ExpectQuery(query).WithArgs(linkID).WillReturnRows(tt.rows)
+
+			repo := NewGormShareLinkRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			link, err := repo.CountDownload(context.Background(), linkID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int32(3), link.DownloadCount)
+				assert.False(t, link.PasswordProtected)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormShareLinkRepository_InFolder(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	folderID := uuid.New()
+	subfolderID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM ancestors WHERE id = $2`)).
+		WithArgs(subfolderID, folderID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	repo := NewGormShareLinkRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+	below, err := repo.InFolder(context.Background(), folderID.String(), subfolderID.String())
+
+	require.NoError(t, err)
+	assert.True(t, below)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrShareNotFound is returned when a share does not exist or neither grants nor was granted to the user
+var ErrShareNotFound = errors.New("share not found")
+
+// foreignKeyViolation is the Postgres error code raised when a referenced row does not exist
+const foreignKeyViolation = "23503"
+
+// Access is a user's role on a file or folder: domain.ShareRoleOwner for the owner, or the
+// best role shared on the item or a folder above it
+type Access struct {
+	OwnerID string
+	Role    string
+}
+
+type ShareRepository interface {
+	FileAccess(ctx context.Context, fileID, userID string) (*Access, error)
+	FolderAccess(ctx context.Context, folderID, userID string) (*Access, error)
+	Grant(ctx context.Context, req *pb.ShareItemRequest) (*pb.Share, error)
+	List(ctx context.Context, fileID, folderID string) ([]*pb.Share, error)
+	Revoke(ctx context.Context, id, userID string) error
+	ListSharedWith(ctx context.Context, userID string, page, pageSize int32) ([]*pb.SharedItem, int32, error)
+}
+
+type gormShareRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormShareRepositoryFromConnection creates a share repository from an existing GORM connection
+func NewGormShareRepositoryFromConnection(conn *database.GormConnection) ShareRepository {
+	return &gormShareRepository{conn: conn}
+}
+
+// FileAccess returns the user's access to a live file. Files the user cannot access are
+// reported as not found, so that their existence is not revealed.
+func (r *gormShareRepository) FileAccess(ctx context.Context, fileID, userID string) (*Access, error) {
+	id, uid, err := parseFileIDs(fileID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := r.access(ctx, `SELECT user_id AS owner_id, COALESCE(file_access_role(?, id), '') AS role
+		FROM files WHERE id = ? AND deleted_at IS NULL`, uid, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file access: %w", err)
+	}
+	if access == nil {
+		return nil, ErrFileNotFound
+	}
+	return access, nil
+}
+
+// FolderAccess returns the user's access to a live folder
+func (r *gormShareRepository) FolderAccess(ctx context.Context, folderID, userID string) (*Access, error) {
+	id, uid, err := parseFolderIDs(folderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := r.access(ctx, `SELECT user_id AS owner_id, COALESCE(folder_access_role(?, id), '') AS role
+		FROM folders WHERE id = ? AND deleted_at IS NULL`, uid, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder access: %w", err)
+	}
+	if access == nil {
+		return nil, ErrFolderNotFound
+	}
+	return access, nil
+}
+
+func (r *gormShareRepository) access(ctx context.Context, query string, args ...interface{}) (*Access, error) {
+	var rows []struct {
+		OwnerID uuid.UUID
+		Role    string
+	}
+	if err := r.conn.DB.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || rows[0].Role == "" {
+		return nil, nil
+	}
+	return &Access{OwnerID: rows[0].OwnerID.String(), Role: rows[0].Role}, nil
+}
+
+// Grant shares an item owned by req.UserId, or changes the role of an existing share
+func (r *gormShareRepository) Grant(ctx context.Context, req *pb.ShareItemRequest) (*pb.Share, error) {
+	ownerID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	granteeID, err := uuid.Parse(req.GranteeId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grantee ID: %w", err)
+	}
+	target, itemID := "file_id", req.FileId
+	if req.FolderId != "" {
+		target, itemID = "folder_id", req.FolderId
+	}
+	id, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", target, err)
+	}
+
+	share := &domain.Share{OwnerID: ownerID, GranteeID: granteeID, Role: req.Role}
+	if req.FolderId != "" {
+		share.FolderID = &id
+	} else {
+		share.FileID = &id
+	}
+
+	err = r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, ownerID); err != nil {
+			return err
+		}
+		return tx.Raw(`INSERT INTO file_shares (owner_id, grantee_id, file_id, folder_id, role)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (`+target+`, grantee_id) WHERE `+target+` IS NOT NULL
+			DO UPDATE SET role = EXCLUDED.role
+			RETURNING *`, share.OwnerID, share.GranteeID, share.FileID, share.FolderID, share.Role).
+			Scan(share).Error
+	})
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to share item: %w", err)
+	}
+
+	return domainShareToProto(share), nil
+}
+
+// List returns the shares of a file or folder, oldest first
+func (r *gormShareRepository) List(ctx context.Context, fileID, folderID string) ([]*pb.Share, error) {
+	target, itemID := "file_id", fileID
+	if folderID != "" {
+		target, itemID = "folder_id", folderID
+	}
+	id, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", target, err)
+	}
+
+	var shares []domain.Share
+	if err := r.conn.DB.WithContext(ctx).
+		Where(target+" = ?", id).
+		Order("created_at, id").
+		Find(&shares).Error; err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+
+	pbShares := make([]*pb.Share, len(shares))
+	for i := range shares {
+		pbShares[i] = domainShareToProto(&shares[i])
+	}
+	return pbShares, nil
+}
+
+// Revoke deletes a share granted by or to the user
+func (r *gormShareRepository) Revoke(ctx context.Context, id, userID string) error {
+	shareID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid share ID: %w", err)
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := actAs(tx, uid); err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND (owner_id = ? OR grantee_id = ?)", shareID, uid, uid).
+			Delete(&domain.Share{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke share: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrShareNotFound
+		}
+		return nil
+	})
+}
+
+// ListSharedWith pages through the live files and folders shared with a user, most recently shared first
+func (r *gormShareRepository) ListSharedWith(ctx context.Context, userID string, page, pageSize int32) ([]*pb.SharedItem, int32, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	query := r.conn.DB.WithContext(ctx).Model(&domain.Share{}).
+		Where("grantee_id = ?", uid).
+		Where("(file_id IN (SELECT id FROM files WHERE deleted_at IS NULL) OR folder_id IN (SELECT id FROM folders WHERE deleted_at IS NULL))")
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count shared items: %w", err)
+	}
+
+	var shares []domain.Share
+	if err := query.
+		Preload("File").
+		Preload("Folder").
+		Order("created_at DESC, id").
+		Limit(int(pageSize)).
+		Offset(int((page - 1) * pageSize)).
+		Find(&shares).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list shared items: %w", err)
+	}
+
+	items := make([]*pb.SharedItem, 0, len(shares))
+	for i := range shares {
+		share := &shares[i]
+		item := &pb.SharedItem{Share: domainShareToProto(share)}
+		switch {
+		case share.File != nil:
+			item.File = domainFileToProto(share.File)
+		case share.Folder != nil:
+			item.Folder = domainFolderToProto(share.Folder)
+		default:
+			// Deleted since it was counted
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, int32(totalCount), nil
+}
+
+// isForeignKeyViolation reports whether err was caused by a missing referenced row
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
+
+// domainShareToProto converts a domain.Share to pb.Share
+func domainShareToProto(share *domain.Share) *pb.Share {
+	pbShare := &pb.Share{
+		Id:        share.ID.String(),
+		OwnerId:   share.OwnerID.String(),
+		GranteeId: share.GranteeID.String(),
+		Role:      share.Role,
+		CreatedAt: timestamppb.New(share.CreatedAt),
+		UpdatedAt: timestamppb.New(share.UpdatedAt),
+	}
+	if share.FileID != nil {
+		pbShare.FileId = share.FileID.String()
+	}
+	if share.FolderID != nil {
+		pbShare.FolderId = share.FolderID.String()
+	}
+
+	return pbShare
+}
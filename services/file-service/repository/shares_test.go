@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+var shareColumns = []string{"id", "owner_id", "grantee_id", "file_id", "folder_id", "role", "created_at", "updated_at"}
+
+func TestGormShareRepository_FileAccess(t *testing.T) {
+	ownerID := uuid.New()
+	userID := uuid.New()
+	fileID := uuid.New()
+	query := regexp.QuoteMeta(`SELECT user_id AS owner_id, COALESCE(file_access_role($1, id), '') AS role`)
+
+	tests := []struct {
+		name          string
+		rows          *sqlmock.Rows
+		expected      *Access
+		expectedError error
+	}{
+		{
+			name:     "shared with the user",
+			rows:     sqlmock.NewRows([]string{"owner_id", "role"}).AddRow(ownerID, domain.ShareRoleEditor),
+			expected: &Access{OwnerID: ownerID.String(), Role: domain.ShareRoleEditor},
+		},
+		{
+			name:          "not shared with the user",
+			rows:          sqlmock.NewRows([]string{"owner_id", "role"}).AddRow(ownerID, ""),
+			expectedError: ErrFileNotFound,
+		},
+		{
+			name:          "file not found",
+			rows:          sqlmock.NewRows([]string{"owner_id", "role"}),
+			expectedError: ErrFileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			mock.ExpectQuery(query).WithArgs(userID, fileID).WillReturnRows(tt.rows)
+
+			repo := NewGormShareRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			access, err := repo.FileAccess(context.Background(), fileID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, access)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormShareRepository_Grant(t *testing.T) {
+	ownerID := uuid.New()
+	granteeID := uuid.New()
+	folderID := uuid.New()
+	shareID := uuid.New()
+	now := time.Now()
+	insert := regexp.QuoteMeta(`INSERT INTO file_shares (owner_id, grantee_id, file_id, folder_id, role)`)
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "folder shared",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('app.user_id', $1, true)`)).
+					WithArgs(ownerID.String()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(insert).
+					WillReturnRows(sqlmock.NewRows(shareColumns).
+						AddRow(shareID, ownerID, granteeID, nil, folderID, domain.ShareRoleViewer, now, now))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "grantee not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(insert).
+					WillReturnError(&pgconn.PgError{Code: foreignKeyViolation})
+				mock.ExpectRollback()
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := NewGormShareRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			share, err := repo.Grant(context.Background(), &pb.ShareItemRequest{
+				UserId:    ownerID.String(),
+				FolderId:  folderID.String(),
+				GranteeId: granteeID.String(),
+				Role:      domain.ShareRoleViewer,
+			})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, shareID.String(), share.Id)
+				assert.Equal(t, folderID.String(), share.FolderId)
+				assert.Empty(t, share.FileId)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormShareRepository_Revoke(t *testing.T) {
+	userID := uuid.New()
+	shareID := uuid.New()
+
+	tests := []struct {
+		name          string
+		rowsAffected  int64
+		expectedError error
+	}{
+		{name: "revoked", rowsAffected: 1},
+		{name: "share not found", rowsAffected: 0, expectedError: ErrShareNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('app.user_id', $1, true)`)).
+				WithArgs(userID.String()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "file_shares" WHERE id = $1 AND (owner_id = $2 OR grantee_id = $3)`)).
+				WithArgs(shareID, userID, userID).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			if tt.expectedError != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			repo := NewGormShareRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			err := repo.Revoke(context.Background(), shareID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
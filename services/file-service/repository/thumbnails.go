@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+)
+
+// ErrThumbnailsNotFound is returned for files that are not images or have no content
+var ErrThumbnailsNotFound = errors.New("thumbnails not found")
+
+// ThumbnailKey returns the storage key of a thumbnail of the content stored at storageKey.
+// Thumbnails live next to their content and are deleted with it.
+func ThumbnailKey(storageKey string, size int) string {
+	return fmt.Sprintf("%s.thumb-%d", storageKey, size)
+}
+
+// ThumbnailRepository tracks the thumbnails of image files. Images are queued by a database
+// trigger whenever their checksum changes; results for content that has been replaced since
+// it was claimed are dropped.
+type ThumbnailRepository interface {
+	// Get returns the thumbnail state of a file's current content
+	Get(ctx context.Context, fileID string) (*domain.FileThumbnails, error)
+	// Claim picks up to limit live images that are pending and due at now, and counts an
+	// attempt for each. They are not picked again before retryAt.
+	Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]ContentJob, error)
+	// Ready records that the thumbnails of a file's content have been stored
+	Ready(ctx context.Context, job ContentJob, generatedAt time.Time) error
+	// Skip records why no thumbnails can be rendered for a file's content
+	Skip(ctx context.Context, job ContentJob, reason string) error
+	// Fail records a failed attempt. The file is retried at retryAt, or given up on when
+	// retryAt is nil.
+	Fail(ctx context.Context, job ContentJob, reason string, retryAt *time.Time) error
+}
+
+type gormThumbnailRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormThumbnailRepositoryFromConnection creates a thumbnail repository from an existing GORM connection
+func NewGormThumbnailRepositoryFromConnection(conn *database.GormConnection) ThumbnailRepository {
+	return &gormThumbnailRepository{conn: conn}
+}
+
+func (r *gormThumbnailRepository) Get(ctx context.Context, fileID string) (*domain.FileThumbnails, error) {
+	id, err := uuid.Parse(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+
+	var thumbnails domain.FileThumbnails
+	if err := r.conn.DB.WithContext(ctx).
+		Select("file_id", "checksum", "status", "attempts", "last_error", "generated_at").
+		First(&thumbnails, "file_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrThumbnailsNotFound
+		}
+		return nil, fmt.Errorf("failed to get thumbnails: %w", err)
+	}
+
+	return &thumbnails, nil
+}
+
+func (r *gormThumbnailRepository) Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]ContentJob, error) {
+	jobs, err := claimContentJobs(r.conn.DB.WithContext(ctx), "file_thumbnails", domain.ThumbnailPending, now, retryAt, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim images to render: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *gormThumbnailRepository) Ready(ctx context.Context, job ContentJob, generatedAt time.Time) error {
+	return r.finish(ctx, job, domain.ThumbnailReady, "", nil, &generatedAt)
+}
+
+func (r *gormThumbnailRepository) Skip(ctx context.Context, job ContentJob, reason string) error {
+	return r.finish(ctx, job, domain.ThumbnailUnsupported, reason, nil, nil)
+}
+
+func (r *gormThumbnailRepository) Fail(ctx context.Context, job ContentJob, reason string, retryAt *time.Time) error {
+	status := domain.ThumbnailPending
+	if retryAt == nil {
+		status = domain.ThumbnailFailed
+	}
+	return r.finish(ctx, job, status, reason, retryAt, nil)
+}
+
+// finish records the outcome of an attempt
+func (r *gormThumbnailRepository) finish(ctx context.Context, job ContentJob, status, reason string, retryAt, generatedAt *time.Time) error {
+	if err := r.conn.DB.WithContext(ctx).Exec(`UPDATE file_thumbnails
+		SET status = ?, last_error = NULLIF(?, ''), next_attempt_at = ?, generated_at = ?
+		WHERE file_id = ? AND checksum = ?`,
+		status, reason, retryAt, generatedAt, job.FileID, job.Checksum).Error; err != nil {
+		return fmt.Errorf("failed to update thumbnails: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+)
+
+func TestThumbnailKey(t *testing.T) {
+	checksum := strings.Repeat("ab", 32)
+	assert.Equal(t, "blobs/ab/"+checksum+".thumb-256", ThumbnailKey(BlobKey(checksum), 256))
+}
+
+func TestGormThumbnailRepository_Get(t *testing.T) {
+	fileID := uuid.New()
+	checksum := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "ready",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT "file_id","checksum","status","attempts","last_error","generated_at" FROM "file_thumbnails" WHERE file_id = $1`)).
+					WithArgs(fileID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"file_id", "checksum", "status", "attempts", "last_error", "generated_at"}).
+						AddRow(fileID, checksum, domain.ThumbnailReady, 1, nil, time.Now()))
+			},
+		},
+		{
+			name: "not an image",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`FROM "file_thumbnails"`)).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrThumbnailsNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+			tt.mockSetup(mock)
+
+			repo := NewGormThumbnailRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			thumbnails, err := repo.Get(context.Background(), fileID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, domain.ThumbnailReady, thumbnails.Status)
+				assert.Equal(t, checksum, thumbnails.Checksum)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormThumbnailRepository_Claim(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	fileID := uuid.New()
+	checksum := strings.Repeat("ab", 32)
+	now := time.Now()
+	retryAt := now.Add(10 * time.Minute)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE file_thumbnails c`)).
+		WithArgs(retryAt, domain.ThumbnailPending, now, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"file_id", "checksum", "attempts", "name", "mime_type", "storage_key", "size"}).
+			AddRow(fileID, checksum, 1, "photo.jpg", "image/jpeg", BlobKey(checksum), 42))
+
+	repo := NewGormThumbnailRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+	jobs, err := repo.Claim(context.Background(), now, retryAt, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, []ContentJob{{
+		FileID: fileID, Checksum: checksum, Name: "photo.jpg", MimeType: "image/jpeg",
+		StorageKey: BlobKey(checksum), Size: 42, Attempts: 1,
+	}}, jobs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormThumbnailRepository_Results(t *testing.T) {
+	job := ContentJob{FileID: uuid.New(), Checksum: strings.Repeat("cd", 32)}
+	now := time.Now()
+	retryAt := now.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		run       func(ThumbnailRepository) error
+		mockSetup func(sqlmock.Sqlmock)
+	}{
+		{
+			name: "ready",
+			run: func(repo ThumbnailRepository) error {
+				return repo.Ready(context.Background(), job, now)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_thumbnails`)).
+					WithArgs(domain.ThumbnailReady, "", nil, &now, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "skip",
+			run: func(repo ThumbnailRepository) error {
+				return repo.Skip(context.Background(), job, "image format not supported")
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_thumbnails`)).
+					WithArgs(domain.ThumbnailUnsupported, "image format not supported", nil, nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "fail and retry",
+			run: func(repo ThumbnailRepository) error {
+				return repo.Fail(context.Background(), job, "storage unavailable", &retryAt)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_thumbnails`)).
+					WithArgs(domain.ThumbnailPending, "storage unavailable", &retryAt, nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "fail for good",
+			run: func(repo ThumbnailRepository) error {
+				return repo.Fail(context.Background(), job, "corrupt image", nil)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE file_thumbnails`)).
+					WithArgs(domain.ThumbnailFailed, "corrupt image", nil, nil, job.FileID, job.Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+			tt.mockSetup(mock)
+
+			repo := NewGormThumbnailRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			require.NoError(t, tt.run(repo))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
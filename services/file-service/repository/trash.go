@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrNotInTrash is returned when restoring an item that does not exist or has not been deleted
+var ErrNotInTrash = errors.New("item not found in trash")
+
+// trashTopLevel selects the deleted items a user deleted directly, leaving out the
+// contents of deleted folders, which share their folder's deletion time
+const trashTopLevel = `
+	SELECT 'folder' AS kind, f.id, f.deleted_at FROM folders f
+	WHERE f.user_id = @user AND f.deleted_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM folders p WHERE p.id = f.parent_id AND p.deleted_at = f.deleted_at)
+	UNION ALL
+	SELECT 'file' AS kind, f.id, f.deleted_at FROM files f
+	WHERE f.user_id = @user AND f.deleted_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM folders p WHERE p.id = f.folder_id AND p.deleted_at = f.deleted_at)`
+
+// deletedAncestorsCTE selects a folder and its ancestors up to, but excluding, the first live one
+const deletedAncestorsCTE = `WITH RECURSIVE chain AS (
+	SELECT id, parent_id, deleted_at FROM folders WHERE id = ? AND user_id = ?
+	UNION
+	SELECT f.id, f.parent_id, f.deleted_at FROM folders f JOIN chain c ON f.id = c.parent_id
+	WHERE c.deleted_at IS NOT NULL
+) `
+
+// deletedSubtreeCTE selects a deleted folder and the descendants that were deleted along with it
+const deletedSubtreeCTE = `WITH RECURSIVE subtree AS (
+	SELECT id FROM folders WHERE id = ? AND deleted_at = ?
+	UNION
+	SELECT f.id FROM folders f JOIN subtree s ON f.parent_id = s.id WHERE f.deleted_at = ?
+) `
+
+type TrashRepository interface {
+	List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.TrashItem, int32, error)
+	RestoreFile(ctx context.Context, id, userID string) (int32, int32, error)
+	RestoreFolder(ctx context.Context, id, userID string) (int32, int32, error)
+	Empty(ctx context.Context, userID string) (*PurgeResult, error)
+	Purge(ctx context.Context, deletedBefore time.Time, limit int) (*PurgeResult, error)
+}
+
+// PurgeResult reports what a hard delete removed. StorageKeys lists content of the removed
+// files and their versions that is not shared through a blob, which the caller deletes from
+// storage once the rows are gone. Shared content is collected when its last reference goes.
+type PurgeResult struct {
+	Folders     int32
+	Files       int32
+	StorageKeys []string
+}
+
+type gormTrashRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormTrashRepositoryFromConnection creates a trash repository from an existing GORM connection
+func NewGormTrashRepositoryFromConnection(conn *database.GormConnection) TrashRepository {
+	return &gormTrashRepository{conn: conn}
+}
+
+// List pages through a user's trash, most recently deleted first
+func (r *gormTrashRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.TrashItem, int32, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var totalCount int64
+	var entries []struct {
+		Kind string
+		ID   uuid.UUID
+	}
+	var folders map[uuid.UUID]domain.Folder
+	var files map[uuid.UUID]domain.File
+	err = acting(ctx, r.conn.DB, func(db *gorm.DB) error {
+		args := map[string]interface{}{"user": ownerID}
+
+		if err := db.Raw("SELECT count(*) FROM ("+trashTopLevel+") trash", args).
+			Scan(&totalCount).Error; err != nil {
+			return fmt.Errorf("failed to count trash: %w", err)
+		}
+
+		args["limit"] = pageSize
+		args["offset"] = (page - 1) * pageSize
+		if err := db.Raw("SELECT kind, id FROM ("+trashTopLevel+") trash ORDER BY deleted_at DESC, id LIMIT @limit OFFSET @offset", args).
+			Scan(&entries).Error; err != nil {
+			return fmt.Errorf("failed to list trash: %w", err)
+		}
+
+		var folderIDs, fileIDs []uuid.UUID
+		for _, entry := range entries {
+			if entry.Kind == "folder" {
+				folderIDs = append(folderIDs, entry.ID)
+			} else {
+				fileIDs = append(fileIDs, entry.ID)
+			}
+		}
+
+		folders = make(map[uuid.UUID]domain.Folder, len(folderIDs))
+		if len(folderIDs) > 0 {
+			var rows []domain.Folder
+			if err := db.Unscoped().Where("id IN ?", folderIDs).Find(&rows).Error; err != nil {
+				return fmt.Errorf("failed to get deleted folders: %w", err)
+			}
+			for _, row := range rows {
+				folders[row.ID] = row
+			}
+		}
+		files = make(map[uuid.UUID]domain.File, len(fileIDs))
+		if len(fileIDs) > 0 {
+			var rows []domain.File
+			if err := db.Unscoped().Where("id IN ?", fileIDs).Find(&rows).Error; err != nil {
+				return fmt.Errorf("failed to get deleted files: %w", err)
+			}
+			for _, row := range rows {
+				files[row.ID] = row
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]*pb.TrashItem, 0, len(entries))
+	for _, entry := range entries {
+		if folder, ok := folders[entry.ID]; ok {
+			items = append(items, &pb.TrashItem{
+				Folder:    domainFolderToProto(&folder),
+				DeletedAt: timestamppb.New(folder.DeletedAt.Time),
+			})
+		} else if file, ok := files[entry.ID]; ok {
+			items = append(items, &pb.TrashItem{
+				File:      domainFileToProto(&file),
+				DeletedAt: timestamppb.New(file.DeletedAt.Time),
+			})
+		}
+	}
+
+	return items, int32(totalCount), nil
+}
+
+// RestoreFile restores a deleted file together with any deleted folders above it.
+// It returns the number of folders and files restored.
+func (r *gormTrashRepository) RestoreFile(ctx context.Context, id, userID string) (int32, int32, error) {
+	fileID, err := uuid.Parse(id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid file ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var folders int64
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		var file domain.File
+		if err := tx.Unscoped().
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", fileID, ownerID).
+			First(&file).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotInTrash
+			}
+			return fmt.Errorf("failed to get deleted file: %w", err)
+		}
+
+		if folders, err = restoreAncestors(tx, ownerID, file.FolderID); err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&file).UpdateColumn("deleted_at", nil).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to restore file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int32(folders), 1, nil
+}
+
+// RestoreFolder restores a deleted folder, everything that was deleted along with it,
+// and any deleted folders above it. It returns the number of folders and files restored.
+func (r *gormTrashRepository) RestoreFolder(ctx context.Context, id, userID string) (int32, int32, error) {
+	folderID, ownerID, err := parseFolderIDs(id, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var folders, files int64
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		var folder domain.Folder
+		if err := tx.Unscoped().
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", folderID, ownerID).
+			First(&folder).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotInTrash
+			}
+			return fmt.Errorf("failed to get deleted folder: %w", err)
+		}
+
+		if folders, err = restoreAncestors(tx, ownerID, folder.ParentID); err != nil {
+			return err
+		}
+
+		deletedAt := folder.DeletedAt.Time
+		var ids []uuid.UUID
+		if err := tx.Raw(deletedSubtreeCTE+"SELECT id FROM subtree", folderID, deletedAt, deletedAt).
+			Scan(&ids).Error; err != nil {
+			return fmt.Errorf("failed to collect deleted subtree: %w", err)
+		}
+
+		result := tx.Unscoped().Model(&domain.Folder{}).
+			Where("id IN ?", ids).
+			UpdateColumn("deleted_at", nil)
+		if result.Error != nil {
+			if isUniqueViolation(result.Error) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to restore folders: %w", result.Error)
+		}
+		folders += result.RowsAffected
+
+		result = tx.Unscoped().Model(&domain.File{}).
+			Where("folder_id IN ? AND deleted_at = ?", ids, deletedAt).
+			UpdateColumn("deleted_at", nil)
+		if result.Error != nil {
+			if isUniqueViolation(result.Error) {
+				return ErrNameConflict
+			}
+			return fmt.Errorf("failed to restore files: %w", result.Error)
+		}
+		files = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int32(folders), int32(files), nil
+}
+
+// Empty permanently deletes everything in a user's trash
+func (r *gormTrashRepository) Empty(ctx context.Context, userID string) (*PurgeResult, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	result := &PurgeResult{}
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		var versions, files []storedContent
+		if err := tx.Raw(`DELETE FROM file_versions WHERE file_id IN (
+				SELECT id FROM files WHERE user_id = ? AND deleted_at IS NOT NULL
+			) RETURNING storage_key, COALESCE(checksum, '') AS checksum`, ownerID).
+			Scan(&versions).Error; err != nil {
+			return fmt.Errorf("failed to purge file versions: %w", err)
+		}
+		if err := tx.Raw("DELETE FROM files WHERE user_id = ? AND deleted_at IS NOT NULL RETURNING storage_key, COALESCE(checksum, '') AS checksum", ownerID).
+			Scan(&files).Error; err != nil {
+			return fmt.Errorf("failed to purge files: %w", err)
+		}
+		result.Files = int32(len(files))
+		result.StorageKeys = unsharedKeys(append(versions, files...))
+
+		deleted := tx.Exec("DELETE FROM folders WHERE user_id = ? AND deleted_at IS NOT NULL", ownerID)
+		if deleted.Error != nil {
+			return fmt.Errorf("failed to purge folders: %w", deleted.Error)
+		}
+		result.Folders = int32(deleted.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Purge permanently deletes up to limit files that were deleted before deletedBefore. Once no
+// such files remain, the folders deleted before then go too; any files they held were deleted
+// no later than the folder and have already been removed.
+func (r *gormTrashRepository) Purge(ctx context.Context, deletedBefore time.Time, limit int) (*PurgeResult, error) {
+	result := &PurgeResult{}
+	err := r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Raw("SELECT id FROM files WHERE deleted_at < ? ORDER BY deleted_at LIMIT ? FOR UPDATE SKIP LOCKED", deletedBefore, limit).
+			Scan(&ids).Error; err != nil {
+			return fmt.Errorf("failed to select expired files: %w", err)
+		}
+
+		if len(ids) > 0 {
+			var versions, files []storedContent
+			if err := tx.Raw("DELETE FROM file_versions WHERE file_id IN ? RETURNING storage_key, COALESCE(checksum, '') AS checksum", ids).
+				Scan(&versions).Error; err != nil {
+				return fmt.Errorf("failed to purge file versions: %w", err)
+			}
+			if err := tx.Raw("DELETE FROM files WHERE id IN ? RETURNING storage_key, COALESCE(checksum, '') AS checksum", ids).
+				Scan(&files).Error; err != nil {
+				return fmt.Errorf("failed to purge files: %w", err)
+			}
+			result.Files = int32(len(files))
+			result.StorageKeys = unsharedKeys(append(versions, files...))
+		}
+		if len(ids) == limit {
+			return nil
+		}
+
+		deleted := tx.Exec("DELETE FROM folders WHERE deleted_at < ?", deletedBefore)
+		if deleted.Error != nil {
+			return fmt.Errorf("failed to purge folders: %w", deleted.Error)
+		}
+		result.Folders = int32(deleted.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// restoreAncestors undeletes the deleted folders above an item, stopping at the first live one
+func restoreAncestors(tx *gorm.DB, userID uuid.UUID, parentID *uuid.UUID) (int64, error) {
+	if parentID == nil {
+		return 0, nil
+	}
+
+	var ids []uuid.UUID
+	if err := tx.Raw(deletedAncestorsCTE+"SELECT id FROM chain WHERE deleted_at IS NOT NULL", *parentID, userID).
+		Scan(&ids).Error; err != nil {
+		return 0, fmt.Errorf("failed to collect deleted parent folders: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := tx.Unscoped().Model(&domain.Folder{}).
+		Where("id IN ?", ids).
+		UpdateColumn("deleted_at", nil)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return 0, ErrNameConflict
+		}
+		return 0, fmt.Errorf("failed to restore parent folders: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+)
+
+func TestGormTrashRepository_List(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	userID := uuid.New()
+	folderID := uuid.New()
+	fileID := uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM (`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT kind, id FROM (`)).
+		WillReturnRows(sqlmock.NewRows([]string{"kind", "id"}).
+			AddRow("file", fileID).
+			AddRow("folder", folderID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders" WHERE id IN ($1)`)).
+		WithArgs(folderID).
+		WillReturnRows(sqlmock.NewRows(folderColumns).
+			AddRow(folderID, "Old", userID, nil, deletedAt, deletedAt, deletedAt))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE id IN ($1)`)).
+		WithArgs(fileID).
+		WillReturnRows(sqlmock.NewRows(fileColumns).
+			AddRow(fileID, "notes.txt", userID, nil, 12, "text/plain", "key", "", deletedAt, deletedAt, deletedAt))
+
+	repo := &gormTrashRepository{conn: &database.GormConnection{DB: gormDB}}
+	items, total, err := repo.List(context.Background(), userID.String(), 1, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), total)
+	require.Len(t, items, 2)
+	assert.Equal(t, "notes.txt", items[0].File.GetName())
+	assert.Equal(t, "Old", items[1].Folder.GetName())
+	assert.True(t, items[1].DeletedAt.AsTime().Equal(deletedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormTrashRepository_RestoreFolder(t *testing.T) {
+	userID := uuid.New()
+	folderID := uuid.New()
+	parentID := uuid.New()
+	childID := uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+
+	expectDeletedFolder := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders" WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`)).
+			WithArgs(folderID, userID, 1).
+			WillReturnRows(sqlmock.NewRows(folderColumns).
+				AddRow(folderID, "Photos", userID, parentID, deletedAt, deletedAt, deletedAt))
+	}
+
+	tests := []struct {
+		name            string
+		mockSetup       func(sqlmock.Sqlmock)
+		expectedFolders int32
+		expectedFiles   int32
+		expectedError   error
+	}{
+		{
+			name: "restores subtree and deleted parent",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectDeletedFolder(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM chain WHERE deleted_at IS NOT NULL`)).
+					WithArgs(parentID, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(parentID))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "folders" SET "deleted_at"=$1 WHERE id IN ($2)`)).
+					WithArgs(nil, parentID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM subtree`)).
+					WithArgs(folderID, deletedAt, deletedAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(folderID).AddRow(childID))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "folders" SET "deleted_at"=$1 WHERE id IN ($2,$3)`)).
+					WithArgs(nil, folderID, childID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "files" SET "deleted_at"=$1 WHERE folder_id IN ($2,$3) AND deleted_at = $4`)).
+					WithArgs(nil, folderID, childID, deletedAt).
+					WillReturnResult(sqlmock.NewResult(0, 5))
+				mock.ExpectCommit()
+			},
+			expectedFolders: 3,
+			expectedFiles:   5,
+		},
+		{
+			name: "not in trash",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "folders"`)).
+					WillReturnRows(sqlmock.NewRows(folderColumns))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrNotInTrash,
+		},
+		{
+			name: "name taken by a live sibling",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectDeletedFolder(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM chain WHERE deleted_at IS NOT NULL`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM subtree`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(folderID))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "folders" SET "deleted_at"=$1`)).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_folders_unique_name"})
+				mock.ExpectRollback()
+			},
+			expectedError: ErrNameConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormTrashRepository{conn: &database.GormConnection{DB: gormDB}}
+			folders, files, err := repo.RestoreFolder(context.Background(), folderID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedFolders, folders)
+				assert.Equal(t, tt.expectedFiles, files)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormTrashRepository_Empty(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	userID := uuid.New()
+	checksum := strings.Repeat("cd", 32)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM file_versions WHERE file_id IN (`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow("a.v1", checksum))
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM files WHERE user_id = $1 AND deleted_at IS NOT NULL RETURNING storage_key, COALESCE(checksum, '') AS checksum`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).
+			AddRow("a", "").
+			AddRow(BlobKey(checksum), checksum))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM folders WHERE user_id = $1 AND deleted_at IS NOT NULL`)).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &gormTrashRepository{conn: &database.GormConnection{DB: gormDB}}
+	result, err := repo.Empty(context.Background(), userID.String())
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result.Folders)
+	assert.Equal(t, int32(2), result.Files)
+	assert.Equal(t, []string{"a.v1", "a"}, result.StorageKeys, "shared content is left to blob collection")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormTrashRepository_Purge(t *testing.T) {
+	before := time.Now().Add(-30 * 24 * time.Hour)
+	fileA := uuid.New()
+	fileB := uuid.New()
+	checksum := strings.Repeat("cd", 32)
+
+	tests := []struct {
+		name            string
+		mockSetup       func(sqlmock.Sqlmock)
+		expectedFolders int32
+		expectedFiles   int32
+		expectedKeys    []string
+	}{
+		{
+			name: "full batch leaves folders for later",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM files WHERE deleted_at < $1 ORDER BY deleted_at LIMIT $2 FOR UPDATE SKIP LOCKED`)).
+					WithArgs(before, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(fileA).AddRow(fileB))
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM file_versions WHERE file_id IN ($1,$2) RETURNING storage_key, COALESCE(checksum, '') AS checksum`)).
+					WithArgs(fileA, fileB).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow(BlobKey(checksum), checksum))
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM files WHERE id IN ($1,$2) RETURNING storage_key, COALESCE(checksum, '') AS checksum`)).
+					WithArgs(fileA, fileB).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow("a", checksum).AddRow("b", ""))
+				mock.ExpectCommit()
+			},
+			expectedFiles: 2,
+			expectedKeys:  []string{"a", "b"},
+		},
+		{
+			name: "last batch purges folders",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM files WHERE deleted_at < $1`)).
+					WithArgs(before, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(fileA))
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM file_versions WHERE file_id IN ($1) RETURNING storage_key`)).
+					WithArgs(fileA).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}))
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM files WHERE id IN ($1) RETURNING storage_key`)).
+					WithArgs(fileA).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow("a", ""))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM folders WHERE deleted_at < $1`)).
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+			expectedFolders: 3,
+			expectedFiles:   1,
+			expectedKeys:    []string{"a"},
+		},
+		{
+			name: "nothing expired",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM files WHERE deleted_at < $1`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM folders WHERE deleted_at < $1`)).
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormTrashRepository{conn: &database.GormConnection{DB: gormDB}}
+			result, err := repo.Purge(context.Background(), before, 2)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFolders, result.Folders)
+			assert.Equal(t, tt.expectedFiles, result.Files)
+			assert.Equal(t, tt.expectedKeys, result.StorageKeys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
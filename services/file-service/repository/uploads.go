@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// ErrUploadNotFound is returned when a resumable upload does not exist or is not owned by the user
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrUploadOffsetMismatch is returned when a chunk does not start at the upload's current offset
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+)
+
+type UploadRepository interface {
+	Create(ctx context.Context, req *pb.CreateUploadRequest, folderOwnerID string) (*pb.Upload, error)
+	GetByID(ctx context.Context, id, userID string) (*pb.Upload, error)
+	CommitPart(ctx context.Context, id, userID string, offset, size int64, storageKey string) (*pb.Upload, error)
+	Parts(ctx context.Context, id string) ([]domain.UploadPart, error)
+	Delete(ctx context.Context, id, userID string) ([]string, error)
+	Reserved(ctx context.Context, ownerID string, activeSince time.Time) (int64, error)
+	Expire(ctx context.Context, activeSince time.Time, limit int) ([]string, error)
+}
+
+type gormUploadRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormUploadRepositoryFromConnection creates an upload repository from an existing GORM connection
+func NewGormUploadRepositoryFromConnection(conn *database.GormConnection) UploadRepository {
+	return &gormUploadRepository{conn: conn}
+}
+
+// Create starts an upload into a folder of folderOwnerID, who differs from the uploading
+// user when the folder is shared with them
+func (r *gormUploadRepository) Create(ctx context.Context, req *pb.CreateUploadRequest, folderOwnerID string) (*pb.Upload, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(folderOwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid folder owner ID: %w", err)
+	}
+
+	folderID, err := resolveFolder(ctx, r.conn.DB, ownerID, req.FolderId)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &domain.Upload{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Name:     req.Name,
+		FolderID: folderID,
+		MimeType: req.MimeType,
+		Size:     req.Size,
+	}
+
+	if err := r.conn.DB.WithContext(ctx).Create(upload).Error; err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	return domainUploadToProto(upload), nil
+}
+
+func (r *gormUploadRepository) GetByID(ctx context.Context, id, userID string) (*pb.Upload, error) {
+	uploadID, ownerID, err := parseUploadIDs(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var upload domain.Upload
+	if err := r.conn.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", uploadID, ownerID).
+		First(&upload).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	return domainUploadToProto(&upload), nil
+}
+
+// CommitPart records a stored chunk and advances the upload's offset past it.
+// The offset only moves if it still equals offset, so concurrent writers cannot both commit.
+func (r *gormUploadRepository) CommitPart(ctx context.Context, id, userID string, offset, size int64, storageKey string) (*pb.Upload, error) {
+	uploadID, ownerID, err := parseUploadIDs(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var upload domain.Upload
+	err = r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&upload).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND user_id = ? AND upload_offset = ? AND upload_offset + ? <= size", uploadID, ownerID, offset, size).
+			Update("upload_offset", gorm.Expr("upload_offset + ?", size))
+		if result.Error != nil {
+			return fmt.Errorf("failed to advance upload offset: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&domain.Upload{}).
+				Where("id = ? AND user_id = ?", uploadID, ownerID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to get upload: %w", err)
+			}
+			if count == 0 {
+				return ErrUploadNotFound
+			}
+			return ErrUploadOffsetMismatch
+		}
+
+		part := &domain.UploadPart{
+			UploadID:   uploadID,
+			Offset:     offset,
+			Size:       size,
+			StorageKey: storageKey,
+		}
+		if err := tx.Create(part).Error; err != nil {
+			return fmt.Errorf("failed to record upload part: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainUploadToProto(&upload), nil
+}
+
+// Parts returns the chunks of an upload in offset order
+func (r *gormUploadRepository) Parts(ctx context.Context, id string) ([]domain.UploadPart, error) {
+	uploadID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload ID: %w", err)
+	}
+
+	var parts []domain.UploadPart
+	if err := r.conn.DB.WithContext(ctx).
+		Where("upload_id = ?", uploadID).
+		Order("part_offset").
+		Find(&parts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list upload parts: %w", err)
+	}
+
+	return parts, nil
+}
+
+// Delete removes an upload and its part records, returning the storage keys of the parts
+// so the caller can discard their blobs
+func (r *gormUploadRepository) Delete(ctx context.Context, id, userID string) ([]string, error) {
+	uploadID, ownerID, err := parseUploadIDs(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	err = r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.UploadPart{}).
+			Where("upload_id = ?", uploadID).
+			Pluck("storage_key", &keys).Error; err != nil {
+			return fmt.Errorf("failed to list upload parts: %w", err)
+		}
+
+		result := tx.Where("id = ? AND user_id = ?", uploadID, ownerID).Delete(&domain.Upload{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete upload: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrUploadNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Reserved returns the declared size of the uploads into ownerID's storage that have been
+// active since activeSince, whoever is sending them
+func (r *gormUploadRepository) Reserved(ctx context.Context, ownerID string, activeSince time.Time) (int64, error) {
+	owner, err := uuid.Parse(ownerID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid owner ID: %w", err)
+	}
+
+	var reserved int64
+	if err := r.conn.DB.WithContext(ctx).Raw(
+		"SELECT COALESCE(SUM(u.size), 0) FROM uploads u LEFT JOIN folders f ON f.id = u.folder_id "+
+			"WHERE COALESCE(f.user_id, u.user_id) = ? AND u.updated_at >= ?", owner, activeSince).
+		Scan(&reserved).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum open uploads: %w", err)
+	}
+
+	return reserved, nil
+}
+
+// Expire removes up to limit uploads that have not been active since activeSince, along
+// with their part records, and returns their IDs so the caller can discard their chunks
+func (r *gormUploadRepository) Expire(ctx context.Context, activeSince time.Time, limit int) ([]string, error) {
+	var ids []uuid.UUID
+	if err := r.conn.DB.WithContext(ctx).Raw(
+		"DELETE FROM uploads WHERE id IN (SELECT id FROM uploads WHERE updated_at < ? ORDER BY updated_at LIMIT ? FOR UPDATE SKIP LOCKED) RETURNING id",
+		activeSince, limit).
+		Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to expire uploads: %w", err)
+	}
+
+	expired := make([]string, len(ids))
+	for i, id := range ids {
+		expired[i] = id.String()
+	}
+	return expired, nil
+}
+
+func parseUploadIDs(id, userID string) (uuid.UUID, uuid.UUID, error) {
+	uploadID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid upload ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	return uploadID, ownerID, nil
+}
+
+// UploadPartPrefix returns the storage key prefix under which an upload's chunks are written
+func UploadPartPrefix(uploadID string) string {
+	return fmt.Sprintf("uploads/%s/", uploadID)
+}
+
+// domainUploadToProto converts a domain.Upload to pb.Upload
+func domainUploadToProto(upload *domain.Upload) *pb.Upload {
+	pbUpload := &pb.Upload{
+		Id:        upload.ID.String(),
+		UserId:    upload.UserID.String(),
+		Name:      upload.Name,
+		MimeType:  upload.MimeType,
+		Size:      upload.Size,
+		Offset:    upload.Offset,
+		CreatedAt: timestamppb.New(upload.CreatedAt),
+		UpdatedAt: timestamppb.New(upload.UpdatedAt),
+	}
+	if upload.FolderID != nil {
+		pbUpload.FolderId = upload.FolderID.String()
+	}
+
+	return pbUpload
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+	pb "go-drive/proto/file"
+)
+
+var uploadColumns = []string{
+	"id", "user_id", "name", "folder_id", "mime_type", "size",
+	"upload_offset", "created_at", "updated_at",
+}
+
+func TestGormUploadRepository_Create(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	userID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "uploads"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+
+	repo := &gormUploadRepository{conn: &database.GormConnection{DB: gormDB}}
+	upload, err := repo.Create(context.Background(), &pb.CreateUploadRequest{
+		UserId:   userID.String(),
+		Name:     "movie.mp4",
+		MimeType: "video/mp4",
+		Size:     1 << 30,
+	}, userID.String())
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, upload.Id)
+	assert.Equal(t, "movie.mp4", upload.Name)
+	assert.Equal(t, int64(1<<30), upload.Size)
+	assert.Zero(t, upload.Offset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormUploadRepository_GetByID(t *testing.T) {
+	userID := uuid.New()
+	uploadID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "upload found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "uploads" WHERE id = $1 AND user_id = $2`)).
+					WithArgs(uploadID, userID, 1).
+					WillReturnRows(sqlmock.NewRows(uploadColumns).
+						AddRow(uploadID, userID, "movie.mp4", nil, "video/mp4", 100, 40, now, now))
+			},
+		},
+		{
+			name: "upload not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "uploads"`)).
+					WillReturnRows(sqlmock.NewRows(uploadColumns))
+			},
+			expectedError: ErrUploadNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormUploadRepository{conn: &database.GormConnection{DB: gormDB}}
+			upload, err := repo.GetByID(context.Background(), uploadID.String(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(40), upload.Offset)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormUploadRepository_CommitPart(t *testing.T) {
+	userID := uuid.New()
+	uploadID := uuid.New()
+	now := time.Now()
+	key := UploadPartPrefix(uploadID.String()) + "part"
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "offset advanced",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "uploads" SET "upload_offset"=upload_offset + $1,"updated_at"=$2 WHERE id = $3 AND user_id = $4 AND upload_offset = $5 AND upload_offset + $6 <= size RETURNING *`)).
+					WithArgs(int64(60), sqlmock.AnyArg(), uploadID, userID, int64(40), int64(60)).
+					WillReturnRows(sqlmock.NewRows(uploadColumns).
+						AddRow(uploadID, userID, "movie.mp4", nil, "video/mp4", 100, 100, now, now))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "upload_parts"`)).
+					WithArgs(uploadID, int64(40), int64(60), key, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "offset moved",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "uploads"`)).
+					WillReturnRows(sqlmock.NewRows(uploadColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "uploads"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrUploadOffsetMismatch,
+		},
+		{
+			name: "upload not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "uploads"`)).
+					WillReturnRows(sqlmock.NewRows(uploadColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "uploads"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrUploadNotFound,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "uploads"`)).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormUploadRepository{conn: &database.GormConnection{DB: gormDB}}
+			upload, err := repo.CommitPart(context.Background(), uploadID.String(), userID.String(), 40, 60, key)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(100), upload.Offset)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormUploadRepository_Delete(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	userID := uuid.New()
+	uploadID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "storage_key" FROM "upload_parts" WHERE upload_id = $1`)).
+		WithArgs(uploadID).
+		WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("uploads/a").AddRow("uploads/b"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "uploads" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(uploadID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &gormUploadRepository{conn: &database.GormConnection{DB: gormDB}}
+	keys, err := repo.Delete(context.Background(), uploadID.String(), userID.String())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"uploads/a", "uploads/b"}, keys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormUploadRepository_Reserved(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	ownerID := uuid.New()
+	activeSince := time.Now().Add(-24 * time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(u.size), 0) FROM uploads u LEFT JOIN folders f ON f.id = u.folder_id WHERE COALESCE(f.user_id, u.user_id) = $1 AND u.updated_at >= $2`)).
+		WithArgs(ownerID, activeSince).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(int64(3 << 30)))
+
+	repo := &gormUploadRepository{conn: &database.GormConnection{DB: gormDB}}
+	reserved, err := repo.Reserved(context.Background(), ownerID.String(), activeSince)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3<<30), reserved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormUploadRepository_Expire(t *testing.T) {
+	gormDB, mock, cleanup := setupGormMock(t)
+	defer cleanup()
+
+	activeSince := time.Now().Add(-24 * time.Hour)
+	uploadA := uuid.New()
+	uploadB := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM uploads WHERE id IN (SELECT id FROM uploads WHERE updated_at < $1 ORDER BY updated_at LIMIT $2 FOR UPDATE SKIP LOCKED) RETURNING id`)).
+		WithArgs(activeSince, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uploadA).AddRow(uploadB))
+
+	repo := &gormUploadRepository{conn: &database.GormConnection{DB: gormDB}}
+	ids, err := repo.Expire(context.Background(), activeSince, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{uploadA.String(), uploadB.String()}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+)
+
+var (
+	// ErrUserNotFound is returned when the user does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrQuotaExceeded is returned when a change would take a user over their storage quota
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+)
+
+// Usage is a user's type and the bytes their files and file versions take up
+type Usage struct {
+	UserType  string
+	UsedBytes int64
+}
+
+type UsageRepository interface {
+	Get(ctx context.Context, userID string) (*Usage, error)
+}
+
+type gormUsageRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormUsageRepositoryFromConnection creates a usage repository from an existing GORM connection
+func NewGormUsageRepositoryFromConnection(conn *database.GormConnection) UsageRepository {
+	return &gormUsageRepository{conn: conn}
+}
+
+func (r *gormUsageRepository) Get(ctx context.Context, userID string) (*Usage, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var usage []Usage
+	if err := r.conn.DB.WithContext(ctx).Raw(`SELECT u.type AS user_type, COALESCE(s.used_bytes, 0) AS used_bytes
+		FROM users u LEFT JOIN storage_usage s ON s.user_id = u.id
+		WHERE u.id = ? AND u.deleted_at IS NULL`, ownerID).
+		Scan(&usage).Error; err != nil {
+		return nil, fmt.Errorf("failed to get storage usage: %w", err)
+	}
+	if len(usage) == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	return &usage[0], nil
+}
+
+// reserveStorage checks that growing a user's usage by delta bytes stays within limit, where a
+// zero limit is unlimited. It locks the user's usage row until the transaction ends so that
+// concurrent uploads are checked one after another; triggers then record the change itself.
+func reserveStorage(tx *gorm.DB, userID uuid.UUID, delta, limit int64) error {
+	if limit <= 0 || delta <= 0 {
+		return nil
+	}
+
+	var used int64
+	if err := tx.Raw(`INSERT INTO storage_usage (user_id) VALUES (?)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING used_bytes`, userID).
+		Scan(&used).Error; err != nil {
+		return fmt.Errorf("failed to lock storage usage: %w", err)
+	}
+	if used+delta > limit {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
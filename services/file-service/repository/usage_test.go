@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+)
+
+// expectUsage expects the storage usage row of userID to be locked and report used bytes
+func expectUsage(mock sqlmock.Sqlmock, userID uuid.UUID, used int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO storage_usage (user_id) VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING used_bytes`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"used_bytes"}).AddRow(used))
+}
+
+func TestGormUsageRepository_Get(t *testing.T) {
+	userID := uuid.New()
+	query := regexp.QuoteMeta(`SELECT u.type AS user_type, COALESCE(s.used_bytes, 0) AS used_bytes`)
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expected      *Usage
+		expectedError error
+	}{
+		{
+			name: "user with stored files",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"user_type", "used_bytes"}).AddRow("premium", 4096))
+			},
+			expected: &Usage{UserType: "premium", UsedBytes: 4096},
+		},
+		{
+			name: "user not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"user_type", "used_bytes"}))
+			},
+			expectedError: ErrUserNotFound,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := NewGormUsageRepositoryFromConnection(&database.GormConnection{DB: gormDB})
+			usage, err := repo.Get(context.Background(), userID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, usage)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
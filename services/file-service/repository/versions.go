@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrVersionNotFound is returned when a file has no version with the requested ID
+var ErrVersionNotFound = errors.New("file version not found")
+
+type VersionRepository interface {
+	List(ctx context.Context, fileID, userID string, page, pageSize int32) ([]*pb.FileVersion, int32, error)
+	GetByID(ctx context.Context, fileID, versionID, userID string) (*pb.FileVersion, error)
+	Restore(ctx context.Context, fileID, versionID, userID string, typ ContentType) (*pb.File, error)
+	Prune(ctx context.Context, fileID, userID string, keep int32, before time.Time) (int32, []string, error)
+}
+
+type gormVersionRepository struct {
+	conn *database.GormConnection
+}
+
+// NewGormVersionRepositoryFromConnection creates a version repository from an existing GORM connection
+func NewGormVersionRepositoryFromConnection(conn *database.GormConnection) VersionRepository {
+	return &gormVersionRepository{conn: conn}
+}
+
+// List pages through the versions of a live file, most recently replaced first
+func (r *gormVersionRepository) List(ctx context.Context, fileID, userID string, page, pageSize int32) ([]*pb.FileVersion, int32, error) {
+	id, ownerID, err := parseFileIDs(fileID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int64
+	var versions []domain.FileVersion
+	err = acting(ctx, r.conn.DB, func(db *gorm.DB) error {
+		if err := requireFile(db, id, ownerID); err != nil {
+			return err
+		}
+
+		query := db.Model(&domain.FileVersion{}).Where("file_id = ?", id)
+		if err := query.Count(&totalCount).Error; err != nil {
+			return fmt.Errorf("failed to count file versions: %w", err)
+		}
+
+		if err := query.
+			Order("created_at DESC, id").
+			Limit(int(pageSize)).
+			Offset(int((page - 1) * pageSize)).
+			Find(&versions).Error; err != nil {
+			return fmt.Errorf("failed to list file versions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pbVersions := make([]*pb.FileVersion, len(versions))
+	for i := range versions {
+		pbVersions[i] = domainVersionToProto(&versions[i])
+	}
+
+	return pbVersions, int32(totalCount), nil
+}
+
+func (r *gormVersionRepository) GetByID(ctx context.Context, fileID, versionID, userID string) (*pb.FileVersion, error) {
+	id, ownerID, err := parseFileIDs(fileID, userID)
+	if err != nil {
+		return nil, err
+	}
+	vid, err := uuid.Parse(versionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version ID: %w", err)
+	}
+
+	var version domain.FileVersion
+	err = acting(ctx, r.conn.DB, func(db *gorm.DB) error {
+		if err := requireFile(db, id, ownerID); err != nil {
+			return err
+		}
+		if err := db.Where("id = ? AND file_id = ?", vid, id).First(&version).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrVersionNotFound
+			}
+			return fmt.Errorf("failed to get file version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainVersionToProto(&version), nil
+}
+
+// Restore makes a version the file's current content. The content it replaces is kept as
+// a new version and the restored version is removed from the history. typ is the type of
+// the restored content; a version already found clean is not scanned again.
+func (r *gormVersionRepository) Restore(ctx context.Context, fileID, versionID, userID string, typ ContentType) (*pb.File, error) {
+	id, ownerID, err := parseFileIDs(fileID, userID)
+	if err != nil {
+		return nil, err
+	}
+	vid, err := uuid.Parse(versionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version ID: %w", err)
+	}
+
+	var file *domain.File
+	err = actingTransaction(ctx, r.conn.DB, func(tx *gorm.DB) error {
+		if file, err = lockFile(tx, id, ownerID); err != nil {
+			return err
+		}
+
+		var version domain.FileVersion
+		if err := tx.Where("id = ? AND file_id = ?", vid, id).First(&version).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrVersionNotFound
+			}
+			return fmt.Errorf("failed to get file version: %w", err)
+		}
+
+		if err := archiveContent(tx, file); err != nil {
+			return err
+		}
+		if err := tx.Delete(&version).Error; err != nil {
+			return fmt.Errorf("failed to delete restored version: %w", err)
+		}
+		if version.ScanStatus == domain.ScanClean {
+			typ.ScanStatus = domain.ScanClean
+		}
+		return setContent(tx, file, version.StorageKey, version.Size, version.Checksum, typ)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFileToProto(file), nil
+}
+
+// Prune deletes the versions of a file beyond the keep newest ones and those replaced
+// before the given time. A zero keep or before leaves that criterion out. It returns the
+// number of versions deleted and the storage keys of their content not shared through a blob.
+func (r *gormVersionRepository) Prune(ctx context.Context, fileID, userID string, keep int32, before time.Time) (int32, []string, error) {
+	id, ownerID, err := parseFileIDs(fileID, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var conditions []string
+	args := []interface{}{id}
+	if keep > 0 {
+		conditions = append(conditions, "id NOT IN (SELECT id FROM file_versions WHERE file_id = ? ORDER BY created_at DESC, id LIMIT ?)")
+		args = append(args, id, keep)
+	}
+	if !before.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, before)
+	}
+	if len(conditions) == 0 {
+		return 0, nil, nil
+	}
+
+	var pruned []storedContent
+	err = acting(ctx, r.conn.DB, func(db *gorm.DB) error {
+		if err := requireFile(db, id, ownerID); err != nil {
+			return err
+		}
+		if err := db.Raw("DELETE FROM file_versions WHERE file_id = ? AND ("+strings.Join(conditions, " OR ")+") RETURNING storage_key, COALESCE(checksum, '') AS checksum", args...).
+			Scan(&pruned).Error; err != nil {
+			return fmt.Errorf("failed to prune file versions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return int32(len(pruned)), unsharedKeys(pruned), nil
+}
+
+// requireFile checks that a live file exists and belongs to the user
+func requireFile(db *gorm.DB, fileID, userID uuid.UUID) error {
+	var count int64
+	if err := db.Model(&domain.File{}).
+		Where("id = ? AND user_id = ?", fileID, userID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to get file: %w", err)
+	}
+	if count == 0 {
+		return ErrFileNotFound
+	}
+	return nil
+}
+
+// parseFileIDs parses the file and owner IDs of a request
+func parseFileIDs(fileID, userID string) (uuid.UUID, uuid.UUID, error) {
+	id, err := uuid.Parse(fileID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid file ID: %w", err)
+	}
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	return id, ownerID, nil
+}
+
+// domainVersionToProto converts a domain.FileVersion to pb.FileVersion
+func domainVersionToProto(version *domain.FileVersion) *pb.FileVersion {
+	return &pb.FileVersion{
+		Id:         version.ID.String(),
+		FileId:     version.FileID.String(),
+		UserId:     version.UserID.String(),
+		Size:       version.Size,
+		Checksum:   version.Checksum,
+		StorageKey: version.StorageKey,
+		CreatedAt:  timestamppb.New(version.CreatedAt),
+		ScanStatus: version.ScanStatus,
+	}
+}
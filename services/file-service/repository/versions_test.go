@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-drive/internal/database"
+	"go-drive/internal/domain"
+)
+
+var versionColumns = []string{"id", "file_id", "user_id", "size", "storage_key", "checksum", "scan_status", "created_at"}
+
+func TestGormVersionRepository_Restore(t *testing.T) {
+	userID := uuid.New()
+	fileID := uuid.New()
+	versionID := uuid.New()
+	now := time.Now()
+	currentKey := ContentKey(userID, fileID)
+	oldKey := StorageKey(userID, fileID)
+
+	expectLockedFile := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE (id = $1 AND user_id = $2) AND "files"."deleted_at" IS NULL ORDER BY "files"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(fileID, userID, 1).
+			WillReturnRows(sqlmock.NewRows(fileColumns).
+				AddRow(fileID, "report.pdf", userID, nil, 2048, "application/pdf", currentKey, "new", now, now, nil))
+	}
+
+	// expectRestore expects the version to be swapped with the current content, leaving
+	// the file with the given scan status
+	expectRestore := func(mock sqlmock.Sqlmock, versionStatus, fileStatus string) {
+		mock.ExpectBegin()
+		expectLockedFile(mock)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "file_versions" WHERE id = $1 AND file_id = $2`)).
+			WithArgs(versionID, fileID, 1).
+			WillReturnRows(sqlmock.NewRows(versionColumns).
+				AddRow(versionID, fileID, userID, 1024, oldKey, "old", versionStatus, now.Add(-time.Hour)))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "file_versions"`)).
+			WithArgs(fileID, userID, int64(2048), currentKey, "new", domain.ScanPending, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "file_versions" WHERE "file_versions"."id" = $1`)).
+			WithArgs(versionID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "files" SET "checksum"=$1,"mime_mismatch"=$2,"mime_type"=$3,"quarantine_reason"=$4,"quarantined"=$5,"scan_status"=$6,"size"=$7,"storage_key"=$8`)).
+			WithArgs("old", false, "application/pdf", "", false, fileStatus, int64(1024), oldKey, sqlmock.AnyArg(), fileID).
+			WillReturnRows(sqlmock.NewRows(fileColumns).
+				AddRow(fileID, "report.pdf", userID, nil, 1024, "application/pdf", oldKey, "old", now, now, nil))
+		mock.ExpectCommit()
+	}
+
+	tests := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "swaps current content with the version",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectRestore(mock, domain.ScanClean, domain.ScanClean)
+			},
+		},
+		{
+			name: "version not scanned yet is queued for a scan",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectRestore(mock, domain.ScanPending, domain.ScanPending)
+			},
+		},
+		{
+			name: "version not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLockedFile(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "file_versions"`)).
+					WillReturnRows(sqlmock.NewRows(versionColumns))
+				mock.ExpectRollback()
+			},
+			expectedError: ErrVersionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormVersionRepository{conn: &database.GormConnection{DB: gormDB}}
+			file, err := repo.Restore(context.Background(), fileID.String(), versionID.String(), userID.String(), ContentType{MimeType: "application/pdf", ScanStatus: domain.ScanPending})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, oldKey, file.StorageKey)
+				assert.Equal(t, "old", file.Checksum)
+				assert.Equal(t, int64(1024), file.Size)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGormVersionRepository_Prune(t *testing.T) {
+	userID := uuid.New()
+	fileID := uuid.New()
+	cutoff := time.Now().Add(-7 * 24 * time.Hour)
+	checksum := strings.Repeat("ab", 32)
+
+	expectFile := func(mock sqlmock.Sqlmock, count int) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "files" WHERE (id = $1 AND user_id = $2) AND "files"."deleted_at" IS NULL`)).
+			WithArgs(fileID, userID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name           string
+		keep           int32
+		before         time.Time
+		mockSetup      func(sqlmock.Sqlmock)
+		expectedPruned int32
+		expectedKeys   []string
+		expectedError  error
+	}{
+		{
+			name: "by count",
+			keep: 3,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectFile(mock, 1)
+				mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM file_versions WHERE file_id = $1 AND (id NOT IN (SELECT id FROM file_versions WHERE file_id = $2 ORDER BY created_at DESC, id LIMIT $3)) RETURNING storage_key, COALESCE(checksum, '') AS checksum`)).
+					WithArgs(fileID, fileID, int32(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).
+						AddRow("a", checksum).
+						AddRow(BlobKey(checksum), checksum))
+			},
+			expectedPruned: 2,
+			expectedKeys:   []string{"a"},
+		},
+		{
+			name:   "by count or age",
+			keep:   3,
+			before: cutoff,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectFile(mock, 1)
+				mock.ExpectQuery(regexp.QuoteMeta(`LIMIT $3) OR created_at < $4) RETURNING storage_key`)).
+					WithArgs(fileID, fileID, int32(3), cutoff).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key", "checksum"}).AddRow("a", ""))
+			},
+			expectedPruned: 1,
+			expectedKeys:   []string{"a"},
+		},
+		{
+			name:   "file not found",
+			before: cutoff,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectFile(mock, 0)
+			},
+			expectedError: ErrFileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, mock, cleanup := setupGormMock(t)
+			defer cleanup()
+
+			tt.mockSetup(mock)
+
+			repo := &gormVersionRepository{conn: &database.GormConnection{DB: gormDB}}
+			pruned, keys, err := repo.Prune(context.Background(), fileID.String(), userID.String(), tt.keep, tt.before)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedPruned, pruned)
+				assert.Equal(t, tt.expectedKeys, keys)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/keyring"
+	"go-drive/internal/sigv4"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const (
+	// accessKeyIDPrefix starts the public IDs of access keys
+	accessKeyIDPrefix = "GD"
+	// accessKeyIDBytes is the number of random bytes in an access key ID, which comes out
+	// at 20 characters like the IDs of AWS keys
+	accessKeyIDBytes = 11
+	// secretKeyBytes is the number of random bytes in a secret, which comes out at 40
+	// characters like AWS secrets
+	secretKeyBytes = 30
+	// maxAccessKeyIDLength bounds the access key IDs that are looked up
+	maxAccessKeyIDLength = 32
+	// maxAccessKeyName is the longest description an access key can have
+	maxAccessKeyName = 255
+	// s3Service is the only service access keys sign requests for
+	s3Service = "s3"
+	// resealBatchSize caps the access keys resealed per transaction
+	resealBatchSize = 100
+)
+
+// CreateAccessKey creates an access key for the S3-compatible API. The secret is returned
+// only here.
+func (s *FileService) CreateAccessKey(ctx context.Context, req *pb.CreateAccessKeyRequest) (*pb.CreateAccessKeyResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if len(req.Name) > maxAccessKeyName {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d bytes", maxAccessKeyName)
+	}
+
+	id := make([]byte, accessKeyIDBytes)
+	secret := make([]byte, secretKeyBytes)
+	if _, err := rand.Read(id); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate access key: %v", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate access key: %v", err)
+	}
+	accessKeyID := accessKeyIDPrefix + base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(id)
+	secretKey := base64.StdEncoding.EncodeToString(secret)
+
+	sealed, err := s.sealSecret(accessKeyID, secretKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create access key: %v", err)
+	}
+	key, err := s.accessKeys.Create(ctx, req.UserId, req.Name, accessKeyID, sealed)
+	if err != nil {
+		return nil, repoError("create access key", err)
+	}
+
+	return &pb.CreateAccessKeyResponse{Key: key, SecretAccessKey: secretKey}, nil
+}
+
+func (s *FileService) ListAccessKeys(ctx context.Context, req *pb.ListAccessKeysRequest) (*pb.ListAccessKeysResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	keys, totalCount, err := s.accessKeys.List(ctx, req.UserId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list access keys", err)
+	}
+
+	return &pb.ListAccessKeysResponse{
+		Keys:       keys,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (s *FileService) RevokeAccessKey(ctx context.Context, req *pb.RevokeAccessKeyRequest) (*pb.RevokeAccessKeyResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.accessKeys.Revoke(ctx, req.Id, req.UserId); err != nil {
+		return nil, repoError("revoke access key", err)
+	}
+
+	return &pb.RevokeAccessKeyResponse{Message: "Access key revoked successfully"}, nil
+}
+
+// GetSigningKey derives the SigV4 signing key of an access key for the scope a request was
+// signed for. A signing key only checks signatures of that one day, region and service, and
+// the secret cannot be recovered from it.
+func (s *FileService) GetSigningKey(ctx context.Context, req *pb.GetSigningKeyRequest) (*pb.GetSigningKeyResponse, error) {
+	if req.AccessKeyId == "" || len(req.AccessKeyId) > maxAccessKeyIDLength {
+		return nil, status.Error(codes.InvalidArgument, "access_key_id is required")
+	}
+	date, err := time.Parse(sigv4.DateFormat, req.Date)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "date must be formatted as YYYYMMDD")
+	}
+	if req.Region == "" {
+		return nil, status.Error(codes.InvalidArgument, "region is required")
+	}
+	if req.Service != s3Service {
+		return nil, status.Errorf(codes.InvalidArgument, "access keys only sign requests for %q", s3Service)
+	}
+
+	stored, err := s.accessKeys.GetByAccessKeyID(ctx, req.AccessKeyId)
+	if err != nil {
+		return nil, repoError("get access key", err)
+	}
+	secret, err := s.openSecret(stored)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read access key: %v", err)
+	}
+
+	return &pb.GetSigningKeyResponse{
+		UserId:     stored.Key.UserId,
+		SigningKey: sigv4.SigningKey(secret, date, req.Region, req.Service),
+	}, nil
+}
+
+// ResealAccessKeys wraps the secrets of access keys with the keyring's active master key,
+// both those wrapped with an earlier key and those stored before a keyring was configured,
+// so that earlier keys can be removed from the keyring. It does nothing without a keyring
+// and returns the number of secrets resealed.
+func (s *FileService) ResealAccessKeys(ctx context.Context) (int, error) {
+	if s.keys == nil {
+		return 0, nil
+	}
+	reseal := func(key *repository.StoredAccessKey) (repository.SealedSecret, error) {
+		secret, err := s.openSecret(key)
+		if err != nil {
+			return repository.SealedSecret{}, err
+		}
+		return s.sealSecret(key.Key.AccessKeyId, secret)
+	}
+
+	var total int
+	for {
+		n, err := s.accessKeys.Reseal(ctx, s.keys.Active(), resealBatchSize, reseal)
+		total += n
+		if err != nil || n < resealBatchSize {
+			return total, err
+		}
+	}
+}
+
+// sealSecret wraps an access key's secret with the keyring's active master key. The wrapped
+// secret is bound to the access key ID, so it cannot be moved to another key. Without a
+// keyring the secret is kept as it is.
+func (s *FileService) sealSecret(accessKeyID, secret string) (repository.SealedSecret, error) {
+	if s.keys == nil {
+		return repository.SealedSecret{Secret: secret}, nil
+	}
+	wrapped, err := s.keys.Wrap([]byte(secret), []byte(accessKeyID))
+	if err != nil {
+		return repository.SealedSecret{}, err
+	}
+	return repository.SealedSecret{
+		Secret:      base64.StdEncoding.EncodeToString(wrapped.Ciphertext),
+		MasterKeyID: wrapped.MasterKeyID,
+	}, nil
+}
+
+// openSecret returns the secret of a stored access key, unwrapping it if it is sealed
+func (s *FileService) openSecret(key *repository.StoredAccessKey) (string, error) {
+	if key.Secret.MasterKeyID == "" {
+		return key.Secret.Secret, nil
+	}
+	if s.keys == nil {
+		return "", errors.New("access key secret is encrypted, but no keyring is configured")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(key.Secret.Secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode access key secret: %w", err)
+	}
+	secret, err := s.keys.Unwrap(keyring.WrappedKey{MasterKeyID: key.Secret.MasterKeyID, Ciphertext: ciphertext}, []byte(key.Key.AccessKeyId))
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/keyring"
+	"go-drive/internal/sigv4"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockAccessKeyRepository is a mock implementation of AccessKeyRepository
+type MockAccessKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccessKeyRepository) Create(ctx context.Context, userID, name, accessKeyID string, secret repository.SealedSecret) (*pb.AccessKey, error) {
+	args := m.Called(ctx, userID, name, accessKeyID, secret)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.AccessKey), args.Error(1)
+}
+
+func (m *MockAccessKeyRepository) GetByAccessKeyID(ctx context.Context, accessKeyID string) (*repository.StoredAccessKey, error) {
+	args := m.Called(ctx, accessKeyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.StoredAccessKey), args.Error(1)
+}
+
+func (m *MockAccessKeyRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.AccessKey, int32, error) {
+	args := m.Called(ctx, userID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.AccessKey), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockAccessKeyRepository) Revoke(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccessKeyRepository) Reseal(ctx context.Context, masterKeyID string, limit int, reseal func(key *repository.StoredAccessKey) (repository.SealedSecret, error)) (int, error) {
+	args := m.Called(ctx, masterKeyID, limit, reseal)
+	return args.Int(0), args.Error(1)
+}
+
+// keyringEntry returns a keyring entry with a new key under id
+func keyringEntry(t *testing.T, id string) string {
+	t.Helper()
+	entry, err := keyring.Generate(id)
+	require.NoError(t, err)
+	return entry
+}
+
+// testKeyring returns a keyring of the given entries, the first one active
+func testKeyring(t *testing.T, entries ...string) *keyring.Keyring {
+	t.Helper()
+	keys, err := keyring.Parse(strings.Join(entries, ","))
+	require.NoError(t, err)
+	return keys
+}
+
+func TestFileService_CreateAccessKey(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	keys := new(MockAccessKeyRepository)
+	service.accessKeys = keys
+	var accessKeyID, secret string
+	keys.On("Create", mock.Anything, testUserID, "rclone", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			accessKeyID, secret = args.String(3), args.Get(4).(repository.SealedSecret).Secret
+		}).
+		Return(&pb.AccessKey{Id: testFileID, UserId: testUserID, Name: "rclone"}, nil)
+
+	resp, err := service.CreateAccessKey(context.Background(), &pb.CreateAccessKeyRequest{UserId: testUserID, Name: "rclone"})
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^GD[A-Z2-7]{18}$`, accessKeyID)
+	assert.Len(t, secret, 40)
+	assert.Equal(t, secret, resp.SecretAccessKey, "the secret is returned once")
+	keys.AssertExpectations(t)
+
+	_, err = service.CreateAccessKey(context.Background(), &pb.CreateAccessKeyRequest{UserId: "invalid"})
+	assertStatusCode(t, err, codes.InvalidArgument)
+}
+
+func TestFileService_GetSigningKey(t *testing.T) {
+	const accessKeyID, secret = "GDEXAMPLEKEY", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	valid := &pb.GetSigningKeyRequest{AccessKeyId: accessKeyID, Date: "20260301", Region: "us-east-1", Service: "s3"}
+
+	tests := []struct {
+		name         string
+		req          *pb.GetSigningKeyRequest
+		stored       *repository.StoredAccessKey
+		err          error
+		expectedCode codes.Code
+	}{
+		{
+			name:   "key of the scope",
+			req:    valid,
+			stored: &repository.StoredAccessKey{Key: &pb.AccessKey{UserId: testUserID, AccessKeyId: accessKeyID}, Secret: repository.SealedSecret{Secret: secret}},
+		},
+		{name: "revoked key", req: valid, err: repository.ErrAccessKeyNotFound, expectedCode: codes.NotFound},
+		{
+			name:         "malformed date",
+			req:          &pb.GetSigningKeyRequest{AccessKeyId: accessKeyID, Date: "2026-03-01", Region: "us-east-1", Service: "s3"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "other service",
+			req:          &pb.GetSigningKeyRequest{AccessKeyId: accessKeyID, Date: "20260301", Region: "us-east-1", Service: "iam"},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			keys := new(MockAccessKeyRepository)
+			service.accessKeys = keys
+			if tt.stored != nil || tt.err != nil {
+				keys.On("GetByAccessKeyID", mock.Anything, accessKeyID).Return(tt.stored, tt.err)
+			}
+
+			resp, err := service.GetSigningKey(context.Background(), tt.req)
+
+			if tt.expectedCode != codes.OK {
+				assertStatusCode(t, err, tt.expectedCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testUserID, resp.UserId)
+			assert.Equal(t, sigv4.SigningKey(secret, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "us-east-1", "s3"), resp.SigningKey)
+			keys.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_AccessKeySecretsSealed(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	service.keys = testKeyring(t, keyringEntry(t, "k1"))
+	keys := new(MockAccessKeyRepository)
+	service.accessKeys = keys
+	var stored repository.StoredAccessKey
+	keys.On("Create", mock.Anything, testUserID, "rclone", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			stored.Key = &pb.AccessKey{UserId: testUserID, AccessKeyId: args.String(3)}
+			stored.Secret = args.Get(4).(repository.SealedSecret)
+		}).
+		Return(&pb.AccessKey{Id: testFileID, UserId: testUserID, Name: "rclone"}, nil)
+
+	created, err := service.CreateAccessKey(context.Background(), &pb.CreateAccessKeyRequest{UserId: testUserID, Name: "rclone"})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", stored.Secret.MasterKeyID)
+	assert.NotContains(t, stored.Secret.Secret, created.SecretAccessKey, "the secret is not stored as it is")
+
+	keys.On("GetByAccessKeyID", mock.Anything, stored.Key.AccessKeyId).Return(&stored, nil)
+	resp, err := service.GetSigningKey(context.Background(), &pb.GetSigningKeyRequest{
+		AccessKeyId: stored.Key.AccessKeyId, Date: "20260301", Region: "us-east-1", Service: "s3",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, sigv4.SigningKey(created.SecretAccessKey, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "us-east-1", "s3"), resp.SigningKey)
+
+	t.Run("sealed secret of another key", func(t *testing.T) {
+		moved := &repository.StoredAccessKey{Key: &pb.AccessKey{UserId: testUserID, AccessKeyId: "GDOTHERKEY"}, Secret: stored.Secret}
+		keys.On("GetByAccessKeyID", mock.Anything, "GDOTHERKEY").Return(moved, nil)
+
+		_, err := service.GetSigningKey(context.Background(), &pb.GetSigningKeyRequest{
+			AccessKeyId: "GDOTHERKEY", Date: "20260301", Region: "us-east-1", Service: "s3",
+		})
+		assertStatusCode(t, err, codes.Internal)
+	})
+}
+
+func TestFileService_ResealAccessKeys(t *testing.T) {
+	const accessKeyID, secret = "GDEXAMPLEKEY", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	oldEntry := keyringEntry(t, "k1")
+
+	tests := []struct {
+		name   string
+		before *keyring.Keyring
+	}{
+		{name: "secret wrapped with a rotated key", before: testKeyring(t, oldEntry)},
+		{name: "secret stored before encryption was turned on"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			service.keys = tt.before
+			sealed, err := service.sealSecret(accessKeyID, secret)
+			require.NoError(t, err)
+			stored := &repository.StoredAccessKey{Key: &pb.AccessKey{AccessKeyId: accessKeyID}, Secret: sealed}
+
+			// k2 is put first in the keyring, and k1 stays to unwrap what it wrapped
+			newEntry := keyringEntry(t, "k2")
+			service.keys = testKeyring(t, newEntry, oldEntry)
+			keys := new(MockAccessKeyRepository)
+			service.accessKeys = keys
+			keys.On("Reseal", mock.Anything, "k2", resealBatchSize, mock.Anything).
+				Run(func(args mock.Arguments) {
+					reseal := args.Get(3).(func(*repository.StoredAccessKey) (repository.SealedSecret, error))
+					resealed, err := reseal(stored)
+					require.NoError(t, err)
+					assert.Equal(t, "k2", resealed.MasterKeyID)
+					stored.Secret = resealed
+				}).
+				Return(1, nil)
+
+			n, err := service.ResealAccessKeys(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+			service.keys = testKeyring(t, newEntry)
+			opened, err := service.openSecret(stored)
+			require.NoError(t, err, "k1 can be removed from the keyring")
+			assert.Equal(t, secret, opened)
+			keys.AssertExpectations(t)
+		})
+	}
+
+	t.Run("without a keyring", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		keys := new(MockAccessKeyRepository)
+		service.accessKeys = keys
+
+		n, err := service.ResealAccessKeys(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		keys.AssertNotCalled(t, "Reseal", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
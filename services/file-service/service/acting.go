@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go-drive/services/file-service/repository"
+)
+
+// ActingUserInterceptor makes the repository calls of a unary RPC act for the user the
+// request is made by, so that the database's row level security policies hold them to
+// what the user may access. Requests without a user, such as those of anonymous link
+// visitors, act for the service itself. Streaming RPCs set their acting user themselves.
+func ActingUserInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if r, ok := req.(interface{ GetUserId() string }); ok && r.GetUserId() != "" {
+		ctx = repository.ActingAs(ctx, r.GetUserId())
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/archive"
+	"go-drive/internal/domain"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const (
+	// maxArchiveEntries caps the entries of an archive that is listed or extracted
+	maxArchiveEntries = 10000
+	// maxExtractedSize caps what one extraction writes, whatever the owner's quota
+	maxExtractedSize = 50 << 30
+	// maxExtractNameTries bounds the numbered names tried when the extracted folder's name is taken
+	maxExtractNameTries = 100
+)
+
+// ListArchiveEntries lists a ZIP or TAR archive. A ZIP's central directory and a plain TAR's
+// headers are read through ranged reads, so the content of the entries is never fetched;
+// a gzip-compressed TAR has to be decompressed as it is read.
+func (s *FileService) ListArchiveEntries(ctx context.Context, req *pb.ListArchiveEntriesRequest) (*pb.ListArchiveEntriesResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	file, format, err := s.archiveFile(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := archive.List(storage.NewReaderAt(ctx, s.blobs, file.StorageKey, file.Size), file.Size, format,
+		archive.Limits{MaxEntries: maxArchiveEntries})
+	if err != nil {
+		return nil, archiveError(err)
+	}
+
+	resp := &pb.ListArchiveEntriesResponse{Entries: make([]*pb.ArchiveEntry, len(entries))}
+	for i, entry := range entries {
+		resp.Entries[i] = &pb.ArchiveEntry{Path: entry.Path, IsFolder: entry.Dir, Size: entry.Size}
+		if !entry.Modified.IsZero() {
+			resp.Entries[i].ModifiedAt = timestamppb.New(entry.Modified)
+		}
+		resp.TotalSize += entry.Size
+	}
+	return resp, nil
+}
+
+// ExtractArchive unpacks a ZIP or TAR archive into a new folder named after it. The archive
+// is listed first, so one that is unsafe, too large for the owner's remaining storage or
+// beyond the limits on entries and size is refused before anything is written. Files are
+// then stored and committed one by one like uploads, and so are typed, scanned and indexed.
+// Files whose name, size or type cannot be stored, and entries whose names clash, are
+// skipped. Should extraction fail part way, the new folder is moved to the trash.
+func (s *FileService) ExtractArchive(ctx context.Context, req *pb.ExtractArchiveRequest) (*pb.ExtractArchiveResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.ParentId != "" {
+		if err := validateID("parent_id", req.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	file, format, err := s.archiveFile(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := s.authorizeParent(ctx, req.ParentId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.usage.Get(ctx, owner)
+	if err != nil {
+		return nil, repoError("get usage", err)
+	}
+	quota := s.quotaFor(usage.UserType)
+
+	src := storage.NewReaderAt(ctx, s.blobs, file.StorageKey, file.Size)
+	limits := archive.Limits{MaxEntries: maxArchiveEntries, MaxSize: maxExtractedSize}
+	entries, err := archive.List(src, file.Size, format, limits)
+	if err != nil {
+		return nil, archiveError(err)
+	}
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+	if quota.Storage > 0 && usage.UsedBytes+total > quota.Storage {
+		return nil, status.Errorf(codes.ResourceExhausted, "extracting needs %d bytes but only %d remain in the storage quota",
+			total, max(quota.Storage-usage.UsedBytes, 0))
+	}
+
+	root, err := s.createExtractFolder(ctx, archive.TrimExtension(file.Name), owner, req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &extraction{
+		s:       s,
+		owner:   owner,
+		quota:   quota,
+		folders: map[string]string{"": root.Id},
+		resp:    &pb.ExtractArchiveResponse{Folder: root},
+	}
+	if err := archive.Walk(src, file.Size, format, limits, func(entry archive.Entry, content io.Reader) error {
+		return x.extract(ctx, entry, content)
+	}); err != nil {
+		if _, _, delErr := s.folders.Delete(context.Background(), root.Id, owner); delErr != nil {
+			log.Printf("Failed to remove folder %s after failed extraction: %v", root.Id, delErr)
+		}
+		return nil, archiveError(err)
+	}
+
+	return x.resp, nil
+}
+
+// archiveFile looks up an archive the user may read, along with its format
+func (s *FileService) archiveFile(ctx context.Context, fileID, userID string) (*pb.File, archive.Format, error) {
+	owner, err := s.authorizeFile(ctx, fileID, userID, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, archive.FormatNone, err
+	}
+	file, err := s.repo.GetByID(ctx, fileID, owner)
+	if err != nil {
+		return nil, archive.FormatNone, repoError("get file", err)
+	}
+	if err := checkDownloadable(file); err != nil {
+		return nil, archive.FormatNone, err
+	}
+	if file.Checksum == "" {
+		return nil, archive.FormatNone, status.Error(codes.FailedPrecondition, "file has no content yet")
+	}
+	format := archive.FormatOf(file.MimeType, file.Name)
+	if format == archive.FormatNone {
+		return nil, archive.FormatNone, status.Error(codes.FailedPrecondition, "file is not a ZIP or TAR archive")
+	}
+	return file, format, nil
+}
+
+// createExtractFolder creates the folder an archive is extracted into, numbering its name
+// when the name is taken
+func (s *FileService) createExtractFolder(ctx context.Context, name, owner, parentID string) (*pb.Folder, error) {
+	for n := 1; n <= maxExtractNameTries; n++ {
+		candidate := name
+		if n > 1 {
+			candidate = numberedName(name, n)
+		}
+		folder, err := s.folders.Create(ctx, &pb.CreateFolderRequest{Name: candidate, UserId: owner, ParentId: parentID})
+		if err == nil {
+			return folder, nil
+		}
+		if !errors.Is(err, repository.ErrNameConflict) {
+			return nil, repoError("create folder", err)
+		}
+	}
+	return nil, status.Error(codes.AlreadyExists, "no free name for the extracted folder")
+}
+
+// extraction tracks the folders created while extracting an archive
+type extraction struct {
+	s     *FileService
+	owner string
+	quota Quota
+	// folders maps the paths of extracted folders to their IDs; "" is the extraction's root
+	folders map[string]string
+	resp    *pb.ExtractArchiveResponse
+}
+
+// extract stores one entry of the archive
+func (x *extraction) extract(ctx context.Context, entry archive.Entry, content io.Reader) error {
+	if !storablePath(entry.Path) {
+		if !entry.Dir {
+			x.resp.Skipped++
+		}
+		return nil
+	}
+	if entry.Dir {
+		// A clash with a file of the same name leaves the folder's files out instead
+		if _, err := x.folder(ctx, entry.Path); err != nil && !errors.Is(err, repository.ErrNameConflict) {
+			return err
+		}
+		return nil
+	}
+
+	name := path.Base(entry.Path)
+	mimeType, _, _ := strings.Cut(mime.TypeByExtension(path.Ext(name)), ";")
+	if checkFileSize(x.quota, entry.Size) != nil || x.s.checkFileType(name, mimeType) != nil {
+		x.resp.Skipped++
+		return nil
+	}
+	parentID, err := x.folder(ctx, path.Dir(entry.Path))
+	if err == nil {
+		err = x.file(ctx, parentID, name, mimeType, entry.Size, content)
+	}
+	if errors.Is(err, repository.ErrNameConflict) {
+		x.resp.Skipped++
+		return nil
+	}
+	return err
+}
+
+// file stores the content of an entry as a new file
+func (x *extraction) file(ctx context.Context, folderID, name, mimeType string, size int64, content io.Reader) error {
+	file, err := x.s.repo.Create(ctx, &pb.CreateFileRequest{
+		Name:     name,
+		UserId:   x.owner,
+		FolderId: folderID,
+		Size:     size,
+		MimeType: mimeType,
+	}, x.quota.Storage)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	info, err := x.s.blobs.Put(ctx, file.StorageKey, io.TeeReader(content, hash), size)
+	if err != nil {
+		x.s.discardFile(file)
+		return err
+	}
+	if _, err := x.s.commitContent(ctx, file, file.StorageKey, info.Size, hex.EncodeToString(hash.Sum(nil)), x.quota.Storage); err != nil {
+		x.s.discardFile(file)
+		if errors.Is(err, errFileTypeBlocked) {
+			x.resp.Skipped++
+			return nil
+		}
+		return err
+	}
+	x.resp.ExtractedFiles++
+	return nil
+}
+
+// folder returns the ID of the extracted folder at p, creating it and its parents as needed
+func (x *extraction) folder(ctx context.Context, p string) (string, error) {
+	if p == "." {
+		p = ""
+	}
+	if id, ok := x.folders[p]; ok {
+		return id, nil
+	}
+	parentID, err := x.folder(ctx, path.Dir(p))
+	if err != nil {
+		return "", err
+	}
+	folder, err := x.s.folders.Create(ctx, &pb.CreateFolderRequest{Name: path.Base(p), UserId: x.owner, ParentId: parentID})
+	if err != nil {
+		return "", err
+	}
+	x.folders[p] = folder.Id
+	x.resp.ExtractedFolders++
+	return folder.Id, nil
+}
+
+// storablePath reports whether every segment of an entry's path makes a valid name and
+// the path is not deeper than paths may be
+func storablePath(p string) bool {
+	segments := strings.Split(p, "/")
+	if len(segments) > maxPathDepth {
+		return false
+	}
+	for _, segment := range segments {
+		if validateName("path", segment) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// archiveError maps a failure to read or extract an archive to a gRPC status
+func archiveError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, archive.ErrUnsafePath), errors.Is(err, archive.ErrCorrupt),
+		errors.Is(err, archive.ErrTooManyEntries), errors.Is(err, archive.ErrTooLarge):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, storage.ErrNotFound):
+		return status.Error(codes.NotFound, "file content not found")
+	}
+	return repoError("extract archive", err)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// storeZip stores a ZIP archive of entries, given as name and content pairs, and returns
+// the archive's file
+func storeZip(t *testing.T, service *FileService, entries ...string) *pb.File {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < len(entries); i += 2 {
+		w, err := zw.Create(entries[i])
+		require.NoError(t, err)
+		_, err = io.WriteString(w, entries[i+1])
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	file := scannedFile()
+	file.Name, file.MimeType, file.StorageKey, file.Size = "build.zip", "application/zip", testUserID+"/archive", int64(buf.Len())
+	_, err := service.blobs.Put(context.Background(), file.StorageKey, &buf, file.Size)
+	require.NoError(t, err)
+	return file
+}
+
+func TestFileService_ListArchiveEntries(t *testing.T) {
+	t.Run("zip archive", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		file := storeZip(t, service, "docs/", "", "docs/guide.md", "# Guide", "notes.txt", "remember")
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(file, nil)
+
+		resp, err := service.ListArchiveEntries(context.Background(), &pb.ListArchiveEntriesRequest{Id: testFileID, UserId: testUserID})
+
+		require.NoError(t, err)
+		require.Len(t, resp.Entries, 3)
+		assert.True(t, resp.Entries[0].IsFolder)
+		assert.Equal(t, "docs/guide.md", resp.Entries[1].Path)
+		assert.Equal(t, int64(7), resp.Entries[1].Size)
+		assert.Equal(t, int64(15), resp.TotalSize)
+	})
+
+	t.Run("unsafe archive", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(storeZip(t, service, "../../etc/passwd", "root"), nil)
+
+		_, err := service.ListArchiveEntries(context.Background(), &pb.ListArchiveEntriesRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.FailedPrecondition)
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		file := scannedFile()
+		file.Name, file.MimeType = "notes.txt", "text/plain"
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(file, nil)
+
+		_, err := service.ListArchiveEntries(context.Background(), &pb.ListArchiveEntriesRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.FailedPrecondition)
+	})
+
+	t.Run("content missing", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		file := scannedFile()
+		file.Name, file.MimeType, file.StorageKey, file.Size = "build.zip", "application/zip", testUserID+"/gone", 100
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(file, nil)
+
+		_, err := service.ListArchiveEntries(context.Background(), &pb.ListArchiveEntriesRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.NotFound)
+	})
+}
+
+func TestFileService_ExtractArchive(t *testing.T) {
+	const rootID, docsID = "423e4567-e89b-12d3-a456-426614174000", "523e4567-e89b-12d3-a456-426614174000"
+	root := &pb.Folder{Id: rootID, Name: "build (2)"}
+	extracted := func(id, name string) *pb.File {
+		return &pb.File{Id: id, UserId: testUserID, Name: name, StorageKey: testUserID + "/" + id}
+	}
+
+	t.Run("folders and files", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		mockFolders := new(MockFolderRepository)
+		service.folders = mockFolders
+		file := storeZip(t, service,
+			"docs/guide.md", "# Guide",
+			"notes.txt", "remember",
+			"notes.txt", "remember twice",
+			strings.Repeat("n", 256)+".txt", "too long a name",
+		)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(file, nil)
+
+		mockFolders.On("Create", mock.Anything, &pb.CreateFolderRequest{Name: "build", UserId: testUserID, ParentId: testFolderID}).
+			Return(nil, repository.ErrNameConflict)
+		mockFolders.On("Create", mock.Anything, &pb.CreateFolderRequest{Name: "build (2)", UserId: testUserID, ParentId: testFolderID}).
+			Return(root, nil)
+		mockFolders.On("Create", mock.Anything, &pb.CreateFolderRequest{Name: "docs", UserId: testUserID, ParentId: rootID}).
+			Return(&pb.Folder{Id: docsID, Name: "docs", ParentId: rootID}, nil)
+
+		guide, notes := extracted("623e4567-e89b-12d3-a456-426614174000", "guide.md"), extracted("723e4567-e89b-12d3-a456-426614174000", "notes.txt")
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(req *pb.CreateFileRequest) bool {
+			return req.Name == "guide.md" && req.FolderId == docsID && req.Size == 7 && req.UserId == testUserID
+		}), mock.Anything).Return(guide, nil)
+		notesReq := mock.MatchedBy(func(req *pb.CreateFileRequest) bool { return req.Name == "notes.txt" && req.FolderId == rootID })
+		mockRepo.On("Create", mock.Anything, notesReq, mock.Anything).Return(notes, nil).Once()
+		mockRepo.On("Create", mock.Anything, notesReq, mock.Anything).Return(nil, repository.ErrNameConflict).Once()
+		mockRepo.On("UpdateContent", mock.Anything, guide.Id, testUserID, repository.BlobKey(sha256Hex("# Guide")), int64(7), sha256Hex("# Guide"), mock.Anything, mock.Anything).
+			Return(guide, nil)
+		mockRepo.On("UpdateContent", mock.Anything, notes.Id, testUserID, repository.BlobKey(sha256Hex("remember")), int64(8), sha256Hex("remember"), mock.Anything, mock.Anything).
+			Return(notes, nil)
+
+		resp, err := service.ExtractArchive(context.Background(), &pb.ExtractArchiveRequest{Id: testFileID, UserId: testUserID, ParentId: testFolderID})
+
+		require.NoError(t, err)
+		assert.Equal(t, root, resp.Folder)
+		assert.Equal(t, int32(2), resp.ExtractedFiles)
+		assert.Equal(t, int32(1), resp.ExtractedFolders)
+		assert.Equal(t, int32(2), resp.Skipped, "the duplicate and the overlong name are skipped")
+
+		stored, _, err := service.blobs.Get(context.Background(), repository.BlobKey(sha256Hex("# Guide")))
+		require.NoError(t, err)
+		defer stored.Close()
+		data, err := io.ReadAll(stored)
+		require.NoError(t, err)
+		assert.Equal(t, "# Guide", string(data))
+		mockRepo.AssertExpectations(t)
+		mockFolders.AssertExpectations(t)
+	})
+
+	t.Run("unsafe archive writes nothing", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+			Return(storeZip(t, service, "notes.txt", "fine", "../outside.txt", "escaped"), nil)
+
+		_, err := service.ExtractArchive(context.Background(), &pb.ExtractArchiveRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.FailedPrecondition)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("over the storage quota", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(storeZip(t, service, "notes.txt", "remember"), nil)
+		usage := new(MockUsageRepository)
+		service.usage = usage
+		service.quotas = map[string]Quota{domain.UserTypeStandard: {Storage: 100}}
+		usage.On("Get", mock.Anything, testUserID).Return(&repository.Usage{UserType: domain.UserTypeStandard, UsedBytes: 95}, nil)
+
+		_, err := service.ExtractArchive(context.Background(), &pb.ExtractArchiveRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.ResourceExhausted)
+	})
+
+	t.Run("failure part way moves the folder to the trash", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		mockFolders := new(MockFolderRepository)
+		service.folders = mockFolders
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(storeZip(t, service, "notes.txt", "remember"), nil)
+		mockFolders.On("Create", mock.Anything, mock.Anything).Return(root, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database unavailable"))
+		mockFolders.On("Delete", mock.Anything, rootID, testUserID).Return(int32(1), int32(0), nil)
+
+		_, err := service.ExtractArchive(context.Background(), &pb.ExtractArchiveRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.Internal)
+		mockFolders.AssertExpectations(t)
+	})
+
+	t.Run("invalid parent", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+
+		_, err := service.ExtractArchive(context.Background(), &pb.ExtractArchiveRequest{Id: testFileID, UserId: testUserID, ParentId: "invalid"})
+
+		assertStatusCode(t, err, codes.InvalidArgument)
+	})
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// collectBatchSize caps the blobs deleted per collection transaction
+const collectBatchSize = 100
+
+// commitContent makes content uploaded to a staging key the current content of a file.
+// Content is stored once per checksum: the staged object becomes the blob when no stored
+// content has that checksum yet and is dropped otherwise. storageLimit is the owner's
+// storage quota, zero meaning unlimited. The new content is indexed for search, and rendered
+// as thumbnails if it is an image, in the background. The content's type is detected from
+// its first bytes; content of a blocked type is discarded, as is the first content of a
+// file request submission whose type turns out to be one the request does not accept.
+func (s *FileService) commitContent(ctx context.Context, file *pb.File, stagedKey string, size int64, checksum string, storageLimit int64) (*pb.File, error) {
+	key := repository.BlobKey(checksum)
+
+	typ, err := s.detectType(ctx, file, stagedKey, size)
+	if err == nil && file.Checksum == "" && typ.MimeType != file.MimeType {
+		err = s.checkSubmittedType(ctx, file.Id, typ.MimeType)
+	}
+	if err != nil {
+		if errors.Is(err, errFileTypeBlocked) || errors.Is(err, errFileTypeNotRequested) {
+			s.deleteBlobs(ctx, []string{stagedKey})
+		}
+		return nil, err
+	}
+
+	// Taking the reference first keeps the blob from being collected while it is stored
+	completed, err := s.repo.UpdateContent(ctx, file.Id, file.UserId, key, size, checksum, typ, storageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.blobs.Stat(ctx, key)
+	switch {
+	case err == nil:
+		s.deleteBlobs(ctx, []string{stagedKey})
+	case errors.Is(err, storage.ErrNotFound):
+		if err := s.blobs.Move(ctx, stagedKey, key); err != nil {
+			return nil, fmt.Errorf("failed to store content: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to stat content: %w", err)
+	}
+
+	s.queueContentJobs()
+	return completed, nil
+}
+
+// hashContent computes the SHA-256 checksum of a stored object
+func (s *FileService) hashContent(ctx context.Context, key string) (string, error) {
+	body, _, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CollectBlobs deletes the content no file or file version uses any more.
+// It returns the number of blobs deleted.
+func (s *FileService) CollectBlobs(ctx context.Context) (int, error) {
+	remove := func(key string) error {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			return err
+		}
+		s.deleteBlobs(ctx, thumbnailKeys(key))
+		return nil
+	}
+
+	var total int
+	for {
+		n, err := s.blobRefs.Collect(ctx, collectBatchSize, remove)
+		total += n
+		if err != nil || n < collectBatchSize {
+			return total, err
+		}
+	}
+}
+
+// deleteContent deletes content no blob accounts for, along with its thumbnails
+func (s *FileService) deleteContent(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		s.deleteBlobs(ctx, append([]string{key}, thumbnailKeys(key)...))
+	}
+}
+
+// collectBlobs frees the content released by a hard delete. Failures are only logged;
+// the trash purger collects whatever is left on its next run.
+func (s *FileService) collectBlobs(ctx context.Context) {
+	if _, err := s.CollectBlobs(ctx); err != nil {
+		log.Printf("Failed to collect unreferenced blobs: %v", err)
+	}
+}
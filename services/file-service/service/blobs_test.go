@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockBlobRepository is a mock implementation of BlobRepository
+type MockBlobRepository struct {
+	mock.Mock
+}
+
+func (m *MockBlobRepository) Collect(ctx context.Context, limit int, remove func(key string) error) (int, error) {
+	args := m.Called(ctx, limit, remove)
+	return args.Int(0), args.Error(1)
+}
+
+func TestFileService_CommitContent(t *testing.T) {
+	ctx := context.Background()
+	const content = "same bytes"
+	checksum := sha256Hex(content)
+	blobKey := repository.BlobKey(checksum)
+
+	mockRepo := new(MockFileRepository)
+	mockRepo.On("UpdateContent", mock.Anything, mock.Anything, testUserID, blobKey, int64(len(content)), checksum, mock.Anything, mock.Anything).
+		Return(&pb.File{Id: testFileID, StorageKey: blobKey, Checksum: checksum}, nil)
+	service := newTestService(t, mockRepo)
+
+	first := &pb.File{Id: testFileID, UserId: testUserID}
+	second := &pb.File{Id: testFolderID, UserId: testUserID}
+	for _, file := range []*pb.File{first, second} {
+		staged := file.UserId + "/" + file.Id
+		_, err := service.blobs.Put(ctx, staged, strings.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+
+		completed, err := service.commitContent(ctx, file, staged, int64(len(content)), checksum, 0)
+		require.NoError(t, err)
+		assert.Equal(t, blobKey, completed.StorageKey)
+
+		_, err = service.blobs.Stat(ctx, staged)
+		assert.Error(t, err, "staged content should not be kept")
+	}
+
+	objects, err := service.blobs.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, objects, 1, "identical content should be stored once")
+	assert.Equal(t, blobKey, objects[0].Key)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFileService_CollectBlobs(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService(t, new(MockFileRepository))
+	unused := repository.BlobKey(sha256Hex("unused"))
+	_, err := service.blobs.Put(ctx, unused, strings.NewReader("unused"), 6)
+	require.NoError(t, err)
+	thumb := repository.ThumbnailKey(unused, defaultThumbnailSize)
+	_, err = service.blobs.Put(ctx, thumb, strings.NewReader("jpeg"), 4)
+	require.NoError(t, err)
+
+	mockBlobs := new(MockBlobRepository)
+	mockBlobs.On("Collect", mock.Anything, collectBatchSize, mock.Anything).
+		Run(func(args mock.Arguments) {
+			remove := args.Get(2).(func(string) error)
+			require.NoError(t, remove(unused))
+		}).
+		Return(collectBatchSize, nil).Once()
+	mockBlobs.On("Collect", mock.Anything, collectBatchSize, mock.Anything).Return(0, nil).Once()
+	service.blobRefs = mockBlobs
+
+	collected, err := service.CollectBlobs(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, collectBatchSize, collected)
+	_, err = service.blobs.Stat(ctx, unused)
+	assert.Error(t, err, "unreferenced content should be deleted")
+	_, err = service.blobs.Stat(ctx, thumb)
+	assert.Error(t, err, "thumbnails should be deleted with their content")
+	mockBlobs.AssertExpectations(t)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-drive/internal/extract"
+	"go-drive/services/file-service/repository"
+)
+
+// maxIndexedText caps the text indexed per file. PostgreSQL limits a tsvector to 1 MB.
+const maxIndexedText = 256 << 10
+
+// IndexContent extracts and indexes the text of the files whose content is waiting to be
+// indexed. It returns the number of files processed, whatever their outcome.
+func (s *FileService) IndexContent(ctx context.Context) (int, error) {
+	return s.processContentJobs(ctx, s.contentIndex.Claim, s.indexFile)
+}
+
+// indexFile extracts the text of a claimed file and records the outcome. Failed attempts
+// are retried with a growing delay. Only failing to record the outcome is an error.
+func (s *FileService) indexFile(ctx context.Context, job repository.ContentJob) error {
+	if !extract.Supported(job.MimeType, job.Name) {
+		return s.contentIndex.Skip(ctx, job, extract.ErrUnsupported.Error())
+	}
+
+	text, err := s.extractText(ctx, job)
+	switch {
+	case err == nil:
+		return s.contentIndex.Store(ctx, job, text, s.now())
+	case errors.Is(err, extract.ErrTooLarge):
+		return s.contentIndex.Skip(ctx, job, err.Error())
+	case ctx.Err() != nil:
+		// Left to the lease, so the file is retried once the service is back
+		return ctx.Err()
+	}
+
+	log.Printf("Failed to index content of file %s (attempt %d of %d): %v", job.FileID, job.Attempts, maxContentJobAttempts, err)
+	return s.contentIndex.Fail(ctx, job, err.Error(), s.retryAt(job))
+}
+
+// extractText reads a file's content and extracts its text
+func (s *FileService) extractText(ctx context.Context, job repository.ContentJob) (string, error) {
+	body, _, err := s.blobs.Get(ctx, job.StorageKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read content: %w", err)
+	}
+	defer body.Close()
+
+	return extract.Text(body, job.MimeType, job.Name, maxIndexedText)
+}
+
+// RunContentIndexer indexes waiting content every interval, and whenever new content is
+// committed, until ctx is cancelled
+func (s *FileService) RunContentIndexer(ctx context.Context, interval time.Duration) {
+	runContentWorker(ctx, interval, s.indexQueued, func() {
+		indexed, err := s.IndexContent(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to index file content: %v", err)
+		} else if indexed > 0 {
+			log.Printf("Processed the content of %d files for search", indexed)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/extract"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockContentIndexRepository is a mock implementation of ContentIndexRepository
+type MockContentIndexRepository struct {
+	mock.Mock
+}
+
+func (m *MockContentIndexRepository) Get(ctx context.Context, fileID string) (*pb.ContentIndex, error) {
+	args := m.Called(ctx, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.ContentIndex), args.Error(1)
+}
+
+func (m *MockContentIndexRepository) Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]repository.ContentJob, error) {
+	args := m.Called(ctx, now, retryAt, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ContentJob), args.Error(1)
+}
+
+func (m *MockContentIndexRepository) Store(ctx context.Context, job repository.ContentJob, text string, indexedAt time.Time) error {
+	return m.Called(ctx, job, text, indexedAt).Error(0)
+}
+
+func (m *MockContentIndexRepository) Skip(ctx context.Context, job repository.ContentJob, reason string) error {
+	return m.Called(ctx, job, reason).Error(0)
+}
+
+func (m *MockContentIndexRepository) Fail(ctx context.Context, job repository.ContentJob, reason string, retryAt *time.Time) error {
+	return m.Called(ctx, job, reason, retryAt).Error(0)
+}
+
+func TestFileService_IndexContent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := func(name, mimeType, key string, attempts int32) repository.ContentJob {
+		return repository.ContentJob{
+			FileID: uuid.MustParse(testFileID), Checksum: strings.Repeat("ab", 32),
+			Name: name, MimeType: mimeType, StorageKey: key, Attempts: attempts,
+		}
+	}
+	stored := job("notes.csv", "text/csv", "stored", 1)
+	missing := job("notes.txt", "text/plain", "missing", 2)
+
+	tests := []struct {
+		name      string
+		jobs      []repository.ContentJob
+		mockSetup func(*MockContentIndexRepository)
+	}{
+		{
+			name: "text extracted and stored",
+			jobs: []repository.ContentJob{stored},
+			mockSetup: func(index *MockContentIndexRepository) {
+				index.On("Store", mock.Anything, stored, "region total\nnorth 42\n", now).Return(nil)
+			},
+		},
+		{
+			name: "unsupported content skipped",
+			jobs: []repository.ContentJob{job("photo.png", "image/png", "stored", 1)},
+			mockSetup: func(index *MockContentIndexRepository) {
+				index.On("Skip", mock.Anything, mock.Anything, extract.ErrUnsupported.Error()).Return(nil)
+			},
+		},
+		{
+			name: "failure retried later",
+			jobs: []repository.ContentJob{missing},
+			mockSetup: func(index *MockContentIndexRepository) {
+				retryAt := now.Add(2 * contentJobRetryDelay)
+				index.On("Fail", mock.Anything, missing, mock.Anything, &retryAt).Return(nil)
+			},
+		},
+		{
+			name: "last attempt gives up",
+			jobs: []repository.ContentJob{job("notes.txt", "text/plain", "missing", maxContentJobAttempts)},
+			mockSetup: func(index *MockContentIndexRepository) {
+				index.On("Fail", mock.Anything, mock.Anything, mock.Anything, (*time.Time)(nil)).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			service.now = func() time.Time { return now }
+			_, err := service.blobs.Put(context.Background(), "stored", strings.NewReader("region,total\nnorth,42\n"), -1)
+			require.NoError(t, err)
+
+			mockIndex := new(MockContentIndexRepository)
+			service.contentIndex = mockIndex
+			mockIndex.On("Claim", mock.Anything, now, now.Add(contentJobLease), contentJobBatchSize).Return(tt.jobs, nil)
+			tt.mockSetup(mockIndex)
+
+			processed, err := service.IndexContent(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.jobs), processed)
+			mockIndex.AssertExpectations(t)
+		})
+	}
+
+	t.Run("claim failure", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockIndex := new(MockContentIndexRepository)
+		service.contentIndex = mockIndex
+		mockIndex.On("Claim", mock.Anything, mock.Anything, mock.Anything, contentJobBatchSize).Return(nil, errors.New("connection refused"))
+
+		_, err := service.IndexContent(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFileService_GetFileContentIndex(t *testing.T) {
+	mockRepo := new(MockFileRepository)
+	mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+		Return(&pb.File{Id: testFileID, Name: "notes.md", UserId: testUserID}, nil)
+	service := newTestService(t, mockRepo)
+	mockIndex := new(MockContentIndexRepository)
+	service.contentIndex = mockIndex
+
+	t.Run("state returned with the file", func(t *testing.T) {
+		mockIndex.On("Get", mock.Anything, testFileID).
+			Return(&pb.ContentIndex{Status: domain.ContentIndexPending, Attempts: 1, Error: "object not found"}, nil).Once()
+
+		resp, err := service.GetFile(context.Background(), &pb.GetFileRequest{Id: testFileID, UserId: testUserID})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ContentIndexPending, resp.ContentIndex.Status)
+		assert.Equal(t, int32(1), resp.ContentIndex.Attempts)
+	})
+
+	t.Run("index unavailable", func(t *testing.T) {
+		mockIndex.On("Get", mock.Anything, testFileID).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := service.GetFile(context.Background(), &pb.GetFileRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.Internal)
+	})
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-drive/services/file-service/repository"
+)
+
+const (
+	// contentJobBatchSize is how many files a background worker claims at a time
+	contentJobBatchSize = 20
+	// maxContentJobAttempts is how many times processing a file's content is tried before
+	// giving up
+	maxContentJobAttempts = 5
+	// contentJobRetryDelay is the wait before retrying a failed attempt. It doubles with
+	// every further attempt.
+	contentJobRetryDelay = time.Minute
+	// contentJobLease is how long a claimed file is left to its worker. Files a worker did
+	// not finish, e.g. because the service stopped, are picked up again afterwards.
+	contentJobLease = 10 * time.Minute
+)
+
+// claimFunc claims up to limit files that are due at now, leasing them until retryAt
+type claimFunc func(ctx context.Context, now, retryAt time.Time, limit int) ([]repository.ContentJob, error)
+
+// processContentJobs claims due files batch by batch and hands each to process until none
+// are left. It returns the number of files processed.
+func (s *FileService) processContentJobs(ctx context.Context, claim claimFunc, process func(context.Context, repository.ContentJob) error) (int, error) {
+	var total int
+	for {
+		now := s.now()
+		jobs, err := claim(ctx, now, now.Add(contentJobLease), contentJobBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, job := range jobs {
+			if err := process(ctx, job); err != nil {
+				return total, err
+			}
+			total++
+		}
+		if len(jobs) < contentJobBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// retryAt returns when a failed job is tried again, or nil once it has used up its attempts
+func (s *FileService) retryAt(job repository.ContentJob) *time.Time {
+	if job.Attempts >= maxContentJobAttempts {
+		return nil
+	}
+	at := s.now().Add(contentJobRetryDelay << (job.Attempts - 1))
+	return &at
+}
+
+// queueContentJobs wakes the background workers after new content was committed
+func (s *FileService) queueContentJobs() {
+	for _, queued := range []chan struct{}{s.indexQueued, s.renderQueued, s.scanQueued} {
+		select {
+		case queued <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// runContentWorker calls work every interval, and whenever woken, until ctx is cancelled
+func runContentWorker(ctx context.Context, interval time.Duration, wake <-chan struct{}, work func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		work()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
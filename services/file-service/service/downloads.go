@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+const (
+	// maxDownloadFiles caps the files in a folder download, and so the size of its listing
+	maxDownloadFiles = 10000
+	// maxDownloadSelection caps the files picked one by one for a download
+	maxDownloadSelection = 1000
+	// selectionArchiveName names the archive of a selection of files
+	selectionArchiveName = "download"
+)
+
+// ListDownloadEntries lists what goes into a ZIP download: a folder with everything below
+// it under the folder's name, or a selection of files side by side. Files the user cannot
+// access, and files that cannot be downloaded, such as quarantined ones, are counted and
+// left out. So are files that have no content yet.
+func (s *FileService) ListDownloadEntries(ctx context.Context, req *pb.ListDownloadEntriesRequest) (*pb.ListDownloadEntriesResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.FolderId != "" && len(req.FileIds) > 0, req.FolderId == "" && len(req.FileIds) == 0:
+		return nil, status.Error(codes.InvalidArgument, "exactly one of folder_id and file_ids is required")
+	case req.FolderId != "":
+		return s.listFolderDownload(ctx, req.FolderId, req.UserId)
+	}
+	return s.listSelectionDownload(ctx, req.FileIds, req.UserId)
+}
+
+func (s *FileService) listFolderDownload(ctx context.Context, folderID, userID string) (*pb.ListDownloadEntriesResponse, error) {
+	if err := validateID("folder_id", folderID); err != nil {
+		return nil, err
+	}
+	// Access to a folder extends to everything below it
+	owner, err := s.authorizeFolder(ctx, folderID, userID, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	folders, files, err := s.folders.ListTree(ctx, folderID, owner, maxDownloadFiles+1)
+	if err != nil {
+		return nil, repoError("list folder", err)
+	}
+	if len(files) > maxDownloadFiles {
+		return nil, status.Errorf(codes.FailedPrecondition, "folder holds more than %d files; download its subfolders instead", maxDownloadFiles)
+	}
+
+	byID := make(map[string]*pb.Folder, len(folders))
+	for _, folder := range folders {
+		byID[folder.Id] = folder
+	}
+	paths := make(map[string]string, len(folders))
+	var folderPath func(id string) string
+	folderPath = func(id string) string {
+		if p, ok := paths[id]; ok {
+			return p
+		}
+		folder := byID[id]
+		p := folder.Name + "/"
+		if id != folderID {
+			p = folderPath(folder.ParentId) + p
+		}
+		paths[id] = p
+		return p
+	}
+
+	resp := &pb.ListDownloadEntriesResponse{Name: byID[folderID].Name}
+	for _, folder := range folders {
+		resp.Entries = append(resp.Entries, &pb.DownloadEntry{Path: folderPath(folder.Id)})
+	}
+	sort.Slice(resp.Entries, func(i, j int) bool { return resp.Entries[i].Path < resp.Entries[j].Path })
+
+	for _, file := range files {
+		if file.Checksum == "" {
+			continue
+		}
+		if checkDownloadable(file) != nil {
+			resp.Skipped++
+			continue
+		}
+		resp.Entries = append(resp.Entries, &pb.DownloadEntry{Path: folderPath(file.FolderId) + file.Name, File: file})
+	}
+	return resp, nil
+}
+
+func (s *FileService) listSelectionDownload(ctx context.Context, fileIDs []string, userID string) (*pb.ListDownloadEntriesResponse, error) {
+	if len(fileIDs) > maxDownloadSelection {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d files can be downloaded together", maxDownloadSelection)
+	}
+	for _, id := range fileIDs {
+		if err := validateID("file_ids", id); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &pb.ListDownloadEntriesResponse{Name: selectionArchiveName}
+	seen := make(map[string]bool, len(fileIDs))
+	used := make(map[string]bool, len(fileIDs))
+	for _, id := range fileIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		file, err := s.downloadableFile(ctx, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			resp.Skipped++
+			continue
+		}
+		if file.Checksum == "" {
+			continue
+		}
+		resp.Entries = append(resp.Entries, &pb.DownloadEntry{Path: uniqueName(used, file.Name), File: file})
+	}
+	return resp, nil
+}
+
+// downloadableFile returns a file the user may download, or nil if they cannot access it
+// or it cannot be downloaded
+func (s *FileService) downloadableFile(ctx context.Context, fileID, userID string) (*pb.File, error) {
+	owner, err := s.authorizeFile(ctx, fileID, userID, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, skipInaccessible(err)
+	}
+	file, err := s.repo.GetByID(ctx, fileID, owner)
+	if err != nil {
+		return nil, skipInaccessible(repoError("get file", err))
+	}
+	if checkDownloadable(file) != nil {
+		return nil, nil
+	}
+	return file, nil
+}
+
+// skipInaccessible drops the errors that mean a file is out of the user's reach
+func skipInaccessible(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound, codes.PermissionDenied:
+		return nil
+	}
+	return err
+}
+
+// uniqueName returns name, or name numbered like "report (2).pdf" when it is already used
+func uniqueName(used map[string]bool, name string) string {
+	unique := name
+	for n := 2; used[unique]; n++ {
+		unique = numberedName(name, n)
+	}
+	used[unique] = true
+	return unique
+}
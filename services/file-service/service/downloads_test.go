@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// downloadPaths returns the archive paths of entries
+func downloadPaths(entries []*pb.DownloadEntry) []string {
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.Path
+	}
+	return paths
+}
+
+func TestFileService_ListFolderDownload(t *testing.T) {
+	const emptyID, tripID = "423e4567-e89b-12d3-a456-426614174000", "523e4567-e89b-12d3-a456-426614174000"
+	folders := []*pb.Folder{
+		{Id: tripID, Name: "Trip", ParentId: testFolderID},
+		{Id: testFolderID, Name: "Photos"},
+		{Id: emptyID, Name: "Empty", ParentId: testFolderID},
+	}
+	beach := scannedFile()
+	beach.Name, beach.FolderId = "beach.jpg", tripID
+	notes := scannedFile()
+	notes.Name, notes.FolderId = "notes.txt", testFolderID
+	infected := scannedFile()
+	infected.Name, infected.FolderId = "setup.exe", tripID
+	infected.ScanStatus, infected.Quarantined = domain.ScanInfected, true
+	empty := &pb.File{Id: testFileID, Name: "draft.txt", FolderId: testFolderID}
+
+	t.Run("subtree with paths", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockFolders := new(MockFolderRepository)
+		service.folders = mockFolders
+		mockFolders.On("ListTree", mock.Anything, testFolderID, testUserID, maxDownloadFiles+1).
+			Return(folders, []*pb.File{beach, infected, empty, notes}, nil)
+
+		resp, err := service.ListDownloadEntries(context.Background(), &pb.ListDownloadEntriesRequest{UserId: testUserID, FolderId: testFolderID})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Photos", resp.Name)
+		assert.Equal(t, []string{"Photos/", "Photos/Empty/", "Photos/Trip/", "Photos/Trip/beach.jpg", "Photos/notes.txt"}, downloadPaths(resp.Entries))
+		assert.Nil(t, resp.Entries[0].File)
+		assert.Equal(t, beach, resp.Entries[3].File)
+		assert.Equal(t, int32(1), resp.Skipped, "quarantined files are left out")
+	})
+
+	t.Run("too many files", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockFolders := new(MockFolderRepository)
+		service.folders = mockFolders
+		mockFolders.On("ListTree", mock.Anything, testFolderID, testUserID, maxDownloadFiles+1).
+			Return(folders, make([]*pb.File, maxDownloadFiles+1), nil)
+
+		_, err := service.ListDownloadEntries(context.Background(), &pb.ListDownloadEntriesRequest{UserId: testUserID, FolderId: testFolderID})
+
+		assertStatusCode(t, err, codes.FailedPrecondition)
+	})
+
+	t.Run("folder shared with the user", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockShares := withShares(service)
+		mockShares.On("FolderAccess", mock.Anything, testFolderID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleViewer}, nil)
+		mockFolders := new(MockFolderRepository)
+		service.folders = mockFolders
+		mockFolders.On("ListTree", mock.Anything, testFolderID, testUserID, maxDownloadFiles+1).
+			Return(folders[1:2], []*pb.File{notes}, nil)
+
+		resp, err := service.ListDownloadEntries(context.Background(), &pb.ListDownloadEntriesRequest{UserId: testGranteeID, FolderId: testFolderID})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Photos/", "Photos/notes.txt"}, downloadPaths(resp.Entries))
+	})
+
+	t.Run("folder not accessible", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockShares := withShares(service)
+		mockShares.On("FolderAccess", mock.Anything, testFolderID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID}, nil)
+
+		_, err := service.ListDownloadEntries(context.Background(), &pb.ListDownloadEntriesRequest{UserId: testGranteeID, FolderId: testFolderID})
+
+		assertStatusCode(t, err, codes.PermissionDenied)
+	})
+}
+
+func TestFileService_ListSelectionDownload(t *testing.T) {
+	const (
+		reportID  = "623e4567-e89b-12d3-a456-426614174000"
+		copyID    = "723e4567-e89b-12d3-a456-426614174000"
+		pendingID = "823e4567-e89b-12d3-a456-426614174000"
+		othersID  = "923e4567-e89b-12d3-a456-426614174000"
+		missingID = "a23e4567-e89b-12d3-a456-426614174000"
+		brokenID  = "b23e4567-e89b-12d3-a456-426614174000"
+		goneID    = "c23e4567-e89b-12d3-a456-426614174000"
+	)
+	file := func(id, name, scanStatus string) *pb.File {
+		f := scannedFile()
+		f.Id, f.Name, f.ScanStatus = id, name, scanStatus
+		return f
+	}
+
+	tests := []struct {
+		name          string
+		fileIDs       []string
+		mockSetup     func(*MockFileRepository, *MockShareRepository)
+		expectedPaths []string
+		expectedSkips int32
+		errorCode     codes.Code
+	}{
+		{
+			name:    "files side by side",
+			fileIDs: []string{reportID, copyID, reportID},
+			mockSetup: func(repo *MockFileRepository, shares *MockShareRepository) {
+				owner := &repository.Access{OwnerID: testUserID, Role: domain.ShareRoleOwner}
+				shares.On("FileAccess", mock.Anything, reportID, testUserID).Return(owner, nil).Once()
+				shares.On("FileAccess", mock.Anything, copyID, testUserID).Return(owner, nil).Once()
+				repo.On("GetByID", mock.Anything, reportID, testUserID).Return(file(reportID, "report.pdf", domain.ScanClean), nil).Once()
+				repo.On("GetByID", mock.Anything, copyID, testUserID).Return(file(copyID, "report.pdf", domain.ScanClean), nil).Once()
+			},
+			expectedPaths: []string{"report.pdf", "report (2).pdf"},
+		},
+		{
+			name:    "inaccessible and undownloadable files skipped",
+			fileIDs: []string{pendingID, othersID, missingID, goneID},
+			mockSetup: func(repo *MockFileRepository, shares *MockShareRepository) {
+				shares.On("FileAccess", mock.Anything, pendingID, testUserID).
+					Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleOwner}, nil)
+				repo.On("GetByID", mock.Anything, pendingID, testUserID).Return(file(pendingID, "new.txt", domain.ScanPending), nil)
+				shares.On("FileAccess", mock.Anything, othersID, testUserID).
+					Return(&repository.Access{OwnerID: testGranteeID}, nil)
+				shares.On("FileAccess", mock.Anything, missingID, testUserID).Return(nil, repository.ErrFileNotFound)
+				shares.On("FileAccess", mock.Anything, goneID, testUserID).
+					Return(&repository.Access{OwnerID: testGranteeID, Role: domain.ShareRoleViewer}, nil)
+				repo.On("GetByID", mock.Anything, goneID, testGranteeID).Return(nil, repository.ErrFileNotFound)
+			},
+			expectedPaths: []string{},
+			expectedSkips: 4,
+		},
+		{
+			name:    "repository failure",
+			fileIDs: []string{brokenID},
+			mockSetup: func(repo *MockFileRepository, shares *MockShareRepository) {
+				shares.On("FileAccess", mock.Anything, brokenID, testUserID).Return(nil, errors.New("database unavailable"))
+			},
+			errorCode: codes.Internal,
+		},
+		{
+			name:      "invalid file ID",
+			fileIDs:   []string{"invalid"},
+			mockSetup: func(repo *MockFileRepository, shares *MockShareRepository) {},
+			errorCode: codes.InvalidArgument,
+		},
+		{
+			name:      "too many files",
+			fileIDs:   make([]string, maxDownloadSelection+1),
+			mockSetup: func(repo *MockFileRepository, shares *MockShareRepository) {},
+			errorCode: codes.InvalidArgument,
+		},
+		{
+			name:      "nothing selected",
+			mockSetup: func(repo *MockFileRepository, shares *MockShareRepository) {},
+			errorCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			service := newTestService(t, mockRepo)
+			mockShares := withShares(service)
+			tt.mockSetup(mockRepo, mockShares)
+
+			resp, err := service.ListDownloadEntries(context.Background(), &pb.ListDownloadEntriesRequest{UserId: testUserID, FileIds: tt.fileIDs})
+
+			if tt.errorCode != codes.OK {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, selectionArchiveName, resp.Name)
+				assert.Equal(t, tt.expectedPaths, downloadPaths(resp.Entries))
+				assert.Equal(t, tt.expectedSkips, resp.Skipped)
+			}
+			mockRepo.AssertExpectations(t)
+			mockShares.AssertExpectations(t)
+		})
+	}
+}
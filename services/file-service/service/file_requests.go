@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// errFileTypeNotRequested is returned for submitted content of a type its file request does not accept
+var errFileTypeNotRequested = errors.New("file type is not accepted by this file request")
+
+const (
+	// FileRequestPathPrefix is where the API gateway serves file request links
+	FileRequestPathPrefix = "/r/"
+	// maxFileRequestNameTries bounds the numbered names tried when an upload's name is taken
+	maxFileRequestNameTries = 100
+)
+
+// CreateFileRequestLink creates an upload-only link into a folder. Only the folder's owner
+// may create one. The token is returned only here; the link stores a hash of it.
+func (s *FileService) CreateFileRequestLink(ctx context.Context, req *pb.CreateFileRequestLinkRequest) (*pb.CreateFileRequestLinkResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if err := validateID("folder_id", req.FolderId); err != nil {
+		return nil, err
+	}
+	if len(req.Title) > 255 {
+		return nil, status.Error(codes.InvalidArgument, "title must be at most 255 bytes")
+	}
+	if req.Deadline != nil && (req.Deadline.CheckValid() != nil || !req.Deadline.AsTime().After(s.now())) {
+		return nil, status.Error(codes.InvalidArgument, "deadline must be in the future")
+	}
+	if req.MaxFileSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_file_size must not be negative")
+	}
+	mimeTypes, err := normalizeMimeTypes("allowed_mime_types", req.AllowedMimeTypes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizeFolder(ctx, req.FolderId, req.UserId, domain.ShareRoleOwner); err != nil {
+		return nil, err
+	}
+
+	token, err := newLinkToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.fileRequests.Create(ctx, &pb.CreateFileRequestLinkRequest{
+		UserId:           req.UserId,
+		FolderId:         req.FolderId,
+		Title:            req.Title,
+		Deadline:         req.Deadline,
+		MaxFileSize:      req.MaxFileSize,
+		AllowedMimeTypes: mimeTypes,
+	}, hashLinkToken(token))
+	if err != nil {
+		return nil, repoError("create file request", err)
+	}
+
+	return &pb.CreateFileRequestLinkResponse{
+		Link:  link,
+		Token: token,
+		Url:   s.publicURL + FileRequestPathPrefix + token,
+	}, nil
+}
+
+func (s *FileService) ListFileRequestLinks(ctx context.Context, req *pb.ListFileRequestLinksRequest) (*pb.ListFileRequestLinksResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	links, totalCount, err := s.fileRequests.List(ctx, req.UserId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list file requests", err)
+	}
+
+	return &pb.ListFileRequestLinksResponse{
+		Links:      links,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (s *FileService) RevokeFileRequestLink(ctx context.Context, req *pb.RevokeFileRequestLinkRequest) (*pb.RevokeFileRequestLinkResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.fileRequests.Revoke(ctx, req.Id, req.UserId); err != nil {
+		return nil, repoError("revoke file request", err)
+	}
+
+	return &pb.RevokeFileRequestLinkResponse{Message: "File request revoked successfully"}, nil
+}
+
+func (s *FileService) ListFileRequestSubmissions(ctx context.Context, req *pb.ListFileRequestSubmissionsRequest) (*pb.ListFileRequestSubmissionsResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	submissions, totalCount, err := s.fileRequests.ListSubmissions(ctx, req.Id, req.UserId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list submissions", err)
+	}
+
+	return &pb.ListFileRequestSubmissionsResponse{
+		Submissions: submissions,
+		TotalCount:  totalCount,
+	}, nil
+}
+
+// OpenFileRequestLink returns what a file request link accepts. Nothing about the
+// folder's contents is revealed.
+func (s *FileService) OpenFileRequestLink(ctx context.Context, req *pb.OpenFileRequestLinkRequest) (*pb.OpenFileRequestLinkResponse, error) {
+	link, err := s.openFileRequest(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.OpenFileRequestLinkResponse{Link: link}, nil
+}
+
+// SubmitFileRequest creates a file in a file request's folder for the gateway to store an
+// upload in. The file belongs to the folder's owner and counts against their quota. A name
+// that is already taken gets a numbered suffix, so uploaders never learn what the folder holds.
+func (s *FileService) SubmitFileRequest(ctx context.Context, req *pb.SubmitFileRequestRequest) (*pb.SubmitFileRequestResponse, error) {
+	if err := validateName("name", req.Name); err != nil {
+		return nil, err
+	}
+	if req.Size < 0 {
+		return nil, status.Error(codes.InvalidArgument, "size must not be negative")
+	}
+	uploaderName := strings.TrimSpace(req.UploaderName)
+	if uploaderName == "" {
+		return nil, status.Error(codes.InvalidArgument, "uploader_name is required")
+	}
+	if len(uploaderName) > 255 {
+		return nil, status.Error(codes.InvalidArgument, "uploader_name must be at most 255 bytes")
+	}
+	email, err := mail.ParseAddress(req.UploaderEmail)
+	if err != nil || email.Name != "" || len(email.Address) > 255 {
+		return nil, status.Error(codes.InvalidArgument, "uploader_email must be a valid email address")
+	}
+
+	link, err := s.openFileRequest(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	mimeType, err := acceptedMimeType(link.AllowedMimeTypes, req.MimeType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkFileType(req.Name, mimeType); err != nil {
+		return nil, err
+	}
+
+	quota, err := s.userQuota(ctx, link.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFileSize(quota, req.Size); err != nil {
+		return nil, err
+	}
+	maxSize := fileSizeLimit(quota)
+	if link.MaxFileSize > 0 && link.MaxFileSize < maxSize {
+		maxSize = link.MaxFileSize
+	}
+	if req.Size > maxSize {
+		return nil, status.Errorf(codes.InvalidArgument, "file exceeds this request's maximum file size of %d bytes", maxSize)
+	}
+	if req.Size > 0 {
+		maxSize = req.Size
+	}
+
+	file, err := s.createRequestedFile(ctx, link, req.Name, req.Size, mimeType, quota.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fileRequests.RecordSubmission(ctx, link.Id, file.Id, uploaderName, email.Address); err != nil {
+		// Without the record the owner could not tell where the file came from
+		keys, delErr := s.repo.Discard(ctx, file.Id, link.OwnerId)
+		if delErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to record submission: %v (cleanup: %v)", err, delErr)
+		}
+		s.deleteContent(ctx, keys)
+		return nil, repoError("record submission", err)
+	}
+
+	return &pb.SubmitFileRequestResponse{
+		File:    file,
+		MaxSize: maxSize,
+	}, nil
+}
+
+// openFileRequest looks up a file request link by its token and checks its deadline.
+// Unknown and revoked tokens are both reported as not found.
+func (s *FileService) openFileRequest(ctx context.Context, token string) (*pb.FileRequestLink, error) {
+	if token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	link, err := s.fileRequests.GetByToken(ctx, hashLinkToken(token))
+	if err != nil {
+		return nil, repoError("get file request", err)
+	}
+	if link.Deadline != nil && !s.now().Before(link.Deadline.AsTime()) {
+		return nil, status.Error(codes.FailedPrecondition, "file request is closed")
+	}
+	return link, nil
+}
+
+// checkSubmittedType checks the detected type of a file's content against the file request
+// the file was submitted through, if any. The type declared on submission was checked
+// already, but the content need not be of that type.
+func (s *FileService) checkSubmittedType(ctx context.Context, fileID, mimeType string) error {
+	link, err := s.fileRequests.GetBySubmission(ctx, fileID)
+	if errors.Is(err, repository.ErrFileRequestNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get file request: %w", err)
+	}
+	if _, err := acceptedMimeType(link.AllowedMimeTypes, mimeType); err != nil {
+		return fmt.Errorf("%w: the content is %s, but it only accepts %s", errFileTypeNotRequested, mimeType, strings.Join(link.AllowedMimeTypes, ", "))
+	}
+	return nil
+}
+
+// createRequestedFile creates a file in a file request's folder, numbering the name until it is free
+func (s *FileService) createRequestedFile(ctx context.Context, link *pb.FileRequestLink, name string, size int64, mimeType string, storageQuota int64) (*pb.File, error) {
+	for n := 0; n < maxFileRequestNameTries; n++ {
+		candidate := name
+		if n > 0 {
+			candidate = numberedName(name, n)
+		}
+		file, err := s.repo.Create(ctx, &pb.CreateFileRequest{
+			Name:     candidate,
+			UserId:   link.OwnerId,
+			FolderId: link.FolderId,
+			Size:     size,
+			MimeType: mimeType,
+		}, storageQuota)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, repository.ErrNameConflict) {
+			return nil, repoError("create file", err)
+		}
+	}
+	return nil, status.Error(codes.AlreadyExists, "no free name for this file")
+}
+
+// numberedName inserts " (n)" before a name's extension: report.pdf becomes report (2).pdf
+func numberedName(name string, n int) string {
+	ext := path.Ext(name)
+	if ext == name {
+		ext = ""
+	}
+	return fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), n, ext)
+}
+
+// normalizeMimeTypes checks and lowercases a list of MIME types. Each is an exact type
+// such as application/pdf or a wildcard such as image/*.
+func normalizeMimeTypes(field string, mimeTypes []string) ([]string, error) {
+	normalized := make([]string, 0, len(mimeTypes))
+	for _, mimeType := range mimeTypes {
+		mediaType, params, err := mime.ParseMediaType(mimeType)
+		major, minor, ok := strings.Cut(mediaType, "/")
+		if err != nil || len(params) > 0 || !ok || major == "*" || minor == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %q is not a MIME type such as application/pdf or image/*", field, mimeType)
+		}
+		normalized = append(normalized, mediaType)
+	}
+	return normalized, nil
+}
+
+// acceptedMimeType returns an upload's media type without parameters, provided the file
+// request accepts it
+func acceptedMimeType(allowed []string, mimeType string) (string, error) {
+	mediaType := ""
+	if mimeType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(mimeType); err != nil {
+			return "", status.Error(codes.InvalidArgument, "mime_type is not a valid MIME type")
+		}
+	}
+	if len(allowed) == 0 {
+		return mediaType, nil
+	}
+
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, accepted := range allowed {
+		if accepted == mediaType || accepted == major+"/*" {
+			return mediaType, nil
+		}
+	}
+	return "", status.Errorf(codes.InvalidArgument, "this file request only accepts %s", strings.Join(allowed, ", "))
+}
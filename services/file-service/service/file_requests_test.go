@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockFileRequestRepository is a mock implementation of FileRequestRepository
+type MockFileRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileRequestRepository) Create(ctx context.Context, req *pb.CreateFileRequestLinkRequest, tokenHash string) (*pb.FileRequestLink, error) {
+	args := m.Called(ctx, req, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.FileRequestLink), args.Error(1)
+}
+
+func (m *MockFileRequestRepository) GetByToken(ctx context.Context, tokenHash string) (*pb.FileRequestLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.FileRequestLink), args.Error(1)
+}
+
+func (m *MockFileRequestRepository) GetBySubmission(ctx context.Context, fileID string) (*pb.FileRequestLink, error) {
+	args := m.Called(ctx, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.FileRequestLink), args.Error(1)
+}
+
+func (m *MockFileRequestRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.FileRequestLink, int32, error) {
+	args := m.Called(ctx, userID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.FileRequestLink), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockFileRequestRepository) Revoke(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockFileRequestRepository) RecordSubmission(ctx context.Context, requestID, fileID, uploaderName, uploaderEmail string) error {
+	args := m.Called(ctx, requestID, fileID, uploaderName, uploaderEmail)
+	return args.Error(0)
+}
+
+func (m *MockFileRequestRepository) ListSubmissions(ctx context.Context, id, userID string, page, pageSize int32) ([]*pb.FileRequestSubmission, int32, error) {
+	args := m.Called(ctx, id, userID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.FileRequestSubmission), args.Get(1).(int32), args.Error(2)
+}
+
+// withFileRequests replaces the service's file request repository with a fresh mock
+func withFileRequests(service *FileService) *MockFileRequestRepository {
+	mockRequests := new(MockFileRequestRepository)
+	service.fileRequests = mockRequests
+	return mockRequests
+}
+
+func TestFileService_CreateFileRequestLink(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.CreateFileRequestLinkRequest
+		mockSetup     func(*MockFileRequestRepository)
+		expectedTypes []string
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name: "link with limits",
+			request: &pb.CreateFileRequestLinkRequest{
+				UserId:           testUserID,
+				FolderId:         testFolderID,
+				Title:            "Tax documents",
+				Deadline:         timestamppb.New(time.Now().Add(24 * time.Hour)),
+				MaxFileSize:      10 << 20,
+				AllowedMimeTypes: []string{"Application/PDF", "image/*"},
+			},
+			mockSetup: func(requests *MockFileRequestRepository) {
+				requests.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateFileRequestLinkRequest"), mock.AnythingOfType("string")).
+					Return(&pb.FileRequestLink{Id: testLinkID, FolderId: testFolderID}, nil)
+			},
+			expectedTypes: []string{"application/pdf", "image/*"},
+		},
+		{
+			name:          "deadline in the past",
+			request:       &pb.CreateFileRequestLinkRequest{UserId: testUserID, FolderId: testFolderID, Deadline: timestamppb.New(time.Now().Add(-time.Hour))},
+			mockSetup:     func(requests *MockFileRequestRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "negative maximum file size",
+			request:       &pb.CreateFileRequestLinkRequest{UserId: testUserID, FolderId: testFolderID, MaxFileSize: -1},
+			mockSetup:     func(requests *MockFileRequestRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "invalid MIME type",
+			request:       &pb.CreateFileRequestLinkRequest{UserId: testUserID, FolderId: testFolderID, AllowedMimeTypes: []string{"pdf"}},
+			mockSetup:     func(requests *MockFileRequestRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "missing folder",
+			request:       &pb.CreateFileRequestLinkRequest{UserId: testUserID},
+			mockSetup:     func(requests *MockFileRequestRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			service.publicURL = "https://drive.example.com"
+			mockRequests := withFileRequests(service)
+			tt.mockSetup(mockRequests)
+
+			resp, err := service.CreateFileRequestLink(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testLinkID, resp.Link.Id)
+				assert.Equal(t, "https://drive.example.com/r/"+resp.Token, resp.Url)
+				mockRequests.AssertCalled(t, "Create", mock.Anything,
+					mock.MatchedBy(func(req *pb.CreateFileRequestLinkRequest) bool {
+						return assert.ObjectsAreEqual(tt.expectedTypes, req.AllowedMimeTypes)
+					}), hashLinkToken(resp.Token))
+			}
+
+			mockRequests.AssertExpectations(t)
+		})
+	}
+
+	t.Run("only the owner creates file requests", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockShares := withShares(service)
+		mockShares.On("FolderAccess", mock.Anything, testFolderID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleEditor}, nil)
+
+		_, err := service.CreateFileRequestLink(context.Background(), &pb.CreateFileRequestLinkRequest{UserId: testGranteeID, FolderId: testFolderID})
+
+		assertStatusCode(t, err, codes.PermissionDenied)
+	})
+}
+
+func TestFileService_SubmitFileRequest(t *testing.T) {
+	openLink := &pb.FileRequestLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID}
+	const storageLimit = int64(15 << 30)
+
+	tests := []struct {
+		name          string
+		request       *pb.SubmitFileRequestRequest
+		mockSetup     func(*MockFileRequestRepository, *MockFileRepository)
+		expectedError bool
+		errorCode     codes.Code
+		validate      func(*testing.T, *pb.SubmitFileRequestResponse)
+	}{
+		{
+			name: "file submitted",
+			request: &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf", Size: 1024,
+				MimeType: "application/pdf", UploaderName: " Ada ", UploaderEmail: "ada@example.com"},
+			mockSetup: func(requests *MockFileRequestRepository, files *MockFileRepository) {
+				requests.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).
+					Return(&pb.FileRequestLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID,
+						MaxFileSize: 4096, AllowedMimeTypes: []string{"application/pdf"}}, nil)
+				files.On("Create", mock.Anything, &pb.CreateFileRequest{Name: "scan.pdf", UserId: testUserID,
+					FolderId: testFolderID, Size: 1024, MimeType: "application/pdf"}, storageLimit).
+					Return(&pb.File{Id: testFileID, Name: "scan.pdf", UserId: testUserID}, nil)
+				requests.On("RecordSubmission", mock.Anything, testLinkID, testFileID, "Ada", "ada@example.com").Return(nil)
+			},
+			validate: func(t *testing.T, resp *pb.SubmitFileRequestResponse) {
+				assert.Equal(t, testFileID, resp.File.Id)
+				assert.Equal(t, int64(1024), resp.MaxSize)
+			},
+		},
+		{
+			name: "taken name gets a number",
+			request: &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf",
+				UploaderName: "Ada", UploaderEmail: "ada@example.com"},
+			mockSetup: func(requests *MockFileRequestRepository, files *MockFileRepository) {
+				requests.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).
+					Return(&pb.FileRequestLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID, MaxFileSize: 4096}, nil)
+				files.On("Create", mock.Anything, mock.MatchedBy(func(req *pb.CreateFileRequest) bool { return req.Name == "scan.pdf" }), storageLimit).
+					Return(nil, repository.ErrNameConflict)
+				files.On("Create", mock.Anything, mock.MatchedBy(func(req *pb.CreateFileRequest) bool { return req.Name == "scan (1).pdf" }), storageLimit).
+					Return(&pb.File{Id: testFileID, Name: "scan (1).pdf", UserId: testUserID}, nil)
+				requests.On("RecordSubmission", mock.Anything, testLinkID, testFileID, "Ada", "ada@example.com").Return(nil)
+			},
+			validate: func(t *testing.T, resp *pb.SubmitFileRequestResponse) {
+				assert.Equal(t, "scan (1).pdf", resp.File.Name)
+				assert.Equal(t, int64(4096), resp.MaxSize)
+			},
+		},
+		{
+			name: "type not accepted",
+			request: &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "run.exe", MimeType: "application/x-msdownload",
+				UploaderName: "Ada", UploaderEmail: "ada@example.com"},
+			mockSetup: func(requests *MockFileRequestRepository, files *MockFileRepository) {
+				requests.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).
+					Return(&pb.FileRequestLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID,
+						AllowedMimeTypes: []string{"image/*"}}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "too large",
+			request: &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf", Size: 8192,
+				UploaderName: "Ada", UploaderEmail: "ada@example.com"},
+			mockSetup: func(requests *MockFileRequestRepository, files *MockFileRepository) {
+				requests.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).
+					Return(&pb.FileRequestLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID, MaxFileSize: 4096}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "past the deadline",
+			request: &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf",
+				UploaderName: "Ada", UploaderEmail: "ada@example.com"},
+			mockSetup: func(requests *MockFileRequestRepository, files *MockFileRepository) {
+				requests.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).
+					Return(&pb.FileRequestLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID,
+						Deadline: timestamppb.New(time.Now().Add(-time.Minute))}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.FailedPrecondition,
+		},
+		{
+			name:          "invalid email",
+			request:       &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf", UploaderName: "Ada", UploaderEmail: "ada"},
+			mockSetup:     func(requests *MockFileRequestRepository, files *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "missing uploader name",
+			request:       &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf", UploaderEmail: "ada@example.com"},
+			mockSetup:     func(requests *MockFileRequestRepository, files *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "revoked link",
+			request: &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf",
+				UploaderName: "Ada", UploaderEmail: "ada@example.com"},
+			mockSetup: func(requests *MockFileRequestRepository, files *MockFileRepository) {
+				requests.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).Return(nil, repository.ErrFileRequestNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+		{
+			name: "submission not recorded",
+			request: &pb.SubmitFileRequestRequest{Token: testLinkToken, Name: "scan.pdf",
+				UploaderName: "Ada", UploaderEmail: "ada@example.com"},
+			mockSetup: func(requests *MockFileRequestRepository, files *MockFileRepository) {
+				requests.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).Return(openLink, nil)
+				files.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateFileRequest"), storageLimit).
+					Return(&pb.File{Id: testFileID, Name: "scan.pdf", UserId: testUserID}, nil)
+				requests.On("RecordSubmission", mock.Anything, testLinkID, testFileID, "Ada", "ada@example.com").
+					Return(repository.ErrFileRequestNotFound)
+				files.On("Discard", mock.Anything, testFileID, testUserID).Return([]string(nil), nil)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			service := newTestService(t, mockRepo)
+			mockRequests := withFileRequests(service)
+			tt.mockSetup(mockRequests, mockRepo)
+
+			resp, err := service.SubmitFileRequest(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				if tt.validate != nil {
+					tt.validate(t, resp)
+				}
+			}
+
+			mockRequests.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_CompleteUpload_SubmittedType(t *testing.T) {
+	storageKey := testUserID + "/" + testFileID
+	checksum := sha256Hex(pngContent)
+
+	tests := []struct {
+		name          string
+		allowed       []string
+		expectedError bool
+	}{
+		{name: "detected type accepted", allowed: []string{"image/*"}},
+		{name: "detected type refused", allowed: []string{"application/pdf"}, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockFileRepository)
+			// The uploader declared a PDF, which the request accepts, but sent an image
+			mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+				Return(&pb.File{Id: testFileID, UserId: testUserID, Name: "scan.pdf", StorageKey: storageKey,
+					MimeType: "application/pdf", DeclaredMimeType: "application/pdf"}, nil)
+			service := newTestService(t, mockRepo)
+			mockRequests := withFileRequests(service)
+			mockRequests.On("GetBySubmission", mock.Anything, testFileID).
+				Return(&pb.FileRequestLink{Id: testLinkID, OwnerId: testUserID, AllowedMimeTypes: tt.allowed}, nil)
+			if tt.expectedError {
+				mockRepo.On("Discard", mock.Anything, testFileID, testUserID).Return([]string(nil), nil)
+			} else {
+				mockRepo.On("UpdateContent", mock.Anything, testFileID, testUserID, repository.BlobKey(checksum), int64(len(pngContent)), checksum, mock.Anything, mock.Anything).
+					Return(&pb.File{Id: testFileID, MimeType: "image/png", Checksum: checksum}, nil)
+			}
+			_, err := service.blobs.Put(ctx, storageKey, strings.NewReader(pngContent), int64(len(pngContent)))
+			require.NoError(t, err)
+
+			resp, err := service.CompleteUpload(ctx, &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: checksum})
+
+			if tt.expectedError {
+				assertStatusCode(t, err, codes.InvalidArgument)
+				_, err = service.blobs.Stat(ctx, storageKey)
+				assert.Error(t, err, "refused content should be deleted")
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "image/png", resp.File.MimeType)
+			}
+			mockRequests.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_ListFileRequestSubmissions(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	mockRequests := withFileRequests(service)
+	mockRequests.On("ListSubmissions", mock.Anything, testLinkID, testGranteeID, int32(1), int32(20)).
+		Return(nil, int32(0), repository.ErrFileRequestNotFound)
+
+	_, err := service.ListFileRequestSubmissions(context.Background(), &pb.ListFileRequestSubmissionsRequest{Id: testLinkID, UserId: testGranteeID})
+
+	assertStatusCode(t, err, codes.NotFound)
+	mockRequests.AssertExpectations(t)
+}
+
+func TestNumberedName(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		expected string
+	}{
+		{name: "report.pdf", n: 1, expected: "report (1).pdf"},
+		{name: "archive.tar.gz", n: 2, expected: "archive.tar (2).gz"},
+		{name: "README", n: 3, expected: "README (3)"},
+		{name: ".env", n: 1, expected: ".env (1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, numberedName(tt.name, tt.n))
+		})
+	}
+}
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-drive/internal/antivirus"
+	"go-drive/internal/domain"
+	"go-drive/internal/keyring"
+	"go-drive/internal/signedurl"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// signedURLTTL is how long issued upload and download URLs stay valid
+const signedURLTTL = 15 * time.Minute
+
+type FileService struct {
+	pb.UnimplementedFileServiceServer
+	repo           repository.FileRepository
+	uploads        repository.UploadRepository
+	folders        repository.FolderRepository
+	trash          repository.TrashRepository
+	versions       repository.VersionRepository
+	blobRefs       repository.BlobRepository
+	usage          repository.UsageRepository
+	shares         repository.ShareRepository
+	links          repository.ShareLinkRepository
+	fileRequests   repository.FileRequestRepository
+	search         repository.SearchRepository
+	contentIndex   repository.ContentIndexRepository
+	thumbnails     repository.ThumbnailRepository
+	scans          repository.ScanRepository
+	accessKeys     repository.AccessKeyRepository
+	scanner        antivirus.Scanner
+	skipScans      bool
+	keys           *keyring.Keyring
+	blobs          storage.Backend
+	urls           *signedurl.Signer
+	publicURL      string
+	trashRetention time.Duration
+	quotas         map[string]Quota
+	fileTypes      FileTypePolicy
+	now            func() time.Time
+	// indexQueued wakes the content indexer when new content is committed
+	indexQueued chan struct{}
+	// renderQueued wakes the thumbnailer when new content is committed
+	renderQueued chan struct{}
+	// scanQueued wakes the malware scanner when new content is committed
+	scanQueued chan struct{}
+}
+
+// Repositories holds the stores the file service keeps its metadata in
+type Repositories struct {
+	Files      repository.FileRepository
+	Uploads    repository.UploadRepository
+	Folders    repository.FolderRepository
+	Trash      repository.TrashRepository
+	Versions   repository.VersionRepository
+	Blobs      repository.BlobRepository
+	Usage      repository.UsageRepository
+	Shares     repository.ShareRepository
+	Links      repository.ShareLinkRepository
+	Requests   repository.FileRequestRepository
+	Search     repository.SearchRepository
+	Content    repository.ContentIndexRepository
+	Thumbnails repository.ThumbnailRepository
+	Scans      repository.ScanRepository
+	AccessKeys repository.AccessKeyRepository
+}
+
+// Options tunes the file service. Zero values select the defaults.
+type Options struct {
+	// TrashRetention is how long deleted items stay in the trash before they are purged
+	TrashRetention time.Duration
+	// Quotas overrides the DefaultQuotas of the user types it lists
+	Quotas map[string]Quota
+	// PublicURL is the gateway's base URL, which share link and file request URLs are built on
+	PublicURL string
+	// FileTypes is the policy for the kinds of files users may store; nil selects
+	// DefaultFileTypePolicy
+	FileTypes *FileTypePolicy
+	// Scanner scans new content for malware before it can be downloaded or shared. Without
+	// one, content stays pending and cannot be handed out unless SkipScans is set.
+	Scanner antivirus.Scanner
+	// SkipScans lets content through unscanned when there is no Scanner. It is marked
+	// unscanned rather than clean.
+	SkipScans bool
+	// Keyring wraps the secrets of access keys at rest; nil stores them as they are
+	Keyring *keyring.Keyring
+}
+
+func NewFileService(repos Repositories, blobs storage.Backend, urls *signedurl.Signer, opts Options) *FileService {
+	if opts.TrashRetention <= 0 {
+		opts.TrashRetention = defaultTrashRetention
+	}
+	quotas := make(map[string]Quota, len(DefaultQuotas))
+	for userType, quota := range DefaultQuotas {
+		quotas[userType] = quota
+	}
+	for userType, quota := range opts.Quotas {
+		quotas[userType] = quota
+	}
+	fileTypes := DefaultFileTypePolicy
+	if opts.FileTypes != nil {
+		fileTypes = *opts.FileTypes
+	}
+
+	return &FileService{
+		repo:           repos.Files,
+		uploads:        repos.Uploads,
+		folders:        repos.Folders,
+		trash:          repos.Trash,
+		versions:       repos.Versions,
+		blobRefs:       repos.Blobs,
+		usage:          repos.Usage,
+		shares:         repos.Shares,
+		links:          repos.Links,
+		fileRequests:   repos.Requests,
+		search:         repos.Search,
+		contentIndex:   repos.Content,
+		thumbnails:     repos.Thumbnails,
+		scans:          repos.Scans,
+		accessKeys:     repos.AccessKeys,
+		scanner:        opts.Scanner,
+		skipScans:      opts.SkipScans,
+		keys:           opts.Keyring,
+		blobs:          blobs,
+		urls:           urls,
+		publicURL:      strings.TrimRight(opts.PublicURL, "/"),
+		trashRetention: opts.TrashRetention,
+		quotas:         quotas,
+		fileTypes:      fileTypes,
+		now:            time.Now,
+		indexQueued:    make(chan struct{}, 1),
+		renderQueued:   make(chan struct{}, 1),
+		scanQueued:     make(chan struct{}, 1),
+	}
+}
+
+func (s *FileService) CreateFile(ctx context.Context, req *pb.CreateFileRequest) (*pb.CreateFileResponse, error) {
+	// Validate request
+	if err := validateName("name", req.Name); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.FolderId != "" {
+		if err := validateID("folder_id", req.FolderId); err != nil {
+			return nil, err
+		}
+	}
+	if req.Size < 0 {
+		return nil, status.Error(codes.InvalidArgument, "size must not be negative")
+	}
+	if err := s.checkFileType(req.Name, req.MimeType); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeParent(ctx, req.FolderId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := s.userQuota(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFileSize(quota, req.Size); err != nil {
+		return nil, err
+	}
+
+	file, err := s.repo.Create(ctx, &pb.CreateFileRequest{
+		Name:     req.Name,
+		UserId:   owner,
+		FolderId: req.FolderId,
+		Size:     req.Size,
+		MimeType: req.MimeType,
+	}, quota.Storage)
+	if err != nil {
+		return nil, repoError("create file", err)
+	}
+
+	maxSize := req.Size
+	if maxSize == 0 {
+		maxSize = fileSizeLimit(quota)
+	}
+
+	return &pb.CreateFileResponse{
+		File:      file,
+		UploadUrl: s.uploadURL(file, file.StorageKey, maxSize),
+		MaxSize:   maxSize,
+	}, nil
+}
+
+func (s *FileService) GetFile(ctx context.Context, req *pb.GetFileRequest) (*pb.GetFileResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFile(ctx, req.Id, req.UserId, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.Id, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+	index, err := s.contentIndex.Get(ctx, file.Id)
+	if err != nil && !errors.Is(err, repository.ErrContentIndexNotFound) {
+		return nil, repoError("get content index", err)
+	}
+
+	return &pb.GetFileResponse{
+		File:         file,
+		ContentIndex: index,
+		DownloadUrl: s.urls.Sign(signedurl.Grant{
+			Method:      http.MethodGet,
+			FileID:      file.Id,
+			UserID:      file.UserId,
+			Key:         file.StorageKey,
+			ContentType: file.MimeType,
+			ExpiresAt:   s.now().Add(signedURLTTL),
+		}),
+	}, nil
+}
+
+func (s *FileService) ListFiles(ctx context.Context, req *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	owner := req.UserId
+	if req.FolderId != nil && *req.FolderId != "" {
+		if err := validateID("folder_id", *req.FolderId); err != nil {
+			return nil, err
+		}
+		var err error
+		if owner, err = s.authorizeFolder(ctx, *req.FolderId, req.UserId, domain.ShareRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	files, totalCount, err := s.repo.List(ctx, owner, req.FolderId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list files", err)
+	}
+
+	return &pb.ListFilesResponse{
+		Files:      files,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (s *FileService) DeleteFile(ctx context.Context, req *pb.DeleteFileRequest) (*pb.DeleteFileResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFile(ctx, req.Id, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.Id, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+
+	// A file whose first upload never completed has nothing worth restoring, so it skips
+	// the trash; this is how clients clean up after an upload that failed. Only owners may
+	// remove files for good, so an editor's goes to the owner's trash like any other.
+	if file.Checksum == "" && owner == req.UserId {
+		keys, err := s.repo.Discard(ctx, req.Id, owner)
+		if err != nil {
+			return nil, repoError("delete file", err)
+		}
+		s.deleteContent(ctx, keys)
+	} else if err := s.repo.Delete(ctx, req.Id, owner); err != nil {
+		return nil, repoError("delete file", err)
+	}
+
+	return &pb.DeleteFileResponse{
+		Message: "File deleted successfully",
+	}, nil
+}
+
+// MoveFile renames a file and moves it to another folder. Files only move within their
+// owner's drive, and a new name is held to the file type policy like a new file's.
+func (s *FileService) MoveFile(ctx context.Context, req *pb.MoveFileRequest) (*pb.MoveFileResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.FolderId != "" {
+		if err := validateID("folder_id", req.FolderId); err != nil {
+			return nil, err
+		}
+	}
+	if req.Name != "" {
+		if err := validateName("name", req.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	owner, err := s.authorizeFile(ctx, req.Id, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeMove(ctx, req.FolderId, req.UserId, owner); err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.Id, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+	name := file.Name
+	if req.Name != "" && req.Name != file.Name {
+		if err := s.checkFileType(req.Name, file.MimeType); err != nil {
+			return nil, err
+		}
+		name = req.Name
+	}
+
+	moved, err := s.repo.Move(ctx, req.Id, owner, req.FolderId, name)
+	if err != nil {
+		return nil, repoError("move file", err)
+	}
+
+	return &pb.MoveFileResponse{File: moved}, nil
+}
+
+func (s *FileService) GetUploadURL(ctx context.Context, req *pb.GetUploadURLRequest) (*pb.GetUploadURLResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.FileId != "" {
+		return s.getReplaceURL(ctx, req)
+	}
+	if err := validateName("file_name", req.FileName); err != nil {
+		return nil, err
+	}
+	if err := s.checkFileType(req.FileName, req.MimeType); err != nil {
+		return nil, err
+	}
+
+	quota, err := s.userQuota(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve the file record so the upload has a storage key to write to.
+	// Its size is filled in once the content has been uploaded.
+	file, err := s.repo.Create(ctx, &pb.CreateFileRequest{
+		Name:     req.FileName,
+		UserId:   req.UserId,
+		MimeType: req.MimeType,
+	}, quota.Storage)
+	if err != nil {
+		return nil, repoError("reserve upload", err)
+	}
+
+	maxSize := fileSizeLimit(quota)
+	return &pb.GetUploadURLResponse{
+		UploadUrl:  s.uploadURL(file, file.StorageKey, maxSize),
+		FileId:     file.Id,
+		StorageKey: file.StorageKey,
+		MaxSize:    maxSize,
+	}, nil
+}
+
+// getReplaceURL issues an upload URL for new content of an existing file. The content is
+// written to a fresh storage key so that the current content survives as a version.
+func (s *FileService) getReplaceURL(ctx context.Context, req *pb.GetUploadURLRequest) (*pb.GetUploadURLResponse, error) {
+	if err := validateID("file_id", req.FileId); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFile(ctx, req.FileId, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.FileId, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+	quota, err := s.userQuota(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	key := repository.ContentKey(uuid.MustParse(file.UserId), uuid.MustParse(file.Id))
+	maxSize := fileSizeLimit(quota)
+	return &pb.GetUploadURLResponse{
+		UploadUrl:  s.uploadURL(file, key, maxSize),
+		FileId:     file.Id,
+		StorageKey: key,
+		MaxSize:    maxSize,
+	}, nil
+}
+
+// CompleteUpload records content uploaded through a signed URL. New content replaces the
+// file's current content, which is kept as a version. Without a checksum the uploaded
+// content is hashed here, since it is stored under its checksum.
+func (s *FileService) CompleteUpload(ctx context.Context, req *pb.CompleteUploadRequest) (*pb.CompleteUploadResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if err := validateChecksum(req.Checksum); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFile(ctx, req.Id, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.Id, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+
+	key := file.StorageKey
+	if req.StorageKey != "" {
+		if !repository.IsContentKey(uuid.MustParse(file.UserId), uuid.MustParse(file.Id), req.StorageKey) {
+			return nil, status.Error(codes.InvalidArgument, "storage_key does not belong to this file")
+		}
+		key = req.StorageKey
+	}
+
+	// The stored object is the source of truth for the size
+	info, err := s.blobs.Stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Error(codes.FailedPrecondition, "no content has been uploaded for this file")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to stat file content: %v", err)
+	}
+	quota, err := s.userQuota(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFileSize(quota, info.Size); err != nil {
+		// Staged content is dropped; the file's current content, if any, is left alone
+		if key != file.StorageKey || file.Checksum == "" {
+			s.deleteBlobs(ctx, []string{key})
+		}
+		return nil, err
+	}
+
+	checksum := req.Checksum
+	if checksum == "" {
+		if checksum, err = s.hashContent(ctx, key); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hash file content: %v", err)
+		}
+	}
+
+	completed, err := s.commitContent(ctx, file, key, info.Size, checksum, quota.Storage)
+	if err != nil {
+		if errors.Is(err, errFileTypeNotRequested) {
+			s.discardFile(file)
+		}
+		return nil, repoError("complete upload", err)
+	}
+
+	return &pb.CompleteUploadResponse{File: completed}, nil
+}
+
+// uploadURL signs a URL that accepts up to maxSize bytes of content for file, stored under key
+func (s *FileService) uploadURL(file *pb.File, key string, maxSize int64) string {
+	return s.urls.Sign(signedurl.Grant{
+		Method:      http.MethodPut,
+		FileID:      file.Id,
+		UserID:      file.UserId,
+		Key:         key,
+		MaxSize:     maxSize,
+		ContentType: file.MimeType,
+		ExpiresAt:   s.now().Add(signedURLTTL),
+	})
+}
+
+// validateID checks that a required request field holds a valid UUID
+func validateID(field, value string) error {
+	if value == "" {
+		return status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s must be a valid UUID", field)
+	}
+	return nil
+}
+
+// validateChecksum checks that an optional checksum is a hex-encoded SHA-256 digest
+func validateChecksum(checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	if b, err := hex.DecodeString(checksum); err != nil || len(b) != sha256.Size {
+		return status.Error(codes.InvalidArgument, "checksum must be a hex-encoded SHA-256 digest")
+	}
+	return nil
+}
+
+// repoError maps repository errors to gRPC status errors
+func repoError(action string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrFileNotFound),
+		errors.Is(err, repository.ErrFolderNotFound),
+		errors.Is(err, repository.ErrUploadNotFound),
+		errors.Is(err, repository.ErrPathNotFound),
+		errors.Is(err, repository.ErrNotInTrash),
+		errors.Is(err, repository.ErrVersionNotFound),
+		errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, repository.ErrShareNotFound),
+		errors.Is(err, repository.ErrShareLinkNotFound),
+		errors.Is(err, repository.ErrFileRequestNotFound),
+		errors.Is(err, repository.ErrAccessKeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrNameConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, errFileTypeBlocked):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, errFileTypeNotRequested):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrUploadOffsetMismatch),
+		errors.Is(err, repository.ErrFolderCycle):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, repository.ErrQuotaExceeded),
+		errors.Is(err, repository.ErrDownloadLimitReached):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Errorf(codes.Internal, "failed to %s: %v", action, err)
+}
@@ -0,0 +1,746 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/signedurl"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const (
+	testUserID   = "123e4567-e89b-12d3-a456-426614174000"
+	testFileID   = "223e4567-e89b-12d3-a456-426614174000"
+	testFolderID = "323e4567-e89b-12d3-a456-426614174000"
+)
+
+// MockFileRepository is a mock implementation of FileRepository
+type MockFileRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileRepository) Create(ctx context.Context, req *pb.CreateFileRequest, storageLimit int64) (*pb.File, error) {
+	args := m.Called(ctx, req, storageLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.File), args.Error(1)
+}
+
+func (m *MockFileRepository) GetByID(ctx context.Context, id, userID string) (*pb.File, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.File), args.Error(1)
+}
+
+func (m *MockFileRepository) List(ctx context.Context, userID string, folderID *string, page, pageSize int32) ([]*pb.File, int32, error) {
+	args := m.Called(ctx, userID, folderID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.File), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockFileRepository) Delete(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockFileRepository) Discard(ctx context.Context, id, userID string) ([]string, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFileRepository) Move(ctx context.Context, id, userID, folderID, name string) (*pb.File, error) {
+	args := m.Called(ctx, id, userID, folderID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.File), args.Error(1)
+}
+
+func (m *MockFileRepository) UpdateContent(ctx context.Context, id, userID, storageKey string, size int64, checksum string, typ repository.ContentType, storageLimit int64) (*pb.File, error) {
+	args := m.Called(ctx, id, userID, storageKey, size, checksum, typ, storageLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.File), args.Error(1)
+}
+
+func (m *MockFileRepository) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockFileRepository) HealthCheck(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// newTestService creates a FileService backed by a temporary local storage directory
+func newTestService(t *testing.T, repo repository.FileRepository) *FileService {
+	t.Helper()
+	blobs, err := storage.NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+	urls, err := signedurl.NewSigner("test-secret", "http://localhost:8080")
+	require.NoError(t, err)
+	blobRefs := new(MockBlobRepository)
+	blobRefs.On("Collect", mock.Anything, collectBatchSize, mock.Anything).Return(0, nil).Maybe()
+	usage := new(MockUsageRepository)
+	usage.On("Get", mock.Anything, mock.Anything).Return(&repository.Usage{UserType: domain.UserTypeStandard}, nil).Maybe()
+	contentIndex := new(MockContentIndexRepository)
+	contentIndex.On("Get", mock.Anything, mock.Anything).Return(nil, repository.ErrContentIndexNotFound).Maybe()
+	thumbnails := new(MockThumbnailRepository)
+	thumbnails.On("Get", mock.Anything, mock.Anything).Return(nil, repository.ErrThumbnailsNotFound).Maybe()
+	requests := new(MockFileRequestRepository)
+	requests.On("GetBySubmission", mock.Anything, mock.Anything).Return(nil, repository.ErrFileRequestNotFound).Maybe()
+	return NewFileService(Repositories{
+		Files:      repo,
+		Uploads:    new(MockUploadRepository),
+		Folders:    new(MockFolderRepository),
+		Trash:      new(MockTrashRepository),
+		Versions:   new(MockVersionRepository),
+		Blobs:      blobRefs,
+		Usage:      usage,
+		Shares:     ownerShares{},
+		Links:      new(MockShareLinkRepository),
+		Requests:   requests,
+		Search:     new(MockSearchRepository),
+		Content:    contentIndex,
+		Thumbnails: thumbnails,
+		Scans:      new(MockScanRepository),
+		AccessKeys: new(MockAccessKeyRepository),
+	}, blobs, urls, Options{})
+}
+
+func assertStatusCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	assert.Error(t, err)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, code, st.Code())
+}
+
+func TestFileService_CreateFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.CreateFileRequest
+		mockSetup     func(*MockFileRepository)
+		expectedError bool
+		errorCode     codes.Code
+		validate      func(*testing.T, *pb.CreateFileResponse)
+	}{
+		{
+			name: "successful file creation",
+			request: &pb.CreateFileRequest{
+				Name:     "report.pdf",
+				UserId:   testUserID,
+				FolderId: testFolderID,
+				Size:     1024,
+				MimeType: "application/pdf",
+			},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateFileRequest"), mock.Anything).
+					Return(&pb.File{
+						Id:         testFileID,
+						Name:       "report.pdf",
+						UserId:     testUserID,
+						FolderId:   testFolderID,
+						Size:       1024,
+						MimeType:   "application/pdf",
+						StorageKey: testUserID + "/" + testFileID,
+						CreatedAt:  timestamppb.Now(),
+						UpdatedAt:  timestamppb.Now(),
+					}, nil)
+			},
+			validate: func(t *testing.T, resp *pb.CreateFileResponse) {
+				assert.NotNil(t, resp.File)
+				assert.Equal(t, testFileID, resp.File.Id)
+				assert.Equal(t, "report.pdf", resp.File.Name)
+				assert.Contains(t, resp.UploadUrl, "/api/v1/blobs/"+testFileID+"?")
+				assert.Contains(t, resp.UploadUrl, "method=PUT")
+				assert.Contains(t, resp.UploadUrl, "max_size=1024")
+			},
+		},
+		{
+			name: "missing name",
+			request: &pb.CreateFileRequest{
+				UserId: testUserID,
+			},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "invalid user id",
+			request: &pb.CreateFileRequest{
+				Name:   "report.pdf",
+				UserId: "not-a-uuid",
+			},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "negative size",
+			request: &pb.CreateFileRequest{
+				Name:   "report.pdf",
+				UserId: testUserID,
+				Size:   -1,
+			},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "folder not found",
+			request: &pb.CreateFileRequest{
+				Name:     "report.pdf",
+				UserId:   testUserID,
+				FolderId: testFolderID,
+			},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateFileRequest"), mock.Anything).
+					Return(nil, repository.ErrFolderNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+		{
+			name: "repository error",
+			request: &pb.CreateFileRequest{
+				Name:   "report.pdf",
+				UserId: testUserID,
+			},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateFileRequest"), mock.Anything).
+					Return(nil, errors.New("database connection failed"))
+			},
+			expectedError: true,
+			errorCode:     codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			tt.mockSetup(mockRepo)
+
+			service := newTestService(t, mockRepo)
+			resp, err := service.CreateFile(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, resp)
+				if tt.validate != nil {
+					tt.validate(t, resp)
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_GetFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.GetFileRequest
+		mockSetup     func(*MockFileRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "successful file retrieval",
+			request: &pb.GetFileRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID, Name: "report.pdf", UserId: testUserID}, nil)
+			},
+		},
+		{
+			name:          "missing file id",
+			request:       &pb.GetFileRequest{UserId: testUserID},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "missing user id",
+			request:       &pb.GetFileRequest{Id: testFileID},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "file not found",
+			request: &pb.GetFileRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(nil, repository.ErrFileNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			tt.mockSetup(mockRepo)
+
+			service := newTestService(t, mockRepo)
+			resp, err := service.GetFile(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testFileID, resp.File.Id)
+				assert.Contains(t, resp.DownloadUrl, "method=GET")
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_ListFiles(t *testing.T) {
+	folderID := testFolderID
+	badFolderID := "not-a-uuid"
+
+	tests := []struct {
+		name          string
+		request       *pb.ListFilesRequest
+		mockSetup     func(*MockFileRepository)
+		expectedError bool
+		errorCode     codes.Code
+		expectedCount int32
+	}{
+		{
+			name:    "defaults pagination",
+			request: &pb.ListFilesRequest{UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("List", mock.Anything, testUserID, (*string)(nil), int32(1), int32(20)).
+					Return([]*pb.File{{Id: testFileID}}, int32(1), nil)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:    "filter by folder",
+			request: &pb.ListFilesRequest{UserId: testUserID, FolderId: &folderID, Page: 2, PageSize: 10},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("List", mock.Anything, testUserID, &folderID, int32(2), int32(10)).
+					Return([]*pb.File{}, int32(0), nil)
+			},
+			expectedCount: 0,
+		},
+		{
+			name:          "invalid folder id",
+			request:       &pb.ListFilesRequest{UserId: testUserID, FolderId: &badFolderID},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "repository error",
+			request: &pb.ListFilesRequest{UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("List", mock.Anything, testUserID, (*string)(nil), int32(1), int32(20)).
+					Return(nil, int32(0), errors.New("database connection failed"))
+			},
+			expectedError: true,
+			errorCode:     codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			tt.mockSetup(mockRepo)
+
+			service := newTestService(t, mockRepo)
+			resp, err := service.ListFiles(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCount, resp.TotalCount)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_DeleteFile(t *testing.T) {
+	stored := &pb.File{Id: testFileID, UserId: testUserID, StorageKey: "blobs/ab/abcd", Checksum: strings.Repeat("ab", 32)}
+	staged := &pb.File{Id: testFileID, UserId: testUserID, StorageKey: "staged/" + testFileID}
+
+	tests := []struct {
+		name          string
+		request       *pb.DeleteFileRequest
+		mockSetup     func(*MockFileRepository)
+		discarded     bool
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "successful deletion",
+			request: &pb.DeleteFileRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(stored, nil)
+				repo.On("Delete", mock.Anything, testFileID, testUserID).Return(nil)
+			},
+		},
+		{
+			name:    "file without content skips the trash",
+			request: &pb.DeleteFileRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(staged, nil)
+				repo.On("Discard", mock.Anything, testFileID, testUserID).Return([]string{staged.StorageKey}, nil)
+			},
+			discarded: true,
+		},
+		{
+			name:          "missing file id",
+			request:       &pb.DeleteFileRequest{UserId: testUserID},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "file not found",
+			request: &pb.DeleteFileRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(nil, repository.ErrFileNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			tt.mockSetup(mockRepo)
+
+			service := newTestService(t, mockRepo)
+			_, err := service.blobs.Put(context.Background(), staged.StorageKey, strings.NewReader("partial"), -1)
+			require.NoError(t, err)
+
+			resp, err := service.DeleteFile(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "File deleted successfully", resp.Message)
+			}
+			_, err = service.blobs.Stat(context.Background(), staged.StorageKey)
+			assert.Equal(t, tt.discarded, errors.Is(err, storage.ErrNotFound), "staged content is only removed with a discarded file")
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("editor's file without content goes to the trash", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(staged, nil)
+		mockRepo.On("Delete", mock.Anything, testFileID, testUserID).Return(nil)
+		service := newTestService(t, mockRepo)
+		mockShares := withShares(service)
+		mockShares.On("FileAccess", mock.Anything, testFileID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleEditor}, nil)
+
+		_, err := service.DeleteFile(context.Background(), &pb.DeleteFileRequest{Id: testFileID, UserId: testGranteeID})
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestFileService_MoveFile(t *testing.T) {
+	report := &pb.File{Id: testFileID, UserId: testUserID, Name: "report.pdf", MimeType: "application/pdf"}
+
+	tests := []struct {
+		name          string
+		request       *pb.MoveFileRequest
+		mockSetup     func(*MockFileRepository)
+		expectedName  string
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "rename and move",
+			request: &pb.MoveFileRequest{Id: testFileID, UserId: testUserID, FolderId: testFolderID, Name: "final.pdf"},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(report, nil)
+				repo.On("Move", mock.Anything, testFileID, testUserID, testFolderID, "final.pdf").
+					Return(&pb.File{Id: testFileID, Name: "final.pdf", FolderId: testFolderID}, nil)
+			},
+			expectedName: "final.pdf",
+		},
+		{
+			name:    "move to root keeps the name",
+			request: &pb.MoveFileRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(report, nil)
+				repo.On("Move", mock.Anything, testFileID, testUserID, "", "report.pdf").
+					Return(&pb.File{Id: testFileID, Name: "report.pdf"}, nil)
+			},
+			expectedName: "report.pdf",
+		},
+		{
+			name:    "blocked name",
+			request: &pb.MoveFileRequest{Id: testFileID, UserId: testUserID, Name: "report.exe"},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(report, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.PermissionDenied,
+		},
+		{
+			name:    "name taken",
+			request: &pb.MoveFileRequest{Id: testFileID, UserId: testUserID, FolderId: testFolderID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(report, nil)
+				repo.On("Move", mock.Anything, testFileID, testUserID, testFolderID, "report.pdf").Return(nil, repository.ErrNameConflict)
+			},
+			expectedError: true,
+			errorCode:     codes.AlreadyExists,
+		},
+		{
+			name:          "invalid name",
+			request:       &pb.MoveFileRequest{Id: testFileID, UserId: testUserID, Name: "a/b"},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			tt.mockSetup(mockRepo)
+
+			service := newTestService(t, mockRepo)
+			service.fileTypes.Blocked = []string{".exe"}
+			resp, err := service.MoveFile(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedName, resp.File.Name)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_MoveFile_SharedFileToRoot(t *testing.T) {
+	mockRepo := new(MockFileRepository)
+	service := newTestService(t, mockRepo)
+	mockShares := withShares(service)
+	mockShares.On("FileAccess", mock.Anything, testFileID, testGranteeID).
+		Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleEditor}, nil)
+
+	_, err := service.MoveFile(context.Background(), &pb.MoveFileRequest{Id: testFileID, UserId: testGranteeID})
+
+	assertStatusCode(t, err, codes.PermissionDenied)
+	mockRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFileService_GetUploadURL(t *testing.T) {
+	t.Run("reserves a file record", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("Create", mock.Anything, &pb.CreateFileRequest{
+			Name:     "photo.jpg",
+			UserId:   testUserID,
+			MimeType: "image/jpeg",
+		}, mock.Anything).Return(&pb.File{
+			Id:         testFileID,
+			UserId:     testUserID,
+			MimeType:   "image/jpeg",
+			StorageKey: testUserID + "/" + testFileID,
+		}, nil)
+
+		service := newTestService(t, mockRepo)
+		resp, err := service.GetUploadURL(context.Background(), &pb.GetUploadURLRequest{
+			FileName: "photo.jpg",
+			UserId:   testUserID,
+			MimeType: "image/jpeg",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, testFileID, resp.FileId)
+		assert.Contains(t, resp.UploadUrl, "/api/v1/blobs/"+testFileID+"?")
+		assert.Contains(t, resp.UploadUrl, "content_type=image%2Fjpeg")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("new content for an existing file", func(t *testing.T) {
+		storageKey := testUserID + "/" + testFileID
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+			Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+
+		service := newTestService(t, mockRepo)
+		resp, err := service.GetUploadURL(context.Background(), &pb.GetUploadURLRequest{
+			UserId: testUserID,
+			FileId: testFileID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, testFileID, resp.FileId)
+		assert.True(t, strings.HasPrefix(resp.StorageKey, storageKey+"."), "content goes to a fresh key")
+		assert.Contains(t, resp.UploadUrl, url.QueryEscape(resp.StorageKey))
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing file name", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		_, err := service.GetUploadURL(context.Background(), &pb.GetUploadURLRequest{UserId: testUserID})
+		assertStatusCode(t, err, codes.InvalidArgument)
+	})
+}
+
+func TestFileService_CompleteUpload(t *testing.T) {
+	storageKey := testUserID + "/" + testFileID
+	checksum := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name          string
+		request       *pb.CompleteUploadRequest
+		content       string
+		contentKey    string
+		mockSetup     func(*MockFileRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "records uploaded size",
+			request: &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: checksum},
+			content: "hello world",
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+				repo.On("UpdateContent", mock.Anything, testFileID, testUserID, repository.BlobKey(checksum), int64(11), checksum, mock.Anything, mock.Anything).
+					Return(&pb.File{Id: testFileID, Size: 11, Checksum: checksum}, nil)
+			},
+		},
+		{
+			name:    "hashes content without a checksum",
+			request: &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID},
+			content: "hello world",
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+				repo.On("UpdateContent", mock.Anything, testFileID, testUserID, repository.BlobKey(sha256Hex("hello world")), int64(11), sha256Hex("hello world"), mock.Anything, mock.Anything).
+					Return(&pb.File{Id: testFileID, Size: 11, Checksum: sha256Hex("hello world")}, nil)
+			},
+		},
+		{
+			name:       "new content under a fresh key",
+			request:    &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: checksum, StorageKey: storageKey + ".2"},
+			content:    "hello world",
+			contentKey: storageKey + ".2",
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+				repo.On("UpdateContent", mock.Anything, testFileID, testUserID, repository.BlobKey(checksum), int64(11), checksum, mock.Anything, mock.Anything).
+					Return(&pb.File{Id: testFileID, Size: 11, Checksum: checksum}, nil)
+			},
+		},
+		{
+			name:    "key of another file",
+			request: &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, StorageKey: testUserID + "/" + testFolderID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "nothing uploaded",
+			request: &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.FailedPrecondition,
+		},
+		{
+			name:          "invalid checksum",
+			request:       &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID, Checksum: "not-hex"},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "file not found",
+			request: &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID},
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(nil, repository.ErrFileNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			tt.mockSetup(mockRepo)
+
+			service := newTestService(t, mockRepo)
+			if tt.content != "" {
+				key := storageKey
+				if tt.contentKey != "" {
+					key = tt.contentKey
+				}
+				_, err := service.blobs.Put(context.Background(), key, strings.NewReader(tt.content), int64(len(tt.content)))
+				require.NoError(t, err)
+			}
+
+			resp, err := service.CompleteUpload(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(tt.content)), resp.File.Size)
+				_, err = service.blobs.Stat(context.Background(), repository.BlobKey(resp.File.Checksum))
+				assert.NoError(t, err, "content should be stored under its checksum")
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
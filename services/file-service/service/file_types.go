@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/filetype"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// errFileTypeBlocked is returned for files the file type policy does not allow
+var errFileTypeBlocked = errors.New("file type is not allowed")
+
+// FileTypePolicy decides which kinds of files may be stored. Entries are MIME types such
+// as application/x-elf, families such as video/*, or extensions such as .exe. A file
+// matches when its name, its declared type or the type detected from its content does.
+type FileTypePolicy struct {
+	// Blocked files are refused
+	Blocked []string
+	// Quarantined files are stored but cannot be downloaded
+	Quarantined []string
+}
+
+// DefaultFileTypePolicy quarantines executables, installers and scripts that run on a
+// double click, and blocks nothing
+var DefaultFileTypePolicy = FileTypePolicy{
+	Quarantined: []string{
+		"application/vnd.microsoft.portable-executable",
+		"application/x-elf",
+		"application/x-mach-binary",
+		"application/x-ms-installer",
+		"application/x-ms-shortcut",
+		".exe", ".dll", ".scr", ".com", ".msi", ".lnk", ".bat", ".cmd", ".ps1", ".vbs",
+	},
+}
+
+// ParseFileTypes parses a comma-separated list of file type policy entries, lowercasing
+// each. Blank entries are skipped.
+func ParseFileTypes(list string) ([]string, error) {
+	entries := []string{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case strings.HasPrefix(entry, "."):
+			if len(entry) == 1 || strings.ContainsAny(entry[1:], "./\\") {
+				return nil, fmt.Errorf("%q is not a file extension such as .exe", entry)
+			}
+		default:
+			mediaType, params, err := mime.ParseMediaType(entry)
+			major, minor, ok := strings.Cut(mediaType, "/")
+			if err != nil || len(params) > 0 || !ok || major == "*" || minor == "" {
+				return nil, fmt.Errorf("%q is not a MIME type such as application/x-elf or video/*", entry)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// check applies the policy to a file's name and types. It fails with errFileTypeBlocked
+// for blocked files and returns the reason a quarantined file is quarantined.
+func (p FileTypePolicy) check(name string, mimeTypes ...string) (string, error) {
+	if entry := matchFileType(p.Blocked, name, mimeTypes); entry != "" {
+		return "", fmt.Errorf("%w: %s", errFileTypeBlocked, entry)
+	}
+	if entry := matchFileType(p.Quarantined, name, mimeTypes); entry != "" {
+		return "file type " + entry, nil
+	}
+	return "", nil
+}
+
+// matchFileType returns the first entry the name or one of the types matches
+func matchFileType(entries []string, name string, mimeTypes []string) string {
+	ext := strings.ToLower(path.Ext(name))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry, ".") {
+			if entry == ext {
+				return entry
+			}
+			continue
+		}
+		for _, mimeType := range mimeTypes {
+			if mimeType != "" && filetype.Is(mimeType, entry) {
+				return entry
+			}
+		}
+	}
+	return ""
+}
+
+// checkFileType refuses a new file whose name or declared type is blocked. Its content
+// is checked again once it has been uploaded.
+func (s *FileService) checkFileType(name, mimeType string) error {
+	if _, err := s.fileTypes.check(name, mimeType); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// detectType works out the type of a file's content, stored under key, from its first
+// bytes and applies the file type policy to it. The content is left pending a malware scan
+// unless scanning is explicitly turned off, which marks it unscanned.
+func (s *FileService) detectType(ctx context.Context, file *pb.File, key string, size int64) (repository.ContentType, error) {
+	var header []byte
+	if size > 0 {
+		body, _, err := s.blobs.GetRange(ctx, key, 0, filetype.HeaderSize)
+		if err != nil {
+			return repository.ContentType{}, fmt.Errorf("failed to read content: %w", err)
+		}
+		defer body.Close()
+		if header, err = io.ReadAll(body); err != nil {
+			return repository.ContentType{}, fmt.Errorf("failed to read content: %w", err)
+		}
+	}
+
+	mimeType, mismatch := filetype.Resolve(file.DeclaredMimeType, header)
+	reason, err := s.fileTypes.check(file.Name, file.DeclaredMimeType, mimeType)
+	if err != nil {
+		return repository.ContentType{}, err
+	}
+	scanStatus := domain.ScanPending
+	if s.scanner == nil && s.skipScans {
+		scanStatus = domain.ScanSkipped
+	}
+	return repository.ContentType{MimeType: mimeType, Mismatch: mismatch, QuarantineReason: reason, ScanStatus: scanStatus}, nil
+}
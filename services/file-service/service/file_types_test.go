@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const (
+	pngContent = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	exeContent = "MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff"
+)
+
+func TestParseFileTypes(t *testing.T) {
+	entries, err := ParseFileTypes(" .EXE, video/*,, application/x-elf ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".exe", "video/*", "application/x-elf"}, entries)
+
+	entries, err = ParseFileTypes("")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	for _, invalid := range []string{".", ".tar.gz", "exe", "*/*", "text/plain; charset=utf-8"} {
+		_, err := ParseFileTypes(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFileTypePolicy_Check(t *testing.T) {
+	policy := FileTypePolicy{
+		Blocked:     []string{".scr", "video/*"},
+		Quarantined: []string{".exe", "application/x-elf"},
+	}
+
+	tests := []struct {
+		name           string
+		fileName       string
+		mimeTypes      []string
+		expectedReason string
+		blocked        bool
+	}{
+		{name: "allowed", fileName: "notes.txt", mimeTypes: []string{"text/plain"}},
+		{name: "blocked extension", fileName: "SCREEN.SCR", blocked: true},
+		{name: "blocked family", fileName: "clip.bin", mimeTypes: []string{"", "video/mp4"}, blocked: true},
+		{name: "quarantined extension", fileName: "setup.exe", expectedReason: "file type .exe"},
+		{name: "quarantined subtype", fileName: "tool", mimeTypes: []string{"application/x-executable"}, expectedReason: "file type application/x-elf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := policy.check(tt.fileName, tt.mimeTypes...)
+
+			if tt.blocked {
+				assert.ErrorIs(t, err, errFileTypeBlocked)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedReason, reason)
+		})
+	}
+}
+
+func TestFileService_CommitContent_DetectsType(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         *pb.File
+		content      string
+		expectedType repository.ContentType
+		skipScans    bool
+		blocked      bool
+	}{
+		{
+			name:         "declared type confirmed",
+			file:         &pb.File{Name: "photo.png", DeclaredMimeType: "image/png"},
+			content:      pngContent,
+			expectedType: repository.ContentType{MimeType: "image/png"},
+		},
+		{
+			name:         "scanning turned off",
+			file:         &pb.File{Name: "photo.png", DeclaredMimeType: "image/png"},
+			content:      pngContent,
+			skipScans:    true,
+			expectedType: repository.ContentType{MimeType: "image/png", ScanStatus: domain.ScanSkipped},
+		},
+		{
+			name:         "declared type contradicted",
+			file:         &pb.File{Name: "photo.jpg", DeclaredMimeType: "image/jpeg"},
+			content:      pngContent,
+			expectedType: repository.ContentType{MimeType: "image/png", Mismatch: true},
+		},
+		{
+			name:    "executable posing as a document quarantined",
+			file:    &pb.File{Name: "invoice.pdf", DeclaredMimeType: "application/pdf"},
+			content: exeContent,
+			expectedType: repository.ContentType{
+				MimeType:         "application/vnd.microsoft.portable-executable",
+				Mismatch:         true,
+				QuarantineReason: "file type application/vnd.microsoft.portable-executable",
+			},
+		},
+		{
+			name:         "empty content keeps the declared type",
+			file:         &pb.File{Name: "empty.txt", DeclaredMimeType: "text/plain"},
+			expectedType: repository.ContentType{MimeType: "text/plain"},
+		},
+		{
+			name:    "blocked type discarded",
+			file:    &pb.File{Name: "movie", DeclaredMimeType: "application/octet-stream"},
+			content: "\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom",
+			blocked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tt.file.Id, tt.file.UserId = testFileID, testUserID
+			checksum := sha256Hex(tt.content)
+			// The test service has no scanner, so content is left pending unless scans are skipped
+			if tt.expectedType.ScanStatus == "" {
+				tt.expectedType.ScanStatus = domain.ScanPending
+			}
+			mockRepo := new(MockFileRepository)
+			if !tt.blocked {
+				mockRepo.On("UpdateContent", mock.Anything, testFileID, testUserID, repository.BlobKey(checksum), int64(len(tt.content)), checksum, tt.expectedType, int64(0)).
+					Return(&pb.File{Id: testFileID, MimeType: tt.expectedType.MimeType}, nil)
+			}
+			service := newTestService(t, mockRepo)
+			service.fileTypes.Blocked = []string{"video/*"}
+			service.skipScans = tt.skipScans
+			staged := testUserID + "/" + testFileID
+			_, err := service.blobs.Put(ctx, staged, strings.NewReader(tt.content), int64(len(tt.content)))
+			require.NoError(t, err)
+
+			_, err = service.commitContent(ctx, tt.file, staged, int64(len(tt.content)), checksum, 0)
+
+			if tt.blocked {
+				assert.ErrorIs(t, err, errFileTypeBlocked)
+				assertStatusCode(t, repoError("complete upload", err), codes.PermissionDenied)
+				_, err = service.blobs.Stat(ctx, staged)
+				assert.Error(t, err, "blocked content should be deleted")
+			} else {
+				require.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_CreateFile_BlockedType(t *testing.T) {
+	mockRepo := new(MockFileRepository)
+	service := newTestService(t, mockRepo)
+	service.fileTypes.Blocked = []string{".scr"}
+
+	_, err := service.CreateFile(context.Background(), &pb.CreateFileRequest{
+		Name:   "screensaver.scr",
+		UserId: testUserID,
+	})
+
+	assertStatusCode(t, err, codes.PermissionDenied)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+func (s *FileService) CreateFolder(ctx context.Context, req *pb.CreateFolderRequest) (*pb.CreateFolderResponse, error) {
+	if err := validateName("name", req.Name); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.ParentId != "" {
+		if err := validateID("parent_id", req.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	owner, err := s.authorizeParent(ctx, req.ParentId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := s.folders.Create(ctx, &pb.CreateFolderRequest{
+		Name:     req.Name,
+		UserId:   owner,
+		ParentId: req.ParentId,
+	})
+	if err != nil {
+		return nil, repoError("create folder", err)
+	}
+
+	return &pb.CreateFolderResponse{Folder: folder}, nil
+}
+
+func (s *FileService) GetFolder(ctx context.Context, req *pb.GetFolderRequest) (*pb.GetFolderResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFolder(ctx, req.Id, req.UserId, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := s.folders.GetByID(ctx, req.Id, owner)
+	if err != nil {
+		return nil, repoError("get folder", err)
+	}
+
+	return &pb.GetFolderResponse{Folder: folder}, nil
+}
+
+func (s *FileService) RenameFolder(ctx context.Context, req *pb.RenameFolderRequest) (*pb.RenameFolderResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if err := validateName("name", req.Name); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFolder(ctx, req.Id, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := s.folders.Rename(ctx, req.Id, owner, req.Name)
+	if err != nil {
+		return nil, repoError("rename folder", err)
+	}
+
+	return &pb.RenameFolderResponse{Folder: folder}, nil
+}
+
+func (s *FileService) MoveFolder(ctx context.Context, req *pb.MoveFolderRequest) (*pb.MoveFolderResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.ParentId != "" {
+		if err := validateID("parent_id", req.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	// Folders only move within their owner's drive; the repository rejects a
+	// parent that belongs to someone else
+	owner, err := s.authorizeFolder(ctx, req.Id, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeMove(ctx, req.ParentId, req.UserId, owner); err != nil {
+		return nil, err
+	}
+
+	folder, err := s.folders.Move(ctx, req.Id, owner, req.ParentId)
+	if err != nil {
+		return nil, repoError("move folder", err)
+	}
+
+	return &pb.MoveFolderResponse{Folder: folder}, nil
+}
+
+func (s *FileService) ListFolder(ctx context.Context, req *pb.ListFolderRequest) (*pb.ListFolderResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	owner := req.UserId
+	if req.FolderId != "" {
+		if err := validateID("folder_id", req.FolderId); err != nil {
+			return nil, err
+		}
+		var err error
+		if owner, err = s.authorizeFolder(ctx, req.FolderId, req.UserId, domain.ShareRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	folders, files, totalCount, err := s.folders.ListChildren(ctx, owner, req.FolderId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list folder", err)
+	}
+
+	return &pb.ListFolderResponse{
+		Folders:    folders,
+		Files:      files,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// DeleteFolder soft deletes a folder and everything below it.
+// File content is kept in storage so the deletion can be undone.
+func (s *FileService) DeleteFolder(ctx context.Context, req *pb.DeleteFolderRequest) (*pb.DeleteFolderResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFolder(ctx, req.Id, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	folders, files, err := s.folders.Delete(ctx, req.Id, owner)
+	if err != nil {
+		return nil, repoError("delete folder", err)
+	}
+
+	return &pb.DeleteFolderResponse{
+		Message:        fmt.Sprintf("Deleted %d folders and %d files", folders, files),
+		DeletedFolders: folders,
+		DeletedFiles:   files,
+	}, nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const testParentID = "523e4567-e89b-12d3-a456-426614174000"
+
+// MockFolderRepository is a mock implementation of FolderRepository
+type MockFolderRepository struct {
+	mock.Mock
+}
+
+func (m *MockFolderRepository) Create(ctx context.Context, req *pb.CreateFolderRequest) (*pb.Folder, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) GetByID(ctx context.Context, id, userID string) (*pb.Folder, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) Rename(ctx context.Context, id, userID, name string) (*pb.Folder, error) {
+	args := m.Called(ctx, id, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) Move(ctx context.Context, id, userID, parentID string) (*pb.Folder, error) {
+	args := m.Called(ctx, id, userID, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) ListChildren(ctx context.Context, userID, folderID string, page, pageSize int32) ([]*pb.Folder, []*pb.File, int32, error) {
+	args := m.Called(ctx, userID, folderID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, nil, 0, args.Error(3)
+	}
+	return args.Get(0).([]*pb.Folder), args.Get(1).([]*pb.File), args.Get(2).(int32), args.Error(3)
+}
+
+func (m *MockFolderRepository) Delete(ctx context.Context, id, userID string) (int32, int32, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Get(0).(int32), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockFolderRepository) ResolvePath(ctx context.Context, userID string, segments []string) (*pb.Folder, *pb.File, error) {
+	args := m.Called(ctx, userID, segments)
+	var folder *pb.Folder
+	if f, ok := args.Get(0).(*pb.Folder); ok {
+		folder = f
+	}
+	var file *pb.File
+	if f, ok := args.Get(1).(*pb.File); ok {
+		file = f
+	}
+	return folder, file, args.Error(2)
+}
+
+func (m *MockFolderRepository) MkdirAll(ctx context.Context, userID string, segments []string) (*pb.Folder, error) {
+	args := m.Called(ctx, userID, segments)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) ListTree(ctx context.Context, id, userID string, limit int) ([]*pb.Folder, []*pb.File, error) {
+	args := m.Called(ctx, id, userID, limit)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*pb.Folder), args.Get(1).([]*pb.File), args.Error(2)
+}
+
+func TestFileService_CreateFolder(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.CreateFolderRequest
+		mockSetup     func(*MockFolderRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "successful creation",
+			request: &pb.CreateFolderRequest{Name: "Documents", UserId: testUserID, ParentId: testParentID},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateFolderRequest")).
+					Return(&pb.Folder{Id: testFolderID, Name: "Documents", ParentId: testParentID}, nil)
+			},
+		},
+		{
+			name:          "name with slash",
+			request:       &pb.CreateFolderRequest{Name: "a/b", UserId: testUserID},
+			mockSetup:     func(repo *MockFolderRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "dot dot name",
+			request:       &pb.CreateFolderRequest{Name: "..", UserId: testUserID},
+			mockSetup:     func(repo *MockFolderRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "parent not found",
+			request: &pb.CreateFolderRequest{Name: "Documents", UserId: testUserID, ParentId: testParentID},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil, repository.ErrFolderNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFolders := new(MockFolderRepository)
+			tt.mockSetup(mockFolders)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.folders = mockFolders
+			resp, err := service.CreateFolder(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testFolderID, resp.Folder.Id)
+			}
+
+			mockFolders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_MoveFolder(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.MoveFolderRequest
+		mockSetup     func(*MockFolderRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "successful move",
+			request: &pb.MoveFolderRequest{Id: testFolderID, UserId: testUserID, ParentId: testParentID},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("Move", mock.Anything, testFolderID, testUserID, testParentID).
+					Return(&pb.Folder{Id: testFolderID, ParentId: testParentID}, nil)
+			},
+		},
+		{
+			name:    "move to root",
+			request: &pb.MoveFolderRequest{Id: testFolderID, UserId: testUserID},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("Move", mock.Anything, testFolderID, testUserID, "").
+					Return(&pb.Folder{Id: testFolderID}, nil)
+			},
+		},
+		{
+			name:    "cycle",
+			request: &pb.MoveFolderRequest{Id: testFolderID, UserId: testUserID, ParentId: testParentID},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("Move", mock.Anything, testFolderID, testUserID, testParentID).
+					Return(nil, repository.ErrFolderCycle)
+			},
+			expectedError: true,
+			errorCode:     codes.FailedPrecondition,
+		},
+		{
+			name:          "invalid parent id",
+			request:       &pb.MoveFolderRequest{Id: testFolderID, UserId: testUserID, ParentId: "invalid"},
+			mockSetup:     func(repo *MockFolderRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFolders := new(MockFolderRepository)
+			tt.mockSetup(mockFolders)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.folders = mockFolders
+			resp, err := service.MoveFolder(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.request.ParentId, resp.Folder.ParentId)
+			}
+
+			mockFolders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_MoveFolder_SharedFolderToRoot(t *testing.T) {
+	mockFolders := new(MockFolderRepository)
+	service := newTestService(t, new(MockFileRepository))
+	service.folders = mockFolders
+	mockShares := withShares(service)
+	mockShares.On("FolderAccess", mock.Anything, testFolderID, testGranteeID).
+		Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleEditor}, nil)
+
+	_, err := service.MoveFolder(context.Background(), &pb.MoveFolderRequest{Id: testFolderID, UserId: testGranteeID})
+
+	assertStatusCode(t, err, codes.PermissionDenied)
+	mockFolders.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFileService_ListFolder(t *testing.T) {
+	mockFolders := new(MockFolderRepository)
+	mockFolders.On("ListChildren", mock.Anything, testUserID, testFolderID, int32(1), int32(20)).
+		Return([]*pb.Folder{{Id: testParentID}}, []*pb.File{{Id: testFileID}}, int32(2), nil)
+
+	service := newTestService(t, new(MockFileRepository))
+	service.folders = mockFolders
+	resp, err := service.ListFolder(context.Background(), &pb.ListFolderRequest{UserId: testUserID, FolderId: testFolderID})
+
+	require.NoError(t, err)
+	assert.Len(t, resp.Folders, 1)
+	assert.Len(t, resp.Files, 1)
+	assert.Equal(t, int32(2), resp.TotalCount)
+	mockFolders.AssertExpectations(t)
+}
+
+func TestFileService_DeleteFolder(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.DeleteFolderRequest
+		mockSetup     func(*MockFolderRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "subtree deleted",
+			request: &pb.DeleteFolderRequest{Id: testFolderID, UserId: testUserID},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("Delete", mock.Anything, testFolderID, testUserID).Return(int32(3), int32(7), nil)
+			},
+		},
+		{
+			name:    "folder not found",
+			request: &pb.DeleteFolderRequest{Id: testFolderID, UserId: testUserID},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("Delete", mock.Anything, testFolderID, testUserID).Return(int32(0), int32(0), repository.ErrFolderNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFolders := new(MockFolderRepository)
+			tt.mockSetup(mockFolders)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.folders = mockFolders
+			resp, err := service.DeleteFolder(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int32(3), resp.DeletedFolders)
+				assert.Equal(t, int32(7), resp.DeletedFiles)
+			}
+
+			mockFolders.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	pb "go-drive/proto/file"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPathDepth bounds the number of segments in a path so a single request cannot
+// trigger an unbounded number of lookups or folder creations
+const maxPathDepth = 64
+
+func (s *FileService) ResolvePath(ctx context.Context, req *pb.ResolvePathRequest) (*pb.ResolvePathResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	segments, err := splitPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, file, err := s.folders.ResolvePath(ctx, req.UserId, segments)
+	if err != nil {
+		return nil, repoError("resolve path", err)
+	}
+
+	return &pb.ResolvePathResponse{Folder: folder, File: file}, nil
+}
+
+func (s *FileService) CreateFolderPath(ctx context.Context, req *pb.CreateFolderPathRequest) (*pb.CreateFolderPathResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	segments, err := splitPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "path must name a folder below the root")
+	}
+
+	folder, err := s.folders.MkdirAll(ctx, req.UserId, segments)
+	if err != nil {
+		return nil, repoError("create folder path", err)
+	}
+
+	return &pb.CreateFolderPathResponse{Folder: folder}, nil
+}
+
+// splitPath splits a slash-separated path into its segments. Leading, trailing and repeated
+// slashes are ignored, so "/", "" and "//" all denote the root.
+func splitPath(path string) ([]string, error) {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if err := validateName("path", segment); err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) > maxPathDepth {
+		return nil, status.Errorf(codes.InvalidArgument, "path must not be deeper than %d levels", maxPathDepth)
+	}
+	return segments, nil
+}
+
+// validateName checks that a file or folder name can be used as a path segment
+func validateName(field, name string) error {
+	switch {
+	case name == "":
+		return status.Errorf(codes.InvalidArgument, "%s is required", field)
+	case len(name) > 255:
+		return status.Errorf(codes.InvalidArgument, "%s must be at most 255 bytes", field)
+	case name == "." || name == "..":
+		return status.Errorf(codes.InvalidArgument, "%s must not be . or ..", field)
+	case strings.ContainsAny(name, "/\x00"):
+		return status.Errorf(codes.InvalidArgument, "%s must not contain / or NUL characters", field)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected []string
+		valid    bool
+	}{
+		{"/", nil, true},
+		{"", nil, true},
+		{"/projects/2026/report.pdf", []string{"projects", "2026", "report.pdf"}, true},
+		{"projects//2026/", []string{"projects", "2026"}, true},
+		{"/projects/../secret", nil, false},
+		{"/./report.pdf", nil, false},
+		{"/bad\x00name", nil, false},
+	}
+
+	for _, tt := range tests {
+		segments, err := splitPath(tt.path)
+		if tt.valid {
+			require.NoError(t, err, tt.path)
+			assert.Equal(t, tt.expected, segments, tt.path)
+		} else {
+			assertStatusCode(t, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestFileService_ResolvePath(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.ResolvePathRequest
+		mockSetup     func(*MockFolderRepository)
+		expectedError bool
+		errorCode     codes.Code
+		validate      func(*testing.T, *pb.ResolvePathResponse)
+	}{
+		{
+			name:    "file",
+			request: &pb.ResolvePathRequest{UserId: testUserID, Path: "/projects/2026/report.pdf"},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("ResolvePath", mock.Anything, testUserID, []string{"projects", "2026", "report.pdf"}).
+					Return(nil, &pb.File{Id: testFileID}, nil)
+			},
+			validate: func(t *testing.T, resp *pb.ResolvePathResponse) {
+				assert.Nil(t, resp.Folder)
+				assert.Equal(t, testFileID, resp.File.Id)
+			},
+		},
+		{
+			name:    "folder",
+			request: &pb.ResolvePathRequest{UserId: testUserID, Path: "/projects/"},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("ResolvePath", mock.Anything, testUserID, []string{"projects"}).
+					Return(&pb.Folder{Id: testFolderID}, nil, nil)
+			},
+			validate: func(t *testing.T, resp *pb.ResolvePathResponse) {
+				assert.Equal(t, testFolderID, resp.Folder.Id)
+				assert.Nil(t, resp.File)
+			},
+		},
+		{
+			name:    "missing path",
+			request: &pb.ResolvePathRequest{UserId: testUserID, Path: "/nope"},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("ResolvePath", mock.Anything, testUserID, []string{"nope"}).
+					Return(nil, nil, repository.ErrPathNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+		{
+			name:          "traversal",
+			request:       &pb.ResolvePathRequest{UserId: testUserID, Path: "/a/../b"},
+			mockSetup:     func(repo *MockFolderRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFolders := new(MockFolderRepository)
+			tt.mockSetup(mockFolders)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.folders = mockFolders
+			resp, err := service.ResolvePath(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				tt.validate(t, resp)
+			}
+
+			mockFolders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_CreateFolderPath(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.CreateFolderPathRequest
+		mockSetup     func(*MockFolderRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "missing parents created",
+			request: &pb.CreateFolderPathRequest{UserId: testUserID, Path: "/projects/2026"},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("MkdirAll", mock.Anything, testUserID, []string{"projects", "2026"}).
+					Return(&pb.Folder{Id: testFolderID, Name: "2026"}, nil)
+			},
+		},
+		{
+			name:    "file in the way",
+			request: &pb.CreateFolderPathRequest{UserId: testUserID, Path: "/report.pdf/sub"},
+			mockSetup: func(repo *MockFolderRepository) {
+				repo.On("MkdirAll", mock.Anything, testUserID, []string{"report.pdf", "sub"}).
+					Return(nil, repository.ErrNameConflict)
+			},
+			expectedError: true,
+			errorCode:     codes.AlreadyExists,
+		},
+		{
+			name:          "root",
+			request:       &pb.CreateFolderPathRequest{UserId: testUserID, Path: "/"},
+			mockSetup:     func(repo *MockFolderRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFolders := new(MockFolderRepository)
+			tt.mockSetup(mockFolders)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.folders = mockFolders
+			resp, err := service.CreateFolderPath(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testFolderID, resp.Folder.Id)
+			}
+
+			mockFolders.AssertExpectations(t)
+		})
+	}
+}
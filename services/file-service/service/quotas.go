@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+// Quota limits what users of one type may store. Zero values mean unlimited.
+type Quota struct {
+	// Storage caps the bytes of a user's files and file versions, trash included
+	Storage int64
+	// MaxFileSize caps the size of a single file
+	MaxFileSize int64
+}
+
+// DefaultQuotas are the quotas applied to user types that have none configured
+var DefaultQuotas = map[string]Quota{
+	domain.UserTypeStandard: {Storage: 15 << 30, MaxFileSize: 2 << 30},
+	domain.UserTypePremium:  {Storage: 1 << 40, MaxFileSize: domain.MaxUploadSize},
+	domain.UserTypeAdmin:    {},
+}
+
+func (s *FileService) GetUsage(ctx context.Context, req *pb.GetUsageRequest) (*pb.GetUsageResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	usage, err := s.usage.Get(ctx, req.UserId)
+	if err != nil {
+		return nil, repoError("get usage", err)
+	}
+	quota := s.quotaFor(usage.UserType)
+
+	remaining := int64(-1)
+	if quota.Storage > 0 {
+		remaining = max(quota.Storage-usage.UsedBytes, 0)
+	}
+
+	return &pb.GetUsageResponse{
+		UserType:       usage.UserType,
+		UsedBytes:      usage.UsedBytes,
+		QuotaBytes:     quota.Storage,
+		RemainingBytes: remaining,
+		MaxFileSize:    fileSizeLimit(quota),
+	}, nil
+}
+
+// userQuota looks up the quota of a user's type
+func (s *FileService) userQuota(ctx context.Context, userID string) (Quota, error) {
+	usage, err := s.usage.Get(ctx, userID)
+	if err != nil {
+		return Quota{}, repoError("get usage", err)
+	}
+	return s.quotaFor(usage.UserType), nil
+}
+
+// quotaFor returns the quota of a user type. Unknown types get the standard quota.
+func (s *FileService) quotaFor(userType string) Quota {
+	if quota, ok := s.quotas[userType]; ok {
+		return quota
+	}
+	return s.quotas[domain.UserTypeStandard]
+}
+
+// fileSizeLimit returns the largest file a quota allows
+func fileSizeLimit(quota Quota) int64 {
+	if quota.MaxFileSize > 0 && quota.MaxFileSize < domain.MaxUploadSize {
+		return quota.MaxFileSize
+	}
+	return domain.MaxUploadSize
+}
+
+// checkFileSize rejects files larger than the quota or the service allow
+func checkFileSize(quota Quota, size int64) error {
+	if size > domain.MaxUploadSize {
+		return status.Errorf(codes.InvalidArgument, "size exceeds the maximum upload size of %d bytes", int64(domain.MaxUploadSize))
+	}
+	if quota.MaxFileSize > 0 && size > quota.MaxFileSize {
+		return status.Errorf(codes.ResourceExhausted, "file exceeds the maximum file size of %d bytes", quota.MaxFileSize)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockUsageRepository is a mock implementation of UsageRepository
+type MockUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockUsageRepository) Get(ctx context.Context, userID string) (*repository.Usage, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Usage), args.Error(1)
+}
+
+// withUsage makes the service see the given usage for every user
+func withUsage(service *FileService, usage *repository.Usage) *MockUsageRepository {
+	mockUsage := new(MockUsageRepository)
+	mockUsage.On("Get", mock.Anything, mock.Anything).Return(usage, nil)
+	service.usage = mockUsage
+	return mockUsage
+}
+
+func TestFileService_GetUsage(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.GetUsageRequest
+		mockSetup     func(*MockUsageRepository)
+		expectedError bool
+		errorCode     codes.Code
+		expected      *pb.GetUsageResponse
+	}{
+		{
+			name:    "standard user",
+			request: &pb.GetUsageRequest{UserId: testUserID},
+			mockSetup: func(usage *MockUsageRepository) {
+				usage.On("Get", mock.Anything, testUserID).
+					Return(&repository.Usage{UserType: domain.UserTypeStandard, UsedBytes: 1 << 30}, nil)
+			},
+			expected: &pb.GetUsageResponse{
+				UserType:       domain.UserTypeStandard,
+				UsedBytes:      1 << 30,
+				QuotaBytes:     15 << 30,
+				RemainingBytes: 14 << 30,
+				MaxFileSize:    2 << 30,
+			},
+		},
+		{
+			name:    "admin is unlimited",
+			request: &pb.GetUsageRequest{UserId: testUserID},
+			mockSetup: func(usage *MockUsageRepository) {
+				usage.On("Get", mock.Anything, testUserID).
+					Return(&repository.Usage{UserType: domain.UserTypeAdmin, UsedBytes: 1 << 40}, nil)
+			},
+			expected: &pb.GetUsageResponse{
+				UserType:       domain.UserTypeAdmin,
+				UsedBytes:      1 << 40,
+				RemainingBytes: -1,
+				MaxFileSize:    domain.MaxUploadSize,
+			},
+		},
+		{
+			name:    "over quota",
+			request: &pb.GetUsageRequest{UserId: testUserID},
+			mockSetup: func(usage *MockUsageRepository) {
+				usage.On("Get", mock.Anything, testUserID).
+					Return(&repository.Usage{UserType: domain.UserTypeStandard, UsedBytes: 16 << 30}, nil)
+			},
+			expected: &pb.GetUsageResponse{
+				UserType:    domain.UserTypeStandard,
+				UsedBytes:   16 << 30,
+				QuotaBytes:  15 << 30,
+				MaxFileSize: 2 << 30,
+			},
+		},
+		{
+			name:          "invalid user id",
+			request:       &pb.GetUsageRequest{UserId: "invalid"},
+			mockSetup:     func(usage *MockUsageRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "user not found",
+			request: &pb.GetUsageRequest{UserId: testUserID},
+			mockSetup: func(usage *MockUsageRepository) {
+				usage.On("Get", mock.Anything, testUserID).Return(nil, repository.ErrUserNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			mockUsage := new(MockUsageRepository)
+			tt.mockSetup(mockUsage)
+			service.usage = mockUsage
+
+			resp, err := service.GetUsage(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected.UserType, resp.UserType)
+				assert.Equal(t, tt.expected.UsedBytes, resp.UsedBytes)
+				assert.Equal(t, tt.expected.QuotaBytes, resp.QuotaBytes)
+				assert.Equal(t, tt.expected.RemainingBytes, resp.RemainingBytes)
+				assert.Equal(t, tt.expected.MaxFileSize, resp.MaxFileSize)
+			}
+
+			mockUsage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_Quotas(t *testing.T) {
+	ctx := context.Background()
+	standard := &repository.Usage{UserType: domain.UserTypeStandard, UsedBytes: 15<<30 - 100}
+
+	t.Run("file larger than the maximum file size", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		withUsage(service, standard)
+
+		_, err := service.CreateFile(ctx, &pb.CreateFileRequest{Name: "movie.mkv", UserId: testUserID, Size: 3 << 30})
+
+		assertStatusCode(t, err, codes.ResourceExhausted)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("create passes the storage quota", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything, int64(15<<30)).Return(nil, repository.ErrQuotaExceeded)
+		service := newTestService(t, mockRepo)
+		withUsage(service, standard)
+
+		_, err := service.CreateFile(ctx, &pb.CreateFileRequest{Name: "report.pdf", UserId: testUserID, Size: 1024})
+
+		assertStatusCode(t, err, codes.ResourceExhausted)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("configured quotas override the defaults", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything, int64(0)).
+			Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: testUserID + "/" + testFileID}, nil)
+		service := newTestService(t, mockRepo)
+		service.quotas[domain.UserTypeStandard] = Quota{}
+		withUsage(service, standard)
+
+		resp, err := service.CreateFile(ctx, &pb.CreateFileRequest{Name: "movie.mkv", UserId: testUserID, Size: 3 << 30})
+
+		require.NoError(t, err)
+		assert.Contains(t, resp.UploadUrl, "max_size=3221225472")
+		assert.Equal(t, int64(3<<30), resp.MaxSize)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("upload that would exceed the storage quota", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockUploads := new(MockUploadRepository)
+		mockUploads.On("Reserved", mock.Anything, testUserID, mock.Anything).Return(int64(0), nil)
+		service.uploads = mockUploads
+		withUsage(service, standard)
+
+		_, err := service.CreateUpload(ctx, &pb.CreateUploadRequest{Name: "backup.tar", UserId: testUserID, Size: 1024})
+
+		assertStatusCode(t, err, codes.ResourceExhausted)
+		mockUploads.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("completed upload larger than the maximum file size", func(t *testing.T) {
+		storageKey := testUserID + "/" + testFileID
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+			Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+		service := newTestService(t, mockRepo)
+		service.quotas[domain.UserTypeStandard] = Quota{MaxFileSize: 4}
+		withUsage(service, standard)
+		_, err := service.blobs.Put(ctx, storageKey, strings.NewReader("hello"), 5)
+		require.NoError(t, err)
+
+		_, err = service.CompleteUpload(ctx, &pb.CompleteUploadRequest{Id: testFileID, UserId: testUserID})
+
+		assertStatusCode(t, err, codes.ResourceExhausted)
+		_, err = service.blobs.Stat(ctx, storageKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound, "rejected content is not kept")
+		mockRepo.AssertNotCalled(t, "UpdateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("streamed upload larger than the maximum file size", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).
+			Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: testUserID + "/" + testFileID}, nil)
+		mockRepo.On("Discard", mock.Anything, testFileID, testUserID).Return([]string{}, nil)
+		service := newTestService(t, mockRepo)
+		service.quotas[domain.UserTypeStandard] = Quota{MaxFileSize: 4}
+		withUsage(service, standard)
+
+		err := service.UploadFile(&fakeUploadStream{
+			requests: uploadRequests(&pb.UploadFileHeader{Name: "hello.txt", UserId: testUserID}, "hello"),
+		})
+
+		assertStatusCode(t, err, codes.ResourceExhausted)
+		mockRepo.AssertExpectations(t)
+	})
+}
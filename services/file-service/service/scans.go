@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/antivirus"
+	"go-drive/internal/domain"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// checkDownloadable refuses to hand out the content of a quarantined file or of one whose
+// malware scan has not come back clean. Files without content have nothing to scan, and
+// content committed while scanning was turned off is let through.
+func checkDownloadable(file *pb.File) error {
+	switch {
+	case file.Quarantined:
+		return status.Errorf(codes.PermissionDenied, "file is quarantined: %s", file.QuarantineReason)
+	case file.Checksum == "" || file.ScanStatus == domain.ScanClean || file.ScanStatus == domain.ScanSkipped:
+		return nil
+	case file.ScanStatus == domain.ScanPending:
+		return status.Error(codes.FailedPrecondition, "file has not been scanned for malware yet")
+	}
+	return status.Error(codes.PermissionDenied, "file could not be scanned for malware")
+}
+
+// checkVersionDownloadable refuses to hand out the content of a file version whose malware
+// scan has not come back clean
+func checkVersionDownloadable(version *pb.FileVersion) error {
+	switch version.ScanStatus {
+	case domain.ScanClean, domain.ScanSkipped:
+		return nil
+	case domain.ScanPending:
+		return status.Error(codes.FailedPrecondition, "file version has not been scanned for malware yet")
+	case domain.ScanInfected:
+		return status.Error(codes.PermissionDenied, "file version is quarantined: malware found")
+	}
+	return status.Error(codes.PermissionDenied, "file version could not be scanned for malware")
+}
+
+// checkShareable refuses to share a file that cannot be downloaded. Folders are shared as
+// they are; each file in them is checked when it is downloaded.
+func (s *FileService) checkShareable(ctx context.Context, fileID, owner string) error {
+	if fileID == "" {
+		return nil
+	}
+	file, err := s.repo.GetByID(ctx, fileID, owner)
+	if err != nil {
+		return repoError("get file", err)
+	}
+	return checkDownloadable(file)
+}
+
+// ListQuarantine lists the quarantined files of every user, for admins only
+func (s *FileService) ListQuarantine(ctx context.Context, req *pb.ListQuarantineRequest) (*pb.ListQuarantineResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	usage, err := s.usage.Get(ctx, req.UserId)
+	if err != nil {
+		return nil, repoError("get user", err)
+	}
+	if usage.UserType != domain.UserTypeAdmin {
+		return nil, status.Error(codes.PermissionDenied, "only admins can review quarantined files")
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	files, totalCount, err := s.scans.ListQuarantined(ctx, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list quarantined files", err)
+	}
+
+	return &pb.ListQuarantineResponse{Files: files, TotalCount: totalCount}, nil
+}
+
+// ScanFiles scans the content waiting for a malware scan. It returns the number of files
+// processed. Without a scanner, content is left pending unless scanning is explicitly
+// turned off.
+func (s *FileService) ScanFiles(ctx context.Context) (int, error) {
+	if s.scanner == nil && !s.skipScans {
+		return 0, nil
+	}
+	return s.processContentJobs(ctx, s.scans.Claim, s.scanContent)
+}
+
+// scanContent scans a claimed file's content and records the outcome. Failed attempts are
+// retried with a growing delay; content that cannot be scanned at all is left with an
+// error status, which keeps it from being downloaded. Only failing to record the outcome
+// is an error.
+func (s *FileService) scanContent(ctx context.Context, job repository.ContentJob) error {
+	if s.scanner == nil {
+		// Queued before scanning was turned off
+		return s.scans.Skip(ctx, job)
+	}
+
+	result, err := s.scanBlob(ctx, job.StorageKey)
+	switch {
+	case err == nil && result.Infected:
+		return s.quarantine(ctx, job, result.Signature)
+	case err == nil:
+		return s.scans.Clean(ctx, job, s.now())
+	case ctx.Err() != nil:
+		// Left to the lease, so the content is scanned once the service is back
+		return ctx.Err()
+	}
+
+	log.Printf("Failed to scan file %s (attempt %d of %d): %v", job.FileID, job.Attempts, maxContentJobAttempts, err)
+	return s.scans.Fail(ctx, job, err.Error(), s.retryAt(job))
+}
+
+// scanBlob streams stored content to the scanner
+func (s *FileService) scanBlob(ctx context.Context, key string) (antivirus.Result, error) {
+	body, _, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		return antivirus.Result{}, fmt.Errorf("failed to read content: %w", err)
+	}
+	defer body.Close()
+	return s.scanner.Scan(ctx, body)
+}
+
+// quarantine marks every file with infected content as such, then moves the content out
+// of reach of downloads and drops its thumbnails. Failing to move it is only logged, as
+// the files are refused either way.
+func (s *FileService) quarantine(ctx context.Context, job repository.ContentJob, signature string) error {
+	log.Printf("Found %s in the content of file %s; quarantining it", signature, job.FileID)
+	if err := s.scans.Infected(ctx, job, signature, s.now()); err != nil {
+		return err
+	}
+
+	err := s.blobs.Move(ctx, job.StorageKey, repository.QuarantineKey(job.Checksum))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Failed to move infected content %s to quarantine: %v", job.StorageKey, err)
+	}
+	s.deleteBlobs(ctx, thumbnailKeys(job.StorageKey))
+	return nil
+}
+
+// RunScanner scans waiting content every interval, and whenever new content is committed,
+// until ctx is cancelled
+func (s *FileService) RunScanner(ctx context.Context, interval time.Duration) {
+	runContentWorker(ctx, interval, s.scanQueued, func() {
+		scanned, err := s.ScanFiles(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to scan files: %v", err)
+		} else if scanned > 0 {
+			log.Printf("Scanned %d files for malware", scanned)
+		}
+	})
+}
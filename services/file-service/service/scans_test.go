@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/antivirus"
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockScanRepository is a mock implementation of ScanRepository
+type MockScanRepository struct {
+	mock.Mock
+}
+
+func (m *MockScanRepository) Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]repository.ContentJob, error) {
+	args := m.Called(ctx, now, retryAt, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ContentJob), args.Error(1)
+}
+
+func (m *MockScanRepository) Clean(ctx context.Context, job repository.ContentJob, scannedAt time.Time) error {
+	return m.Called(ctx, job, scannedAt).Error(0)
+}
+
+func (m *MockScanRepository) Skip(ctx context.Context, job repository.ContentJob) error {
+	return m.Called(ctx, job).Error(0)
+}
+
+func (m *MockScanRepository) Infected(ctx context.Context, job repository.ContentJob, signature string, scannedAt time.Time) error {
+	return m.Called(ctx, job, signature, scannedAt).Error(0)
+}
+
+func (m *MockScanRepository) Fail(ctx context.Context, job repository.ContentJob, reason string, retryAt *time.Time) error {
+	return m.Called(ctx, job, reason, retryAt).Error(0)
+}
+
+func (m *MockScanRepository) ListQuarantined(ctx context.Context, page, pageSize int32) ([]*pb.File, int32, error) {
+	args := m.Called(ctx, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.File), args.Get(1).(int32), args.Error(2)
+}
+
+// fakeScanner reports content containing "EICAR" as infected and fails on content
+// containing "unscannable"
+type fakeScanner struct{}
+
+func (fakeScanner) Scan(ctx context.Context, r io.Reader) (antivirus.Result, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return antivirus.Result{}, err
+	}
+	switch {
+	case strings.Contains(string(content), "EICAR"):
+		return antivirus.Result{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	case strings.Contains(string(content), "unscannable"):
+		return antivirus.Result{}, errors.New("clamd error: INSTREAM size limit exceeded")
+	}
+	return antivirus.Result{}, nil
+}
+
+// scannedFile returns a file with content that was scanned clean
+func scannedFile() *pb.File {
+	return &pb.File{Id: testFileID, UserId: testUserID, Checksum: strings.Repeat("ab", 32), ScanStatus: domain.ScanClean}
+}
+
+func TestCheckDownloadable(t *testing.T) {
+	checksum := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name      string
+		file      *pb.File
+		errorCode codes.Code
+	}{
+		{name: "clean", file: &pb.File{Checksum: checksum, ScanStatus: domain.ScanClean}, errorCode: codes.OK},
+		{name: "scanning turned off", file: &pb.File{Checksum: checksum, ScanStatus: domain.ScanSkipped}, errorCode: codes.OK},
+		{name: "no content yet", file: &pb.File{ScanStatus: domain.ScanPending}, errorCode: codes.OK},
+		{name: "scan pending", file: &pb.File{Checksum: checksum, ScanStatus: domain.ScanPending}, errorCode: codes.FailedPrecondition},
+		{name: "scan failed", file: &pb.File{Checksum: checksum, ScanStatus: domain.ScanError}, errorCode: codes.PermissionDenied},
+		{
+			name:      "infected",
+			file:      &pb.File{Checksum: checksum, ScanStatus: domain.ScanInfected, Quarantined: true, QuarantineReason: "malware Eicar-Test-Signature"},
+			errorCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDownloadable(tt.file)
+
+			if tt.errorCode == codes.OK {
+				assert.NoError(t, err)
+			} else {
+				assertStatusCode(t, err, tt.errorCode)
+			}
+		})
+	}
+}
+
+func TestFileService_ScanFiles(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := func(key string, attempts int32) repository.ContentJob {
+		return repository.ContentJob{
+			FileID: uuid.MustParse(testFileID), Checksum: strings.Repeat("ab", 32),
+			Name: "attachment", StorageKey: key, Attempts: attempts,
+		}
+	}
+	clean := job("clean", 1)
+	infected := job("infected", 1)
+	unscannable := job("unscannable", 2)
+
+	tests := []struct {
+		name          string
+		jobs          []repository.ContentJob
+		scanner       antivirus.Scanner
+		skipScans     bool
+		mockSetup     func(*MockScanRepository)
+		quarantined   bool
+		expectedError bool
+	}{
+		{
+			name:    "clean content",
+			jobs:    []repository.ContentJob{clean},
+			scanner: fakeScanner{},
+			mockSetup: func(scans *MockScanRepository) {
+				scans.On("Clean", mock.Anything, clean, now).Return(nil)
+			},
+		},
+		{
+			name:    "infected content quarantined",
+			jobs:    []repository.ContentJob{infected},
+			scanner: fakeScanner{},
+			mockSetup: func(scans *MockScanRepository) {
+				scans.On("Infected", mock.Anything, infected, "Eicar-Test-Signature", now).Return(nil)
+			},
+			quarantined: true,
+		},
+		{
+			name:    "infected content left in place when it cannot be recorded",
+			jobs:    []repository.ContentJob{infected},
+			scanner: fakeScanner{},
+			mockSetup: func(scans *MockScanRepository) {
+				scans.On("Infected", mock.Anything, infected, mock.Anything, now).Return(errors.New("database unavailable"))
+			},
+			expectedError: true,
+		},
+		{
+			name:    "failure retried later",
+			jobs:    []repository.ContentJob{unscannable},
+			scanner: fakeScanner{},
+			mockSetup: func(scans *MockScanRepository) {
+				retryAt := now.Add(2 * contentJobRetryDelay)
+				scans.On("Fail", mock.Anything, unscannable, "clamd error: INSTREAM size limit exceeded", &retryAt).Return(nil)
+			},
+		},
+		{
+			name:    "last attempt gives up",
+			jobs:    []repository.ContentJob{job("missing", maxContentJobAttempts)},
+			scanner: fakeScanner{},
+			mockSetup: func(scans *MockScanRepository) {
+				scans.On("Fail", mock.Anything, mock.Anything, mock.Anything, (*time.Time)(nil)).Return(nil)
+			},
+		},
+		{
+			name:      "scanning turned off",
+			jobs:      []repository.ContentJob{infected},
+			skipScans: true,
+			mockSetup: func(scans *MockScanRepository) {
+				scans.On("Skip", mock.Anything, infected).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := newTestService(t, new(MockFileRepository))
+			service.now = func() time.Time { return now }
+			service.scanner = tt.scanner
+			service.skipScans = tt.skipScans
+			for key, content := range map[string]string{
+				"clean":              "quarterly report",
+				"infected":           "EICAR test file",
+				"infected.thumb-256": "thumbnail",
+				"unscannable":        "unscannable archive",
+			} {
+				_, err := service.blobs.Put(ctx, key, strings.NewReader(content), -1)
+				require.NoError(t, err)
+			}
+
+			mockScans := new(MockScanRepository)
+			service.scans = mockScans
+			mockScans.On("Claim", mock.Anything, now, now.Add(contentJobLease), contentJobBatchSize).Return(tt.jobs, nil)
+			tt.mockSetup(mockScans)
+
+			processed, err := service.ScanFiles(ctx)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, len(tt.jobs), processed)
+			}
+			_, err = service.blobs.Stat(ctx, "infected")
+			assert.Equal(t, tt.quarantined, err != nil, "infected content should only be moved once recorded")
+			_, err = service.blobs.Stat(ctx, repository.QuarantineKey(infected.Checksum))
+			assert.Equal(t, tt.quarantined, err == nil)
+			_, err = service.blobs.Stat(ctx, "infected.thumb-256")
+			assert.Equal(t, tt.quarantined, err != nil, "thumbnails of infected content should be deleted")
+			mockScans.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_ScanFilesWithoutScanner(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	mockScans := new(MockScanRepository)
+	service.scans = mockScans
+
+	processed, err := service.ScanFiles(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+	mockScans.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFileService_ShareUnscannedFile(t *testing.T) {
+	pending := scannedFile()
+	pending.ScanStatus = domain.ScanPending
+
+	mockRepo := new(MockFileRepository)
+	mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(pending, nil)
+	service := newTestService(t, mockRepo)
+	mockLinks := withLinks(service)
+
+	_, err := service.ShareItem(context.Background(), &pb.ShareItemRequest{
+		UserId:    testUserID,
+		FileId:    testFileID,
+		GranteeId: testGranteeID,
+		Role:      domain.ShareRoleViewer,
+	})
+	assertStatusCode(t, err, codes.FailedPrecondition)
+
+	_, err = service.CreateShareLink(context.Background(), &pb.CreateShareLinkRequest{UserId: testUserID, FileId: testFileID})
+	assertStatusCode(t, err, codes.FailedPrecondition)
+
+	mockLinks.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFileService_ListQuarantine(t *testing.T) {
+	quarantined := []*pb.File{{Id: testFileID, Quarantined: true, QuarantineReason: "malware Eicar-Test-Signature"}}
+
+	tests := []struct {
+		name          string
+		userType      string
+		request       *pb.ListQuarantineRequest
+		mockSetup     func(*MockScanRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:     "admin with default paging",
+			userType: domain.UserTypeAdmin,
+			request:  &pb.ListQuarantineRequest{UserId: testUserID},
+			mockSetup: func(scans *MockScanRepository) {
+				scans.On("ListQuarantined", mock.Anything, int32(1), int32(20)).Return(quarantined, int32(1), nil)
+			},
+		},
+		{
+			name:          "not an admin",
+			userType:      domain.UserTypeStandard,
+			request:       &pb.ListQuarantineRequest{UserId: testUserID},
+			mockSetup:     func(scans *MockScanRepository) {},
+			expectedError: true,
+			errorCode:     codes.PermissionDenied,
+		},
+		{
+			name:          "invalid user",
+			userType:      domain.UserTypeAdmin,
+			request:       &pb.ListQuarantineRequest{UserId: "invalid"},
+			mockSetup:     func(scans *MockScanRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			withUsage(service, &repository.Usage{UserType: tt.userType})
+			mockScans := new(MockScanRepository)
+			service.scans = mockScans
+			tt.mockSetup(mockScans)
+
+			resp, err := service.ListQuarantine(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, quarantined, resp.Files)
+				assert.Equal(t, int32(1), resp.TotalCount)
+			}
+			mockScans.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// SearchFiles finds the live files and folders whose names match a query, best matches
+// first. Searching a folder covers everything below it, including folders shared with the
+// user; otherwise the user's own drive is searched. Pages are continued with next_cursor.
+func (s *FileService) SearchFiles(ctx context.Context, req *pb.SearchFilesRequest) (*pb.SearchFilesResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if len(query) > 255 {
+		return nil, status.Error(codes.InvalidArgument, "query must be at most 255 bytes")
+	}
+	mimeTypes, err := normalizeMimeTypes("mime_types", req.MimeTypes)
+	if err != nil {
+		return nil, err
+	}
+	if req.MinSize != nil && *req.MinSize < 0 || req.MaxSize != nil && *req.MaxSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "sizes must not be negative")
+	}
+	if req.MinSize != nil && req.MaxSize != nil && *req.MinSize > *req.MaxSize {
+		return nil, status.Error(codes.InvalidArgument, "min_size must not exceed max_size")
+	}
+
+	filter := repository.SearchFilter{
+		UserID:    req.UserId,
+		Query:     query,
+		Words:     searchWords(query),
+		MimeTypes: mimeTypes,
+		MinSize:   req.MinSize,
+		MaxSize:   req.MaxSize,
+	}
+	if req.ModifiedAfter != nil {
+		if err := req.ModifiedAfter.CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "modified_after is not a valid timestamp")
+		}
+		after := req.ModifiedAfter.AsTime()
+		filter.ModifiedAfter = &after
+	}
+	if req.ModifiedBefore != nil {
+		if err := req.ModifiedBefore.CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "modified_before is not a valid timestamp")
+		}
+		before := req.ModifiedBefore.AsTime()
+		filter.ModifiedBefore = &before
+	}
+	if req.Cursor != "" {
+		if filter.After, err = decodeSearchCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	if req.FolderId != "" {
+		if err := validateID("folder_id", req.FolderId); err != nil {
+			return nil, err
+		}
+		if filter.UserID, err = s.authorizeFolder(ctx, req.FolderId, req.UserId, domain.ShareRoleViewer); err != nil {
+			return nil, err
+		}
+		filter.FolderID = req.FolderId
+	}
+	limit := int(req.Limit)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	// One more than a page tells whether another page follows
+	filter.Limit = limit + 1
+
+	results, err := s.search.Search(ctx, filter)
+	if err != nil {
+		return nil, repoError("search", err)
+	}
+
+	resp := &pb.SearchFilesResponse{}
+	if len(results) > limit {
+		results = results[:limit]
+		resp.NextCursor = encodeSearchCursor(results[limit-1].Cursor)
+	}
+	resp.Hits = make([]*pb.SearchHit, len(results))
+	for i, result := range results {
+		hit := &pb.SearchHit{File: result.File, Folder: result.Folder, Score: result.Cursor.Score}
+		name := result.File.GetName()
+		if result.Folder != nil {
+			name = result.Folder.Name
+		}
+		hit.Highlights = highlightName(name, filter.Words)
+		resp.Hits[i] = hit
+	}
+	return resp, nil
+}
+
+// searchWords splits a query into lowercase words of letters and digits
+func searchWords(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlightName marks the case-insensitive occurrences of words in a name, in characters.
+// Overlapping and adjacent occurrences are merged.
+func highlightName(name string, words []string) []*pb.Highlight {
+	lowered := []rune(strings.Map(unicode.ToLower, name))
+
+	var spans [][2]int
+	for _, word := range words {
+		w := []rune(word)
+		for start := 0; start+len(w) <= len(lowered); start++ {
+			if string(lowered[start:start+len(w)]) == word {
+				spans = append(spans, [2]int{start, start + len(w)})
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var highlights []*pb.Highlight
+	for _, span := range spans {
+		if n := len(highlights); n > 0 && span[0] <= int(highlights[n-1].End) {
+			highlights[n-1].End = max(highlights[n-1].End, int32(span[1]))
+			continue
+		}
+		highlights = append(highlights, &pb.Highlight{Start: int32(span[0]), End: int32(span[1])})
+	}
+	return highlights
+}
+
+// encodeSearchCursor turns the position of a page's last result into an opaque cursor
+func encodeSearchCursor(cursor repository.SearchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSearchCursor(token string) (*repository.SearchCursor, error) {
+	var cursor repository.SearchCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil || (cursor.Kind != repository.SearchKindFile && cursor.Kind != repository.SearchKindFolder) {
+		return nil, status.Error(codes.InvalidArgument, "cursor is invalid")
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockSearchRepository is a mock implementation of SearchRepository
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, filter repository.SearchFilter) ([]repository.SearchResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.SearchResult), args.Error(1)
+}
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
+
+func TestFileService_SearchFiles(t *testing.T) {
+	fileHit := repository.SearchResult{
+		File:   &pb.File{Id: testFileID, Name: "Quarterly Report.pdf"},
+		Cursor: repository.SearchCursor{Score: 0.9, Kind: repository.SearchKindFile, ID: uuid.MustParse(testFileID)},
+	}
+	folderHit := repository.SearchResult{
+		Folder: &pb.Folder{Id: testFolderID, Name: "reports"},
+		Cursor: repository.SearchCursor{Score: 0.5, Kind: repository.SearchKindFolder, ID: uuid.MustParse(testFolderID)},
+	}
+
+	tests := []struct {
+		name          string
+		request       *pb.SearchFilesRequest
+		mockSetup     func(*MockSearchRepository)
+		expectedError bool
+		errorCode     codes.Code
+		validate      func(*testing.T, *pb.SearchFilesResponse)
+	}{
+		{
+			name:    "files and folders with highlights",
+			request: &pb.SearchFilesRequest{UserId: testUserID, Query: " report "},
+			mockSetup: func(search *MockSearchRepository) {
+				search.On("Search", mock.Anything, repository.SearchFilter{
+					UserID: testUserID, Query: "report", Words: []string{"report"}, MimeTypes: []string{}, Limit: 21,
+				}).Return([]repository.SearchResult{fileHit, folderHit}, nil)
+			},
+			validate: func(t *testing.T, resp *pb.SearchFilesResponse) {
+				require.Len(t, resp.Hits, 2)
+				assert.Equal(t, testFileID, resp.Hits[0].File.Id)
+				assert.Equal(t, []*pb.Highlight{{Start: 10, End: 16}}, resp.Hits[0].Highlights)
+				assert.Equal(t, testFolderID, resp.Hits[1].Folder.Id)
+				assert.Equal(t, []*pb.Highlight{{Start: 0, End: 6}}, resp.Hits[1].Highlights)
+				assert.Empty(t, resp.NextCursor)
+			},
+		},
+		{
+			name:    "next page",
+			request: &pb.SearchFilesRequest{UserId: testUserID, Query: "report", Limit: 1},
+			mockSetup: func(search *MockSearchRepository) {
+				search.On("Search", mock.Anything, mock.MatchedBy(func(filter repository.SearchFilter) bool { return filter.Limit == 2 })).
+					Return([]repository.SearchResult{fileHit, folderHit}, nil)
+			},
+			validate: func(t *testing.T, resp *pb.SearchFilesResponse) {
+				require.Len(t, resp.Hits, 1)
+				cursor, err := decodeSearchCursor(resp.NextCursor)
+				require.NoError(t, err)
+				assert.Equal(t, fileHit.Cursor, *cursor)
+			},
+		},
+		{
+			name: "cursor and filters passed on",
+			request: &pb.SearchFilesRequest{UserId: testUserID, Query: "report", MimeTypes: []string{"Image/*"},
+				MinSize: int64Ptr(1), MaxSize: int64Ptr(100), Cursor: encodeSearchCursor(folderHit.Cursor)},
+			mockSetup: func(search *MockSearchRepository) {
+				search.On("Search", mock.Anything, mock.MatchedBy(func(filter repository.SearchFilter) bool {
+					return filter.After != nil && *filter.After == folderHit.Cursor &&
+						assert.ObjectsAreEqual([]string{"image/*"}, filter.MimeTypes) &&
+						*filter.MinSize == 1 && *filter.MaxSize == 100
+				})).Return([]repository.SearchResult{}, nil)
+			},
+			validate: func(t *testing.T, resp *pb.SearchFilesResponse) {
+				assert.Empty(t, resp.Hits)
+			},
+		},
+		{
+			name:          "missing query",
+			request:       &pb.SearchFilesRequest{UserId: testUserID, Query: "  "},
+			mockSetup:     func(search *MockSearchRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "size range reversed",
+			request:       &pb.SearchFilesRequest{UserId: testUserID, Query: "report", MinSize: int64Ptr(10), MaxSize: int64Ptr(1)},
+			mockSetup:     func(search *MockSearchRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "invalid cursor",
+			request:       &pb.SearchFilesRequest{UserId: testUserID, Query: "report", Cursor: "not a cursor"},
+			mockSetup:     func(search *MockSearchRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "invalid MIME type",
+			request:       &pb.SearchFilesRequest{UserId: testUserID, Query: "report", MimeTypes: []string{"pdf"}},
+			mockSetup:     func(search *MockSearchRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			mockSearch := new(MockSearchRepository)
+			service.search = mockSearch
+			tt.mockSetup(mockSearch)
+
+			resp, err := service.SearchFiles(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				if tt.validate != nil {
+					tt.validate(t, resp)
+				}
+			}
+
+			mockSearch.AssertExpectations(t)
+		})
+	}
+
+	t.Run("shared folder searched under its owner", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockShares := withShares(service)
+		mockShares.On("FolderAccess", mock.Anything, testFolderID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleViewer}, nil)
+		mockSearch := new(MockSearchRepository)
+		service.search = mockSearch
+		mockSearch.On("Search", mock.Anything, mock.MatchedBy(func(filter repository.SearchFilter) bool {
+			return filter.UserID == testUserID && filter.FolderID == testFolderID
+		})).Return([]repository.SearchResult{}, nil)
+
+		_, err := service.SearchFiles(context.Background(), &pb.SearchFilesRequest{UserId: testGranteeID, Query: "report", FolderId: testFolderID})
+
+		require.NoError(t, err)
+		mockSearch.AssertExpectations(t)
+	})
+}
+
+func TestHighlightName(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		expected []*pb.Highlight
+	}{
+		{name: "Report-2026-report.txt", words: []string{"report"}, expected: []*pb.Highlight{{Start: 0, End: 6}, {Start: 12, End: 18}}},
+		{name: "annual report", words: []string{"rep", "report"}, expected: []*pb.Highlight{{Start: 7, End: 13}}},
+		{name: "Übersicht.pdf", words: []string{"übersicht"}, expected: []*pb.Highlight{{Start: 0, End: 9}}},
+		{name: "notes.txt", words: []string{"report"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, highlightName(tt.name, tt.words))
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+const (
+	// ShareLinkPathPrefix is where the API gateway serves public share links
+	ShareLinkPathPrefix = "/s/"
+	// linkTokenBytes is the number of random bytes in a share or file request link token
+	linkTokenBytes = 32
+	// maxShareLinkPassword is the longest password bcrypt can hash
+	maxShareLinkPassword = 72
+)
+
+// CreateShareLink creates a public link to a file or folder. Only the owner may create one.
+// The token is returned only here; the link stores a hash of it.
+func (s *FileService) CreateShareLink(ctx context.Context, req *pb.CreateShareLinkRequest) (*pb.CreateShareLinkResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.MaxDownloads < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_downloads must not be negative")
+	}
+	if req.ExpiresAt != nil && (req.ExpiresAt.CheckValid() != nil || !req.ExpiresAt.AsTime().After(s.now())) {
+		return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+	}
+	if len(req.Password) > maxShareLinkPassword {
+		return nil, status.Errorf(codes.InvalidArgument, "password must be at most %d bytes", maxShareLinkPassword)
+	}
+	owner, err := s.authorizeItem(ctx, req.FileId, req.FolderId, req.UserId, domain.ShareRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkShareable(ctx, req.FileId, owner); err != nil {
+		return nil, err
+	}
+
+	var passwordHash string
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hash password: %v", err)
+		}
+		passwordHash = string(hash)
+	}
+
+	token, err := newLinkToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.links.Create(ctx, req, hashLinkToken(token), passwordHash)
+	if err != nil {
+		return nil, repoError("create share link", err)
+	}
+
+	return &pb.CreateShareLinkResponse{
+		Link:  link,
+		Token: token,
+		Url:   s.publicURL + ShareLinkPathPrefix + token,
+	}, nil
+}
+
+func (s *FileService) ListShareLinks(ctx context.Context, req *pb.ListShareLinksRequest) (*pb.ListShareLinksResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	links, totalCount, err := s.links.List(ctx, req.UserId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list share links", err)
+	}
+
+	return &pb.ListShareLinksResponse{
+		Links:      links,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (s *FileService) RevokeShareLink(ctx context.Context, req *pb.RevokeShareLinkRequest) (*pb.RevokeShareLinkResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.links.Revoke(ctx, req.Id, req.UserId); err != nil {
+		return nil, repoError("revoke share link", err)
+	}
+
+	return &pb.RevokeShareLinkResponse{Message: "Share link revoked successfully"}, nil
+}
+
+// OpenShareLink returns what a public link points at. A folder link can open any file or
+// folder below the linked folder; folders come with a page of their contents.
+func (s *FileService) OpenShareLink(ctx context.Context, req *pb.OpenShareLinkRequest) (*pb.OpenShareLinkResponse, error) {
+	link, err := s.openShareLink(ctx, req.Token, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.FileId != "" || req.FileId != "" {
+		file, err := s.linkedFile(ctx, link, req.FileId)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.OpenShareLinkResponse{Link: link, File: file}, nil
+	}
+
+	folderID := link.FolderId
+	if req.FolderId != "" {
+		if err := validateID("folder_id", req.FolderId); err != nil {
+			return nil, err
+		}
+		below, err := s.links.InFolder(ctx, link.FolderId, req.FolderId)
+		if err != nil {
+			return nil, repoError("check folder", err)
+		}
+		if !below {
+			return nil, status.Error(codes.NotFound, "folder not found")
+		}
+		folderID = req.FolderId
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	folder, err := s.folders.GetByID(ctx, folderID, link.OwnerId)
+	if err != nil {
+		return nil, repoError("get folder", err)
+	}
+	folders, files, totalCount, err := s.folders.ListChildren(ctx, link.OwnerId, folderID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list folder", err)
+	}
+
+	return &pb.OpenShareLinkResponse{
+		Link:       link,
+		Folder:     folder,
+		Folders:    folders,
+		Files:      files,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// DownloadShareLink counts a download through a public link and returns the file for the
+// gateway to send. Preview-only links and links that reached their limit are refused. A
+// resumed download is not counted again, but only links that have been downloaded before
+// can be resumed.
+func (s *FileService) DownloadShareLink(ctx context.Context, req *pb.DownloadShareLinkRequest) (*pb.DownloadShareLinkResponse, error) {
+	link, err := s.openShareLink(ctx, req.Token, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	if link.PreviewOnly {
+		return nil, status.Error(codes.PermissionDenied, "this link only allows previews")
+	}
+	file, err := s.linkedFile(ctx, link, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDownloadable(file); err != nil {
+		return nil, err
+	}
+	if req.Resume && link.DownloadCount > 0 {
+		return &pb.DownloadShareLinkResponse{Link: link, File: file}, nil
+	}
+
+	link, err = s.links.CountDownload(ctx, link.Id)
+	if err != nil {
+		return nil, repoError("count download", err)
+	}
+
+	return &pb.DownloadShareLinkResponse{Link: link, File: file}, nil
+}
+
+// openShareLink looks up a link by its token and checks its expiry and password.
+// Unknown and revoked tokens are both reported as not found.
+func (s *FileService) openShareLink(ctx context.Context, token, password string) (*pb.ShareLink, error) {
+	if token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	stored, err := s.links.GetByToken(ctx, hashLinkToken(token))
+	if err != nil {
+		return nil, repoError("get share link", err)
+	}
+	link := stored.Link
+	if link.ExpiresAt != nil && !s.now().Before(link.ExpiresAt.AsTime()) {
+		return nil, status.Error(codes.FailedPrecondition, "share link has expired")
+	}
+	if stored.PasswordHash != "" {
+		if password == "" {
+			return nil, status.Error(codes.Unauthenticated, "share link requires a password")
+		}
+		if bcrypt.CompareHashAndPassword([]byte(stored.PasswordHash), []byte(password)) != nil {
+			return nil, status.Error(codes.Unauthenticated, "incorrect share link password")
+		}
+	}
+
+	return link, nil
+}
+
+// linkedFile returns a file reachable through a link: the linked file itself, or a file
+// below the linked folder
+func (s *FileService) linkedFile(ctx context.Context, link *pb.ShareLink, fileID string) (*pb.File, error) {
+	if link.FileId != "" {
+		if fileID != "" && fileID != link.FileId {
+			return nil, status.Error(codes.NotFound, "file not found")
+		}
+		file, err := s.repo.GetByID(ctx, link.FileId, link.OwnerId)
+		if err != nil {
+			return nil, repoError("get file", err)
+		}
+		return file, nil
+	}
+
+	if err := validateID("file_id", fileID); err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, fileID, link.OwnerId)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+	if file.FolderId == "" {
+		return nil, status.Error(codes.NotFound, "file not found")
+	}
+	below, err := s.links.InFolder(ctx, link.FolderId, file.FolderId)
+	if err != nil {
+		return nil, repoError("check folder", err)
+	}
+	if !below {
+		return nil, status.Error(codes.NotFound, "file not found")
+	}
+	return file, nil
+}
+
+// newLinkToken generates the secret token of a public link
+func newLinkToken() (string, error) {
+	raw := make([]byte, linkTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to generate token: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashLinkToken returns the hash a link's token is stored as. Tokens are random,
+// so a fast hash is enough to keep a leaked table from exposing working links.
+func hashLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const (
+	testLinkID    = "623e4567-e89b-12d3-a456-426614174000"
+	testLinkToken = "q0bxjW3sT5yq1oMZ0yQ8cN2xvYd7tJm4aL6pKe9hR1s"
+	testSubfolder = "723e4567-e89b-12d3-a456-426614174000"
+)
+
+// MockShareLinkRepository is a mock implementation of ShareLinkRepository
+type MockShareLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, req *pb.CreateShareLinkRequest, tokenHash, passwordHash string) (*pb.ShareLink, error) {
+	args := m.Called(ctx, req, tokenHash, passwordHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) GetByToken(ctx context.Context, tokenHash string) (*repository.StoredShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.StoredShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.ShareLink, int32, error) {
+	args := m.Called(ctx, userID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.ShareLink), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockShareLinkRepository) Revoke(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) CountDownload(ctx context.Context, id string) (*pb.ShareLink, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) InFolder(ctx context.Context, folderID, descendantID string) (bool, error) {
+	args := m.Called(ctx, folderID, descendantID)
+	return args.Bool(0), args.Error(1)
+}
+
+// withLinks replaces the service's share link repository with a fresh mock
+func withLinks(service *FileService) *MockShareLinkRepository {
+	mockLinks := new(MockShareLinkRepository)
+	service.links = mockLinks
+	return mockLinks
+}
+
+// expectLink makes the link with testLinkToken resolve to link, protected by password if it is set
+func expectLink(t *testing.T, links *MockShareLinkRepository, link *pb.ShareLink, password string) {
+	t.Helper()
+	stored := &repository.StoredShareLink{Link: link}
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		require.NoError(t, err)
+		stored.PasswordHash = string(hash)
+	}
+	links.On("GetByToken", mock.Anything, hashLinkToken(testLinkToken)).Return(stored, nil)
+}
+
+func TestFileService_CreateShareLink(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.CreateShareLinkRequest
+		mockSetup     func(*MockShareLinkRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name: "password protected file link",
+			request: &pb.CreateShareLinkRequest{
+				UserId:       testUserID,
+				FileId:       testFileID,
+				Password:     "open sesame",
+				MaxDownloads: 3,
+				ExpiresAt:    timestamppb.New(time.Now().Add(24 * time.Hour)),
+			},
+			mockSetup: func(links *MockShareLinkRepository) {
+				links.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateShareLinkRequest"), mock.AnythingOfType("string"),
+					mock.MatchedBy(func(hash string) bool {
+						return bcrypt.CompareHashAndPassword([]byte(hash), []byte("open sesame")) == nil
+					})).
+					Return(&pb.ShareLink{Id: testLinkID, FileId: testFileID, PasswordProtected: true, MaxDownloads: 3}, nil)
+			},
+		},
+		{
+			name:          "expiry in the past",
+			request:       &pb.CreateShareLinkRequest{UserId: testUserID, FileId: testFileID, ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour))},
+			mockSetup:     func(links *MockShareLinkRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "negative download limit",
+			request:       &pb.CreateShareLinkRequest{UserId: testUserID, FolderId: testFolderID, MaxDownloads: -1},
+			mockSetup:     func(links *MockShareLinkRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "password too long",
+			request:       &pb.CreateShareLinkRequest{UserId: testUserID, FileId: testFileID, Password: strings.Repeat("x", maxShareLinkPassword+1)},
+			mockSetup:     func(links *MockShareLinkRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(scannedFile(), nil).Maybe()
+			service := newTestService(t, mockRepo)
+			service.publicURL = "https://drive.example.com"
+			mockLinks := withLinks(service)
+			tt.mockSetup(mockLinks)
+
+			resp, err := service.CreateShareLink(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testLinkID, resp.Link.Id)
+				assert.Equal(t, "https://drive.example.com/s/"+resp.Token, resp.Url)
+				mockLinks.AssertCalled(t, "Create", mock.Anything, tt.request, hashLinkToken(resp.Token), mock.Anything)
+			}
+
+			mockLinks.AssertExpectations(t)
+		})
+	}
+
+	t.Run("only the owner creates links", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockShares := withShares(service)
+		mockShares.On("FileAccess", mock.Anything, testFileID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleEditor}, nil)
+
+		_, err := service.CreateShareLink(context.Background(), &pb.CreateShareLinkRequest{UserId: testGranteeID, FileId: testFileID})
+
+		assertStatusCode(t, err, codes.PermissionDenied)
+	})
+}
+
+func TestFileService_OpenShareLink(t *testing.T) {
+	fileLink := &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FileId: testFileID}
+	folderLink := &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID}
+
+	tests := []struct {
+		name          string
+		request       *pb.OpenShareLinkRequest
+		mockSetup     func(*testing.T, *MockShareLinkRepository, *MockFileRepository, *MockFolderRepository)
+		expectedError bool
+		errorCode     codes.Code
+		validate      func(*testing.T, *pb.OpenShareLinkResponse)
+	}{
+		{
+			name:    "file link",
+			request: &pb.OpenShareLinkRequest{Token: testLinkToken},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				expectLink(t, links, fileLink, "")
+				files.On("GetByID", mock.Anything, testFileID, testUserID).Return(&pb.File{Id: testFileID}, nil)
+			},
+			validate: func(t *testing.T, resp *pb.OpenShareLinkResponse) {
+				assert.Equal(t, testFileID, resp.File.Id)
+			},
+		},
+		{
+			name:    "correct password",
+			request: &pb.OpenShareLinkRequest{Token: testLinkToken, Password: "open sesame"},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				expectLink(t, links, fileLink, "open sesame")
+				files.On("GetByID", mock.Anything, testFileID, testUserID).Return(&pb.File{Id: testFileID}, nil)
+			},
+		},
+		{
+			name:    "missing password",
+			request: &pb.OpenShareLinkRequest{Token: testLinkToken},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				expectLink(t, links, fileLink, "open sesame")
+			},
+			expectedError: true,
+			errorCode:     codes.Unauthenticated,
+		},
+		{
+			name:    "wrong password",
+			request: &pb.OpenShareLinkRequest{Token: testLinkToken, Password: "guess"},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				expectLink(t, links, fileLink, "open sesame")
+			},
+			expectedError: true,
+			errorCode:     codes.Unauthenticated,
+		},
+		{
+			name:    "expired",
+			request: &pb.OpenShareLinkRequest{Token: testLinkToken},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				expectLink(t, links, &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FileId: testFileID,
+					ExpiresAt: timestamppb.New(time.Now().Add(-time.Minute))}, "")
+			},
+			expectedError: true,
+			errorCode:     codes.FailedPrecondition,
+		},
+		{
+			name:    "unknown token",
+			request: &pb.OpenShareLinkRequest{Token: "nope"},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				links.On("GetByToken", mock.Anything, hashLinkToken("nope")).Return(nil, repository.ErrShareLinkNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+		{
+			name:    "folder below a folder link",
+			request: &pb.OpenShareLinkRequest{Token: testLinkToken, FolderId: testSubfolder},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				expectLink(t, links, folderLink, "")
+				links.On("InFolder", mock.Anything, testFolderID, testSubfolder).Return(true, nil)
+				folders.On("GetByID", mock.Anything, testSubfolder, testUserID).Return(&pb.Folder{Id: testSubfolder}, nil)
+				folders.On("ListChildren", mock.Anything, testUserID, testSubfolder, int32(1), int32(20)).
+					Return([]*pb.Folder{}, []*pb.File{{Id: testFileID}}, int32(1), nil)
+			},
+			validate: func(t *testing.T, resp *pb.OpenShareLinkResponse) {
+				assert.Equal(t, testSubfolder, resp.Folder.Id)
+				assert.Len(t, resp.Files, 1)
+				assert.Equal(t, int32(1), resp.TotalCount)
+			},
+		},
+		{
+			name:    "folder outside a folder link",
+			request: &pb.OpenShareLinkRequest{Token: testLinkToken, FolderId: testSubfolder},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository, folders *MockFolderRepository) {
+				expectLink(t, links, folderLink, "")
+				links.On("InFolder", mock.Anything, testFolderID, testSubfolder).Return(false, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			service := newTestService(t, mockRepo)
+			mockFolders := new(MockFolderRepository)
+			service.folders = mockFolders
+			mockLinks := withLinks(service)
+			tt.mockSetup(t, mockLinks, mockRepo, mockFolders)
+
+			resp, err := service.OpenShareLink(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				if tt.validate != nil {
+					tt.validate(t, resp)
+				}
+			}
+
+			mockLinks.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+			mockFolders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_DownloadShareLink(t *testing.T) {
+	folderLink := &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FolderId: testFolderID}
+
+	tests := []struct {
+		name          string
+		request       *pb.DownloadShareLinkRequest
+		mockSetup     func(*testing.T, *MockShareLinkRepository, *MockFileRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "file below a folder link",
+			request: &pb.DownloadShareLinkRequest{Token: testLinkToken, FileId: testFileID},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository) {
+				expectLink(t, links, folderLink, "")
+				files.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID, FolderId: testSubfolder}, nil)
+				links.On("InFolder", mock.Anything, testFolderID, testSubfolder).Return(true, nil)
+				links.On("CountDownload", mock.Anything, testLinkID).
+					Return(&pb.ShareLink{Id: testLinkID, DownloadCount: 1}, nil)
+			},
+		},
+		{
+			name:    "file outside a folder link",
+			request: &pb.DownloadShareLinkRequest{Token: testLinkToken, FileId: testFileID},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository) {
+				expectLink(t, links, folderLink, "")
+				files.On("GetByID", mock.Anything, testFileID, testUserID).
+					Return(&pb.File{Id: testFileID}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+		{
+			name:    "preview only",
+			request: &pb.DownloadShareLinkRequest{Token: testLinkToken},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository) {
+				expectLink(t, links, &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FileId: testFileID, PreviewOnly: true}, "")
+			},
+			expectedError: true,
+			errorCode:     codes.PermissionDenied,
+		},
+		{
+			name:    "download limit reached",
+			request: &pb.DownloadShareLinkRequest{Token: testLinkToken},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository) {
+				expectLink(t, links, &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FileId: testFileID, MaxDownloads: 1, DownloadCount: 1}, "")
+				files.On("GetByID", mock.Anything, testFileID, testUserID).Return(&pb.File{Id: testFileID}, nil)
+				links.On("CountDownload", mock.Anything, testLinkID).Return(nil, repository.ErrDownloadLimitReached)
+			},
+			expectedError: true,
+			errorCode:     codes.ResourceExhausted,
+		},
+		{
+			name:    "resumed download is not counted again",
+			request: &pb.DownloadShareLinkRequest{Token: testLinkToken, Resume: true},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository) {
+				expectLink(t, links, &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FileId: testFileID, MaxDownloads: 1, DownloadCount: 1}, "")
+				files.On("GetByID", mock.Anything, testFileID, testUserID).Return(&pb.File{Id: testFileID}, nil)
+			},
+		},
+		{
+			name:    "resume of a link never downloaded is counted",
+			request: &pb.DownloadShareLinkRequest{Token: testLinkToken, Resume: true},
+			mockSetup: func(t *testing.T, links *MockShareLinkRepository, files *MockFileRepository) {
+				expectLink(t, links, &pb.ShareLink{Id: testLinkID, OwnerId: testUserID, FileId: testFileID, MaxDownloads: 1}, "")
+				files.On("GetByID", mock.Anything, testFileID, testUserID).Return(&pb.File{Id: testFileID}, nil)
+				links.On("CountDownload", mock.Anything, testLinkID).
+					Return(&pb.ShareLink{Id: testLinkID, MaxDownloads: 1, DownloadCount: 1}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			service := newTestService(t, mockRepo)
+			mockLinks := withLinks(service)
+			tt.mockSetup(t, mockLinks, mockRepo)
+
+			resp, err := service.DownloadShareLink(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testFileID, resp.File.Id)
+				assert.Equal(t, int32(1), resp.Link.DownloadCount)
+			}
+
+			mockLinks.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_RevokeShareLink(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	mockLinks := withLinks(service)
+	mockLinks.On("Revoke", mock.Anything, testLinkID, testUserID).Return(repository.ErrShareLinkNotFound)
+
+	_, err := service.RevokeShareLink(context.Background(), &pb.RevokeShareLinkRequest{Id: testLinkID, UserId: testUserID})
+
+	assertStatusCode(t, err, codes.NotFound)
+	mockLinks.AssertExpectations(t)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+)
+
+// ShareItem shares a file or folder with another user. Only the owner may share an item;
+// sharing it again with the same user changes their role.
+func (s *FileService) ShareItem(ctx context.Context, req *pb.ShareItemRequest) (*pb.ShareItemResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if err := validateID("grantee_id", req.GranteeId); err != nil {
+		return nil, err
+	}
+	if req.GranteeId == req.UserId {
+		return nil, status.Error(codes.InvalidArgument, "items cannot be shared with their owner")
+	}
+	if !domain.IsValidShareRole(req.Role) {
+		return nil, status.Error(codes.InvalidArgument, "role must be viewer, commenter or editor")
+	}
+	owner, err := s.authorizeItem(ctx, req.FileId, req.FolderId, req.UserId, domain.ShareRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkShareable(ctx, req.FileId, owner); err != nil {
+		return nil, err
+	}
+
+	share, err := s.shares.Grant(ctx, req)
+	if err != nil {
+		return nil, repoError("share item", err)
+	}
+
+	return &pb.ShareItemResponse{Share: share}, nil
+}
+
+// ListShares lists the users an item is shared with directly. Shares inherited from
+// folders above the item are listed on those folders.
+func (s *FileService) ListShares(ctx context.Context, req *pb.ListSharesRequest) (*pb.ListSharesResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizeItem(ctx, req.FileId, req.FolderId, req.UserId, domain.ShareRoleOwner); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shares.List(ctx, req.FileId, req.FolderId)
+	if err != nil {
+		return nil, repoError("list shares", err)
+	}
+
+	return &pb.ListSharesResponse{Shares: shares}, nil
+}
+
+// RevokeShare deletes a share. The owner revokes it; the user it was granted to leaves it.
+func (s *FileService) RevokeShare(ctx context.Context, req *pb.RevokeShareRequest) (*pb.RevokeShareResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.shares.Revoke(ctx, req.Id, req.UserId); err != nil {
+		return nil, repoError("revoke share", err)
+	}
+
+	return &pb.RevokeShareResponse{Message: "Share revoked successfully"}, nil
+}
+
+func (s *FileService) ListSharedWithMe(ctx context.Context, req *pb.ListSharedWithMeRequest) (*pb.ListSharedWithMeResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	items, totalCount, err := s.shares.ListSharedWith(ctx, req.UserId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list shared items", err)
+	}
+
+	return &pb.ListSharedWithMeResponse{
+		Items:      items,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// authorizeFile checks that the user has at least the required role on a file and returns
+// the file's owner. Records are kept under their owner, so repository calls use that ID.
+func (s *FileService) authorizeFile(ctx context.Context, fileID, userID, required string) (string, error) {
+	access, err := s.shares.FileAccess(ctx, fileID, userID)
+	if err != nil {
+		return "", repoError("check file access", err)
+	}
+	if !domain.ShareRoleAllows(access.Role, required) {
+		return "", status.Errorf(codes.PermissionDenied, "%s access to this file is required", required)
+	}
+	return access.OwnerID, nil
+}
+
+// authorizeFolder checks that the user has at least the required role on a folder and
+// returns the folder's owner
+func (s *FileService) authorizeFolder(ctx context.Context, folderID, userID, required string) (string, error) {
+	access, err := s.shares.FolderAccess(ctx, folderID, userID)
+	if err != nil {
+		return "", repoError("check folder access", err)
+	}
+	if !domain.ShareRoleAllows(access.Role, required) {
+		return "", status.Errorf(codes.PermissionDenied, "%s access to this folder is required", required)
+	}
+	return access.OwnerID, nil
+}
+
+// authorizeParent returns the owner of the folder new items are added to. Adding to a
+// shared folder takes editor access, and the items belong to the folder's owner.
+func (s *FileService) authorizeParent(ctx context.Context, folderID, userID string) (string, error) {
+	if folderID == "" {
+		return userID, nil
+	}
+	return s.authorizeFolder(ctx, folderID, userID, domain.ShareRoleEditor)
+}
+
+// authorizeMove checks that an item of owner may be moved into a folder. Items only move
+// within their owner's drive, so the top of it, named by an empty folderID, is a target for
+// the owner alone; anyone else's would be their own drive.
+func (s *FileService) authorizeMove(ctx context.Context, folderID, userID, owner string) error {
+	if folderID == "" && userID != owner {
+		return status.Error(codes.PermissionDenied, "only the owner can move items to the top of their drive")
+	}
+	_, err := s.authorizeParent(ctx, folderID, userID)
+	return err
+}
+
+// authorizeItem checks access to a file or folder named by a request that must set exactly one of them
+func (s *FileService) authorizeItem(ctx context.Context, fileID, folderID, userID, required string) (string, error) {
+	if (fileID == "") == (folderID == "") {
+		return "", status.Error(codes.InvalidArgument, "exactly one of file_id and folder_id is required")
+	}
+	if fileID != "" {
+		if err := validateID("file_id", fileID); err != nil {
+			return "", err
+		}
+		return s.authorizeFile(ctx, fileID, userID, required)
+	}
+	if err := validateID("folder_id", folderID); err != nil {
+		return "", err
+	}
+	return s.authorizeFolder(ctx, folderID, userID, required)
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const (
+	testGranteeID = "423e4567-e89b-12d3-a456-426614174000"
+	testShareID   = "523e4567-e89b-12d3-a456-426614174000"
+)
+
+// ownerShares reports every user as the owner of every item, so tests that are not about
+// sharing see the service behave as it does for a user's own files
+type ownerShares struct {
+	repository.ShareRepository
+}
+
+func (ownerShares) FileAccess(ctx context.Context, fileID, userID string) (*repository.Access, error) {
+	return &repository.Access{OwnerID: userID, Role: domain.ShareRoleOwner}, nil
+}
+
+func (ownerShares) FolderAccess(ctx context.Context, folderID, userID string) (*repository.Access, error) {
+	return &repository.Access{OwnerID: userID, Role: domain.ShareRoleOwner}, nil
+}
+
+// MockShareRepository is a mock implementation of ShareRepository
+type MockShareRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareRepository) FileAccess(ctx context.Context, fileID, userID string) (*repository.Access, error) {
+	args := m.Called(ctx, fileID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Access), args.Error(1)
+}
+
+func (m *MockShareRepository) FolderAccess(ctx context.Context, folderID, userID string) (*repository.Access, error) {
+	args := m.Called(ctx, folderID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Access), args.Error(1)
+}
+
+func (m *MockShareRepository) Grant(ctx context.Context, req *pb.ShareItemRequest) (*pb.Share, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Share), args.Error(1)
+}
+
+func (m *MockShareRepository) List(ctx context.Context, fileID, folderID string) ([]*pb.Share, error) {
+	args := m.Called(ctx, fileID, folderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pb.Share), args.Error(1)
+}
+
+func (m *MockShareRepository) Revoke(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockShareRepository) ListSharedWith(ctx context.Context, userID string, page, pageSize int32) ([]*pb.SharedItem, int32, error) {
+	args := m.Called(ctx, userID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.SharedItem), args.Get(1).(int32), args.Error(2)
+}
+
+// withShares replaces the service's owner-only access checks with a mock
+func withShares(service *FileService) *MockShareRepository {
+	mockShares := new(MockShareRepository)
+	service.shares = mockShares
+	return mockShares
+}
+
+func TestFileService_ShareItem(t *testing.T) {
+	ownerAccess := &repository.Access{OwnerID: testUserID, Role: domain.ShareRoleOwner}
+
+	tests := []struct {
+		name          string
+		request       *pb.ShareItemRequest
+		mockSetup     func(*MockShareRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "share a folder",
+			request: &pb.ShareItemRequest{UserId: testUserID, FolderId: testFolderID, GranteeId: testGranteeID, Role: domain.ShareRoleEditor},
+			mockSetup: func(shares *MockShareRepository) {
+				shares.On("FolderAccess", mock.Anything, testFolderID, testUserID).Return(ownerAccess, nil)
+				shares.On("Grant", mock.Anything, mock.AnythingOfType("*file.ShareItemRequest")).
+					Return(&pb.Share{Id: testShareID, FolderId: testFolderID, GranteeId: testGranteeID, Role: domain.ShareRoleEditor}, nil)
+			},
+		},
+		{
+			name:          "invalid role",
+			request:       &pb.ShareItemRequest{UserId: testUserID, FileId: testFileID, GranteeId: testGranteeID, Role: domain.ShareRoleOwner},
+			mockSetup:     func(shares *MockShareRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "share with self",
+			request:       &pb.ShareItemRequest{UserId: testUserID, FileId: testFileID, GranteeId: testUserID, Role: domain.ShareRoleViewer},
+			mockSetup:     func(shares *MockShareRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "both file and folder",
+			request:       &pb.ShareItemRequest{UserId: testUserID, FileId: testFileID, FolderId: testFolderID, GranteeId: testGranteeID, Role: domain.ShareRoleViewer},
+			mockSetup:     func(shares *MockShareRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "editor cannot reshare",
+			request: &pb.ShareItemRequest{UserId: testUserID, FileId: testFileID, GranteeId: testGranteeID, Role: domain.ShareRoleViewer},
+			mockSetup: func(shares *MockShareRepository) {
+				shares.On("FileAccess", mock.Anything, testFileID, testUserID).
+					Return(&repository.Access{OwnerID: testGranteeID, Role: domain.ShareRoleEditor}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.PermissionDenied,
+		},
+		{
+			name:    "grantee not found",
+			request: &pb.ShareItemRequest{UserId: testUserID, FileId: testFileID, GranteeId: testGranteeID, Role: domain.ShareRoleViewer},
+			mockSetup: func(shares *MockShareRepository) {
+				shares.On("FileAccess", mock.Anything, testFileID, testUserID).Return(ownerAccess, nil)
+				shares.On("Grant", mock.Anything, mock.Anything).Return(nil, repository.ErrUserNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(scannedFile(), nil).Maybe()
+			service := newTestService(t, mockRepo)
+			mockShares := withShares(service)
+			tt.mockSetup(mockShares)
+
+			resp, err := service.ShareItem(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testShareID, resp.Share.Id)
+			}
+
+			mockShares.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_ListShares(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	mockShares := withShares(service)
+	mockShares.On("FileAccess", mock.Anything, testFileID, testUserID).
+		Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleOwner}, nil)
+	mockShares.On("List", mock.Anything, testFileID, "").
+		Return([]*pb.Share{{Id: testShareID, FileId: testFileID}}, nil)
+
+	resp, err := service.ListShares(context.Background(), &pb.ListSharesRequest{UserId: testUserID, FileId: testFileID})
+
+	require.NoError(t, err)
+	assert.Len(t, resp.Shares, 1)
+	mockShares.AssertExpectations(t)
+}
+
+func TestFileService_RevokeShare(t *testing.T) {
+	tests := []struct {
+		name          string
+		revokeErr     error
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{name: "revoked"},
+		{name: "share not found", revokeErr: repository.ErrShareNotFound, expectedError: true, errorCode: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, new(MockFileRepository))
+			mockShares := withShares(service)
+			mockShares.On("Revoke", mock.Anything, testShareID, testUserID).Return(tt.revokeErr)
+
+			_, err := service.RevokeShare(context.Background(), &pb.RevokeShareRequest{Id: testShareID, UserId: testUserID})
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+			}
+			mockShares.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_ListSharedWithMe(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	mockShares := withShares(service)
+	mockShares.On("ListSharedWith", mock.Anything, testGranteeID, int32(1), int32(20)).
+		Return([]*pb.SharedItem{{Share: &pb.Share{Id: testShareID}, Folder: &pb.Folder{Id: testFolderID}}}, int32(1), nil)
+
+	resp, err := service.ListSharedWithMe(context.Background(), &pb.ListSharedWithMeRequest{UserId: testGranteeID})
+
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, int32(1), resp.TotalCount)
+	mockShares.AssertExpectations(t)
+}
+
+func TestFileService_SharedAccess(t *testing.T) {
+	t.Run("viewer reads the owner's file", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+			Return(&pb.File{Id: testFileID, UserId: testUserID}, nil)
+		service := newTestService(t, mockRepo)
+		mockShares := withShares(service)
+		mockShares.On("FileAccess", mock.Anything, testFileID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleViewer}, nil)
+
+		resp, err := service.GetFile(context.Background(), &pb.GetFileRequest{Id: testFileID, UserId: testGranteeID})
+
+		require.NoError(t, err)
+		assert.Equal(t, testUserID, resp.File.UserId)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("viewer cannot delete", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		service := newTestService(t, mockRepo)
+		mockShares := withShares(service)
+		mockShares.On("FileAccess", mock.Anything, testFileID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleViewer}, nil)
+
+		_, err := service.DeleteFile(context.Background(), &pb.DeleteFileRequest{Id: testFileID, UserId: testGranteeID})
+
+		assertStatusCode(t, err, codes.PermissionDenied)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unshared file is not found", func(t *testing.T) {
+		service := newTestService(t, new(MockFileRepository))
+		mockShares := withShares(service)
+		mockShares.On("FileAccess", mock.Anything, testFileID, testGranteeID).Return(nil, repository.ErrFileNotFound)
+
+		_, err := service.GetFile(context.Background(), &pb.GetFileRequest{Id: testFileID, UserId: testGranteeID})
+
+		assertStatusCode(t, err, codes.NotFound)
+	})
+
+	t.Run("editor creates files owned by the folder owner", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockRepo.On("Create", mock.Anything, &pb.CreateFileRequest{Name: "notes.txt", UserId: testUserID, FolderId: testFolderID}, mock.Anything).
+			Return(&pb.File{Id: testFileID, UserId: testUserID, FolderId: testFolderID}, nil)
+		service := newTestService(t, mockRepo)
+		mockShares := withShares(service)
+		mockShares.On("FolderAccess", mock.Anything, testFolderID, testGranteeID).
+			Return(&repository.Access{OwnerID: testUserID, Role: domain.ShareRoleEditor}, nil)
+
+		resp, err := service.CreateFile(context.Background(), &pb.CreateFileRequest{Name: "notes.txt", UserId: testGranteeID, FolderId: testFolderID})
+
+		require.NoError(t, err)
+		assert.Equal(t, testUserID, resp.File.UserId)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// downloadChunkSize is the size of content chunks sent by DownloadFile
+const downloadChunkSize = 256 << 10
+
+// UploadFile stores content streamed by the client as a new file.
+// The first message must be the header; every following message carries a chunk.
+func (s *FileService) UploadFile(stream pb.FileService_UploadFileServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "upload header is required")
+		}
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "first message must be the upload header")
+	}
+	if err := validateUploadHeader(header); err != nil {
+		return err
+	}
+	if err := s.checkFileType(header.Name, header.MimeType); err != nil {
+		return err
+	}
+
+	ctx := repository.ActingAs(stream.Context(), header.UserId)
+	owner, err := s.authorizeParent(ctx, header.FolderId, header.UserId)
+	if err != nil {
+		return err
+	}
+	quota, err := s.userQuota(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if err := checkFileSize(quota, header.Size); err != nil {
+		return err
+	}
+
+	file, err := s.repo.Create(ctx, &pb.CreateFileRequest{
+		Name:     header.Name,
+		UserId:   owner,
+		FolderId: header.FolderId,
+		MimeType: header.MimeType,
+	}, quota.Storage)
+	if err != nil {
+		return repoError("create file", err)
+	}
+
+	size := header.Size
+	if size == 0 {
+		size = -1
+	}
+	chunks := &chunkReader{stream: stream}
+	hash := sha256.New()
+	limit := fileSizeLimit(quota)
+	content := io.TeeReader(io.LimitReader(chunks, limit+1), hash)
+
+	info, err := s.blobs.Put(ctx, file.StorageKey, content, size)
+	switch {
+	case chunks.err != nil:
+		s.discardFile(file)
+		return chunks.err
+	case err != nil && header.Size > 0 && chunks.received != header.Size:
+		s.discardFile(file)
+		return status.Errorf(codes.InvalidArgument, "received %d bytes but the header declared %d", chunks.received, header.Size)
+	case err != nil:
+		s.discardFile(file)
+		return status.Errorf(codes.Internal, "failed to store file content: %v", err)
+	case info.Size > limit:
+		s.discardFile(file)
+		return checkFileSize(quota, info.Size)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if header.Checksum != "" && !strings.EqualFold(checksum, header.Checksum) {
+		s.discardFile(file)
+		return status.Errorf(codes.DataLoss, "checksum mismatch: received content hashes to %s", checksum)
+	}
+
+	completed, err := s.commitContent(ctx, file, file.StorageKey, info.Size, checksum, quota.Storage)
+	if err != nil {
+		s.discardFile(file)
+		return repoError("complete upload", err)
+	}
+
+	return stream.SendAndClose(&pb.UploadFileResponse{File: completed})
+}
+
+// DownloadFile streams a file's metadata followed by its content, or the requested part of it.
+// Full downloads are checked against the stored checksum and fail with DataLoss on a mismatch.
+func (s *FileService) DownloadFile(req *pb.DownloadFileRequest, stream pb.FileService_DownloadFileServer) error {
+	if err := validateID("id", req.Id); err != nil {
+		return err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return err
+	}
+	if req.Offset < 0 || req.Length < 0 {
+		return status.Error(codes.InvalidArgument, "offset and length must not be negative")
+	}
+
+	ctx := repository.ActingAs(stream.Context(), req.UserId)
+	owner, err := s.authorizeFile(ctx, req.Id, req.UserId, domain.ShareRoleViewer)
+	if err != nil {
+		return err
+	}
+	file, err := s.repo.GetByID(ctx, req.Id, owner)
+	if err != nil {
+		return repoError("get file", err)
+	}
+	if err := checkDownloadable(file); err != nil {
+		return err
+	}
+	if req.Offset > file.Size {
+		return status.Errorf(codes.OutOfRange, "offset %d is past the end of the file (%d bytes)", req.Offset, file.Size)
+	}
+
+	length := req.Length
+	if length == 0 {
+		length = -1
+	}
+	body, _, err := s.blobs.GetRange(ctx, file.StorageKey, req.Offset, length)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			return status.Error(codes.NotFound, "file content not found")
+		case errors.Is(err, storage.ErrInvalidRange):
+			return status.Error(codes.OutOfRange, err.Error())
+		}
+		return status.Errorf(codes.Internal, "failed to read file content: %v", err)
+	}
+	defer body.Close()
+
+	if err := stream.Send(&pb.DownloadFileResponse{Data: &pb.DownloadFileResponse_File{File: file}}); err != nil {
+		return err
+	}
+
+	verify := req.Offset == 0 && req.Length == 0 && file.Checksum != ""
+	hash := sha256.New()
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := io.ReadFull(body, buf)
+		if n > 0 {
+			hash.Write(buf[:n])
+			if sendErr := stream.Send(&pb.DownloadFileResponse{Data: &pb.DownloadFileResponse_Chunk{Chunk: buf[:n]}}); sendErr != nil {
+				return sendErr
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read file content: %v", err)
+		}
+	}
+
+	if verify && hex.EncodeToString(hash.Sum(nil)) != file.Checksum {
+		return status.Error(codes.DataLoss, "stored content does not match the file checksum")
+	}
+
+	return nil
+}
+
+func validateUploadHeader(header *pb.UploadFileHeader) error {
+	if err := validateName("name", header.Name); err != nil {
+		return err
+	}
+	if err := validateID("user_id", header.UserId); err != nil {
+		return err
+	}
+	if header.FolderId != "" {
+		if err := validateID("folder_id", header.FolderId); err != nil {
+			return err
+		}
+	}
+	if header.Size < 0 {
+		return status.Error(codes.InvalidArgument, "size must not be negative")
+	}
+	if header.Size > domain.MaxUploadSize {
+		return status.Errorf(codes.InvalidArgument, "size exceeds the maximum upload size of %d bytes", int64(domain.MaxUploadSize))
+	}
+	return validateChecksum(header.Checksum)
+}
+
+// discardFile removes the record and any stored content of a file whose upload failed.
+// It runs detached from the request so a cancelled stream still cleans up.
+func (s *FileService) discardFile(file *pb.File) {
+	ctx := context.Background()
+	if err := s.blobs.Delete(ctx, file.StorageKey); err != nil {
+		log.Printf("Failed to delete content of file %s: %v", file.Id, err)
+	}
+	keys, err := s.repo.Discard(ctx, file.Id, file.UserId)
+	if err != nil {
+		log.Printf("Failed to remove file %s after failed upload: %v", file.Id, err)
+	}
+	s.deleteContent(ctx, keys)
+}
+
+// chunkReader reads the content chunks of an UploadFile stream
+type chunkReader struct {
+	stream   pb.FileService_UploadFileServer
+	buf      []byte
+	received int64
+	// err holds a protocol violation by the client, reported instead of the storage error
+	err error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if msg.GetHeader() != nil {
+			r.err = status.Error(codes.InvalidArgument, "upload header may only be sent once")
+			return 0, r.err
+		}
+		r.buf = msg.GetChunk()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	r.received += int64(n)
+	return n, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// fakeUploadStream replays requests to UploadFile and records its response
+type fakeUploadStream struct {
+	grpc.ServerStream
+	requests []*pb.UploadFileRequest
+	response *pb.UploadFileResponse
+}
+
+func (s *fakeUploadStream) Context() context.Context { return context.Background() }
+
+func (s *fakeUploadStream) Recv() (*pb.UploadFileRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *fakeUploadStream) SendAndClose(resp *pb.UploadFileResponse) error {
+	s.response = resp
+	return nil
+}
+
+// fakeDownloadStream collects the messages sent by DownloadFile
+type fakeDownloadStream struct {
+	grpc.ServerStream
+	file    *pb.File
+	content bytes.Buffer
+}
+
+func (s *fakeDownloadStream) Context() context.Context { return context.Background() }
+
+func (s *fakeDownloadStream) Send(resp *pb.DownloadFileResponse) error {
+	if file := resp.GetFile(); file != nil {
+		s.file = file
+		return nil
+	}
+	s.content.Write(resp.GetChunk())
+	return nil
+}
+
+func uploadRequests(header *pb.UploadFileHeader, chunks ...string) []*pb.UploadFileRequest {
+	requests := []*pb.UploadFileRequest{{Data: &pb.UploadFileRequest_Header{Header: header}}}
+	for _, chunk := range chunks {
+		requests = append(requests, &pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: []byte(chunk)}})
+	}
+	return requests
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestFileService_UploadFile(t *testing.T) {
+	const content = "hello, streamed world"
+	created := &pb.File{Id: testFileID, UserId: testUserID, Name: "hello.txt", StorageKey: testUserID + "/" + testFileID}
+
+	tests := []struct {
+		name          string
+		requests      []*pb.UploadFileRequest
+		mockSetup     func(*MockFileRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name: "successful upload with checksum",
+			requests: uploadRequests(&pb.UploadFileHeader{
+				Name: "hello.txt", UserId: testUserID, Size: int64(len(content)), Checksum: sha256Hex(content),
+			}, "hello, ", "streamed world"),
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(req *pb.CreateFileRequest) bool {
+					return req.Name == "hello.txt" && req.UserId == testUserID
+				}), mock.Anything).Return(created, nil)
+				repo.On("UpdateContent", mock.Anything, testFileID, testUserID, repository.BlobKey(sha256Hex(content)), int64(len(content)), sha256Hex(content), mock.Anything, mock.Anything).
+					Return(&pb.File{Id: testFileID, Size: int64(len(content)), Checksum: sha256Hex(content)}, nil)
+			},
+		},
+		{
+			name:          "missing header",
+			requests:      []*pb.UploadFileRequest{{Data: &pb.UploadFileRequest_Chunk{Chunk: []byte(content)}}},
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "invalid user id",
+			requests:      uploadRequests(&pb.UploadFileHeader{Name: "hello.txt", UserId: "invalid"}, content),
+			mockSetup:     func(repo *MockFileRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "checksum mismatch",
+			requests: uploadRequests(&pb.UploadFileHeader{
+				Name: "hello.txt", UserId: testUserID, Checksum: sha256Hex("something else"),
+			}, content),
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(created, nil)
+				repo.On("Discard", mock.Anything, testFileID, testUserID).Return([]string{}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.DataLoss,
+		},
+		{
+			name: "size mismatch",
+			requests: uploadRequests(&pb.UploadFileHeader{
+				Name: "hello.txt", UserId: testUserID, Size: 100,
+			}, content),
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(created, nil)
+				repo.On("Discard", mock.Anything, testFileID, testUserID).Return([]string{}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name: "repeated header",
+			requests: append(uploadRequests(&pb.UploadFileHeader{Name: "hello.txt", UserId: testUserID}, content),
+				&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Header{Header: &pb.UploadFileHeader{}}}),
+			mockSetup: func(repo *MockFileRepository) {
+				repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(created, nil)
+				repo.On("Discard", mock.Anything, testFileID, testUserID).Return([]string{}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockFileRepository)
+			tt.mockSetup(repo)
+			svc := newTestService(t, repo)
+			stream := &fakeUploadStream{requests: tt.requests}
+
+			err := svc.UploadFile(stream)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+				_, statErr := svc.blobs.Stat(context.Background(), created.StorageKey)
+				assert.Error(t, statErr, "content of a failed upload must be removed")
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, stream.response)
+				assert.Equal(t, sha256Hex(content), stream.response.File.Checksum)
+
+				stored, _, err := svc.blobs.Get(context.Background(), repository.BlobKey(sha256Hex(content)))
+				require.NoError(t, err)
+				defer stored.Close()
+				data, err := io.ReadAll(stored)
+				require.NoError(t, err)
+				assert.Equal(t, content, string(data))
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_DownloadFile(t *testing.T) {
+	const content = "0123456789abcdefghij"
+	storageKey := testUserID + "/" + testFileID
+
+	tests := []struct {
+		name          string
+		request       *pb.DownloadFileRequest
+		checksum      string
+		quarantined   bool
+		scanStatus    string
+		expected      string
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:     "full download",
+			request:  &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID},
+			checksum: sha256Hex(content),
+			expected: content,
+		},
+		{
+			name:     "ranged download",
+			request:  &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID, Offset: 5, Length: 5},
+			checksum: sha256Hex(content),
+			expected: "56789",
+		},
+		{
+			name:     "offset to end",
+			request:  &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID, Offset: 15},
+			checksum: sha256Hex(content),
+			expected: "fghij",
+		},
+		{
+			name:          "checksum mismatch",
+			request:       &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID},
+			checksum:      sha256Hex("tampered"),
+			expected:      content,
+			expectedError: true,
+			errorCode:     codes.DataLoss,
+		},
+		{
+			name:          "offset past end",
+			request:       &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID, Offset: 21},
+			checksum:      sha256Hex(content),
+			expectedError: true,
+			errorCode:     codes.OutOfRange,
+		},
+		{
+			name:          "negative length",
+			request:       &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID, Length: -1},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "quarantined file",
+			request:       &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID},
+			checksum:      sha256Hex(content),
+			quarantined:   true,
+			expectedError: true,
+			errorCode:     codes.PermissionDenied,
+		},
+		{
+			name:          "not scanned yet",
+			request:       &pb.DownloadFileRequest{Id: testFileID, UserId: testUserID},
+			checksum:      sha256Hex(content),
+			scanStatus:    domain.ScanPending,
+			expectedError: true,
+			errorCode:     codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.scanStatus == "" {
+				tt.scanStatus = domain.ScanClean
+			}
+			repo := new(MockFileRepository)
+			repo.On("GetByID", mock.Anything, testFileID, testUserID).Return(&pb.File{
+				Id: testFileID, UserId: testUserID, Size: int64(len(content)), StorageKey: storageKey, Checksum: tt.checksum,
+				Quarantined: tt.quarantined, ScanStatus: tt.scanStatus,
+			}, nil).Maybe()
+			svc := newTestService(t, repo)
+			_, err := svc.blobs.Put(context.Background(), storageKey, bytes.NewReader([]byte(content)), int64(len(content)))
+			require.NoError(t, err)
+			stream := &fakeDownloadStream{}
+
+			err = svc.DownloadFile(tt.request, stream)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, stream.file)
+				assert.Equal(t, testFileID, stream.file.Id)
+			}
+			assert.Equal(t, tt.expected, stream.content.String())
+		})
+	}
+}
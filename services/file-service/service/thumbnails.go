@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/storage"
+	"go-drive/internal/thumbnail"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// thumbnailSizes are the boxes thumbnails are rendered for, smallest first
+var thumbnailSizes = []int{128, 256, 512}
+
+// defaultThumbnailSize is served when a request does not ask for a size
+const defaultThumbnailSize = 256
+
+// GetThumbnail returns a thumbnail of an image file. Until its thumbnails are rendered,
+// or when they cannot be, only their status is returned.
+func (s *FileService) GetThumbnail(ctx context.Context, req *pb.GetThumbnailRequest) (*pb.GetThumbnailResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Size < 0 {
+		return nil, status.Error(codes.InvalidArgument, "size must not be negative")
+	}
+
+	owner, err := s.authorizeFile(ctx, req.Id, req.UserId, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.Id, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+
+	thumbnails, err := s.thumbnails.Get(ctx, req.Id)
+	if errors.Is(err, repository.ErrThumbnailsNotFound) {
+		return nil, status.Error(codes.NotFound, "file has no thumbnails")
+	}
+	if err != nil {
+		return nil, repoError("get thumbnails", err)
+	}
+
+	size := thumbnailSize(int(req.Size))
+	resp := &pb.GetThumbnailResponse{
+		Status:      thumbnails.Status,
+		Size:        int32(size),
+		ContentType: thumbnail.ContentType,
+		Etag:        fmt.Sprintf("%s-%d", file.Checksum, size),
+	}
+	// The row may briefly describe the previous content while new content is committed
+	if thumbnails.Checksum != file.Checksum {
+		resp.Status = domain.ThumbnailPending
+	}
+	if resp.Status != domain.ThumbnailReady {
+		return resp, nil
+	}
+
+	body, _, err := s.blobs.Get(ctx, repository.ThumbnailKey(file.StorageKey, size))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "thumbnail not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to read thumbnail: %v", err)
+	}
+	defer body.Close()
+
+	if resp.Content, err = io.ReadAll(body); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read thumbnail: %v", err)
+	}
+	return resp, nil
+}
+
+// thumbnailSize picks the smallest rendered size that covers the requested one
+func thumbnailSize(requested int) int {
+	if requested == 0 {
+		return defaultThumbnailSize
+	}
+	for _, size := range thumbnailSizes {
+		if size >= requested {
+			return size
+		}
+	}
+	return thumbnailSizes[len(thumbnailSizes)-1]
+}
+
+// GenerateThumbnails renders the thumbnails of the images waiting for them. It returns
+// the number of images processed, whatever their outcome.
+func (s *FileService) GenerateThumbnails(ctx context.Context) (int, error) {
+	return s.processContentJobs(ctx, s.thumbnails.Claim, s.renderThumbnails)
+}
+
+// renderThumbnails renders and stores the thumbnails of a claimed image and records the
+// outcome. Failed attempts are retried with a growing delay. Only failing to record the
+// outcome is an error.
+func (s *FileService) renderThumbnails(ctx context.Context, job repository.ContentJob) error {
+	if !thumbnail.Supported(job.MimeType) {
+		return s.thumbnails.Skip(ctx, job, thumbnail.ErrUnsupported.Error())
+	}
+
+	err := s.storeThumbnails(ctx, job)
+	switch {
+	case err == nil:
+		return s.thumbnails.Ready(ctx, job, s.now())
+	case errors.Is(err, thumbnail.ErrUnsupported), errors.Is(err, thumbnail.ErrTooLarge):
+		return s.thumbnails.Skip(ctx, job, err.Error())
+	case ctx.Err() != nil:
+		// Left to the lease, so the image is retried once the service is back
+		return ctx.Err()
+	}
+
+	log.Printf("Failed to render thumbnails of file %s (attempt %d of %d): %v", job.FileID, job.Attempts, maxContentJobAttempts, err)
+	return s.thumbnails.Fail(ctx, job, err.Error(), s.retryAt(job))
+}
+
+// storeThumbnails reads an image and stores its thumbnails next to it
+func (s *FileService) storeThumbnails(ctx context.Context, job repository.ContentJob) error {
+	if job.Size > thumbnail.MaxSourceSize {
+		return thumbnail.ErrTooLarge
+	}
+	body, _, err := s.blobs.Get(ctx, job.StorageKey)
+	if err != nil {
+		return fmt.Errorf("failed to read content: %w", err)
+	}
+	defer body.Close()
+
+	rendered, err := thumbnail.Render(body, thumbnailSizes)
+	if err != nil {
+		return err
+	}
+	for _, size := range thumbnailSizes {
+		data := rendered[size]
+		if _, err := s.blobs.Put(ctx, repository.ThumbnailKey(job.StorageKey, size), bytes.NewReader(data), int64(len(data))); err != nil {
+			return fmt.Errorf("failed to store thumbnail: %w", err)
+		}
+	}
+	return nil
+}
+
+// thumbnailKeys returns the storage keys of every thumbnail of the content at storageKey
+func thumbnailKeys(storageKey string) []string {
+	keys := make([]string, len(thumbnailSizes))
+	for i, size := range thumbnailSizes {
+		keys[i] = repository.ThumbnailKey(storageKey, size)
+	}
+	return keys
+}
+
+// RunThumbnailer renders waiting thumbnails every interval, and whenever new content is
+// committed, until ctx is cancelled
+func (s *FileService) RunThumbnailer(ctx context.Context, interval time.Duration) {
+	runContentWorker(ctx, interval, s.renderQueued, func() {
+		rendered, err := s.GenerateThumbnails(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to render thumbnails: %v", err)
+		} else if rendered > 0 {
+			log.Printf("Processed thumbnails of %d images", rendered)
+		}
+	})
+}
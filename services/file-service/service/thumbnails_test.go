@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/thumbnail"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockThumbnailRepository is a mock implementation of ThumbnailRepository
+type MockThumbnailRepository struct {
+	mock.Mock
+}
+
+func (m *MockThumbnailRepository) Get(ctx context.Context, fileID string) (*domain.FileThumbnails, error) {
+	args := m.Called(ctx, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileThumbnails), args.Error(1)
+}
+
+func (m *MockThumbnailRepository) Claim(ctx context.Context, now, retryAt time.Time, limit int) ([]repository.ContentJob, error) {
+	args := m.Called(ctx, now, retryAt, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ContentJob), args.Error(1)
+}
+
+func (m *MockThumbnailRepository) Ready(ctx context.Context, job repository.ContentJob, generatedAt time.Time) error {
+	return m.Called(ctx, job, generatedAt).Error(0)
+}
+
+func (m *MockThumbnailRepository) Skip(ctx context.Context, job repository.ContentJob, reason string) error {
+	return m.Called(ctx, job, reason).Error(0)
+}
+
+func (m *MockThumbnailRepository) Fail(ctx context.Context, job repository.ContentJob, reason string, retryAt *time.Time) error {
+	return m.Called(ctx, job, reason, retryAt).Error(0)
+}
+
+func TestFileService_GenerateThumbnails(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := func(mimeType, key string, attempts int32) repository.ContentJob {
+		return repository.ContentJob{
+			FileID: uuid.MustParse(testFileID), Checksum: strings.Repeat("ab", 32),
+			Name: "photo", MimeType: mimeType, StorageKey: key, Attempts: attempts,
+		}
+	}
+	photo := job("image/png", "image", 1)
+	missing := job("image/png", "missing", 2)
+
+	tests := []struct {
+		name      string
+		jobs      []repository.ContentJob
+		mockSetup func(*MockThumbnailRepository)
+		stored    bool
+	}{
+		{
+			name: "thumbnails stored",
+			jobs: []repository.ContentJob{photo},
+			mockSetup: func(thumbnails *MockThumbnailRepository) {
+				thumbnails.On("Ready", mock.Anything, photo, now).Return(nil)
+			},
+			stored: true,
+		},
+		{
+			name: "unsupported format skipped",
+			jobs: []repository.ContentJob{job("image/svg+xml", "image", 1)},
+			mockSetup: func(thumbnails *MockThumbnailRepository) {
+				thumbnails.On("Skip", mock.Anything, mock.Anything, thumbnail.ErrUnsupported.Error()).Return(nil)
+			},
+		},
+		{
+			name: "undecodable content skipped",
+			jobs: []repository.ContentJob{job("image/jpeg", "text", 1)},
+			mockSetup: func(thumbnails *MockThumbnailRepository) {
+				thumbnails.On("Skip", mock.Anything, mock.Anything, thumbnail.ErrUnsupported.Error()).Return(nil)
+			},
+		},
+		{
+			name: "failure retried later",
+			jobs: []repository.ContentJob{missing},
+			mockSetup: func(thumbnails *MockThumbnailRepository) {
+				retryAt := now.Add(2 * contentJobRetryDelay)
+				thumbnails.On("Fail", mock.Anything, missing, mock.Anything, &retryAt).Return(nil)
+			},
+		},
+		{
+			name: "last attempt gives up",
+			jobs: []repository.ContentJob{job("image/png", "missing", maxContentJobAttempts)},
+			mockSetup: func(thumbnails *MockThumbnailRepository) {
+				thumbnails.On("Fail", mock.Anything, mock.Anything, mock.Anything, (*time.Time)(nil)).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := newTestService(t, new(MockFileRepository))
+			service.now = func() time.Time { return now }
+			var buf bytes.Buffer
+			require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 600, 300))))
+			_, err := service.blobs.Put(ctx, "image", &buf, -1)
+			require.NoError(t, err)
+			_, err = service.blobs.Put(ctx, "text", strings.NewReader("not a picture"), -1)
+			require.NoError(t, err)
+
+			mockThumbnails := new(MockThumbnailRepository)
+			service.thumbnails = mockThumbnails
+			mockThumbnails.On("Claim", mock.Anything, now, now.Add(contentJobLease), contentJobBatchSize).Return(tt.jobs, nil)
+			tt.mockSetup(mockThumbnails)
+
+			processed, err := service.GenerateThumbnails(ctx)
+
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.jobs), processed)
+			for _, key := range thumbnailKeys("image") {
+				_, err := service.blobs.Stat(ctx, key)
+				assert.Equal(t, tt.stored, err == nil, key)
+			}
+			mockThumbnails.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_GetThumbnail(t *testing.T) {
+	checksum := strings.Repeat("ab", 32)
+	file := &pb.File{Id: testFileID, Name: "photo.png", UserId: testUserID, Checksum: checksum, StorageKey: repository.BlobKey(checksum)}
+
+	tests := []struct {
+		name           string
+		request        *pb.GetThumbnailRequest
+		thumbnails     *domain.FileThumbnails
+		expectedStatus string
+		expectedSize   int32
+		expectedBody   string
+		errorCode      codes.Code
+	}{
+		{
+			name:           "default size",
+			request:        &pb.GetThumbnailRequest{Id: testFileID, UserId: testUserID},
+			thumbnails:     &domain.FileThumbnails{Checksum: checksum, Status: domain.ThumbnailReady},
+			expectedStatus: domain.ThumbnailReady,
+			expectedSize:   256,
+			expectedBody:   "thumb-256",
+		},
+		{
+			name:           "smallest covering size",
+			request:        &pb.GetThumbnailRequest{Id: testFileID, UserId: testUserID, Size: 129},
+			thumbnails:     &domain.FileThumbnails{Checksum: checksum, Status: domain.ThumbnailReady},
+			expectedStatus: domain.ThumbnailReady,
+			expectedSize:   256,
+			expectedBody:   "thumb-256",
+		},
+		{
+			name:           "capped at the largest size",
+			request:        &pb.GetThumbnailRequest{Id: testFileID, UserId: testUserID, Size: 4000},
+			thumbnails:     &domain.FileThumbnails{Checksum: checksum, Status: domain.ThumbnailReady},
+			expectedStatus: domain.ThumbnailReady,
+			expectedSize:   512,
+			expectedBody:   "thumb-512",
+		},
+		{
+			name:           "still rendering",
+			request:        &pb.GetThumbnailRequest{Id: testFileID, UserId: testUserID},
+			thumbnails:     &domain.FileThumbnails{Checksum: checksum, Status: domain.ThumbnailPending},
+			expectedStatus: domain.ThumbnailPending,
+			expectedSize:   256,
+		},
+		{
+			name:           "previous content",
+			request:        &pb.GetThumbnailRequest{Id: testFileID, UserId: testUserID},
+			thumbnails:     &domain.FileThumbnails{Checksum: strings.Repeat("cd", 32), Status: domain.ThumbnailReady},
+			expectedStatus: domain.ThumbnailPending,
+			expectedSize:   256,
+		},
+		{
+			name:      "not an image",
+			request:   &pb.GetThumbnailRequest{Id: testFileID, UserId: testUserID},
+			errorCode: codes.NotFound,
+		},
+		{
+			name:      "negative size",
+			request:   &pb.GetThumbnailRequest{Id: testFileID, UserId: testUserID, Size: -1},
+			errorCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockFileRepository)
+			mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).Return(file, nil).Maybe()
+			service := newTestService(t, mockRepo)
+			for _, size := range thumbnailSizes {
+				data := fmt.Sprintf("thumb-%d", size)
+				_, err := service.blobs.Put(ctx, repository.ThumbnailKey(file.StorageKey, size), strings.NewReader(data), -1)
+				require.NoError(t, err)
+			}
+			if tt.thumbnails != nil {
+				mockThumbnails := new(MockThumbnailRepository)
+				mockThumbnails.On("Get", mock.Anything, testFileID).Return(tt.thumbnails, nil)
+				service.thumbnails = mockThumbnails
+			}
+
+			resp, err := service.GetThumbnail(ctx, tt.request)
+
+			if tt.errorCode != codes.OK {
+				assertStatusCode(t, err, tt.errorCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.Status)
+			assert.Equal(t, tt.expectedSize, resp.Size)
+			assert.Equal(t, tt.expectedBody, string(resp.Content))
+			assert.Equal(t, thumbnail.ContentType, resp.ContentType)
+			assert.Equal(t, fmt.Sprintf("%s-%d", checksum, tt.expectedSize), resp.Etag)
+		})
+	}
+}
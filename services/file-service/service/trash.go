@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "go-drive/proto/file"
+)
+
+const (
+	// defaultTrashRetention is how long deleted items are kept when no retention is configured
+	defaultTrashRetention = 30 * 24 * time.Hour
+	// purgeBatchSize caps the files hard deleted per purge transaction
+	purgeBatchSize = 500
+)
+
+func (s *FileService) ListTrash(ctx context.Context, req *pb.ListTrashRequest) (*pb.ListTrashResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	items, totalCount, err := s.trash.List(ctx, req.UserId, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list trash", err)
+	}
+	for _, item := range items {
+		item.PurgeAt = timestamppb.New(item.DeletedAt.AsTime().Add(s.trashRetention))
+	}
+
+	return &pb.ListTrashResponse{
+		Items:      items,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// Restore takes a file or folder out of the trash. Deleted folders above it are restored
+// as well so that it is reachable again; a folder brings back the contents deleted with it.
+func (s *FileService) Restore(ctx context.Context, req *pb.RestoreRequest) (*pb.RestoreResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if (req.FileId == "") == (req.FolderId == "") {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of file_id and folder_id is required")
+	}
+
+	var folders, files int32
+	var err error
+	if req.FileId != "" {
+		if err := validateID("file_id", req.FileId); err != nil {
+			return nil, err
+		}
+		folders, files, err = s.trash.RestoreFile(ctx, req.FileId, req.UserId)
+	} else {
+		if err := validateID("folder_id", req.FolderId); err != nil {
+			return nil, err
+		}
+		folders, files, err = s.trash.RestoreFolder(ctx, req.FolderId, req.UserId)
+	}
+	if err != nil {
+		return nil, repoError("restore", err)
+	}
+
+	return &pb.RestoreResponse{
+		Message:         fmt.Sprintf("Restored %d folders and %d files", folders, files),
+		RestoredFolders: folders,
+		RestoredFiles:   files,
+	}, nil
+}
+
+// EmptyTrash permanently deletes everything in the user's trash, including file content
+func (s *FileService) EmptyTrash(ctx context.Context, req *pb.EmptyTrashRequest) (*pb.EmptyTrashResponse, error) {
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	result, err := s.trash.Empty(ctx, req.UserId)
+	if err != nil {
+		return nil, repoError("empty trash", err)
+	}
+	s.deleteContent(ctx, result.StorageKeys)
+	s.collectBlobs(ctx)
+
+	return &pb.EmptyTrashResponse{
+		Message:       fmt.Sprintf("Permanently deleted %d folders and %d files", result.Folders, result.Files),
+		PurgedFolders: result.Folders,
+		PurgedFiles:   result.Files,
+	}, nil
+}
+
+// PurgeTrash permanently deletes every item that has been in the trash longer than the
+// retention period. It returns the number of folders and files removed.
+func (s *FileService) PurgeTrash(ctx context.Context) (int32, int32, error) {
+	before := s.now().Add(-s.trashRetention)
+
+	var folders, files int32
+	for {
+		result, err := s.trash.Purge(ctx, before, purgeBatchSize)
+		if err != nil {
+			return folders, files, err
+		}
+		s.deleteContent(ctx, result.StorageKeys)
+		folders += result.Folders
+		files += result.Files
+
+		if result.Files < purgeBatchSize {
+			return folders, files, nil
+		}
+	}
+}
+
+// RunTrashPurger purges expired trash and uploads and collects the content nothing uses
+// any more every interval until ctx is cancelled
+func (s *FileService) RunTrashPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		folders, files, err := s.PurgeTrash(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to purge trash: %v", err)
+		} else if folders > 0 || files > 0 {
+			log.Printf("Purged %d folders and %d files from trash", folders, files)
+		}
+
+		uploads, err := s.PurgeUploads(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to purge expired uploads: %v", err)
+		} else if uploads > 0 {
+			log.Printf("Purged %d expired uploads", uploads)
+		}
+
+		collected, err := s.CollectBlobs(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to collect unreferenced blobs: %v", err)
+		} else if collected > 0 {
+			log.Printf("Collected %d unreferenced blobs", collected)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+// MockTrashRepository is a mock implementation of TrashRepository
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) List(ctx context.Context, userID string, page, pageSize int32) ([]*pb.TrashItem, int32, error) {
+	args := m.Called(ctx, userID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.TrashItem), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockTrashRepository) RestoreFile(ctx context.Context, id, userID string) (int32, int32, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Get(0).(int32), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockTrashRepository) RestoreFolder(ctx context.Context, id, userID string) (int32, int32, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Get(0).(int32), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockTrashRepository) Empty(ctx context.Context, userID string) (*repository.PurgeResult, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.PurgeResult), args.Error(1)
+}
+
+func (m *MockTrashRepository) Purge(ctx context.Context, deletedBefore time.Time, limit int) (*repository.PurgeResult, error) {
+	args := m.Called(ctx, deletedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.PurgeResult), args.Error(1)
+}
+
+func TestFileService_ListTrash(t *testing.T) {
+	deletedAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	mockTrash := new(MockTrashRepository)
+	mockTrash.On("List", mock.Anything, testUserID, int32(1), int32(20)).Return([]*pb.TrashItem{
+		{File: &pb.File{Id: testFileID, Name: "notes.txt"}, DeletedAt: timestamppb.New(deletedAt)},
+	}, int32(1), nil)
+
+	service := newTestService(t, new(MockFileRepository))
+	service.trash = mockTrash
+	service.trashRetention = 7 * 24 * time.Hour
+	resp, err := service.ListTrash(context.Background(), &pb.ListTrashRequest{UserId: testUserID})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, deletedAt.Add(7*24*time.Hour), resp.Items[0].PurgeAt.AsTime())
+	assert.Equal(t, int32(1), resp.TotalCount)
+	mockTrash.AssertExpectations(t)
+}
+
+func TestFileService_Restore(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.RestoreRequest
+		mockSetup     func(*MockTrashRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "file with deleted parents",
+			request: &pb.RestoreRequest{UserId: testUserID, FileId: testFileID},
+			mockSetup: func(repo *MockTrashRepository) {
+				repo.On("RestoreFile", mock.Anything, testFileID, testUserID).Return(int32(2), int32(1), nil)
+			},
+		},
+		{
+			name:    "folder",
+			request: &pb.RestoreRequest{UserId: testUserID, FolderId: testFolderID},
+			mockSetup: func(repo *MockTrashRepository) {
+				repo.On("RestoreFolder", mock.Anything, testFolderID, testUserID).Return(int32(2), int32(1), nil)
+			},
+		},
+		{
+			name:          "both ids",
+			request:       &pb.RestoreRequest{UserId: testUserID, FileId: testFileID, FolderId: testFolderID},
+			mockSetup:     func(repo *MockTrashRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "no id",
+			request:       &pb.RestoreRequest{UserId: testUserID},
+			mockSetup:     func(repo *MockTrashRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "not in trash",
+			request: &pb.RestoreRequest{UserId: testUserID, FileId: testFileID},
+			mockSetup: func(repo *MockTrashRepository) {
+				repo.On("RestoreFile", mock.Anything, testFileID, testUserID).Return(int32(0), int32(0), repository.ErrNotInTrash)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+		{
+			name:    "name taken",
+			request: &pb.RestoreRequest{UserId: testUserID, FolderId: testFolderID},
+			mockSetup: func(repo *MockTrashRepository) {
+				repo.On("RestoreFolder", mock.Anything, testFolderID, testUserID).Return(int32(0), int32(0), repository.ErrNameConflict)
+			},
+			expectedError: true,
+			errorCode:     codes.AlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTrash := new(MockTrashRepository)
+			tt.mockSetup(mockTrash)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.trash = mockTrash
+			resp, err := service.Restore(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int32(2), resp.RestoredFolders)
+				assert.Equal(t, int32(1), resp.RestoredFiles)
+			}
+
+			mockTrash.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_EmptyTrash(t *testing.T) {
+	service := newTestService(t, new(MockFileRepository))
+	key := testUserID + "/" + testFileID
+	_, err := service.blobs.Put(context.Background(), key, strings.NewReader("gone"), 4)
+	require.NoError(t, err)
+
+	mockTrash := new(MockTrashRepository)
+	mockTrash.On("Empty", mock.Anything, testUserID).Return(&repository.PurgeResult{
+		Folders:     1,
+		Files:       1,
+		StorageKeys: []string{key},
+	}, nil)
+	service.trash = mockTrash
+
+	resp, err := service.EmptyTrash(context.Background(), &pb.EmptyTrashRequest{UserId: testUserID})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), resp.PurgedFolders)
+	assert.Equal(t, int32(1), resp.PurgedFiles)
+	_, err = service.blobs.Stat(context.Background(), key)
+	assert.Error(t, err, "content should be deleted with the file")
+	mockTrash.AssertExpectations(t)
+}
+
+func TestFileService_PurgeTrash(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	fullBatch := make([]string, purgeBatchSize)
+	for i := range fullBatch {
+		fullBatch[i] = "missing"
+	}
+
+	mockTrash := new(MockTrashRepository)
+	mockTrash.On("Purge", mock.Anything, now.Add(-defaultTrashRetention), purgeBatchSize).
+		Return(&repository.PurgeResult{Files: purgeBatchSize, StorageKeys: fullBatch}, nil).Once()
+	mockTrash.On("Purge", mock.Anything, now.Add(-defaultTrashRetention), purgeBatchSize).
+		Return(&repository.PurgeResult{Folders: 4, Files: 1, StorageKeys: []string{"last"}}, nil).Once()
+
+	service := newTestService(t, new(MockFileRepository))
+	service.trash = mockTrash
+	service.now = func() time.Time { return now }
+
+	folders, files, err := service.PurgeTrash(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), folders)
+	assert.Equal(t, int32(purgeBatchSize+1), files)
+	mockTrash.AssertExpectations(t)
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/storage"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// uploadExpiry is how long an upload is kept after the last chunk arrived
+const uploadExpiry = 24 * time.Hour
+
+func (s *FileService) CreateUpload(ctx context.Context, req *pb.CreateUploadRequest) (*pb.CreateUploadResponse, error) {
+	if err := validateName("name", req.Name); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.FolderId != "" {
+		if err := validateID("folder_id", req.FolderId); err != nil {
+			return nil, err
+		}
+	}
+	if req.Size < 0 {
+		return nil, status.Error(codes.InvalidArgument, "size must not be negative")
+	}
+	if err := s.checkFileType(req.Name, req.MimeType); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeParent(ctx, req.FolderId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Uploads are checked against the quota up front so that a user does not send
+	// gigabytes only to have them refused when the upload is finished
+	usage, err := s.usage.Get(ctx, owner)
+	if err != nil {
+		return nil, repoError("get usage", err)
+	}
+	quota := s.quotaFor(usage.UserType)
+	if err := checkFileSize(quota, req.Size); err != nil {
+		return nil, err
+	}
+	if quota.Storage > 0 {
+		// Other uploads still running claim their declared size so that several of them
+		// cannot each fit the quota on their own and overrun it together
+		reserved, err := s.uploads.Reserved(ctx, owner, s.now().Add(-uploadExpiry))
+		if err != nil {
+			return nil, repoError("sum open uploads", err)
+		}
+		if usage.UsedBytes+reserved+req.Size > quota.Storage {
+			return nil, status.Errorf(codes.ResourceExhausted, "upload exceeds the storage quota: %d of %d bytes used, %d reserved by open uploads", usage.UsedBytes, quota.Storage, reserved)
+		}
+	}
+
+	upload, err := s.uploads.Create(ctx, req, owner)
+	if err != nil {
+		return nil, repoError("create upload", err)
+	}
+
+	return &pb.CreateUploadResponse{Upload: withExpiry(upload)}, nil
+}
+
+func (s *FileService) GetUpload(ctx context.Context, req *pb.GetUploadRequest) (*pb.GetUploadResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	upload, err := s.openUpload(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetUploadResponse{Upload: upload}, nil
+}
+
+func (s *FileService) CommitUploadChunk(ctx context.Context, req *pb.CommitUploadChunkRequest) (*pb.CommitUploadChunkResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Offset < 0 || req.Size <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative and size must be positive")
+	}
+	if !strings.HasPrefix(req.StorageKey, repository.UploadPartPrefix(req.Id)) {
+		return nil, status.Error(codes.InvalidArgument, "storage_key does not belong to this upload")
+	}
+
+	upload, err := s.uploads.CommitPart(ctx, req.Id, req.UserId, req.Offset, req.Size, req.StorageKey)
+	if err != nil {
+		return nil, repoError("commit upload chunk", err)
+	}
+
+	return &pb.CommitUploadChunkResponse{Upload: withExpiry(upload)}, nil
+}
+
+// FinishUpload concatenates the chunks of a complete upload into a new file
+// and discards the upload state
+func (s *FileService) FinishUpload(ctx context.Context, req *pb.FinishUploadRequest) (*pb.FinishUploadResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	upload, err := s.openUpload(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Offset != upload.Size {
+		return nil, status.Errorf(codes.FailedPrecondition, "upload is incomplete: %d of %d bytes received", upload.Offset, upload.Size)
+	}
+
+	parts, err := s.uploads.Parts(ctx, req.Id)
+	if err != nil {
+		return nil, repoError("list upload parts", err)
+	}
+	// Editor access to a shared folder may have been revoked while the upload was running
+	owner, err := s.authorizeParent(ctx, upload.FolderId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := s.userQuota(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.repo.Create(ctx, &pb.CreateFileRequest{
+		Name:     upload.Name,
+		UserId:   owner,
+		FolderId: upload.FolderId,
+		MimeType: upload.MimeType,
+	}, quota.Storage)
+	if err != nil {
+		return nil, repoError("create file", err)
+	}
+
+	hash := sha256.New()
+	content := &partReader{ctx: ctx, blobs: s.blobs, parts: parts}
+	_, err = s.blobs.Put(ctx, file.StorageKey, io.TeeReader(content, hash), upload.Size)
+	content.Close()
+
+	var completed *pb.File
+	if err == nil {
+		completed, err = s.commitContent(ctx, file, file.StorageKey, upload.Size, hex.EncodeToString(hash.Sum(nil)), quota.Storage)
+	}
+	if err != nil {
+		s.discardFile(file)
+		if errors.Is(err, repository.ErrQuotaExceeded) || errors.Is(err, errFileTypeBlocked) {
+			return nil, repoError("assemble upload", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to assemble upload: %v", err)
+	}
+
+	s.discardUpload(ctx, req.Id, req.UserId)
+
+	return &pb.FinishUploadResponse{File: completed}, nil
+}
+
+func (s *FileService) DeleteUpload(ctx context.Context, req *pb.DeleteUploadRequest) (*pb.DeleteUploadResponse, error) {
+	if err := validateID("id", req.Id); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.uploads.Delete(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, repoError("delete upload", err)
+	}
+	s.deleteBlobs(ctx, keys)
+
+	return &pb.DeleteUploadResponse{
+		Message: "Upload deleted successfully",
+	}, nil
+}
+
+// PurgeUploads discards the uploads that have not received a chunk within uploadExpiry,
+// including chunks stored under them that were never committed. It returns the number
+// of uploads removed.
+func (s *FileService) PurgeUploads(ctx context.Context) (int, error) {
+	activeSince := s.now().Add(-uploadExpiry)
+
+	var purged int
+	for {
+		ids, err := s.uploads.Expire(ctx, activeSince, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			parts, err := s.blobs.List(ctx, repository.UploadPartPrefix(id))
+			if err != nil {
+				log.Printf("Failed to list chunks of expired upload %s: %v", id, err)
+				continue
+			}
+			for _, part := range parts {
+				if err := s.blobs.Delete(ctx, part.Key); err != nil {
+					log.Printf("Failed to delete blob %s: %v", part.Key, err)
+				}
+			}
+		}
+		purged += len(ids)
+
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// openUpload returns an upload of the user that has not expired yet
+func (s *FileService) openUpload(ctx context.Context, id, userID string) (*pb.Upload, error) {
+	upload, err := s.uploads.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, repoError("get upload", err)
+	}
+	upload = withExpiry(upload)
+	if !s.now().Before(upload.ExpiresAt.AsTime()) {
+		return nil, status.Error(codes.NotFound, "upload has expired")
+	}
+	return upload, nil
+}
+
+// withExpiry sets when an upload is discarded unless more of it arrives
+func withExpiry(upload *pb.Upload) *pb.Upload {
+	upload.ExpiresAt = timestamppb.New(upload.UpdatedAt.AsTime().Add(uploadExpiry))
+	return upload
+}
+
+// discardUpload removes the state and chunks of an upload that has been assembled.
+// Failures are only logged since the file itself is already complete.
+func (s *FileService) discardUpload(ctx context.Context, id, userID string) {
+	keys, err := s.uploads.Delete(ctx, id, userID)
+	if err != nil {
+		log.Printf("Failed to delete finished upload %s: %v", id, err)
+		return
+	}
+	s.deleteBlobs(ctx, keys)
+}
+
+func (s *FileService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// partReader streams the content of upload parts back to back
+type partReader struct {
+	ctx   context.Context
+	blobs storage.Backend
+	parts []domain.UploadPart
+	cur   io.ReadCloser
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+			rc, _, err := r.blobs.Get(r.ctx, r.parts[0].StorageKey)
+			if err != nil {
+				return 0, fmt.Errorf("failed to open upload part at offset %d: %w", r.parts[0].Offset, err)
+			}
+			r.cur = rc
+			r.parts = r.parts[1:]
+		}
+
+		n, err := r.cur.Read(p)
+		if errors.Is(err, io.EOF) {
+			r.cur.Close()
+			r.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *partReader) Close() error {
+	if r.cur != nil {
+		return r.cur.Close()
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const testUploadID = "423e4567-e89b-12d3-a456-426614174000"
+
+// MockUploadRepository is a mock implementation of UploadRepository
+type MockUploadRepository struct {
+	mock.Mock
+}
+
+func (m *MockUploadRepository) Create(ctx context.Context, req *pb.CreateUploadRequest, folderOwnerID string) (*pb.Upload, error) {
+	args := m.Called(ctx, req, folderOwnerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Upload), args.Error(1)
+}
+
+func (m *MockUploadRepository) GetByID(ctx context.Context, id, userID string) (*pb.Upload, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Upload), args.Error(1)
+}
+
+func (m *MockUploadRepository) CommitPart(ctx context.Context, id, userID string, offset, size int64, storageKey string) (*pb.Upload, error) {
+	args := m.Called(ctx, id, userID, offset, size, storageKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.Upload), args.Error(1)
+}
+
+func (m *MockUploadRepository) Parts(ctx context.Context, id string) ([]domain.UploadPart, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.UploadPart), args.Error(1)
+}
+
+func (m *MockUploadRepository) Delete(ctx context.Context, id, userID string) ([]string, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUploadRepository) Reserved(ctx context.Context, ownerID string, activeSince time.Time) (int64, error) {
+	args := m.Called(ctx, ownerID, activeSince)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUploadRepository) Expire(ctx context.Context, activeSince time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, activeSince, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestFileService_CreateUpload(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.CreateUploadRequest
+		mockSetup     func(*MockUploadRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "successful creation",
+			request: &pb.CreateUploadRequest{UserId: testUserID, Name: "movie.mp4", Size: 1 << 30},
+			mockSetup: func(repo *MockUploadRepository) {
+				repo.On("Reserved", mock.Anything, testUserID, mock.Anything).Return(int64(2<<30), nil)
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*file.CreateUploadRequest"), testUserID).
+					Return(&pb.Upload{Id: testUploadID, Size: 1 << 30, UpdatedAt: timestamppb.Now()}, nil)
+			},
+		},
+		{
+			name:    "open uploads reserve the quota",
+			request: &pb.CreateUploadRequest{UserId: testUserID, Name: "movie.mp4", Size: 1 << 30},
+			mockSetup: func(repo *MockUploadRepository) {
+				repo.On("Reserved", mock.Anything, testUserID, mock.Anything).Return(int64(14<<30+1), nil)
+			},
+			expectedError: true,
+			errorCode:     codes.ResourceExhausted,
+		},
+		{
+			name:          "missing name",
+			request:       &pb.CreateUploadRequest{UserId: testUserID, Size: 10},
+			mockSetup:     func(repo *MockUploadRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "too large",
+			request:       &pb.CreateUploadRequest{UserId: testUserID, Name: "huge.bin", Size: domain.MaxUploadSize + 1},
+			mockSetup:     func(repo *MockUploadRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:    "folder not found",
+			request: &pb.CreateUploadRequest{UserId: testUserID, Name: "movie.mp4", FolderId: testFolderID, Size: 10},
+			mockSetup: func(repo *MockUploadRepository) {
+				repo.On("Reserved", mock.Anything, testUserID, mock.Anything).Return(int64(0), nil)
+				repo.On("Create", mock.Anything, mock.Anything, testUserID).Return(nil, repository.ErrFolderNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUploads := new(MockUploadRepository)
+			tt.mockSetup(mockUploads)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.uploads = mockUploads
+			resp, err := service.CreateUpload(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testUploadID, resp.Upload.Id)
+				assert.WithinDuration(t, time.Now().Add(uploadExpiry), resp.Upload.ExpiresAt.AsTime(), time.Minute)
+			}
+
+			mockUploads.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_CommitUploadChunk(t *testing.T) {
+	partKey := repository.UploadPartPrefix(testUploadID) + "00000000000000000000-part"
+
+	tests := []struct {
+		name          string
+		request       *pb.CommitUploadChunkRequest
+		mockSetup     func(*MockUploadRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "chunk committed",
+			request: &pb.CommitUploadChunkRequest{Id: testUploadID, UserId: testUserID, Offset: 0, Size: 5, StorageKey: partKey},
+			mockSetup: func(repo *MockUploadRepository) {
+				repo.On("CommitPart", mock.Anything, testUploadID, testUserID, int64(0), int64(5), partKey).
+					Return(&pb.Upload{Id: testUploadID, Offset: 5, Size: 10}, nil)
+			},
+		},
+		{
+			name:    "offset conflict",
+			request: &pb.CommitUploadChunkRequest{Id: testUploadID, UserId: testUserID, Offset: 0, Size: 5, StorageKey: partKey},
+			mockSetup: func(repo *MockUploadRepository) {
+				repo.On("CommitPart", mock.Anything, testUploadID, testUserID, int64(0), int64(5), partKey).
+					Return(nil, repository.ErrUploadOffsetMismatch)
+			},
+			expectedError: true,
+			errorCode:     codes.FailedPrecondition,
+		},
+		{
+			name:          "foreign storage key",
+			request:       &pb.CommitUploadChunkRequest{Id: testUploadID, UserId: testUserID, Offset: 0, Size: 5, StorageKey: testUserID + "/" + testFileID},
+			mockSetup:     func(repo *MockUploadRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "empty chunk",
+			request:       &pb.CommitUploadChunkRequest{Id: testUploadID, UserId: testUserID, Offset: 0, Size: 0, StorageKey: partKey},
+			mockSetup:     func(repo *MockUploadRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUploads := new(MockUploadRepository)
+			tt.mockSetup(mockUploads)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.uploads = mockUploads
+			resp, err := service.CommitUploadChunk(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(5), resp.Upload.Offset)
+			}
+
+			mockUploads.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_FinishUpload(t *testing.T) {
+	ctx := context.Background()
+	storageKey := testUserID + "/" + testFileID
+	prefix := repository.UploadPartPrefix(testUploadID)
+
+	t.Run("assembles parts into a file", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockUploads := new(MockUploadRepository)
+		service := newTestService(t, mockRepo)
+		service.uploads = mockUploads
+
+		parts := []domain.UploadPart{
+			{Offset: 0, Size: 6, StorageKey: prefix + "0"},
+			{Offset: 6, Size: 5, StorageKey: prefix + "6"},
+		}
+		for i, content := range []string{"hello ", "world"} {
+			_, err := service.blobs.Put(ctx, parts[i].StorageKey, strings.NewReader(content), int64(len(content)))
+			require.NoError(t, err)
+		}
+		sum := sha256.Sum256([]byte("hello world"))
+		checksum := hex.EncodeToString(sum[:])
+
+		mockUploads.On("GetByID", mock.Anything, testUploadID, testUserID).
+			Return(&pb.Upload{Id: testUploadID, UserId: testUserID, Name: "hello.txt", MimeType: "text/plain", Size: 11, Offset: 11, UpdatedAt: timestamppb.Now()}, nil)
+		mockUploads.On("Parts", mock.Anything, testUploadID).Return(parts, nil)
+		mockRepo.On("Create", mock.Anything, &pb.CreateFileRequest{Name: "hello.txt", UserId: testUserID, MimeType: "text/plain"}, mock.Anything).
+			Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+		mockRepo.On("UpdateContent", mock.Anything, testFileID, testUserID, repository.BlobKey(checksum), int64(11), checksum, mock.Anything, mock.Anything).
+			Return(&pb.File{Id: testFileID, Size: 11, Checksum: checksum}, nil)
+		mockUploads.On("Delete", mock.Anything, testUploadID, testUserID).
+			Return([]string{parts[0].StorageKey, parts[1].StorageKey}, nil)
+
+		resp, err := service.FinishUpload(ctx, &pb.FinishUploadRequest{Id: testUploadID, UserId: testUserID})
+		require.NoError(t, err)
+		assert.Equal(t, checksum, resp.File.Checksum)
+
+		info, err := service.blobs.Stat(ctx, repository.BlobKey(checksum))
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), info.Size)
+		_, err = service.blobs.Stat(ctx, storageKey)
+		assert.Error(t, err, "assembled content should move to its blob key")
+
+		remaining, err := service.blobs.List(ctx, prefix)
+		require.NoError(t, err)
+		assert.Empty(t, remaining, "parts should be removed once assembled")
+
+		mockRepo.AssertExpectations(t)
+		mockUploads.AssertExpectations(t)
+	})
+
+	t.Run("incomplete upload", func(t *testing.T) {
+		mockUploads := new(MockUploadRepository)
+		service := newTestService(t, new(MockFileRepository))
+		service.uploads = mockUploads
+
+		mockUploads.On("GetByID", mock.Anything, testUploadID, testUserID).
+			Return(&pb.Upload{Id: testUploadID, Size: 11, Offset: 6, UpdatedAt: timestamppb.Now()}, nil)
+
+		_, err := service.FinishUpload(ctx, &pb.FinishUploadRequest{Id: testUploadID, UserId: testUserID})
+		assertStatusCode(t, err, codes.FailedPrecondition)
+	})
+
+	t.Run("expired upload", func(t *testing.T) {
+		mockUploads := new(MockUploadRepository)
+		service := newTestService(t, new(MockFileRepository))
+		service.uploads = mockUploads
+
+		mockUploads.On("GetByID", mock.Anything, testUploadID, testUserID).
+			Return(&pb.Upload{Id: testUploadID, Size: 11, Offset: 11, UpdatedAt: timestamppb.New(time.Now().Add(-uploadExpiry - time.Minute))}, nil)
+
+		_, err := service.FinishUpload(ctx, &pb.FinishUploadRequest{Id: testUploadID, UserId: testUserID})
+		assertStatusCode(t, err, codes.NotFound)
+		mockUploads.AssertNotCalled(t, "Parts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing part removes the file record", func(t *testing.T) {
+		mockRepo := new(MockFileRepository)
+		mockUploads := new(MockUploadRepository)
+		service := newTestService(t, mockRepo)
+		service.uploads = mockUploads
+
+		mockUploads.On("GetByID", mock.Anything, testUploadID, testUserID).
+			Return(&pb.Upload{Id: testUploadID, UserId: testUserID, Name: "hello.txt", Size: 11, Offset: 11, UpdatedAt: timestamppb.Now()}, nil)
+		mockUploads.On("Parts", mock.Anything, testUploadID).
+			Return([]domain.UploadPart{{Offset: 0, Size: 11, StorageKey: prefix + "missing"}}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).
+			Return(&pb.File{Id: testFileID, UserId: testUserID, StorageKey: storageKey}, nil)
+		mockRepo.On("Discard", mock.Anything, testFileID, testUserID).Return([]string{}, nil)
+
+		_, err := service.FinishUpload(ctx, &pb.FinishUploadRequest{Id: testUploadID, UserId: testUserID})
+		assertStatusCode(t, err, codes.Internal)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestFileService_DeleteUpload(t *testing.T) {
+	ctx := context.Background()
+	mockUploads := new(MockUploadRepository)
+	service := newTestService(t, new(MockFileRepository))
+	service.uploads = mockUploads
+
+	partKey := repository.UploadPartPrefix(testUploadID) + "0"
+	_, err := service.blobs.Put(ctx, partKey, strings.NewReader("chunk"), 5)
+	require.NoError(t, err)
+
+	mockUploads.On("Delete", mock.Anything, testUploadID, testUserID).Return([]string{partKey}, nil)
+
+	_, err = service.DeleteUpload(ctx, &pb.DeleteUploadRequest{Id: testUploadID, UserId: testUserID})
+	require.NoError(t, err)
+
+	_, err = service.blobs.Stat(ctx, partKey)
+	assert.Error(t, err)
+	mockUploads.AssertExpectations(t)
+}
+
+func TestFileService_PurgeUploads(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockUploads := new(MockUploadRepository)
+	service := newTestService(t, new(MockFileRepository))
+	service.uploads = mockUploads
+	service.now = func() time.Time { return now }
+
+	// A chunk that was stored but never committed is removed along with the committed ones
+	prefix := repository.UploadPartPrefix(testUploadID)
+	for _, key := range []string{prefix + "0", prefix + "5-uncommitted"} {
+		_, err := service.blobs.Put(ctx, key, strings.NewReader("chunk"), 5)
+		require.NoError(t, err)
+	}
+	otherKey := repository.UploadPartPrefix(testFileID) + "0"
+	_, err := service.blobs.Put(ctx, otherKey, strings.NewReader("chunk"), 5)
+	require.NoError(t, err)
+
+	mockUploads.On("Expire", mock.Anything, now.Add(-uploadExpiry), purgeBatchSize).Return([]string{testUploadID}, nil)
+
+	purged, err := service.PurgeUploads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	remaining, err := service.blobs.List(ctx, prefix)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+	_, err = service.blobs.Stat(ctx, otherKey)
+	assert.NoError(t, err, "chunks of other uploads should be kept")
+	mockUploads.AssertExpectations(t)
+}
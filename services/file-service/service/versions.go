@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-drive/internal/domain"
+	"go-drive/internal/signedurl"
+	pb "go-drive/proto/file"
+)
+
+func (s *FileService) ListFileVersions(ctx context.Context, req *pb.ListFileVersionsRequest) (*pb.ListFileVersionsResponse, error) {
+	if err := validateID("file_id", req.FileId); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	owner, err := s.authorizeFile(ctx, req.FileId, req.UserId, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, totalCount, err := s.versions.List(ctx, req.FileId, owner, req.Page, req.PageSize)
+	if err != nil {
+		return nil, repoError("list file versions", err)
+	}
+
+	return &pb.ListFileVersionsResponse{
+		Versions:   versions,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (s *FileService) GetFileVersion(ctx context.Context, req *pb.GetFileVersionRequest) (*pb.GetFileVersionResponse, error) {
+	if err := validateID("file_id", req.FileId); err != nil {
+		return nil, err
+	}
+	if err := validateID("version_id", req.VersionId); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFile(ctx, req.FileId, req.UserId, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.FileId, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+	version, err := s.versions.GetByID(ctx, req.FileId, req.VersionId, owner)
+	if err != nil {
+		return nil, repoError("get file version", err)
+	}
+	if err := checkVersionDownloadable(version); err != nil {
+		return nil, err
+	}
+	// Versions do not record their type, so it is detected again
+	typ, err := s.detectType(ctx, file, version.StorageKey, version.Size)
+	if err != nil {
+		return nil, repoError("detect version type", err)
+	}
+	if typ.QuarantineReason != "" {
+		return nil, status.Errorf(codes.PermissionDenied, "file version is quarantined: %s", typ.QuarantineReason)
+	}
+
+	return &pb.GetFileVersionResponse{
+		Version: version,
+		DownloadUrl: s.urls.Sign(signedurl.Grant{
+			Method:      http.MethodGet,
+			FileID:      version.FileId,
+			UserID:      version.UserId,
+			Key:         version.StorageKey,
+			VersionID:   version.Id,
+			ContentType: typ.MimeType,
+			ExpiresAt:   s.now().Add(signedURLTTL),
+		}),
+	}, nil
+}
+
+func (s *FileService) RestoreFileVersion(ctx context.Context, req *pb.RestoreFileVersionRequest) (*pb.RestoreFileVersionResponse, error) {
+	if err := validateID("file_id", req.FileId); err != nil {
+		return nil, err
+	}
+	if err := validateID("version_id", req.VersionId); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.authorizeFile(ctx, req.FileId, req.UserId, domain.ShareRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.repo.GetByID(ctx, req.FileId, owner)
+	if err != nil {
+		return nil, repoError("get file", err)
+	}
+	version, err := s.versions.GetByID(ctx, req.FileId, req.VersionId, owner)
+	if err != nil {
+		return nil, repoError("get file version", err)
+	}
+	// Infected content has been moved to quarantine, out of reach of the file
+	if version.ScanStatus == domain.ScanInfected {
+		return nil, status.Error(codes.PermissionDenied, "file version is quarantined: malware found")
+	}
+	typ, err := s.detectType(ctx, file, version.StorageKey, version.Size)
+	if err != nil {
+		return nil, repoError("detect version type", err)
+	}
+
+	file, err = s.versions.Restore(ctx, req.FileId, req.VersionId, owner, typ)
+	if err != nil {
+		return nil, repoError("restore file version", err)
+	}
+
+	return &pb.RestoreFileVersionResponse{File: file}, nil
+}
+
+// PruneFileVersions deletes old versions of a file along with content no longer used elsewhere
+func (s *FileService) PruneFileVersions(ctx context.Context, req *pb.PruneFileVersionsRequest) (*pb.PruneFileVersionsResponse, error) {
+	if err := validateID("file_id", req.FileId); err != nil {
+		return nil, err
+	}
+	if err := validateID("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if req.KeepCount < 0 {
+		return nil, status.Error(codes.InvalidArgument, "keep_count must not be negative")
+	}
+	if req.KeepCount == 0 && req.OlderThan == nil {
+		return nil, status.Error(codes.InvalidArgument, "keep_count or older_than is required")
+	}
+
+	var before time.Time
+	if req.OlderThan != nil {
+		if err := req.OlderThan.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "older_than is invalid: %v", err)
+		}
+		before = req.OlderThan.AsTime()
+	}
+
+	// Pruning deletes history for good, so it is left to the owner
+	if _, err := s.authorizeFile(ctx, req.FileId, req.UserId, domain.ShareRoleOwner); err != nil {
+		return nil, err
+	}
+
+	pruned, keys, err := s.versions.Prune(ctx, req.FileId, req.UserId, req.KeepCount, before)
+	if err != nil {
+		return nil, repoError("prune file versions", err)
+	}
+	s.deleteContent(ctx, keys)
+	s.collectBlobs(ctx)
+
+	return &pb.PruneFileVersionsResponse{
+		Message:        fmt.Sprintf("Deleted %d versions", pruned),
+		PrunedVersions: pruned,
+	}, nil
+}
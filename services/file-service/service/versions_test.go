@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-drive/internal/domain"
+	pb "go-drive/proto/file"
+	"go-drive/services/file-service/repository"
+)
+
+const testVersionID = "623e4567-e89b-12d3-a456-426614174000"
+
+// MockVersionRepository is a mock implementation of VersionRepository
+type MockVersionRepository struct {
+	mock.Mock
+}
+
+func (m *MockVersionRepository) List(ctx context.Context, fileID, userID string, page, pageSize int32) ([]*pb.FileVersion, int32, error) {
+	args := m.Called(ctx, fileID, userID, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*pb.FileVersion), args.Get(1).(int32), args.Error(2)
+}
+
+func (m *MockVersionRepository) GetByID(ctx context.Context, fileID, versionID, userID string) (*pb.FileVersion, error) {
+	args := m.Called(ctx, fileID, versionID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.FileVersion), args.Error(1)
+}
+
+func (m *MockVersionRepository) Restore(ctx context.Context, fileID, versionID, userID string, typ repository.ContentType) (*pb.File, error) {
+	args := m.Called(ctx, fileID, versionID, userID, typ)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.File), args.Error(1)
+}
+
+func (m *MockVersionRepository) Prune(ctx context.Context, fileID, userID string, keep int32, before time.Time) (int32, []string, error) {
+	args := m.Called(ctx, fileID, userID, keep, before)
+	if args.Get(1) == nil {
+		return args.Get(0).(int32), nil, args.Error(2)
+	}
+	return args.Get(0).(int32), args.Get(1).([]string), args.Error(2)
+}
+
+func TestFileService_GetFileVersion(t *testing.T) {
+	versionKey := testUserID + "/" + testFileID + ".1"
+
+	tests := []struct {
+		name          string
+		scanStatus    string
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{name: "clean version", scanStatus: domain.ScanClean},
+		{name: "version not scanned yet", scanStatus: domain.ScanPending, expectedError: true, errorCode: codes.FailedPrecondition},
+		{name: "infected version", scanStatus: domain.ScanInfected, expectedError: true, errorCode: codes.PermissionDenied},
+		{name: "version that could not be scanned", scanStatus: domain.ScanError, expectedError: true, errorCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFileRepository)
+			mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+				Return(&pb.File{Id: testFileID, UserId: testUserID, MimeType: "text/plain"}, nil)
+			mockVersions := new(MockVersionRepository)
+			mockVersions.On("GetByID", mock.Anything, testFileID, testVersionID, testUserID).
+				Return(&pb.FileVersion{Id: testVersionID, FileId: testFileID, UserId: testUserID, StorageKey: versionKey, ScanStatus: tt.scanStatus}, nil)
+
+			service := newTestService(t, mockRepo)
+			service.versions = mockVersions
+			resp, err := service.GetFileVersion(context.Background(), &pb.GetFileVersionRequest{
+				FileId: testFileID, VersionId: testVersionID, UserId: testUserID,
+			})
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testVersionID, resp.Version.Id)
+				assert.Contains(t, resp.DownloadUrl, "version_id="+testVersionID)
+				assert.Contains(t, resp.DownloadUrl, "key="+url.QueryEscape(versionKey))
+			}
+			mockVersions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_RestoreFileVersion(t *testing.T) {
+	versionKey := testUserID + "/" + testFileID + ".1"
+	version := &pb.FileVersion{Id: testVersionID, FileId: testFileID, UserId: testUserID, Size: 8, StorageKey: versionKey}
+
+	tests := []struct {
+		name          string
+		mockSetup     func(*MockVersionRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name: "version restored with its detected type",
+			mockSetup: func(repo *MockVersionRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testVersionID, testUserID).Return(version, nil)
+				repo.On("Restore", mock.Anything, testFileID, testVersionID, testUserID, repository.ContentType{MimeType: "application/pdf", ScanStatus: domain.ScanPending}).
+					Return(&pb.File{Id: testFileID, Checksum: "old"}, nil)
+			},
+		},
+		{
+			name: "infected version",
+			mockSetup: func(repo *MockVersionRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testVersionID, testUserID).
+					Return(&pb.FileVersion{Id: testVersionID, FileId: testFileID, StorageKey: versionKey, ScanStatus: domain.ScanInfected}, nil)
+			},
+			expectedError: true,
+			errorCode:     codes.PermissionDenied,
+		},
+		{
+			name: "version not found",
+			mockSetup: func(repo *MockVersionRepository) {
+				repo.On("GetByID", mock.Anything, testFileID, testVersionID, testUserID).
+					Return(nil, repository.ErrVersionNotFound)
+			},
+			expectedError: true,
+			errorCode:     codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVersions := new(MockVersionRepository)
+			tt.mockSetup(mockVersions)
+			mockRepo := new(MockFileRepository)
+			mockRepo.On("GetByID", mock.Anything, testFileID, testUserID).
+				Return(&pb.File{Id: testFileID, UserId: testUserID, Name: "report.pdf", DeclaredMimeType: "application/pdf"}, nil)
+
+			service := newTestService(t, mockRepo)
+			service.versions = mockVersions
+			_, err := service.blobs.Put(context.Background(), versionKey, strings.NewReader("%PDF-1.7"), -1)
+			require.NoError(t, err)
+			resp, err := service.RestoreFileVersion(context.Background(), &pb.RestoreFileVersionRequest{
+				FileId: testFileID, VersionId: testVersionID, UserId: testUserID,
+			})
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "old", resp.File.Checksum)
+			}
+
+			mockVersions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileService_PruneFileVersions(t *testing.T) {
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		request       *pb.PruneFileVersionsRequest
+		mockSetup     func(*MockVersionRepository)
+		expectedError bool
+		errorCode     codes.Code
+	}{
+		{
+			name:    "by count and age",
+			request: &pb.PruneFileVersionsRequest{FileId: testFileID, UserId: testUserID, KeepCount: 5, OlderThan: timestamppb.New(cutoff)},
+			mockSetup: func(repo *MockVersionRepository) {
+				repo.On("Prune", mock.Anything, testFileID, testUserID, int32(5), cutoff).
+					Return(int32(1), []string{testUserID + "/" + testFileID}, nil)
+			},
+		},
+		{
+			name:          "no criteria",
+			request:       &pb.PruneFileVersionsRequest{FileId: testFileID, UserId: testUserID},
+			mockSetup:     func(repo *MockVersionRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+		{
+			name:          "negative count",
+			request:       &pb.PruneFileVersionsRequest{FileId: testFileID, UserId: testUserID, KeepCount: -1},
+			mockSetup:     func(repo *MockVersionRepository) {},
+			expectedError: true,
+			errorCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVersions := new(MockVersionRepository)
+			tt.mockSetup(mockVersions)
+
+			service := newTestService(t, new(MockFileRepository))
+			service.versions = mockVersions
+			key := testUserID + "/" + testFileID
+			_, err := service.blobs.Put(context.Background(), key, strings.NewReader("old"), 3)
+			require.NoError(t, err)
+
+			resp, err := service.PruneFileVersions(context.Background(), tt.request)
+
+			if tt.expectedError {
+				assertStatusCode(t, err, tt.errorCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int32(1), resp.PrunedVersions)
+				_, err = service.blobs.Stat(context.Background(), key)
+				assert.Error(t, err, "pruned content should be deleted")
+			}
+
+			mockVersions.AssertExpectations(t)
+		})
+	}
+}